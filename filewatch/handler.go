@@ -69,6 +69,11 @@ type LogHandlerConfig struct {
 	AttachFilename          bool
 	Trim                    bool // run trim space on entries
 	TimestampWindow         timegrinder.TimestampWindow
+	TimestampLocale         string
+	TimestampAnchor         string
+	TimestampCounters       *timegrinder.Counters    // optional, shared extraction stats
+	TimestampZones          *timegrinder.SourceZones // optional, per source timezones keyed by Src and file name
+	Pool                    *entry.Pool              // optional, entries are drawn from the pool; only set it if entries go straight to a muxer
 }
 
 type logWriter interface {
@@ -88,6 +93,10 @@ func NewLogHandler(cfg LogHandlerConfig, w logWriter) (*LogHandler, error) {
 		tcfg := timegrinder.Config{
 			EnableLeftMostSeed: true,
 			TSWindow:           cfg.TimestampWindow,
+			Locale:             cfg.TimestampLocale,
+			Anchor:             cfg.TimestampAnchor,
+			Counters:           cfg.TimestampCounters,
+			Zones:              cfg.TimestampZones,
 		}
 		if tg, err = timegrinder.NewTimeGrinder(tcfg); err != nil {
 			return nil, err
@@ -167,6 +176,7 @@ func (lh *LogHandler) HandleLog(b []byte, catchts time.Time, fname string) error
 	}
 
 	if !lh.IgnoreTS {
+		lh.tg.SetSource(lh.Src, fname)
 		ts, ok, err = lh.tg.Extract(b)
 		if err != nil {
 			lh.Logger.Error("catastrophic timegrinder failure", log.KVErr(err))
@@ -179,12 +189,11 @@ func (lh *LogHandler) HandleLog(b []byte, catchts time.Time, fname string) error
 	if lh.Debugger != nil {
 		lh.Debugger("GOT %s %s\n", ts.Format(time.RFC3339), string(b))
 	}
-	ent := &entry.Entry{
-		SRC:  lh.Src,
-		TS:   entry.FromStandard(ts),
-		Tag:  lh.LogHandlerConfig.Tag,
-		Data: b,
-	}
+	//b belongs to the reader, so the entry always gets its own copy
+	ent := lh.Pool.GetCopy(b)
+	ent.SRC = lh.Src
+	ent.TS = entry.FromStandard(ts)
+	ent.Tag = lh.LogHandlerConfig.Tag
 	if lh.AttachFilename {
 		ent.AddEnumeratedValue(entry.EnumeratedValue{
 			Name:  evFilenameName,
@@ -0,0 +1,158 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package filewatch
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gravwell/gravwell/v3/ingest/entry"
+	"github.com/gravwell/gravwell/v3/ingest/log"
+)
+
+// poolingWriter stands in for the muxer, it takes ownership of each entry and hands it back to the pool
+type poolingWriter struct {
+	pool *entry.Pool
+	ents []*entry.Entry
+	keep bool
+	cnt  int
+}
+
+func (pw *poolingWriter) ProcessContext(ent *entry.Entry, ctx context.Context) error {
+	pw.cnt++
+	if pw.keep {
+		pw.ents = append(pw.ents, ent)
+	} else {
+		pw.pool.Put(ent)
+	}
+	return nil
+}
+
+func TestHandlerOwnership(t *testing.T) {
+	name := filepath.Join(t.TempDir(), `test.log`)
+	lines := []string{`short line`, string(randomString(8192)) + `long`, `last line`}
+	fout, err := os.Create(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, ln := range lines {
+		fmt.Fprintf(fout, "%s\n", ln)
+	}
+	if err = fout.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	//the reader reuses its buffers, every entry the handler produces must still hold its own line
+	for _, pool := range []*entry.Pool{nil, entry.NewPool()} {
+		pw := &poolingWriter{pool: pool, keep: true}
+		lh, err := NewLogHandler(LogHandlerConfig{
+			IgnoreTS: true,
+			Logger:   log.NewDiscardLogger(),
+			Ctx:      context.Background(),
+			Pool:     pool,
+		}, pw)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fin, err := os.Open(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		lnr, err := NewLineReader(ReaderConfig{Fin: fin, MaxLineLen: defMaxLine})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for {
+			ln, ok, _, err := lnr.ReadEntry()
+			if err != nil {
+				t.Fatal(err)
+			} else if !ok {
+				break
+			}
+			if err = lh.HandleLog(ln, time.Now(), name); err != nil {
+				t.Fatal(err)
+			}
+		}
+		lnr.Close()
+		if len(pw.ents) != len(lines) {
+			t.Fatalf("bad entry count: %d != %d", len(pw.ents), len(lines))
+		}
+		for i, ent := range pw.ents {
+			if string(ent.Data) != lines[i] {
+				t.Fatalf("entry %d was clobbered: %q", i, ent.Data)
+			}
+		}
+	}
+}
+
+// BenchmarkHandleLog reads lines through a LineReader and LogHandler with and without an entry pool,
+// run it with -benchtime=1000000x -benchmem to compare allocations over a million lines.
+func BenchmarkHandleLog(b *testing.B) {
+	name := filepath.Join(b.TempDir(), `bench.log`)
+	fout, err := os.Create(name)
+	if err != nil {
+		b.Fatal(err)
+	}
+	for i := 0; i < testLineCount; i++ {
+		fmt.Fprintf(fout, "%s %d\n", randomString(256), i)
+	}
+	if err = fout.Close(); err != nil {
+		b.Fatal(err)
+	}
+	b.Run("nopool", func(b *testing.B) {
+		benchmarkHandleLog(b, name, nil)
+	})
+	b.Run("pool", func(b *testing.B) {
+		benchmarkHandleLog(b, name, entry.NewPool())
+	})
+}
+
+func benchmarkHandleLog(b *testing.B, name string, pool *entry.Pool) {
+	pw := &poolingWriter{pool: pool}
+	lh, err := NewLogHandler(LogHandlerConfig{
+		IgnoreTS: true,
+		Logger:   log.NewDiscardLogger(),
+		Ctx:      context.Background(),
+		Pool:     pool,
+	}, pw)
+	if err != nil {
+		b.Fatal(err)
+	}
+	fin, err := os.Open(name)
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer fin.Close()
+	lnr, err := NewLineReader(ReaderConfig{Fin: fin, MaxLineLen: defMaxLine})
+	if err != nil {
+		b.Fatal(err)
+	}
+	now := time.Now()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for pw.cnt < b.N {
+		ln, ok, _, err := lnr.ReadEntry()
+		if err != nil {
+			b.Fatal(err)
+		} else if !ok {
+			//wrap around to the start of the file
+			if err = lnr.SeekFile(0); err != nil {
+				b.Fatal(err)
+			}
+			continue
+		}
+		if err = lh.HandleLog(ln, now, name); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -31,10 +31,19 @@ func NewLineReader(cfg ReaderConfig) (*LineReader, error) {
 	}, nil
 }
 
+// ReadEntry hands back the next complete line, the returned slice points into buffers owned by the
+// LineReader and is only valid until the next call to ReadEntry or ReadRemaining.
 func (lr *LineReader) ReadEntry() (ln []byte, ok bool, wasEOF bool, err error) {
 	for {
-		//ReadBytes garuntees that it returns err == nil ONLY when the results hit the delimiter
-		b, lerr := lr.brdr.ReadBytes(byte('\n'))
+		//ReadSlice garuntees that it returns err == nil ONLY when the results hit the delimiter
+		//the slice points into the bufio buffer, anything we hang onto across reads goes into currLine
+		b, lerr := lr.brdr.ReadSlice(byte('\n'))
+		if lerr == bufio.ErrBufferFull {
+			//line is longer than the bufio buffer, stash what we have and keep reading
+			lr.idx += int64(len(b))
+			lr.currLine = append(lr.currLine, b...)
+			continue
+		}
 		//legit error
 		if lerr != nil && lerr != io.EOF {
 			err = lerr //set the error for return
@@ -54,8 +63,8 @@ func (lr *LineReader) ReadEntry() (ln []byte, ok bool, wasEOF bool, err error) {
 		if len(b) == 0 {
 			//we just got the ending to a line that we had the beginning of
 			if len(lr.currLine) != 0 {
-				ln = lr.currLine
-				lr.currLine = nil
+				ln = bytes.TrimRight(lr.currLine, "\r")
+				lr.currLine = lr.currLine[:0]
 				ok = true
 				return
 			}
@@ -73,7 +82,7 @@ func (lr *LineReader) ReadEntry() (ln []byte, ok bool, wasEOF bool, err error) {
 		//if we had stuff in curr line append it, otherwise just assign
 		if len(lr.currLine) != 0 {
 			ln = append(lr.currLine, b...)
-			lr.currLine = nil
+			lr.currLine = ln[:0]
 		} else {
 			ln = b
 		}
@@ -83,6 +92,7 @@ func (lr *LineReader) ReadEntry() (ln []byte, ok bool, wasEOF bool, err error) {
 	return
 }
 
+// ReadRemaining hands back any partial line, the same buffer rules as ReadEntry apply.
 func (lr *LineReader) ReadRemaining() (ln []byte, err error) {
 	var ok bool
 	if ln, ok, _, err = lr.ReadEntry(); err != nil || ok {
@@ -90,7 +100,7 @@ func (lr *LineReader) ReadRemaining() (ln []byte, err error) {
 		return
 	} else if len(lr.currLine) != 0 {
 		ln = lr.currLine
-		lr.currLine = nil
+		lr.currLine = lr.currLine[:0]
 	}
 	return
 }
@@ -25,6 +25,8 @@ const (
 	RegexEngine int = 1
 )
 
+// Reader pulls entries out of a file.  Readers are free to reuse their buffers, so the slices handed back
+// by ReadEntry and ReadRemaining are only valid until the next call; callers must copy anything they keep.
 type Reader interface {
 	SeekFile(int64) error
 	ReadEntry() ([]byte, bool, bool, error)
@@ -19,6 +19,7 @@ import (
 	"time"
 
 	"github.com/crewjam/rfc5424"
+	"github.com/gravwell/gravwell/v3/timegrinder"
 )
 
 const (
@@ -177,8 +178,44 @@ type IngesterState struct {
 	CacheSize     uint64
 	LastSeen      time.Time
 	Children      map[string]IngesterState
-	Configuration json.RawMessage `json:",omitempty"`
-	Metadata      json.RawMessage `json:",omitempty"`
+	Configuration json.RawMessage     `json:",omitempty"`
+	Metadata      json.RawMessage     `json:",omitempty"`
+	Preprocessors []PreprocessorStats `json:",omitempty"`
+	Timestamps    []TimestampStats    `json:",omitempty"`
+	Schemas       []SchemaStats       `json:",omitempty"`
+}
+
+// PreprocessorStats holds the cumulative counters for a single preprocessor
+type PreprocessorStats struct {
+	Set            string        // name the preprocessor set was registered under
+	Name           string        // name of the preprocessor block
+	Type           string        // preprocessor type
+	EntriesIn      uint64        // entries handed to the preprocessor
+	EntriesOut     uint64        // entries emitted by the preprocessor
+	Dropped        uint64        // entries consumed without being emitted
+	Errors         uint64        // calls to the preprocessor that returned an error
+	ProcessingTime time.Duration // cumulative time spent in the preprocessor
+	SpoolEntries   uint64        `json:",omitempty"` // entries waiting in an on-disk spool
+	SpoolBytes     uint64        `json:",omitempty"` // bytes waiting in an on-disk spool
+	SpoolDropped   uint64        `json:",omitempty"` // entries dropped because the spool was full
+}
+
+// PreprocessorStatsSource is implemented by anything that can report preprocessor counters,
+// typically a processors.ProcessorSet
+type PreprocessorStatsSource interface {
+	PreprocessorStats() []PreprocessorStats
+}
+
+// TimestampStats holds the timestamp extraction counters for a named source, such as a listener
+type TimestampStats struct {
+	Name string
+	timegrinder.Stats
+}
+
+// TimestampStatsSource is implemented by anything that can report timestamp extraction counters,
+// typically a *timegrinder.Counters shared by the timegrinders of a listener
+type TimestampStatsSource interface {
+	Stats() timegrinder.Stats
 }
 
 type writeCounter struct {
@@ -297,6 +334,19 @@ func (s IngesterState) Copy() (r IngesterState) {
 	for k, v := range s.Children {
 		r.Children[k] = v.Copy()
 	}
+	if s.Preprocessors != nil {
+		r.Preprocessors = append([]PreprocessorStats(nil), s.Preprocessors...)
+	}
+	if s.Timestamps != nil {
+		r.Timestamps = make([]TimestampStats, len(s.Timestamps))
+		for i, v := range s.Timestamps {
+			r.Timestamps[i] = TimestampStats{Name: v.Name}
+			r.Timestamps[i].Add(v.Stats)
+		}
+	}
+	if s.Schemas != nil {
+		r.Schemas = append([]SchemaStats(nil), s.Schemas...)
+	}
 	return
 }
 
@@ -336,8 +386,11 @@ func (s IngesterState) MarshalJSON() ([]byte, error) {
 		CacheSize     uint64
 		LastSeen      time.Time
 		Children      mis
-		Configuration json.RawMessage `json:",omitempty"`
-		Metadata      json.RawMessage `json:",omitempty"`
+		Configuration json.RawMessage     `json:",omitempty"`
+		Metadata      json.RawMessage     `json:",omitempty"`
+		Preprocessors []PreprocessorStats `json:",omitempty"`
+		Timestamps    []TimestampStats    `json:",omitempty"`
+		Schemas       []SchemaStats       `json:",omitempty"`
 	}{
 		UUID:          s.UUID,
 		Name:          s.Name,
@@ -355,6 +408,9 @@ func (s IngesterState) MarshalJSON() ([]byte, error) {
 		Children:      mis{mp: s.Children},
 		Configuration: s.Configuration,
 		Metadata:      s.Metadata,
+		Preprocessors: s.Preprocessors,
+		Timestamps:    s.Timestamps,
+		Schemas:       s.Schemas,
 	}
 	return json.Marshal(x)
 }
@@ -13,6 +13,8 @@ import (
 	"encoding/binary"
 	"reflect"
 	"testing"
+
+	"github.com/gravwell/gravwell/v3/timegrinder"
 )
 
 func TestStreamConfigurationEncodeDecode(t *testing.T) {
@@ -100,3 +102,60 @@ func TestIngestState(t *testing.T) {
 		t.Fatalf("ReadWrite failure: %+v != %+v\n", x, y)
 	}
 }
+
+func TestIngestStatePreprocessors(t *testing.T) {
+	bb := bytes.NewBuffer(make([]byte, 0, 64))
+	x := IngesterState{
+		Name:     "foobar",
+		Tags:     []string{},
+		Children: map[string]IngesterState{},
+		Preprocessors: []PreprocessorStats{
+			{Set: `listener`, Name: `kv`, Type: `kv`, EntriesIn: 10, EntriesOut: 8, Dropped: 2, Errors: 1, ProcessingTime: 1234},
+		},
+	}
+	var y IngesterState
+	if err := x.Write(bb); err != nil {
+		t.Fatal(err)
+	}
+	if err := y.Read(bb); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(x, y) {
+		t.Fatalf("ReadWrite failure: %+v != %+v\n", x, y)
+	}
+	// copies must not share the stats slice
+	z := x.Copy()
+	z.Preprocessors[0].EntriesIn = 0
+	if x.Preprocessors[0].EntriesIn != 10 {
+		t.Fatal("Copy shares preprocessor stats")
+	}
+}
+
+func TestIngestStateTimestamps(t *testing.T) {
+	bb := bytes.NewBuffer(make([]byte, 0, 64))
+	x := IngesterState{
+		Name:     "foobar",
+		Tags:     []string{},
+		Children: map[string]IngesterState{},
+		Timestamps: []TimestampStats{
+			{Name: `syslog`, Stats: timegrinder.Stats{Hits: 10, Misses: 2, Fallbacks: 1, Formats: map[string]uint64{`Syslog`: 12}}},
+		},
+	}
+	var y IngesterState
+	if err := x.Write(bb); err != nil {
+		t.Fatal(err)
+	}
+	if err := y.Read(bb); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(x, y) {
+		t.Fatalf("ReadWrite failure: %+v != %+v\n", x, y)
+	}
+	// copies must not share the stats or format maps
+	z := x.Copy()
+	z.Timestamps[0].Hits = 0
+	z.Timestamps[0].Formats[`Syslog`] = 0
+	if x.Timestamps[0].Hits != 10 || x.Timestamps[0].Formats[`Syslog`] != 12 {
+		t.Fatal("Copy shares timestamp stats")
+	}
+}
@@ -25,8 +25,11 @@ import (
 const (
 	// The number of times to hash the shared secret
 	HASH_ITERATIONS uint16 = 16
-	// Auth protocol version number
-	VERSION uint16 = 0x9
+	// Auth protocol version number, this is also the minor API version.
+	// Version 0xA added column blocks, ingesters only send them to indexers that negotiate 0xA or newer
+	// and fall back to individual entries for older indexers.
+	// Version 0xB added list and map enumerated values, they are sent to older indexers as strings.
+	VERSION uint16 = 0xB
 	// Authenticated, but not ready for ingest
 	STATE_AUTHENTICATED uint32 = 0xBEEF42
 	// Not authenticated
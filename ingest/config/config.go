@@ -123,6 +123,10 @@ type IngestConfig struct {
 	Stats_Sample_Interval      string   `json:",omitempty"` // if set to > 0 duration then we periodically throw stats
 	Timestamp_Max_Past_Delta   string   // if set to > 0 (e.g. "1h"), set TS of entries further than this in the past to now
 	Timestamp_Max_Future_Delta string   // if set to > 0, set TS of entries further that this in the future to now.
+	Timestamp_Locale           string   `json:",omitempty"` // locale used for month and weekday names by every ingester timestamp extractor (e.g. "de")
+	Timezone_Map               []string `json:",omitempty"` // per source timezones for zoneless timestamps (e.g. "10.1.0.0/16 America/New_York")
+	Infer_Source_Timezone      bool     `json:",omitempty"` // infer a fixed UTC offset for unmapped sources from zoneless timestamps in live data, does not follow DST
+	Schema_File                []string `json:",omitempty"` // files declaring the enumerated value schemas of tags
 }
 
 type IngestStreamConfig struct {
@@ -133,6 +137,19 @@ type TimeFormat struct {
 	Format           string
 	Regex            string
 	Extraction_Regex string
+	Strftime_Format  string // strftime pattern, generates Format and Regex
+	Java_Format      string // Java DateTimeFormatter pattern, generates Format and Regex
+}
+
+func (tf *TimeFormat) customFormat(name string) timegrinder.CustomFormat {
+	return timegrinder.CustomFormat{
+		Name:             name,
+		Format:           tf.Format,
+		Regex:            tf.Regex,
+		Extraction_Regex: tf.Extraction_Regex,
+		Strftime_Format:  tf.Strftime_Format,
+		Java_Format:      tf.Java_Format,
+	}
 }
 
 type CustomTimeFormat map[string]*TimeFormat
@@ -226,6 +243,12 @@ func (ic *IngestConfig) Verify() error {
 	if err := ic.checkLogLevel(); err != nil {
 		return err
 	}
+	if err := timegrinder.ValidateLocale(ic.Timestamp_Locale); err != nil {
+		return err
+	}
+	if err := timegrinder.ValidateZoneMap(ic.Timezone_Map); err != nil {
+		return err
+	}
 
 	if ic.Log_UDP_Target != `` && ic.Log_File != `` {
 		return errors.New("Log-File and Log-UDP-Target are mutually exclusive")
@@ -522,6 +545,15 @@ func (ic *IngestConfig) GlobalTimestampWindow() (w timegrinder.TimestampWindow,
 	return
 }
 
+// SourceZones returns the per source timezones derived from the `Timezone-Map` and
+// `Infer-Source-Timezone` values, a nil SourceZones is returned if neither is set.
+func (ic *IngestConfig) SourceZones() (*timegrinder.SourceZones, error) {
+	if len(ic.Timezone_Map) == 0 && !ic.Infer_Source_Timezone {
+		return nil, nil
+	}
+	return timegrinder.NewSourceZones(ic.Timezone_Map, ic.Infer_Source_Timezone)
+}
+
 func writeFull(w io.Writer, b []byte) error {
 	var written int
 	for written < len(b) {
@@ -544,12 +576,7 @@ func (ctf CustomTimeFormat) Validate() (err error) {
 		if v == nil {
 			continue
 		}
-		cf := timegrinder.CustomFormat{
-			Name:             k,
-			Format:           v.Format,
-			Regex:            v.Regex,
-			Extraction_Regex: v.Extraction_Regex,
-		}
+		cf := v.customFormat(k)
 		if err = cf.Validate(); err != nil {
 			return
 		}
@@ -568,12 +595,7 @@ func (ctf CustomTimeFormat) LoadFormats(tg *timegrinder.TimeGrinder) (err error)
 		if v == nil {
 			continue
 		}
-		cf := timegrinder.CustomFormat{
-			Name:             k,
-			Format:           v.Format,
-			Regex:            v.Regex,
-			Extraction_Regex: v.Extraction_Regex,
-		}
+		cf := v.customFormat(k)
 		if p, err = timegrinder.NewCustomProcessor(cf); err != nil {
 			return
 		} else if _, err = tg.AddProcessor(p); err != nil {
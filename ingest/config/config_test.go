@@ -11,6 +11,9 @@ package config
 import (
 	"net"
 	"testing"
+	"time"
+
+	"github.com/gravwell/gravwell/v3/timegrinder"
 )
 
 func TestParseSourceIP(t *testing.T) {
@@ -53,3 +56,42 @@ func TestParseSourceIntegter(t *testing.T) {
 		}
 	}
 }
+
+func TestCustomTimeFormatPatterns(t *testing.T) {
+	var v struct {
+		TimeFormat CustomTimeFormat
+	}
+	b := []byte(`
+	[TimeFormat "strf"]
+		Strftime-Format="%d|%m|%Y %H:%M:%S"
+	[TimeFormat "java"]
+		Java-Format="yyyy.MM.dd'@'HH.mm.ss.SSS"
+	`)
+	if err := LoadConfigBytes(&v, b); err != nil {
+		t.Fatal(err)
+	} else if err = v.TimeFormat.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	tg, err := timegrinder.New(timegrinder.Config{})
+	if err != nil {
+		t.Fatal(err)
+	} else if err = v.TimeFormat.LoadFormats(tg); err != nil {
+		t.Fatal(err)
+	}
+	tsts := map[string]time.Time{
+		`foo 04|03|2024 05:06:07 bar`:     time.Date(2024, 3, 4, 5, 6, 7, 0, time.UTC),
+		`foo 2024.03.04@05.06.07.089 bar`: time.Date(2024, 3, 4, 5, 6, 7, 89000000, time.UTC),
+	}
+	for data, exp := range tsts {
+		if ts, ok, err := tg.Extract([]byte(data)); err != nil || !ok {
+			t.Fatalf("failed to extract from %q: %v", data, err)
+		} else if !ts.Equal(exp) {
+			t.Fatalf("bad timestamp from %q: %v != %v", data, ts, exp)
+		}
+	}
+
+	v.TimeFormat[`strf`].Java_Format = `HH:mm:ss`
+	if err = v.TimeFormat.Validate(); err == nil {
+		t.Fatal("failed to catch multiple patterns")
+	}
+}
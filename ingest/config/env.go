@@ -546,3 +546,19 @@ func loadStringFromFile(pth string, val *string) (err error) {
 
 	return
 }
+
+// LoadSecret resolves a secret using the same rules as Ingest-Secret. A directly specified value
+// is used first, then the contents of the file at pth, and finally the environment variable envName
+// (or the file referenced by envName_FILE). An empty string is returned if no source is populated.
+func LoadSecret(val, pth, envName string) (s string, err error) {
+	if s = val; len(s) > 0 {
+		return
+	} else if pth != `` {
+		if err = loadStringFromFile(pth, &s); err != nil {
+			err = fmt.Errorf("Failed to load secret from %q %w", pth, err)
+		}
+		return
+	}
+	err = loadEnvVarString(&s, envName, ``)
+	return
+}
@@ -367,3 +367,34 @@ func TestEnvFileLoadInt64(t *testing.T) {
 		t.Fatalf("Did not pull value from environment: %v != %v", v, tval)
 	}
 }
+
+func TestLoadSecret(t *testing.T) {
+	envId := `GRAVWELL_TEST_SECRET`
+	pth := filepath.Join(t.TempDir(), `secret`)
+	if err := os.WriteFile(pth, []byte("filesecret\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(envId, `envsecret`)
+
+	if s, err := LoadSecret(`direct`, pth, envId); err != nil {
+		t.Fatal(err)
+	} else if s != `direct` {
+		t.Fatalf("direct value not preferred: %q", s)
+	}
+	if s, err := LoadSecret(``, pth, envId); err != nil {
+		t.Fatal(err)
+	} else if s != `filesecret` {
+		t.Fatalf("file value not loaded: %q", s)
+	}
+	if s, err := LoadSecret(``, ``, envId); err != nil {
+		t.Fatal(err)
+	} else if s != `envsecret` {
+		t.Fatalf("env value not loaded: %q", s)
+	}
+	if s, err := LoadSecret(``, ``, ``); err != nil || s != `` {
+		t.Fatalf("unexpected secret %q %v", s, err)
+	}
+	if _, err := LoadSecret(``, filepath.Join(t.TempDir(), `nothere`), envId); err == nil {
+		t.Fatal("failed to catch missing file")
+	}
+}
@@ -0,0 +1,383 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package entry
+
+import (
+	"encoding/binary"
+	"errors"
+	"net"
+
+	"github.com/klauspost/compress/snappy"
+)
+
+const (
+	columnBlockVersion byte = 1
+
+	colFlagCompressed byte = 0x1
+
+	// payloads smaller than this are not worth compressing
+	minColumnCompressSize = 256
+	// snappy cannot expand data beyond this ratio, anything claiming more is corrupt
+	maxColumnCompressRatio = 32
+)
+
+var (
+	ErrInvalidColumnBlock = errors.New("ColumnBlock is invalid")
+	ErrColumnBlockVersion = errors.New("ColumnBlock version is not supported")
+)
+
+// ColumnBlock is a columnar encoding of a set of entries intended for high rate streams where
+// entries share a handful of tags and sources and carry near identical timestamps.
+// Tags and sources are dictionary encoded, timestamps are delta encoded against the previous entry,
+// and the data and enumerated values of every entry are concatenated into a single payload column
+// which is optionally snappy compressed as a unit.
+//
+// The encoding is:
+//
+//	version (byte) | flags (byte) | entry count (uvarint)
+//	tag count (uvarint) | tags (uvarint...) | tag index per entry (uvarint...) if more than one tag
+//	source count (uvarint) | sources (length byte + bytes...) | source index per entry (uvarint...) if more than one source
+//	first timestamp (varint sec, varint nsec) | deltas from the previous entry (varint sec, varint nsec...)
+//	data length per entry (uvarint...) | EV length per entry (uvarint...)
+//	payload length (uvarint) | payload
+type ColumnBlock struct {
+	entries []*Entry
+}
+
+// NewColumnBlock creates a new ColumnBlock from a set of entries, the set is NOT copied
+func NewColumnBlock(set []*Entry) ColumnBlock {
+	return ColumnBlock{entries: set}
+}
+
+// Add adds an entry to the block
+func (cb *ColumnBlock) Add(e *Entry) {
+	cb.entries = append(cb.entries, e)
+}
+
+// Count returns the number of entries held in the block
+func (cb *ColumnBlock) Count() int {
+	return len(cb.entries)
+}
+
+// Entries returns the underlying entry slice
+func (cb *ColumnBlock) Entries() []*Entry {
+	return cb.entries
+}
+
+// Encode encodes the block, compressing the payload column if it is worthwhile
+func (cb *ColumnBlock) Encode(compress bool) ([]byte, error) {
+	return cb.AppendEncode(nil, compress)
+}
+
+// AppendEncode encodes the block onto the end of buff and returns the extended buffer
+func (cb *ColumnBlock) AppendEncode(buff []byte, compress bool) ([]byte, error) {
+	cnt := len(cb.entries)
+	if cnt == 0 {
+		return nil, ErrInvalidColumnBlock
+	} else if uint64(cnt) > uint64(MaxSliceCount) {
+		return nil, ErrSliceLenTooLarge
+	}
+	var payloadSize uint64
+	tagIdx := map[EntryTag]uint64{}
+	var tags []EntryTag
+	srcIdx := map[string]uint64{}
+	var srcs []net.IP
+	for _, e := range cb.entries {
+		if e == nil {
+			return nil, ErrNilEntry
+		} else if len(e.Data) > int(MaxDataSize) {
+			return nil, ErrDataSizeTooLarge
+		} else if l := len(e.SRC); l != 0 && l != IPV4_SRC_SIZE && l != SRC_SIZE {
+			return nil, ErrInvalidColumnBlock
+		}
+		if _, ok := tagIdx[e.Tag]; !ok {
+			tagIdx[e.Tag] = uint64(len(tags))
+			tags = append(tags, e.Tag)
+		}
+		if _, ok := srcIdx[string(e.SRC)]; !ok {
+			srcIdx[string(e.SRC)] = uint64(len(srcs))
+			srcs = append(srcs, e.SRC)
+		}
+		payloadSize += uint64(len(e.Data)) + e.EVB.Size()
+	}
+	if payloadSize > maxEntryBlockSize {
+		return nil, ErrBlockTooLarge
+	}
+
+	start := len(buff)
+	buff = append(buff, columnBlockVersion, 0)
+	buff = binary.AppendUvarint(buff, uint64(cnt))
+
+	//tag column
+	buff = binary.AppendUvarint(buff, uint64(len(tags)))
+	for _, t := range tags {
+		buff = binary.AppendUvarint(buff, uint64(t))
+	}
+	if len(tags) > 1 {
+		for _, e := range cb.entries {
+			buff = binary.AppendUvarint(buff, tagIdx[e.Tag])
+		}
+	}
+
+	//source column
+	buff = binary.AppendUvarint(buff, uint64(len(srcs)))
+	for _, s := range srcs {
+		buff = append(buff, byte(len(s)))
+		buff = append(buff, s...)
+	}
+	if len(srcs) > 1 {
+		for _, e := range cb.entries {
+			buff = binary.AppendUvarint(buff, srcIdx[string(e.SRC)])
+		}
+	}
+
+	//timestamp column
+	var prev Timestamp
+	for _, e := range cb.entries {
+		buff = binary.AppendVarint(buff, e.TS.Sec-prev.Sec)
+		buff = binary.AppendVarint(buff, e.TS.Nsec-prev.Nsec)
+		prev = e.TS
+	}
+
+	//length columns
+	for _, e := range cb.entries {
+		buff = binary.AppendUvarint(buff, uint64(len(e.Data)))
+	}
+	for _, e := range cb.entries {
+		buff = binary.AppendUvarint(buff, e.EVB.Size())
+	}
+
+	//payload column
+	payload := make([]byte, payloadSize)
+	var off int
+	for _, e := range cb.entries {
+		off += copy(payload[off:], e.Data)
+		if e.EVB.Populated() {
+			n, err := e.EVB.EncodeBuffer(payload[off:])
+			if err != nil {
+				return nil, err
+			}
+			off += n
+		}
+	}
+	if compress && len(payload) >= minColumnCompressSize {
+		if c := snappy.Encode(nil, payload); len(c) < len(payload) {
+			payload = c
+			buff[start+1] |= colFlagCompressed
+		}
+	}
+	buff = binary.AppendUvarint(buff, uint64(len(payload)))
+	buff = append(buff, payload...)
+	return buff, nil
+}
+
+// Decode decodes a ColumnBlock from a buffer, replacing any entries held in the block.
+// If the payload was not compressed the entries directly reference the buffer and it
+// cannot be re-used while the entries are in use.
+func (cb *ColumnBlock) Decode(b []byte) (err error) {
+	d := colDecoder{b: b}
+	if len(b) < 2 {
+		return ErrInvalidSrcBuff
+	} else if b[0] != columnBlockVersion {
+		return ErrColumnBlockVersion
+	}
+	flags := b[1]
+	if (flags &^ colFlagCompressed) != 0 {
+		return ErrInvalidColumnBlock
+	}
+	d.off = 2
+
+	cnt := d.count()
+	if cnt == 0 && d.err == nil {
+		return ErrInvalidColumnBlock
+	} else if uint64(cnt) > uint64(MaxSliceCount) {
+		return ErrSliceLenTooLarge
+	}
+	tags := make([]EntryTag, d.count())
+	for i := range tags {
+		if v := d.uvarint(); v > 0xffff {
+			return ErrInvalidColumnBlock
+		} else {
+			tags[i] = EntryTag(v)
+		}
+	}
+	if d.err != nil {
+		return d.err
+	} else if len(tags) == 0 {
+		return ErrInvalidColumnBlock
+	}
+	ents := make([]Entry, cnt)
+	for i := range ents {
+		ents[i].Tag = tags[d.index(len(tags))]
+	}
+
+	srcs := make([]net.IP, d.count())
+	for i := range srcs {
+		l := int(d.byte())
+		if l != 0 && l != IPV4_SRC_SIZE && l != SRC_SIZE {
+			return ErrInvalidColumnBlock
+		}
+		srcs[i] = net.IP(d.bytes(l))
+	}
+	if d.err != nil {
+		return d.err
+	} else if len(srcs) == 0 {
+		return ErrInvalidColumnBlock
+	}
+	for i := range ents {
+		ents[i].SRC = srcs[d.index(len(srcs))]
+	}
+
+	var prev Timestamp
+	for i := range ents {
+		prev.Sec += d.varint()
+		prev.Nsec += d.varint()
+		ents[i].TS = prev
+	}
+
+	var total uint64
+	dlens := make([]uint64, cnt)
+	for i := range dlens {
+		if dlens[i] = d.uvarint(); dlens[i] > uint64(MaxDataSize) {
+			return ErrDataSizeTooLarge
+		}
+		total += dlens[i]
+	}
+	evlens := make([]uint64, cnt)
+	for i := range evlens {
+		if evlens[i] = d.uvarint(); evlens[i] > uint64(MaxDataSize) {
+			return ErrInvalidColumnBlock
+		}
+		total += evlens[i]
+	}
+	if total > maxEntryBlockSize {
+		return ErrBlockTooLarge
+	}
+
+	payload := d.bytes(d.count())
+	if d.err != nil {
+		return d.err
+	} else if d.off != len(b) {
+		return ErrPartialDecode
+	}
+	if (flags & colFlagCompressed) != 0 {
+		if uint64(len(payload))*maxColumnCompressRatio < total {
+			return ErrInvalidColumnBlock
+		} else if n, err := snappy.DecodedLen(payload); err != nil || uint64(n) != total {
+			return ErrInvalidColumnBlock
+		}
+		if payload, err = snappy.Decode(nil, payload); err != nil {
+			return ErrInvalidColumnBlock
+		}
+	}
+	if uint64(len(payload)) != total {
+		return ErrPartialDecode
+	}
+
+	cb.entries = make([]*Entry, cnt)
+	var off uint64
+	for i := range ents {
+		ents[i].Data = payload[off : off+dlens[i]]
+		off += dlens[i]
+		if evlens[i] > 0 {
+			if n, err := ents[i].EVB.DecodeAlt(payload[off : off+evlens[i]]); err != nil {
+				return err
+			} else if uint64(n) != evlens[i] {
+				return ErrInvalidColumnBlock
+			}
+			off += evlens[i]
+		}
+		cb.entries[i] = &ents[i]
+	}
+	return nil
+}
+
+// colDecoder walks a ColumnBlock buffer, the first error encountered is sticky
+// and all subsequent reads return zero values
+type colDecoder struct {
+	b   []byte
+	off int
+	err error
+}
+
+func (d *colDecoder) uvarint() (v uint64) {
+	if d.err != nil {
+		return
+	}
+	var n int
+	if v, n = binary.Uvarint(d.b[d.off:]); n <= 0 {
+		d.err = ErrPartialDecode
+		return 0
+	}
+	d.off += n
+	return
+}
+
+func (d *colDecoder) varint() (v int64) {
+	if d.err != nil {
+		return
+	}
+	var n int
+	if v, n = binary.Varint(d.b[d.off:]); n <= 0 {
+		d.err = ErrPartialDecode
+		return 0
+	}
+	d.off += n
+	return
+}
+
+// count reads a count of items that each consume at least a byte of the remaining buffer,
+// this prevents a corrupt count from driving huge allocations
+func (d *colDecoder) count() int {
+	v := d.uvarint()
+	if d.err == nil && v > uint64(len(d.b)-d.off) {
+		d.err = ErrPartialDecode
+		return 0
+	}
+	return int(v)
+}
+
+// index reads an index into a dictionary of n items, single item dictionaries are implicit
+func (d *colDecoder) index(n int) int {
+	if n == 1 || d.err != nil {
+		return 0
+	}
+	v := d.uvarint()
+	if v >= uint64(n) {
+		if d.err == nil {
+			d.err = ErrInvalidColumnBlock
+		}
+		return 0
+	}
+	return int(v)
+}
+
+func (d *colDecoder) byte() (v byte) {
+	if d.err != nil {
+		return
+	} else if d.off >= len(d.b) {
+		d.err = ErrPartialDecode
+		return
+	}
+	v = d.b[d.off]
+	d.off++
+	return
+}
+
+func (d *colDecoder) bytes(n int) (v []byte) {
+	if d.err != nil || n == 0 {
+		return
+	} else if n > len(d.b)-d.off {
+		d.err = ErrPartialDecode
+		return
+	}
+	v = d.b[d.off : d.off+n]
+	d.off += n
+	return
+}
@@ -0,0 +1,186 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package entry
+
+import (
+	"fmt"
+	"net"
+	"testing"
+)
+
+// genFlowEntries generates entries that look like a high rate netflow stream
+func genFlowEntries(cnt int, tags, srcs int, evs bool) (r []*Entry) {
+	base := Now()
+	for i := 0; i < cnt; i++ {
+		e := &Entry{
+			TS:   Timestamp{Sec: base.Sec + int64(i/100), Nsec: int64(i%100) * 1000},
+			Tag:  EntryTag(i % tags),
+			SRC:  net.IPv4(10, 0, 0, byte(i%srcs)).To4(),
+			Data: []byte(fmt.Sprintf(`{"src":"10.1.%d.%d","dst":"192.168.0.%d","sport":%d,"dport":443,"bytes":%d}`, i%3, i%250, i%7, 30000+i, i*17)),
+		}
+		if evs {
+			e.AddEnumeratedValueEx(`flow`, uint64(i))
+			e.AddEnumeratedValueEx(`proto`, `tcp`)
+		}
+		r = append(r, e)
+	}
+	return
+}
+
+func TestColumnBlockRoundTrip(t *testing.T) {
+	v6, err := genRandomEntryWithEvs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		ents []*Entry
+	}{
+		{`single`, genFlowEntries(1, 1, 1, false)},
+		{`uniform`, genFlowEntries(1000, 1, 1, false)},
+		{`mixed`, genFlowEntries(1000, 3, 17, false)},
+		{`evs`, genFlowEntries(1000, 2, 2, true)},
+		{`ipv6`, []*Entry{&v6, {TS: Timestamp{Sec: 1}, Tag: 7, Data: []byte(`no source`)}}},
+	}
+	for _, tt := range tests {
+		for _, compress := range []bool{false, true} {
+			cb := NewColumnBlock(tt.ents)
+			buff, err := cb.Encode(compress)
+			if err != nil {
+				t.Fatalf("%s: %v", tt.name, err)
+			}
+			var cb2 ColumnBlock
+			if err = cb2.Decode(buff); err != nil {
+				t.Fatalf("%s: %v", tt.name, err)
+			} else if cb2.Count() != len(tt.ents) {
+				t.Fatalf("%s: bad count %d != %d", tt.name, cb2.Count(), len(tt.ents))
+			}
+			for i, e := range cb2.Entries() {
+				if err = tt.ents[i].Compare(e); err != nil {
+					t.Fatalf("%s: entry %d: %v", tt.name, i, err)
+				}
+			}
+		}
+	}
+}
+
+func TestColumnBlockSize(t *testing.T) {
+	ents := genFlowEntries(1000, 1, 4, false)
+	eb := NewEntryBlock(ents, 0)
+	cb := NewColumnBlock(ents)
+	raw, err := cb.Encode(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	compressed, err := cb.Encode(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if uint64(len(raw)) >= eb.EncodedSize() {
+		t.Fatalf("columnar encoding is not smaller: %d >= %d", len(raw), eb.EncodedSize())
+	} else if len(compressed) >= len(raw) {
+		t.Fatalf("compressed encoding is not smaller: %d >= %d", len(compressed), len(raw))
+	}
+	t.Logf("row %d, columnar %d, compressed %d", eb.EncodedSize(), len(raw), len(compressed))
+}
+
+func TestColumnBlockErrors(t *testing.T) {
+	var empty ColumnBlock
+	if _, err := empty.Encode(false); err != ErrInvalidColumnBlock {
+		t.Fatalf("bad empty block error: %v", err)
+	}
+	empty.Add(nil)
+	if _, err := empty.Encode(false); err != ErrNilEntry {
+		t.Fatalf("bad nil entry error: %v", err)
+	}
+	badsrc := NewColumnBlock([]*Entry{{SRC: net.IP{1, 2}}})
+	if _, err := badsrc.Encode(false); err == nil {
+		t.Fatal("encoded an invalid source")
+	}
+
+	cb := NewColumnBlock(genFlowEntries(100, 2, 2, true))
+	buff, err := cb.Encode(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var x ColumnBlock
+	for i := 0; i < len(buff); i++ {
+		if err = x.Decode(buff[:i]); err == nil {
+			t.Fatalf("decoded truncated block at %d", i)
+		}
+	}
+	if err = x.Decode(append(buff, 0)); err == nil {
+		t.Fatal("decoded block with trailing bytes")
+	}
+	bad := append([]byte(nil), buff...)
+	bad[0] = 0xff
+	if err = x.Decode(bad); err != ErrColumnBlockVersion {
+		t.Fatalf("bad version error: %v", err)
+	}
+}
+
+func BenchmarkColumnBlockEncode(b *testing.B) {
+	ents := genFlowEntries(1000, 1, 4, false)
+	cb := NewColumnBlock(ents)
+	var buff []byte
+	var err error
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if buff, err = cb.AppendEncode(buff[:0], true); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.SetBytes(int64(len(buff)))
+}
+
+func BenchmarkEntryBlockEncode(b *testing.B) {
+	ents := genFlowEntries(1000, 1, 4, false)
+	eb := NewEntryBlock(ents, 0)
+	buff := make([]byte, eb.EncodedSize())
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := eb.EncodeInto(buff); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.SetBytes(int64(len(buff)))
+}
+
+func FuzzColumnBlock(f *testing.F) {
+	for x := 0; x < fuzzCorpusSize; x++ {
+		cb := NewColumnBlock(genFlowEntries(x+1, x%3+1, x%5+1, x%2 == 0))
+		buff, err := cb.Encode(x%4 < 2)
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(buff)
+	}
+	f.Fuzz(func(t *testing.T, orig []byte) {
+		var cb ColumnBlock
+		if err := cb.Decode(orig); err != nil {
+			return
+		}
+		//anything that decodes must survive a round trip
+		buff, err := cb.Encode(false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var cb2 ColumnBlock
+		if err = cb2.Decode(buff); err != nil {
+			t.Fatal(err)
+		}
+		for i, e := range cb2.Entries() {
+			if err = cb.Entries()[i].Compare(e); err != nil {
+				t.Fatalf("entry %d: %v", i, err)
+			}
+		}
+	})
+}
@@ -47,6 +47,7 @@ type Entry struct {
 	TS   Timestamp
 	SRC  net.IP
 	Tag  EntryTag
+	pool uint8 // size class + 1 if the entry and its Data buffer came from a Pool, this fits in the padding after Tag
 	Data []byte
 	EVB  EVBlock `json:",omitempty"`
 }
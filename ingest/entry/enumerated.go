@@ -203,27 +203,29 @@ func (ev *EnumeratedValue) DecodeAlt(r []byte) (n int, err error) {
 
 // DecodeReader decodes an enumerated value from the io.Reader and returns the number of bytes read as well as a potential error.
 func (ev *EnumeratedValue) DecodeReader(r io.Reader) (int, error) {
+	return ev.decodeReader(r, make([]byte, evHeaderLen))
+}
+
+// decodeReader decodes using a caller provided header buffer so that block decodes can share a single buffer.
+// The name and value are read in a single pass, the value references the same allocation as the name bytes.
+func (ev *EnumeratedValue) decodeReader(r io.Reader, hdr []byte) (int, error) {
 	var h evheader
 	//read out the header
-	buff := make([]byte, evHeaderLen)
-	if err := readAll(r, buff); err != nil {
+	if err := readAll(r, hdr[:evHeaderLen]); err != nil {
 		return -1, err
-	} else if h, err = decodeHeader(buff); err != nil {
+	} else if h, err = decodeHeader(hdr); err != nil {
 		return -1, err
 	}
 
-	//read out the name
-	buff = make([]byte, h.nameLen)
+	//read out the name and data together
+	buff := make([]byte, int(h.nameLen)+int(h.dataLen))
 	if err := readAll(r, buff); err != nil {
 		return -1, err
 	}
-	ev.Name = string(buff)
+	ev.Name = string(buff[:h.nameLen])
 
 	ev.Value.evtype = h.dataType
-	ev.Value.data = make([]byte, h.dataLen)
-	if err := readAll(r, ev.Value.data); err != nil {
-		return -1, err
-	}
+	ev.Value.data = buff[h.nameLen:len(buff):len(buff)]
 	if !ev.Valid() {
 		return -1, ErrCorruptedEnumeratedValue
 	}
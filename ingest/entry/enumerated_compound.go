@@ -0,0 +1,355 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package entry
+
+import (
+	"encoding/binary"
+	"errors"
+	"net"
+	"sort"
+	"strings"
+)
+
+// Compound enumerated data types hold a set of scalar enumerated data items.
+// Lists are homogeneous and encoded as:
+//
+//	element type (uint8) | element count (uint16) | [element length (uint16) | element data]...
+//
+// Maps are keyed by string, values may be of any scalar type, and keys are encoded in ascending order:
+//
+//	entry count (uint16) | [key length (uint16) | key | value type (uint8) | value length (uint16) | value data]...
+//
+// Compound types cannot be nested and the entire encoding must fit within MaxEvDataLength.
+const (
+	listHeaderLen     = 3
+	listElemHeaderLen = 2
+	mapHeaderLen      = 2
+	mapEntryHeaderLen = 5 // key length, value type, value length
+)
+
+var (
+	ErrMixedListTypes       = errors.New("enumerated data list elements must all be the same type")
+	ErrNestedEnumeratedData = errors.New("enumerated data lists and maps cannot be nested")
+	ErrEnumeratedDataSize   = errors.New("enumerated data is too large")
+	ErrInvalidMapKey        = errors.New("invalid enumerated data map key")
+)
+
+// ListEnumData creates a list enumerated data item from a set of scalar enumerated data items.
+// All elements must be valid and of the same type.
+func ListEnumData(vals []EnumeratedData) (EnumeratedData, error) {
+	var elemType uint8
+	sz := listHeaderLen
+	for i, v := range vals {
+		if isCompoundType(v.evtype) {
+			return EnumeratedData{}, ErrNestedEnumeratedData
+		} else if !v.Valid() {
+			return EnumeratedData{}, ErrInvalidEnumeratedData
+		} else if i == 0 {
+			elemType = v.evtype
+		} else if v.evtype != elemType {
+			return EnumeratedData{}, ErrMixedListTypes
+		}
+		if sz += listElemHeaderLen + len(v.data); sz > MaxEvDataLength {
+			return EnumeratedData{}, ErrEnumeratedDataSize
+		}
+	}
+	dt := make([]byte, listHeaderLen, sz)
+	dt[0] = elemType
+	binary.LittleEndian.PutUint16(dt[1:], uint16(len(vals)))
+	for _, v := range vals {
+		dt = binary.LittleEndian.AppendUint16(dt, uint16(len(v.data)))
+		dt = append(dt, v.data...)
+	}
+	return EnumeratedData{
+		data:   dt,
+		evtype: typeList,
+	}, nil
+}
+
+// MapEnumData creates a map enumerated data item from a set of string keyed scalar enumerated data items.
+// Keys must be non-empty and no longer than MaxEvNameLength.
+func MapEnumData(vals map[string]EnumeratedData) (EnumeratedData, error) {
+	keys := make([]string, 0, len(vals))
+	sz := mapHeaderLen
+	for k, v := range vals {
+		if len(k) == 0 || len(k) > MaxEvNameLength {
+			return EnumeratedData{}, ErrInvalidMapKey
+		} else if isCompoundType(v.evtype) {
+			return EnumeratedData{}, ErrNestedEnumeratedData
+		} else if !v.Valid() {
+			return EnumeratedData{}, ErrInvalidEnumeratedData
+		}
+		if sz += mapEntryHeaderLen + len(k) + len(v.data); sz > MaxEvDataLength {
+			return EnumeratedData{}, ErrEnumeratedDataSize
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	dt := make([]byte, mapHeaderLen, sz)
+	binary.LittleEndian.PutUint16(dt, uint16(len(keys)))
+	for _, k := range keys {
+		v := vals[k]
+		dt = binary.LittleEndian.AppendUint16(dt, uint16(len(k)))
+		dt = append(dt, k...)
+		dt = append(dt, v.evtype)
+		dt = binary.LittleEndian.AppendUint16(dt, uint16(len(v.data)))
+		dt = append(dt, v.data...)
+	}
+	return EnumeratedData{
+		data:   dt,
+		evtype: typeMap,
+	}, nil
+}
+
+// List returns the elements of a list enumerated data item, the elements reference the underlying buffer.
+func (ev EnumeratedData) List() (r []EnumeratedData, err error) {
+	if ev.evtype != typeList {
+		return nil, ErrInvalidEnumeratedData
+	}
+	err = walkList(ev.data, func(v EnumeratedData) {
+		r = append(r, v)
+	})
+	return
+}
+
+// Map returns the entries of a map enumerated data item, the values reference the underlying buffer.
+func (ev EnumeratedData) Map() (r map[string]EnumeratedData, err error) {
+	if ev.evtype != typeMap {
+		return nil, ErrInvalidEnumeratedData
+	}
+	r = map[string]EnumeratedData{}
+	err = walkMap(ev.data, func(k string, v EnumeratedData) {
+		r[k] = v
+	})
+	return
+}
+
+// FlattenCompoundEVs returns the entry with its list and map enumerated values rendered as strings, for peers
+// that predate compound types.  An entry without compound values is returned as is, otherwise the result is a
+// shallow copy and the original entry is not modified.
+func (ent *Entry) FlattenCompoundEVs() *Entry {
+	var found bool
+	for _, ev := range ent.EVB.evs {
+		if found = isCompoundType(ev.Value.evtype); found {
+			break
+		}
+	}
+	if !found {
+		return ent
+	}
+	r := &Entry{
+		TS:   ent.TS,
+		SRC:  ent.SRC,
+		Tag:  ent.Tag,
+		Data: ent.Data,
+	}
+	for _, ev := range ent.EVB.evs {
+		if isCompoundType(ev.Value.evtype) {
+			s := ev.Value.String()
+			if len(s) > MaxEvDataLength {
+				s = strings.ToValidUTF8(s[:MaxEvDataLength], ``)
+			}
+			ev.Value = StringEnumData(s)
+		}
+		r.EVB.Add(ev)
+	}
+	return r
+}
+
+func isCompoundType(t uint8) bool {
+	return t == typeList || t == typeMap
+}
+
+// walkList validates a list encoding and hands each element to the callback
+func walkList(dt []byte, fn func(EnumeratedData)) error {
+	if len(dt) < listHeaderLen || len(dt) > MaxEvDataLength {
+		return ErrInvalidEnumeratedData
+	}
+	elemType := dt[0]
+	cnt := int(binary.LittleEndian.Uint16(dt[1:]))
+	if isCompoundType(elemType) || (cnt > 0 && elemType == 0) {
+		return ErrInvalidEnumeratedData
+	}
+	dt = dt[listHeaderLen:]
+	for i := 0; i < cnt; i++ {
+		if len(dt) < listElemHeaderLen {
+			return ErrInvalidEnumeratedData
+		}
+		l := int(binary.LittleEndian.Uint16(dt))
+		dt = dt[listElemHeaderLen:]
+		if l > len(dt) {
+			return ErrInvalidEnumeratedData
+		}
+		v := EnumeratedData{data: dt[:l:l], evtype: elemType}
+		if !v.Valid() {
+			return ErrInvalidEnumeratedData
+		} else if fn != nil {
+			fn(v)
+		}
+		dt = dt[l:]
+	}
+	if len(dt) != 0 {
+		return ErrInvalidEnumeratedData
+	}
+	return nil
+}
+
+// walkMap validates a map encoding and hands each entry to the callback, keys must be strictly ascending
+func walkMap(dt []byte, fn func(string, EnumeratedData)) error {
+	if len(dt) < mapHeaderLen || len(dt) > MaxEvDataLength {
+		return ErrInvalidEnumeratedData
+	}
+	cnt := int(binary.LittleEndian.Uint16(dt))
+	dt = dt[mapHeaderLen:]
+	var prev string
+	for i := 0; i < cnt; i++ {
+		if len(dt) < mapEntryHeaderLen {
+			return ErrInvalidEnumeratedData
+		}
+		kl := int(binary.LittleEndian.Uint16(dt))
+		if kl == 0 || kl > MaxEvNameLength || (kl+mapEntryHeaderLen) > len(dt) {
+			return ErrInvalidEnumeratedData
+		}
+		k := string(dt[2 : 2+kl])
+		if i > 0 && k <= prev {
+			return ErrInvalidEnumeratedData
+		}
+		prev = k
+		dt = dt[2+kl:]
+		vt := dt[0]
+		vl := int(binary.LittleEndian.Uint16(dt[1:]))
+		dt = dt[3:]
+		if isCompoundType(vt) || vl > len(dt) {
+			return ErrInvalidEnumeratedData
+		}
+		v := EnumeratedData{data: dt[:vl:vl], evtype: vt}
+		if !v.Valid() {
+			return ErrInvalidEnumeratedData
+		} else if fn != nil {
+			fn(k, v)
+		}
+		dt = dt[vl:]
+	}
+	if len(dt) != 0 {
+		return ErrInvalidEnumeratedData
+	}
+	return nil
+}
+
+// inferList converts a set of native values into a list, every value must infer to the same type
+func inferList(cnt int, get func(int) interface{}) (EnumeratedData, error) {
+	vals := make([]EnumeratedData, 0, cnt)
+	for i := 0; i < cnt; i++ {
+		v, err := InferEnumeratedData(get(i))
+		if err != nil {
+			return EnumeratedData{}, err
+		}
+		vals = append(vals, v)
+	}
+	return ListEnumData(vals)
+}
+
+// inferMap converts a set of string keyed native values into a map
+func inferMap(m map[string]interface{}) (EnumeratedData, error) {
+	vals := make(map[string]EnumeratedData, len(m))
+	for k, x := range m {
+		v, err := InferEnumeratedData(x)
+		if err != nil {
+			return EnumeratedData{}, err
+		}
+		vals[k] = v
+	}
+	return MapEnumData(vals)
+}
+
+func inferCompound(val interface{}) (EnumeratedData, bool, error) {
+	var ed EnumeratedData
+	var err error
+	switch v := val.(type) {
+	case []EnumeratedData:
+		ed, err = ListEnumData(v)
+	case map[string]EnumeratedData:
+		ed, err = MapEnumData(v)
+	case []interface{}:
+		ed, err = inferList(len(v), func(i int) interface{} { return v[i] })
+	case []string:
+		ed, err = inferList(len(v), func(i int) interface{} { return v[i] })
+	case []net.IP:
+		ed, err = inferList(len(v), func(i int) interface{} { return v[i] })
+	case []int64:
+		ed, err = inferList(len(v), func(i int) interface{} { return v[i] })
+	case []uint64:
+		ed, err = inferList(len(v), func(i int) interface{} { return v[i] })
+	case []float64:
+		ed, err = inferList(len(v), func(i int) interface{} { return v[i] })
+	case []bool:
+		ed, err = inferList(len(v), func(i int) interface{} { return v[i] })
+	case map[string]interface{}:
+		ed, err = inferMap(v)
+	case map[string]string:
+		m := make(map[string]interface{}, len(v))
+		for k, x := range v {
+			m[k] = x
+		}
+		ed, err = inferMap(m)
+	default:
+		return ed, false, nil
+	}
+	return ed, true, err
+}
+
+func compoundInterface(ev EnumeratedData) (v interface{}) {
+	switch ev.evtype {
+	case typeList:
+		r := []interface{}{}
+		walkList(ev.data, func(x EnumeratedData) {
+			r = append(r, x.Interface())
+		})
+		v = r
+	case typeMap:
+		r := map[string]interface{}{}
+		walkMap(ev.data, func(k string, x EnumeratedData) {
+			r[k] = x.Interface()
+		})
+		v = r
+	}
+	return
+}
+
+// compoundString renders lists and maps the same way the fmt package renders native slices and maps
+func compoundString(ev EnumeratedData) string {
+	var sb strings.Builder
+	switch ev.evtype {
+	case typeList:
+		sb.WriteByte('[')
+		var i int
+		walkList(ev.data, func(x EnumeratedData) {
+			if i > 0 {
+				sb.WriteByte(' ')
+			}
+			sb.WriteString(x.String())
+			i++
+		})
+		sb.WriteByte(']')
+	case typeMap:
+		sb.WriteString(`map[`)
+		var i int
+		walkMap(ev.data, func(k string, x EnumeratedData) {
+			if i > 0 {
+				sb.WriteByte(' ')
+			}
+			sb.WriteString(k)
+			sb.WriteByte(':')
+			sb.WriteString(x.String())
+			i++
+		})
+		sb.WriteByte(']')
+	}
+	return sb.String()
+}
@@ -0,0 +1,242 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package entry
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestEnumeratedCompoundCycle(t *testing.T) {
+	tests := []interface{}{
+		[]string{`foo`, `bar`, `baz`},
+		[]net.IP{net.ParseIP("192.168.1.1").To4(), net.ParseIP("10.0.0.1").To4()},
+		[]int64{1, -2, 3},
+		[]uint64{0xdeadbeef},
+		[]float64{3.14159, 1.618},
+		[]bool{true, false},
+		[]string{},
+		map[string]string{`a`: `apple`, `b`: `banana`, `c`: `cherry`},
+	}
+	for _, v := range tests {
+		ev, err := NewEnumeratedValue(`testing`, v)
+		if err != nil {
+			t.Fatalf("%T: %v", v, err)
+		} else if !ev.Valid() {
+			t.Fatalf("%T: not valid", v)
+		} else if s := fmt.Sprintf("%v", v); s != ev.Value.String() {
+			t.Fatalf("invalid string output: %q != %q", s, ev.Value.String())
+		}
+
+		var ev2 EnumeratedValue
+		if n, err := ev2.Decode(ev.Encode()); err != nil {
+			t.Fatalf("%T: %v", v, err)
+		} else if n != ev.Size() {
+			t.Fatalf("%T: bad decode size %d != %d", v, n, ev.Size())
+		} else if err = ev.Compare(ev2); err != nil {
+			t.Fatalf("%T: %v", v, err)
+		}
+	}
+}
+
+func TestEnumeratedCompoundInterface(t *testing.T) {
+	ed, err := InferEnumeratedData([]interface{}{int64(1), int64(2)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if x, ok := ed.Interface().([]interface{}); !ok {
+		t.Fatalf("bad interface type %T", ed.Interface())
+	} else if !reflect.DeepEqual(x, []interface{}{int64(1), int64(2)}) {
+		t.Fatalf("bad interface value %v", x)
+	}
+	lst, err := ed.List()
+	if err != nil {
+		t.Fatal(err)
+	} else if len(lst) != 2 || lst[1].String() != `2` {
+		t.Fatalf("bad list: %v", lst)
+	}
+
+	m := map[string]EnumeratedData{
+		`ip`:    IPEnumData(net.ParseIP("10.0.0.1").To4()),
+		`port`:  Uint16EnumData(443),
+		`label`: StringEnumData(`web`),
+	}
+	if ed, err = MapEnumData(m); err != nil {
+		t.Fatal(err)
+	}
+	mm, err := ed.Map()
+	if err != nil {
+		t.Fatal(err)
+	} else if len(mm) != len(m) {
+		t.Fatalf("bad map length %d != %d", len(mm), len(m))
+	}
+	for k, v := range m {
+		if err = (EnumeratedValue{Name: k, Value: v}).Compare(EnumeratedValue{Name: k, Value: mm[k]}); err != nil {
+			t.Fatalf("%s: %v", k, err)
+		}
+	}
+	if s := ed.String(); s != `map[ip:10.0.0.1 label:web port:443]` {
+		t.Fatalf("bad map string %q", s)
+	}
+}
+
+func TestEnumeratedCompoundErrors(t *testing.T) {
+	if _, err := InferEnumeratedData([]interface{}{`a`, int64(1)}); err != ErrMixedListTypes {
+		t.Fatalf("failed to catch mixed list: %v", err)
+	} else if _, err = InferEnumeratedData([]interface{}{[]interface{}{`a`}}); err != ErrNestedEnumeratedData {
+		t.Fatalf("failed to catch nested list: %v", err)
+	} else if _, err = InferEnumeratedData(map[string]interface{}{`a`: []string{`b`}}); err != ErrNestedEnumeratedData {
+		t.Fatalf("failed to catch nested map: %v", err)
+	} else if _, err = InferEnumeratedData(map[string]string{``: `b`}); err != ErrInvalidMapKey {
+		t.Fatalf("failed to catch empty key: %v", err)
+	} else if _, err = InferEnumeratedData([]interface{}{nil}); err != ErrUnknownType {
+		t.Fatalf("failed to catch unknown element: %v", err)
+	}
+
+	//build lists right up against the size limit
+	elem := strings.Repeat(`A`, 1024)
+	cnt := (MaxEvDataLength - listHeaderLen) / (listElemHeaderLen + len(elem))
+	big := make([]string, cnt)
+	for i := range big {
+		big[i] = elem
+	}
+	if ed, err := InferEnumeratedData(big); err != nil {
+		t.Fatalf("failed to build maximum list: %v", err)
+	} else if !ed.Valid() {
+		t.Fatal("maximum list is not valid")
+	} else if _, err = InferEnumeratedData(append(big, elem)); err != ErrEnumeratedDataSize {
+		t.Fatalf("failed to catch oversized list: %v", err)
+	}
+}
+
+func TestEnumeratedCompoundJSON(t *testing.T) {
+	ent := Entry{TS: Now(), Data: []byte(`hello`)}
+	ent.AddEnumeratedValueEx(`ips`, []net.IP{net.ParseIP("1.1.1.1").To4(), net.ParseIP("8.8.8.8").To4()})
+	ent.AddEnumeratedValueEx(`labels`, map[string]string{`env`: `prod`, `team`: `net`})
+	bts, err := json.Marshal(ent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var nent Entry
+	if err = json.Unmarshal(bts, &nent); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{`ips`, `labels`} {
+		a, _ := ent.EVB.Get(name)
+		b, ok := nent.EVB.Get(name)
+		if !ok {
+			t.Fatalf("missing %s after JSON decode", name)
+		} else if a.Value.String() != b.Value.String() {
+			t.Fatalf("%s: %q != %q", name, a.Value.String(), b.Value.String())
+		}
+	}
+}
+
+func TestEnumeratedCompoundBlock(t *testing.T) {
+	var evb EVBlock
+	evb.Add(EnumeratedValue{Name: `ints`, Value: mustInfer(t, []int64{1, 2, 3})})
+	evb.Add(EnumeratedValue{Name: `map`, Value: mustInfer(t, map[string]interface{}{`x`: float64(1), `y`: `z`})})
+	evb.Add(EnumeratedValue{Name: `empty`, Value: mustInfer(t, []interface{}{})})
+	buff, err := evb.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var evb2 EVBlock
+	if _, err = evb2.Decode(buff); err != nil {
+		t.Fatal(err)
+	} else if err = evb.Compare(evb2); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFlattenCompoundEVs(t *testing.T) {
+	ent := &Entry{Tag: 1, Data: []byte(`testing`)}
+	if ent.FlattenCompoundEVs() != ent {
+		t.Fatal("entry without EVs was copied")
+	}
+	ent.AddEnumeratedValue(EnumeratedValue{Name: `int`, Value: IntEnumData(5)})
+	if ent.FlattenCompoundEVs() != ent {
+		t.Fatal("entry without compound EVs was copied")
+	}
+	list := mustInfer(t, []int64{1, 2, 3})
+	ent.AddEnumeratedValue(EnumeratedValue{Name: `list`, Value: list})
+	//IPs encode far smaller than they render, so this one only overflows once flattened
+	ips := make([]net.IP, 8000)
+	for i := range ips {
+		ips[i] = net.IPv4(255, 255, 255, byte(i)).To4()
+	}
+	ent.AddEnumeratedValue(EnumeratedValue{Name: `big`, Value: mustInfer(t, ips)})
+
+	fent := ent.FlattenCompoundEVs()
+	if fent == ent {
+		t.Fatal("entry with compound EVs was not copied")
+	} else if fent.Tag != ent.Tag || string(fent.Data) != string(ent.Data) {
+		t.Fatal("flattened entry lost its data")
+	}
+	evs := fent.EVB.Values()
+	if len(evs) != 3 {
+		t.Fatalf("bad EV count %d", len(evs))
+	} else if evs[0].Value.Interface() != int64(5) {
+		t.Fatalf("scalar EV was modified: %v", evs[0].Value)
+	} else if evs[1].Value.Interface() != list.String() {
+		t.Fatalf("list EV not flattened: %v", evs[1].Value)
+	} else if s, ok := evs[2].Value.Interface().(string); !ok || len(s) != MaxEvDataLength {
+		t.Fatalf("oversized EV not truncated: %T %d", evs[2].Value.Interface(), len(s))
+	} else if err := fent.EVB.Valid(); err != nil {
+		t.Fatal("flattened block is invalid")
+	}
+	//the original is untouched
+	if v := ent.EVB.Values()[1].Value; v.String() != list.String() || !isCompoundType(v.evtype) {
+		t.Fatalf("original entry was modified: %v", v)
+	}
+}
+
+func mustInfer(t *testing.T, v interface{}) EnumeratedData {
+	ed, err := InferEnumeratedData(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ed
+}
+
+func FuzzEVCompound(f *testing.F) {
+	for _, v := range []interface{}{
+		[]string{`foo`, `bar`},
+		[]net.IP{net.ParseIP("192.168.1.1"), net.ParseIP("fe80::1")},
+		[]float64{1, 2, 3},
+		map[string]interface{}{`a`: `b`, `c`: int64(4)},
+		map[string]string{},
+	} {
+		if err := addFuzzSample(f, v); err != nil {
+			f.Fatal(err)
+		}
+	}
+	f.Fuzz(func(t *testing.T, buff []byte) {
+		var ev EnumeratedValue
+		if _, err := ev.Decode(buff); err != nil {
+			return
+		}
+		//anything that decodes must render and survive a re-encode
+		_ = ev.Value.String()
+		_ = ev.Value.Interface()
+		if len(ev.Value.data) > MaxEvDataLength {
+			t.Fatalf("decoded oversized data %d", len(ev.Value.data))
+		}
+		var ev2 EnumeratedValue
+		if _, err := ev2.Decode(ev.Encode()); err != nil {
+			t.Fatal(err)
+		} else if err = ev.Compare(ev2); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
@@ -428,3 +428,46 @@ func encodeEv(a interface{}) (buff []byte, err error) {
 	}
 	return
 }
+
+func TestEnumeratedCoerce(t *testing.T) {
+	tests := []struct {
+		in  EnumeratedData
+		typ string
+		out string
+		ok  bool
+	}{
+		{StringEnumData(`10.0.0.1`), `ip`, `10.0.0.1`, true},
+		{StringEnumData(`443`), `uint16`, `443`, true},
+		{StringEnumData(`70000`), `uint16`, ``, false},
+		{Int64EnumData(-5), `int8`, `-5`, true},
+		{Uint64EnumData(7), `float64`, `7`, true},
+		{StringEnumData(`1m30s`), `duration`, `1m30s`, true},
+		{StringEnumData(`true`), `bool`, `true`, true},
+		{IPEnumData(net.ParseIP("192.168.1.1").To4()), `string`, `192.168.1.1`, true},
+		{StringEnumData(`not an ip`), `ip`, ``, false},
+	}
+	for _, tt := range tests {
+		id, err := ParseEVType(tt.typ)
+		if err != nil {
+			t.Fatal(err)
+		} else if EVTypeName(id) != tt.typ {
+			t.Fatalf("bad type name %q != %q", EVTypeName(id), tt.typ)
+		}
+		r, err := tt.in.Coerce(id)
+		if !tt.ok {
+			if err == nil {
+				t.Fatalf("coerced %v to %s", tt.in, tt.typ)
+			}
+			continue
+		} else if err != nil {
+			t.Fatalf("failed to coerce %v to %s: %v", tt.in, tt.typ, err)
+		} else if !r.Valid() || r.evtype != id {
+			t.Fatalf("bad coerced value %v", r)
+		} else if r.String() != tt.out {
+			t.Fatalf("bad coerced string %q != %q", r.String(), tt.out)
+		}
+	}
+	if _, err := ParseEVType(`bogus`); err != ErrUnknownType {
+		t.Fatalf("bad error on unknown type: %v", err)
+	}
+}
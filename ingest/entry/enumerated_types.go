@@ -16,6 +16,7 @@ import (
 	"math"
 	"net"
 	"strconv"
+	"strings"
 	"time"
 	"unicode/utf8"
 )
@@ -39,13 +40,54 @@ const (
 	typeIP        uint8 = 15 // Proper net.IP
 	typeTS        uint8 = 16 // Time
 	typeDuration  uint8 = 17 // in and out as a time.Duration, but is an int64 internally
+	typeList      uint8 = 18 // homogeneous list of any of the scalar types above
+	typeMap       uint8 = 19 // string keyed map of any of the scalar types above
 )
 
 var (
 	ErrUnknownType           = errors.New("unknown native type")
 	ErrInvalidEnumeratedData = errors.New("invalid enumerated data type or data package")
+	ErrCannotCoerce          = errors.New("enumerated data cannot be coerced to the requested type")
+
+	evTypeNames = map[uint8]string{
+		typeByteSlice: `bytes`,
+		typeBool:      `bool`,
+		typeByte:      `byte`,
+		typeInt8:      `int8`,
+		typeInt16:     `int16`,
+		typeUint16:    `uint16`,
+		typeInt32:     `int32`,
+		typeUint32:    `uint32`,
+		typeInt64:     `int64`,
+		typeUint64:    `uint64`,
+		typeFloat32:   `float32`,
+		typeFloat64:   `float64`,
+		typeUnicode:   `string`,
+		typeMAC:       `mac`,
+		typeIP:        `ip`,
+		typeTS:        `timestamp`,
+		typeDuration:  `duration`,
+		typeList:      `list`,
+		typeMap:       `map`,
+	}
 )
 
+// EVTypeName returns the human friendly name of an enumerated data type ID, unknown types return an empty string.
+func EVTypeName(id uint8) string {
+	return evTypeNames[id]
+}
+
+// ParseEVType converts a type name as returned by EVTypeName back into a type ID, names are case insensitive.
+func ParseEVType(name string) (uint8, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for k, v := range evTypeNames {
+		if v == name {
+			return k, nil
+		}
+	}
+	return 0, ErrUnknownType
+}
+
 type EnumeratedData struct {
 	data   []byte
 	evtype uint8 //you don't get access to this, sorry
@@ -124,6 +166,9 @@ func InferEnumeratedData(val interface{}) (EnumeratedData, error) {
 	case time.Duration:
 		return DurationEnumData(v), nil
 	}
+	if ed, ok, err := inferCompound(val); ok {
+		return ed, err
+	}
 
 	//unknown type
 	return EnumeratedData{}, ErrUnknownType
@@ -408,6 +453,8 @@ func (ev EnumeratedData) Interface() (v interface{}) {
 		var ts Timestamp
 		ts.UnmarshalBinary(ev.data)
 		v = ts
+	case typeList, typeMap:
+		v = compoundInterface(ev)
 	}
 	return
 }
@@ -481,6 +528,8 @@ func (ev EnumeratedData) String() string {
 			d = time.Duration(binary.LittleEndian.Uint64(ev.data))
 		}
 		return d.String()
+	case typeList, typeMap:
+		return compoundString(ev)
 	}
 	return `` //return empty string on default
 }
@@ -547,6 +596,10 @@ func (ev EnumeratedData) Valid() bool {
 			return true
 		}
 		return false
+	case typeList:
+		return walkList(ev.data, nil) == nil
+	case typeMap:
+		return walkMap(ev.data, nil) == nil
 	}
 	return false //bad type
 }
@@ -567,3 +620,105 @@ func (ev *EnumeratedData) UnmarshalJSON(v []byte) error {
 	*ev = nev
 	return nil
 }
+
+// Coerce attempts to convert the enumerated data to the given type ID by re-parsing its string representation.
+// Coercing to the current type is a no-op, lists and maps cannot be coerced.
+func (ev EnumeratedData) Coerce(id uint8) (r EnumeratedData, err error) {
+	if ev.evtype == id {
+		return ev, nil
+	} else if isCompoundType(id) || isCompoundType(ev.evtype) {
+		return r, ErrCannotCoerce
+	}
+	s := ev.String()
+	switch id {
+	case typeByteSlice:
+		return SliceEnumData([]byte(s)), nil
+	case typeUnicode:
+		return StringEnumData(s), nil
+	case typeBool:
+		var v bool
+		if v, err = strconv.ParseBool(s); err == nil {
+			r = BoolEnumData(v)
+		}
+	case typeByte, typeUint16, typeUint32, typeUint64:
+		var v uint64
+		if v, err = strconv.ParseUint(s, 10, 8*fixedSize(id)); err == nil {
+			switch id {
+			case typeByte:
+				r = ByteEnumData(byte(v))
+			case typeUint16:
+				r = Uint16EnumData(uint16(v))
+			case typeUint32:
+				r = Uint32EnumData(uint32(v))
+			default:
+				r = Uint64EnumData(v)
+			}
+		}
+	case typeInt8, typeInt16, typeInt32, typeInt64:
+		var v int64
+		if v, err = strconv.ParseInt(s, 10, 8*fixedSize(id)); err == nil {
+			switch id {
+			case typeInt8:
+				r = Int8EnumData(int8(v))
+			case typeInt16:
+				r = Int16EnumData(int16(v))
+			case typeInt32:
+				r = Int32EnumData(int32(v))
+			default:
+				r = Int64EnumData(v)
+			}
+		}
+	case typeFloat32:
+		var v float64
+		if v, err = strconv.ParseFloat(s, 32); err == nil {
+			r = Float32EnumData(float32(v))
+		}
+	case typeFloat64:
+		var v float64
+		if v, err = strconv.ParseFloat(s, 64); err == nil {
+			r = Float64EnumData(v)
+		}
+	case typeMAC:
+		var v net.HardwareAddr
+		if v, err = net.ParseMAC(s); err == nil {
+			r = MACEnumData(v)
+		}
+	case typeIP:
+		if v := net.ParseIP(s); v == nil {
+			err = ErrCannotCoerce
+		} else if v4 := v.To4(); v4 != nil {
+			r = IPEnumData(v4)
+		} else {
+			r = IPEnumData(v)
+		}
+	case typeTS:
+		var v time.Time
+		if v, err = time.Parse(time.RFC3339Nano, s); err == nil {
+			r = TSEnumData(FromStandard(v))
+		}
+	case typeDuration:
+		var v time.Duration
+		if v, err = time.ParseDuration(s); err == nil {
+			r = DurationEnumData(v)
+		}
+	default:
+		err = ErrUnknownType
+	}
+	if err != nil && err != ErrUnknownType {
+		err = ErrCannotCoerce
+	}
+	return
+}
+
+// fixedSize returns the encoded size of the fixed width integer types
+func fixedSize(id uint8) int {
+	switch id {
+	case typeByte, typeInt8:
+		return 1
+	case typeInt16, typeUint16:
+		return 2
+	case typeInt32, typeUint32:
+		return 4
+	}
+	return 8
+}
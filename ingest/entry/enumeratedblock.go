@@ -77,6 +77,22 @@ func (eb *EVBlock) updateEv(ev EnumeratedValue) {
 	eb.evs = append(eb.evs, ev)
 }
 
+// Delete removes the named enumerated value from the block, returning whether it was present.
+func (eb *EVBlock) Delete(name string) bool {
+	for i, x := range eb.evs {
+		if x.Name == name {
+			eb.evs = append(eb.evs[:i], eb.evs[i+1:]...)
+			if len(eb.evs) == 0 {
+				eb.size = 0
+			} else {
+				eb.size -= uint64(x.Size())
+			}
+			return true
+		}
+	}
+	return false
+}
+
 // AddSet adds a slice of enumerated value to an evbloc, this function keeps a running tally of size for fast query.
 func (eb *EVBlock) AddSet(evs []EnumeratedValue) {
 	if len(evs) == 0 {
@@ -410,7 +426,7 @@ func (eb *EVBlock) DecodeReader(r io.Reader) (int, error) {
 	for i := uint16(0); i < h.Count; i++ {
 		var ev EnumeratedValue
 		var n int
-		if n, err = ev.DecodeReader(r); err != nil {
+		if n, err = ev.decodeReader(r, buff); err != nil {
 			return -1, err
 		}
 		total += n
@@ -154,3 +154,29 @@ func TestEnumeratedValueBlockEmptyAdd(t *testing.T) {
 	}
 
 }
+
+func TestEnumeratedValueBlockDelete(t *testing.T) {
+	var evb EVBlock
+	evb.Add(EnumeratedValue{Name: `a`, Value: StringEnumData(`foo`)})
+	evb.Add(EnumeratedValue{Name: `b`, Value: Uint64EnumData(99)})
+	sz := evb.Size()
+	if evb.Delete(`c`) {
+		t.Fatal("deleted a missing value")
+	} else if !evb.Delete(`a`) {
+		t.Fatal("failed to delete value")
+	} else if evb.Count() != 1 {
+		t.Fatalf("bad count %d", evb.Count())
+	} else if evb.Size() >= sz {
+		t.Fatalf("size not reduced: %d >= %d", evb.Size(), sz)
+	} else if err := evb.Valid(); err != nil {
+		t.Fatal(err)
+	}
+	if buff, err := evb.Encode(); err != nil {
+		t.Fatal(err)
+	} else if uint64(len(buff)) != evb.Size() {
+		t.Fatalf("bad size after delete %d != %d", len(buff), evb.Size())
+	}
+	if !evb.Delete(`b`) || evb.Size() != 0 || evb.Populated() {
+		t.Fatal("failed to empty block")
+	}
+}
@@ -0,0 +1,106 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package entry
+
+import (
+	"math/bits"
+	"sync"
+)
+
+const (
+	minPoolClass = 6  // 64 byte buffers
+	maxPoolClass = 20 // 1MB buffers
+	poolClasses  = maxPoolClass - minPoolClass + 1
+
+	// MaxPooledDataSize is the largest Data buffer a Pool will hand out, larger requests are allocated directly
+	MaxPooledDataSize = 1 << maxPoolClass
+)
+
+// Pool recycles entries along with their Data buffers.  Buffers are grouped into power of two size classes
+// from 64B to 1MB so that a recycled entry can be handed back out for any Data size in its class.
+//
+// Ownership of a pooled entry moves with the entry, whoever holds the entry last is responsible for calling Put.
+// Once an entry has been handed to Put neither the entry, its Data, nor its enumerated values may be referenced again.
+// Only entries obtained from a Pool are recycled, Put silently ignores all other entries so it is always safe to
+// hand an entry back regardless of where it came from.
+//
+// A nil Pool is valid, it allocates on every Get and discards on every Put.
+type Pool struct {
+	classes [poolClasses]sync.Pool
+}
+
+// NewPool creates a new entry pool
+func NewPool() *Pool {
+	p := &Pool{}
+	for i := range p.classes {
+		sz := 1 << (minPoolClass + i)
+		p.classes[i].New = func() interface{} {
+			return &Entry{Data: make([]byte, 0, sz)}
+		}
+	}
+	return p
+}
+
+// Get returns an empty entry with a Data buffer of length sz.
+// The contents of the Data buffer are undefined, callers must overwrite all of it.
+func (p *Pool) Get(sz int) *Entry {
+	if sz < 0 {
+		sz = 0
+	}
+	if p == nil || sz > MaxPooledDataSize {
+		return &Entry{Data: make([]byte, sz)}
+	}
+	class := poolClass(sz)
+	ent := p.classes[class].Get().(*Entry)
+	ent.pool = uint8(class + 1)
+	ent.Data = ent.Data[:sz]
+	return ent
+}
+
+// GetCopy returns an entry whose Data is a copy of b
+func (p *Pool) GetCopy(b []byte) *Entry {
+	ent := p.Get(len(b))
+	copy(ent.Data, b)
+	return ent
+}
+
+// Put returns an entry and its Data buffer to the pool, entries that did not come from a Pool are ignored.
+// If the Data buffer was swapped out or resliced from the front the buffer is discarded.
+func (p *Pool) Put(ent *Entry) {
+	if p == nil || ent == nil || ent.pool == 0 {
+		return
+	}
+	class := int(ent.pool - 1)
+	ent.pool = 0 //guard against double puts
+	if class >= poolClasses || cap(ent.Data) != 1<<(minPoolClass+class) {
+		return
+	}
+	evs := ent.EVB.evs
+	clear(evs) //drop references to the value buffers
+	*ent = Entry{
+		Data: ent.Data[:0],
+		EVB:  EVBlock{evs: evs[:0]},
+	}
+	p.classes[class].Put(ent)
+}
+
+// PutBatch returns a set of entries to the pool
+func (p *Pool) PutBatch(ents []*Entry) {
+	for _, ent := range ents {
+		p.Put(ent)
+	}
+}
+
+// poolClass returns the index of the smallest class that can hold sz bytes
+func poolClass(sz int) int {
+	if sz <= 1<<minPoolClass {
+		return 0
+	}
+	return bits.Len(uint(sz-1)) - minPoolClass
+}
@@ -0,0 +1,121 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package entry
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestPoolClass(t *testing.T) {
+	tests := []struct {
+		sz    int
+		class int
+	}{
+		{0, 0}, {1, 0}, {64, 0}, {65, 1}, {128, 1}, {129, 2},
+		{4096, 6}, {4097, 7}, {MaxPooledDataSize, poolClasses - 1},
+	}
+	for _, tc := range tests {
+		if c := poolClass(tc.sz); c != tc.class {
+			t.Fatalf("bad class for %d: %d != %d", tc.sz, c, tc.class)
+		}
+	}
+}
+
+func TestPoolGetPut(t *testing.T) {
+	p := NewPool()
+	for _, sz := range []int{0, 1, 100, 4000, 65536, MaxPooledDataSize} {
+		ent := p.Get(sz)
+		if len(ent.Data) != sz {
+			t.Fatalf("bad data length %d != %d", len(ent.Data), sz)
+		} else if cap(ent.Data) != 1<<(minPoolClass+poolClass(sz)) {
+			t.Fatalf("bad data capacity for %d: %d", sz, cap(ent.Data))
+		}
+		ent.TS = Now()
+		ent.Tag = 5
+		if err := ent.AddEnumeratedValueEx(`foo`, sz); err != nil {
+			t.Fatal(err)
+		}
+		p.Put(ent)
+		if len(ent.Data) != 0 || ent.Tag != 0 || ent.EVB.Populated() || ent.pool != 0 {
+			t.Fatalf("entry was not reset: %+v", ent)
+		}
+		//double puts are ignored
+		p.Put(ent)
+	}
+
+	//oversized entries are not pooled
+	ent := p.Get(MaxPooledDataSize + 1)
+	if ent.pool != 0 || len(ent.Data) != MaxPooledDataSize+1 {
+		t.Fatalf("bad oversized entry: %d %d", ent.pool, len(ent.Data))
+	}
+
+	//copies are copies
+	b := []byte("hello world")
+	if ent = p.GetCopy(b); !bytes.Equal(ent.Data, b) {
+		t.Fatalf("bad copy %q", ent.Data)
+	}
+	b[0] = 'j'
+	if ent.Data[0] != 'h' {
+		t.Fatal("copy shares the source buffer")
+	}
+
+	//entries with swapped out buffers are dropped rather than recycled
+	ent.Data = b
+	p.Put(ent)
+	if !bytes.Equal(b, []byte("jello world")) || len(ent.Data) != len(b) {
+		t.Fatal("swapped buffer was recycled")
+	}
+
+	//foreign entries are ignored
+	ent = &Entry{Data: []byte("foo")}
+	p.Put(ent)
+	if string(ent.Data) != "foo" {
+		t.Fatal("foreign entry was recycled")
+	}
+}
+
+func TestNilPool(t *testing.T) {
+	var p *Pool
+	ent := p.GetCopy([]byte("foo"))
+	if string(ent.Data) != "foo" || ent.pool != 0 {
+		t.Fatalf("bad entry from nil pool: %q %d", ent.Data, ent.pool)
+	}
+	p.Put(ent)
+	p.PutBatch([]*Entry{ent, nil})
+	if string(ent.Data) != "foo" {
+		t.Fatal("nil pool modified an entry")
+	}
+
+	//entries from a real pool are left alone by a nil pool
+	ent = NewPool().Get(10)
+	p.Put(ent)
+	if len(ent.Data) != 10 || ent.pool == 0 {
+		t.Fatal("nil pool modified a pooled entry")
+	}
+}
+
+func BenchmarkPool(b *testing.B) {
+	data := bytes.Repeat([]byte("x"), 300)
+	b.Run("nopool", func(b *testing.B) {
+		benchmarkPool(b, nil, data)
+	})
+	b.Run("pool", func(b *testing.B) {
+		benchmarkPool(b, NewPool(), data)
+	})
+}
+
+func benchmarkPool(b *testing.B, p *Pool, data []byte) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		ent := p.GetCopy(data)
+		ent.TS = Now()
+		p.Put(ent)
+	}
+}
@@ -41,6 +41,7 @@ type entryConfBuffer struct {
 	capacity int
 	head     int
 	count    int
+	pool     *entry.Pool // confirmed entries are handed back to the pool, nil is fine
 }
 
 func newEntryConfirmationBuffer(unconfirmedBufferSize int) (entryConfBuffer, error) {
@@ -143,7 +144,10 @@ func (ecb *entryConfBuffer) Confirm(id entrySendID) error {
 	if ec.EntryID != id {
 		return ecb.popUnalligned(id)
 	}
-	_, err := ecb.popHead()
+	ent, err := ecb.popHead()
+	if err == nil {
+		ecb.pool.Put(ent)
+	}
 	return err
 }
 
@@ -189,7 +193,10 @@ func (ecb *entryConfBuffer) popUnalligned(id entrySendID) error {
 	var curr, next int
 	//simple sanity check in case we are popping the head
 	if ecb.buff[ecb.head] != nil && ecb.buff[ecb.head].EntryID == id {
-		_, err := ecb.popHead()
+		ent, err := ecb.popHead()
+		if err == nil {
+			ecb.pool.Put(ent)
+		}
 		return err
 	}
 	//not the head, so go do the hard work
@@ -203,6 +210,7 @@ func (ecb *entryConfBuffer) popUnalligned(id entrySendID) error {
 		//found the ID, so remove it and shift forward
 		//if this hits we ARE going to return
 		if ecb.buff[i].EntryID == id {
+			ecb.pool.Put(ecb.buff[i].Ent)
 			//remove the ID from the list
 			for ; i < ecb.count; i++ {
 				if i == ecb.capacity {
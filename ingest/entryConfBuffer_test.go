@@ -102,3 +102,40 @@ func TestMixedUnorderedPushPop(t *testing.T) {
 		}
 	}
 }
+
+func TestPooledConfirm(t *testing.T) {
+	entcb, err := newEntryConfirmationBuffer(DEFAULT_MAX_UNCONFIRMED)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entcb.pool = entry.NewPool()
+	var ents []*entry.Entry
+	for i := entrySendID(1); i <= entrySendID(8); i++ {
+		ent := entcb.pool.GetCopy([]byte("hello"))
+		if err = entcb.Add(&entryConfirmation{i, ent}); err != nil {
+			t.Fatal(err)
+		}
+		ents = append(ents, ent)
+	}
+	//ejected entries are resent so they must not be handed back to the pool
+	if ejected := entcb.ejectAll(); len(ejected) != len(ents) {
+		t.Fatalf("bad eject count: %d != %d", len(ejected), len(ents))
+	}
+	for i, ent := range ents {
+		if string(ent.Data) != "hello" {
+			t.Fatalf("ejected entry %d was recycled", i)
+		}
+		if err = entcb.Add(&entryConfirmation{entrySendID(i + 1), ent}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	//confirm from the head and out of order, every entry should land back in the pool
+	for _, id := range []entrySendID{1, 3, 2, 4, 5, 6, 7, 8} {
+		if err = entcb.Confirm(id); err != nil {
+			t.Fatal(err)
+		}
+		if ent := ents[id-1]; len(ent.Data) != 0 {
+			t.Fatalf("entry %d was not returned to the pool", id)
+		}
+	}
+}
@@ -49,6 +49,9 @@ var (
 	errFailedToReadCommand = errors.New("Failed to read command")
 	ErrOversizedEntry      = errors.New("Entry data exceeds maximum size")
 	ErrPendingDittoBlock   = errors.New("Received ditto block")
+	ErrOversizedBlock      = errors.New("Column block exceeds maximum size")
+	errPendingColumnBlock  = errors.New("Received column block")
+	ErrColumnBlockInDitto  = errors.New("Received column block inside a ditto block")
 
 	ackBatchReadTimerDuration = 10 * time.Millisecond
 	defaultReaderTimeout      = 10 * time.Minute
@@ -93,6 +96,9 @@ type EntryReader struct {
 	igState           IngesterState           // the most recent state message received
 	stateCallbacks    []IngesterStateCallback // functions to be called when an IngesterState message is received
 	pendingDittoBlock []*entry.Entry
+	pendingColumn     []*entry.Entry // entries decoded from a column block that have not been read
+	pendingColumnID   entrySendID    // send ID of the first pending column entry
+	pool              *entry.Pool    // optional, entries handed out by Read are drawn from the pool
 }
 
 func NewEntryReader(conn net.Conn) (*EntryReader, error) {
@@ -125,6 +131,7 @@ func NewEntryReaderEx(cfg EntryReaderWriterConfig) (*EntryReader, error) {
 		timeout:    cfg.Timeout,
 		tagMan:     cfg.TagMan,
 		igStateMtx: &sync.Mutex{},
+		pool:       cfg.Pool,
 	}, nil
 }
 
@@ -309,6 +316,8 @@ func (er *EntryReader) Close() error {
 	return nil
 }
 
+// Read hands back the next entry, the caller owns the entry.
+// If the reader was configured with a Pool the entry may be returned to it with Pool.Put once the caller is done.
 func (er *EntryReader) Read() (e *entry.Entry, err error) {
 	er.mtx.Lock()
 	if e, err = er.read(); err == nil {
@@ -345,12 +354,18 @@ func (er *EntryReader) read() (*entry.Entry, error) {
 		id     entrySendID
 		hasEvs bool
 	)
-	ent := &entry.Entry{}
+	if len(er.pendingColumn) > 0 {
+		return er.popColumnEntry()
+	}
+	var hdr entry.Entry
 
-	if err = er.fillHeader(ent, &id, &sz, &hasEvs); err != nil {
+	if err = er.fillHeader(&hdr, &id, &sz, &hasEvs); err == errPendingColumnBlock {
+		return er.popColumnEntry()
+	} else if err != nil {
 		return nil, err
 	}
-	ent.Data = make([]byte, sz)
+	ent := er.pool.Get(int(sz))
+	ent.TS, ent.SRC, ent.Tag = hdr.TS, hdr.SRC, hdr.Tag
 	if _, err = io.ReadFull(er.bIO, ent.Data); err != nil {
 		return nil, err
 	} else if hasEvs {
@@ -364,6 +379,46 @@ func (er *EntryReader) read() (*entry.Entry, error) {
 	return ent, nil
 }
 
+// popColumnEntry hands back the next entry from a decoded column block and acks it
+func (er *EntryReader) popColumnEntry() (*entry.Entry, error) {
+	ent := er.pendingColumn[0]
+	er.pendingColumn[0] = nil
+	er.pendingColumn = er.pendingColumn[1:]
+	id := er.pendingColumnID
+	er.pendingColumnID++
+	if err := er.throwAck(id); err != nil {
+		return nil, err
+	}
+	return ent, nil
+}
+
+// readColumnBlock reads and decodes a column block, the entries are queued up
+// and handed out by subsequent reads
+func (er *EntryReader) readColumnBlock() error {
+	n, err := io.ReadFull(er.bIO, er.buff[0:12])
+	if err != nil {
+		return err
+	} else if n < 12 {
+		return errFailedFullRead
+	}
+	id := entrySendID(binary.LittleEndian.Uint64(er.buff[0:8]))
+	length := binary.LittleEndian.Uint32(er.buff[8:12])
+	if int(length) > maxColumnBlockWireSize {
+		return ErrOversizedBlock
+	}
+	buff := make([]byte, length)
+	if _, err = io.ReadFull(er.bIO, buff); err != nil {
+		return err
+	}
+	var cb entry.ColumnBlock
+	if err = cb.Decode(buff); err != nil {
+		return err
+	}
+	er.pendingColumn = cb.Entries()
+	er.pendingColumnID = id
+	return nil
+}
+
 func (er *EntryReader) readNoAck() (*entry.Entry, error) {
 	var (
 		err    error
@@ -373,7 +428,12 @@ func (er *EntryReader) readNoAck() (*entry.Entry, error) {
 	)
 	ent := &entry.Entry{}
 
-	if err = er.fillHeader(ent, &id, &sz, &hasEvs); err != nil {
+	if err = er.fillHeader(ent, &id, &sz, &hasEvs); err == errPendingColumnBlock {
+		// ditto blocks are only ever made up of individual entries, a column block
+		// here means the stream is desynced so drop it and bail on the connection
+		er.pendingColumn = nil
+		return nil, ErrColumnBlockInDitto
+	} else if err != nil {
 		return nil, err
 	}
 	ent.Data = make([]byte, sz)
@@ -415,6 +475,11 @@ headerLoop:
 			}
 		case NEW_ENTRY_MAGIC:
 			break headerLoop
+		case COLUMN_BLOCK_MAGIC:
+			if err := er.readColumnBlock(); err != nil {
+				return err
+			}
+			return errPendingColumnBlock
 		case TAG_MAGIC:
 			// read length of string
 			n, err = io.ReadFull(er.bIO, er.buff[0:4])
@@ -43,6 +43,13 @@ const (
 	MINIMUM_INGEST_STATE_VERSION    uint16 = 0x6 // minimum server version to send detailed ingester state messages
 	MINIMUM_INGEST_EV_VERSION       uint16 = 0x8 // minimum server version to send enumerated values attached to entries
 	MINIMUM_DITTO_VERSION           uint16 = 0x9 // minimum server version to send ditto blocks
+	MINIMUM_COLUMN_BLOCK_VERSION    uint16 = 0xA // minimum server version to send columnar entry blocks
+	MINIMUM_COMPOUND_EV_VERSION     uint16 = 0xB // minimum server version to send list and map enumerated values
+
+	//maximum number of data and EV bytes packed into a single column block
+	MAX_COLUMN_BLOCK_PAYLOAD int = 4 * 1024 * 1024
+	//the per entry columns can never exceed the payload limit, so twice the payload is a hard cap
+	maxColumnBlockWireSize int = 2 * MAX_COLUMN_BLOCK_PAYLOAD
 
 	maxThrottleDur time.Duration = 5 * time.Second
 
@@ -72,6 +79,7 @@ const (
 	INGESTER_STATE_MAGIC         IngestCommand = 0x44556600
 	CONFIRM_INGESTER_STATE_MAGIC IngestCommand = 0x44556601
 	CONFIRM_DITTO_BLOCK_MAGIC    IngestCommand = 0x55667788
+	COLUMN_BLOCK_MAGIC           IngestCommand = 0xC7C95ACC
 )
 
 type IngestCommand uint32
@@ -96,6 +104,7 @@ type EntryWriter struct {
 	ackTimeout    time.Duration
 	serverVersion uint16
 	ctx           context.Context
+	colBuff       []byte
 }
 
 func NewEntryWriter(conn net.Conn) (*EntryWriter, error) {
@@ -128,6 +137,8 @@ type EntryReaderWriterConfig struct {
 	Timeout               time.Duration
 	TagMan                TagManager
 	CTX                   context.Context
+	// Pool is optional, readers draw entries from it and writers return pooled entries to it once they are confirmed
+	Pool *entry.Pool
 }
 
 func NewEntryWriterEx(cfg EntryReaderWriterConfig) (*EntryWriter, error) {
@@ -139,6 +150,7 @@ func NewEntryWriterEx(cfg EntryReaderWriterConfig) (*EntryWriter, error) {
 	if cfg.CTX == nil {
 		cfg.CTX = context.Background()
 	}
+	ecb.pool = cfg.Pool
 
 	return &EntryWriter{
 		conn:       utc,
@@ -168,6 +180,13 @@ type connWrapper func(conn) conn
 
 // wrapConn passes in a function that can wrap a reader/writer
 // when called we reset the write buffer, caller should make sure there isn't anything buffered
+// setPool sets the pool that confirmed entries are returned to
+func (ew *EntryWriter) setPool(p *entry.Pool) {
+	ew.mtx.Lock()
+	ew.ecb.pool = p
+	ew.mtx.Unlock()
+}
+
 func (ew *EntryWriter) setConn(c conn) {
 	ew.mtx.Lock()
 	ew.conn = c
@@ -294,6 +313,8 @@ func (ew *EntryWriter) forceAckNoLock(ctx context.Context) error {
 // fails to confirm.  If a buffer is re-used and the entry fails
 // to confirm we will send the new modified buffer which may not
 // have the original data.
+// Once the entry is confirmed it is returned to the configured Pool,
+// entries that did not come from a Pool are left to the garbage collector.
 func (ew *EntryWriter) Write(ent *entry.Entry) error {
 	return ew.writeFlush(ent, false)
 }
@@ -357,13 +378,20 @@ func (ew *EntryWriter) WriteWithHint(ent *entry.Entry) (bool, error) {
 
 // WriteBatch takes a slice of entries and writes them,
 // this function is useful in multithreaded environments where
-// we want to lessen the impact of hits on a channel by threads
+// we want to lessen the impact of hits on a channel by threads.
+// If the server supports it the batch is sent as columnar blocks.
+// Ownership of each entry follows the same rules as Write, the
+// slice itself may be reused by the caller once WriteBatch returns.
 func (ew *EntryWriter) WriteBatch(ents [](*entry.Entry)) (int, error) {
 	var err error
 
 	ew.mtx.Lock()
 	defer ew.mtx.Unlock()
 
+	if ew.serverVersion >= MINIMUM_COLUMN_BLOCK_VERSION && len(ents) > 1 {
+		return ew.writeColumnBatch(ents)
+	}
+
 	for i := range ents {
 		if _, err = ew.writeEntry(ents[i], false); err != nil {
 			return i, err
@@ -373,6 +401,102 @@ func (ew *EntryWriter) WriteBatch(ents [](*entry.Entry)) (int, error) {
 	return len(ents), nil
 }
 
+// writeColumnBatch packs the entries into column blocks sized to fit the confirmation buffer.
+// Every entry still gets its own send ID, so confirmations and resends behave exactly as
+// they do for entries sent individually.  Caller MUST HOLD THE LOCK
+func (ew *EntryWriter) writeColumnBatch(ents []*entry.Entry) (n int, err error) {
+	if ew.serverVersion < MINIMUM_COMPOUND_EV_VERSION {
+		ents = flattenCompoundEVs(ents)
+	}
+	for n < len(ents) {
+		if ew.ecb.Full() {
+			if err = ew.flush(); err != nil {
+				return
+			}
+			if err = ew.serviceAcks(true, ew.ctx); err != nil {
+				return
+			}
+		}
+		var cnt, sz int
+		slots := ew.ecb.Free() - 1
+		for cnt < slots && (n+cnt) < len(ents) {
+			ent := ents[n+cnt]
+			if ent == nil {
+				return n, entry.ErrNilEntry
+			} else if len(ent.Data) > MAX_ENTRY_SIZE {
+				return n, ErrOversizedEntry
+			}
+			esz := len(ent.Data) + ent.EVSize()
+			if (sz + esz) > MAX_COLUMN_BLOCK_PAYLOAD {
+				break
+			}
+			sz += esz
+			cnt++
+		}
+		if cnt == 0 {
+			//a single entry too large for a column block, just send it on its own
+			if _, err = ew.writeEntry(ents[n], false); err != nil {
+				return
+			}
+			n++
+			continue
+		}
+		if err = ew.sendColumnBlock(ents[n : n+cnt]); err != nil {
+			return
+		}
+		n += cnt
+	}
+	return
+}
+
+// flattenCompoundEVs renders list and map enumerated values as strings for servers that predate them,
+// the slice is only copied if an entry had to be flattened.
+func flattenCompoundEVs(ents []*entry.Entry) []*entry.Entry {
+	r := ents
+	var copied bool
+	for i, ent := range ents {
+		if ent == nil {
+			continue
+		} else if fent := ent.FlattenCompoundEVs(); fent != ent {
+			if !copied {
+				r = append([]*entry.Entry(nil), ents...)
+				copied = true
+			}
+			r[i] = fent
+		}
+	}
+	return r
+}
+
+// sendColumnBlock encodes and sends a set of entries as a single column block, the caller
+// must ensure there is room in the confirmation buffer for every entry in the set.
+// The payload column is only compressed when the transport is not already compressed.
+func (ew *EntryWriter) sendColumnBlock(ents []*entry.Entry) (err error) {
+	if ew.serverVersion < MINIMUM_COLUMN_BLOCK_VERSION {
+		return fmt.Errorf("server version %d is too old to handle column blocks (version %v required)", ew.serverVersion, MINIMUM_COLUMN_BLOCK_VERSION)
+	}
+	cb := entry.NewColumnBlock(ents)
+	ew.colBuff = append(ew.colBuff[:0], make([]byte, 16)...)
+	if ew.colBuff, err = cb.AppendEncode(ew.colBuff, ew.flshr == nil); err != nil {
+		return
+	} else if len(ew.colBuff)-16 > maxColumnBlockWireSize {
+		return ErrOversizedEntry
+	}
+	binary.LittleEndian.PutUint32(ew.colBuff, uint32(COLUMN_BLOCK_MAGIC))
+	binary.LittleEndian.PutUint64(ew.colBuff[4:], uint64(ew.id))
+	binary.LittleEndian.PutUint32(ew.colBuff[12:], uint32(len(ew.colBuff)-16))
+	if err = ew.writeAll(ew.colBuff); err != nil {
+		return
+	}
+	for _, ent := range ents {
+		if err = ew.ecb.Add(&entryConfirmation{ew.id, ent}); err != nil {
+			return
+		}
+		ew.id++
+	}
+	return
+}
+
 func (ew *EntryWriter) writeEntry(ent *entry.Entry, flush bool) (bool, error) {
 	//if our conf buffer is full force an ack service
 	if ew.ecb.Full() {
@@ -412,6 +536,8 @@ func (ew *EntryWriter) encodeAndSendEntry(ent *entry.Entry, flush bool) (flushed
 			SRC:  ent.SRC,
 			Data: ent.Data,
 		}
+	} else if ew.serverVersion < MINIMUM_COMPOUND_EV_VERSION {
+		ent = ent.FlattenCompoundEVs()
 	}
 
 	//throw the magic
@@ -1122,6 +1248,8 @@ func (ic IngestCommand) String() string {
 		return `INGESTER_STATE_CONFIRM`
 	case CONFIRM_DITTO_BLOCK_MAGIC:
 		return `DITTO_BLOCK_CONFIRM`
+	case COLUMN_BLOCK_MAGIC:
+		return `COLUMN_BLOCK`
 	}
 	return `UNKNOWN`
 }
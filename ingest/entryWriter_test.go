@@ -11,6 +11,7 @@
 package ingest
 
 import (
+	"encoding/binary"
 	"errors"
 	"fmt"
 	"io"
@@ -274,6 +275,202 @@ func TestDittoWriteFail(t *testing.T) {
 	lst.Close()
 }
 
+func TestColumnBatch(t *testing.T) {
+	if err := cleanup(); err != nil {
+		t.Fatal(err)
+	}
+	lst, cli, srv, err := getConnections()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	etSrv, err := NewEntryReader(srv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	etSrv.Start()
+
+	etCli, err := NewEntryWriter(cli)
+	if err != nil {
+		t.Fatal(err)
+	}
+	etCli.serverVersion = VERSION
+
+	//more than the confirmation buffer can hold so that blocks are split and acks serviced
+	count := 3 * etCli.OptimalBatchWriteSize()
+	var ents []*entry.Entry
+	for i := 0; i < count; i++ {
+		ent := makeEntryWithKey(int64(i))
+		ent.Tag = entry.EntryTag(i % 3)
+		if i%2 == 0 {
+			ent.AddEnumeratedValueEx(`idx`, uint64(i))
+		}
+		ents = append(ents, ent)
+	}
+
+	entChan := make(chan []*entry.Entry, 1)
+	errChan := make(chan error, 1)
+	go func() {
+		var got []*entry.Entry
+		for {
+			ent, err := etSrv.Read()
+			if err == io.EOF {
+				break
+			} else if err != nil {
+				errChan <- err
+				return
+			}
+			got = append(got, ent)
+		}
+		entChan <- got
+	}()
+
+	if n, err := etCli.WriteBatch(ents); err != nil {
+		t.Fatal(err)
+	} else if n != count {
+		t.Fatalf("short batch write %d != %d", n, count)
+	}
+	if err = etCli.ForceAck(); err != nil {
+		t.Fatal(err)
+	} else if c := etCli.ecb.Count(); c != 0 {
+		t.Fatalf("%d entries left unconfirmed", c)
+	}
+	if err = etCli.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case err = <-errChan:
+		t.Fatal(err)
+	case got := <-entChan:
+		if len(got) != count {
+			t.Fatalf("read count invalid: %d != %d", len(got), count)
+		}
+		for i := range got {
+			if err = ents[i].Compare(got[i]); err != nil {
+				t.Fatalf("entry %d: %v", i, err)
+			}
+		}
+	}
+
+	if err = etSrv.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err = closeConnections(cli, srv); err != nil {
+		t.Fatal(err)
+	}
+	lst.Close()
+}
+
+func TestColumnBlockVersion(t *testing.T) {
+	if err := cleanup(); err != nil {
+		t.Fatal(err)
+	}
+	lst, cli, srv, err := getConnections()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lst.Close()
+	defer closeConnections(cli, srv)
+
+	etCli, err := NewEntryWriter(cli)
+	if err != nil {
+		t.Fatal(err)
+	}
+	//old indexers never see column blocks, even if asked directly
+	etCli.serverVersion = MINIMUM_COLUMN_BLOCK_VERSION - 1
+	if err = etCli.sendColumnBlock([]*entry.Entry{makeEntry(), makeEntry()}); err == nil {
+		t.Fatal("sent a column block to an old indexer")
+	}
+
+	//a column block inside a ditto block is rejected by the reader
+	etSrv, err := NewEntryReader(srv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	etSrv.Start()
+	defer etSrv.Close()
+	cb := entry.NewColumnBlock([]*entry.Entry{makeEntry(), makeEntry()})
+	payload, err := cb.Encode(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buff := make([]byte, 28, 28+len(payload))
+	binary.LittleEndian.PutUint32(buff, uint32(DITTO_BLOCK_MAGIC))
+	binary.LittleEndian.PutUint64(buff[4:], 1)
+	binary.LittleEndian.PutUint32(buff[12:], uint32(COLUMN_BLOCK_MAGIC))
+	binary.LittleEndian.PutUint64(buff[16:], 0)
+	binary.LittleEndian.PutUint32(buff[24:], uint32(len(payload)))
+	go cli.Write(append(buff, payload...))
+	if _, err = etSrv.Read(); err != ErrColumnBlockInDitto {
+		t.Fatalf("failed to reject column block in a ditto block: %v", err)
+	}
+}
+
+func TestCompoundEVVersion(t *testing.T) {
+	if err := cleanup(); err != nil {
+		t.Fatal(err)
+	}
+	lst, cli, srv, err := getConnections()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lst.Close()
+	defer closeConnections(cli, srv)
+
+	etSrv, err := NewEntryReader(srv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	etSrv.Start()
+	defer etSrv.Close()
+	etCli, err := NewEntryWriter(cli)
+	if err != nil {
+		t.Fatal(err)
+	}
+	//indexers that predate list and map values get them as strings
+	etCli.serverVersion = MINIMUM_COMPOUND_EV_VERSION - 1
+	ent := makeEntry()
+	if err = ent.AddEnumeratedValueEx(`list`, []string{`foo`, `bar`}); err != nil {
+		t.Fatal(err)
+	}
+	orig := ent.EVB.Values()[0].Value
+	errChan := make(chan error, 1)
+	go func() {
+		for i := 0; i < 3; i++ {
+			rent, err := etSrv.Read()
+			if err != nil {
+				errChan <- err
+				return
+			}
+			evs := rent.EVB.Values()
+			if len(evs) != 1 {
+				errChan <- fmt.Errorf("bad EV count %d", len(evs))
+				return
+			} else if v, ok := evs[0].Value.Interface().(string); !ok || v != orig.String() {
+				errChan <- fmt.Errorf("compound EV was not flattened: %T %v", evs[0].Value.Interface(), evs[0].Value)
+				return
+			}
+		}
+		errChan <- nil
+	}()
+	if err = etCli.Write(ent); err != nil {
+		t.Fatal(err)
+	} else if _, err = etCli.WriteBatch([]*entry.Entry{ent, ent}); err != nil {
+		t.Fatal(err)
+	} else if err = etCli.ForceAck(); err != nil {
+		t.Fatal(err)
+	} else if err = <-errChan; err != nil {
+		t.Fatal(err)
+	}
+	//the caller's entry is left alone
+	if v := ent.EVB.Values()[0].Value; v.String() != orig.String() {
+		t.Fatalf("original entry was modified: %v", v)
+	} else if _, ok := v.Interface().([]interface{}); !ok {
+		t.Fatalf("original entry was modified: %T", v.Interface())
+	}
+}
+
 func TestSingleRead(t *testing.T) {
 	if err := cleanup(); err != nil {
 		t.Fatal(err)
@@ -698,6 +895,105 @@ func BenchmarkBatch(b *testing.B) {
 	b.StartTimer()
 }
 
+// BenchmarkPooled runs entries through a writer and reader with and without an entry pool,
+// run it with -benchtime=1000000x -benchmem to compare allocations over a million entries.
+func BenchmarkPooled(b *testing.B) {
+	b.Run("nopool", func(b *testing.B) {
+		benchmarkPooled(b, nil)
+	})
+	b.Run("pool", func(b *testing.B) {
+		benchmarkPooled(b, entry.NewPool())
+	})
+}
+
+func benchmarkPooled(b *testing.B, pool *entry.Pool) {
+	b.StopTimer()
+	if err := cleanup(); err != nil {
+		b.Fatal(err)
+	}
+	errChan := make(chan error)
+	lst, cli, srv, err := getConnections()
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	etSrv, err := NewEntryReaderEx(EntryReaderWriterConfig{
+		Conn:                  srv,
+		OutstandingEntryCount: MAX_UNCONFIRMED_COUNT,
+		BufferSize:            READ_BUFFER_SIZE,
+		Timeout:               defaultReaderTimeout,
+		Pool:                  pool,
+	})
+	if err != nil {
+		b.Fatal(err)
+	}
+	etSrv.Start()
+
+	etCli, err := NewEntryWriterEx(EntryReaderWriterConfig{
+		Conn:                  cli,
+		OutstandingEntryCount: MAX_UNCONFIRMED_COUNT,
+		BufferSize:            WRITE_BUFFER_SIZE,
+		Timeout:               CLOSING_SERVICE_ACK_TIMEOUT,
+		Pool:                  pool,
+	})
+	if err != nil {
+		b.Fatal(err)
+	}
+	etCli.serverVersion = VERSION
+
+	go pooledReader(etSrv, pool, b.N, errChan)
+	b.ReportAllocs()
+	b.StartTimer()
+
+	for i := 0; i < b.N; i++ {
+		dt := entryPad[:ENTRY_MIN_SIZE+(i%(ENTRY_MAX_SIZE-ENTRY_MIN_SIZE))]
+		ent := pool.GetCopy(dt)
+		ent.TS = entry.Now()
+		ent.SRC = entIp
+		if err = etCli.Write(ent); err != nil {
+			b.Fatal(err)
+		}
+	}
+	if err = etCli.Close(); err != nil {
+		b.Fatal(err)
+	}
+	if err = <-errChan; err != nil {
+		b.Fatal(err)
+	}
+	b.StopTimer()
+	if err = etSrv.Close(); err != nil {
+		b.Fatal(err)
+	}
+	if err = closeConnections(cli, srv); err != nil {
+		b.Fatal(err)
+	}
+	lst.Close()
+	if err = cleanup(); err != nil {
+		b.Fatal(err)
+	}
+}
+
+// pooledReader is reader but it hands every entry back to the pool once it is done with it
+func pooledReader(et *EntryReader, pool *entry.Pool, count int, errChan chan error) {
+	var cntRead int
+	for {
+		ent, err := et.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			errChan <- err
+			return
+		}
+		cntRead++
+		pool.Put(ent)
+	}
+	if cntRead != count {
+		errChan <- fmt.Errorf("read count invalid: %d != %d", cntRead, count)
+	} else {
+		errChan <- nil
+	}
+}
+
 func cleanup() error {
 	err := os.Remove(PIPE_LOCATION)
 	if err != nil && !strings.Contains(err.Error(), "no such file") {
@@ -22,6 +22,7 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"sort"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -154,12 +155,16 @@ type IngestMuxer struct {
 	rateParent           *parent
 	logSourceOverride    net.IP
 	ingesterState        IngesterState
-	ingesterStateUpdated bool         //ingesterState has been updated (usually a child member)
+	ingesterStateUpdated bool //ingesterState has been updated (usually a child member)
+	ppStats              map[string]PreprocessorStatsSource
+	tsStats              map[string]TimestampStatsSource
 	logbuff              *EntryBuffer // for holding logs until we can push them
 	start                time.Time    // when the muxer was started
 	attacher             *attach.Attacher
 	attachActive         bool
 	minVersion           uint16
+	schemas              *SchemaRegistry
+	pool                 *entry.Pool
 }
 
 type UniformMuxerConfig struct {
@@ -185,6 +190,8 @@ type UniformMuxerConfig struct {
 	LogSourceOverride net.IP
 	Attach            attach.AttachConfig
 	MinVersion        uint16 // minimum API version of indexers
+	Schemas           *SchemaRegistry
+	EntryPool         *entry.Pool // confirmed entries that came from a pool are returned here, a pool is created if nil
 }
 
 type MuxerConfig struct {
@@ -208,6 +215,8 @@ type MuxerConfig struct {
 	LogSourceOverride net.IP
 	Attach            attach.AttachConfig
 	MinVersion        uint16 // minimum API version of indexers
+	Schemas           *SchemaRegistry
+	EntryPool         *entry.Pool // confirmed entries that came from a pool are returned here, a pool is created if nil
 }
 
 func NewUniformMuxer(c UniformMuxerConfig) (*IngestMuxer, error) {
@@ -272,6 +281,8 @@ func newUniformIngestMuxerEx(c UniformMuxerConfig) (*IngestMuxer, error) {
 		LogSourceOverride:  c.LogSourceOverride,
 		Attach:             c.Attach,
 		MinVersion:         c.MinVersion,
+		Schemas:            c.Schemas,
+		EntryPool:          c.EntryPool,
 	}
 	return newIngestMuxer(cfg)
 }
@@ -303,6 +314,21 @@ func newIngestMuxer(c MuxerConfig) (*IngestMuxer, error) {
 		}
 		localTags = append(localTags, c.Tags[i])
 	}
+	// any tags the schemas reference must be known up front so the registry can be bound
+	for _, v := range c.Schemas.Tags() {
+		var ok bool
+		for i := range localTags {
+			if ok = localTags[i] == v; ok {
+				break
+			}
+		}
+		if !ok {
+			localTags = append(localTags, v)
+		}
+	}
+	if len(localTags) > int(entry.MaxTagId) {
+		return nil, ErrTooManyTags
+	}
 	if c.Logger == nil {
 		c.Logger = log.NewDiscardLogger()
 	}
@@ -398,6 +424,12 @@ func newIngestMuxer(c MuxerConfig) (*IngestMuxer, error) {
 		writeTagCache(tagMap, c.CachePath)
 	}
 
+	if c.Schemas != nil {
+		if err = c.Schemas.bind(tagMap); err != nil {
+			return nil, err
+		}
+	}
+
 	var p *parent
 	if c.RateLimitBps > 0 {
 		p = newParent(c.RateLimitBps, 0)
@@ -436,6 +468,11 @@ func newIngestMuxer(c MuxerConfig) (*IngestMuxer, error) {
 		tc.add(v)
 	}
 
+	pool := c.EntryPool
+	if pool == nil {
+		pool = entry.NewPool()
+	}
+
 	ctx, cf := context.WithCancel(context.Background())
 
 	return &IngestMuxer{
@@ -480,6 +517,8 @@ func newIngestMuxer(c MuxerConfig) (*IngestMuxer, error) {
 		attacher:          atch,
 		attachActive:      atch.Active(),
 		minVersion:        c.MinVersion,
+		schemas:           c.Schemas,
+		pool:              pool,
 	}, nil
 }
 
@@ -646,6 +685,9 @@ func (im *IngestMuxer) getIngesterState(lastPush time.Time, lastEntryCount uint6
 	}
 	im.ingesterState.Uptime = time.Since(im.start)
 	im.ingesterState.Tags = im.tags
+	im.ingesterState.Preprocessors = im.gatherPreprocessorStats()
+	im.ingesterState.Timestamps = im.gatherTimestampStats()
+	im.ingesterState.Schemas = im.schemas.Stats()
 
 	// The ingesterState object is of type ingest.IngesterState which contains a map of children.
 	// You must make a deep copy (which is what Copy does) if you are going to concurrently read and write it.
@@ -771,6 +813,85 @@ func (im *IngestMuxer) RegisterChild(k string, v IngesterState) {
 	im.mtx.Unlock()
 }
 
+// RegisterPreprocessorStats attaches a source of preprocessor counters to the ingester state under the given name.
+// Registering a source with an existing name replaces it.
+func (im *IngestMuxer) RegisterPreprocessorStats(k string, src PreprocessorStatsSource) {
+	if src == nil {
+		return
+	}
+	im.mtx.Lock()
+	if im.ppStats == nil {
+		im.ppStats = map[string]PreprocessorStatsSource{}
+	}
+	im.ppStats[k] = src
+	im.ingesterStateUpdated = true
+	im.mtx.Unlock()
+}
+
+func (im *IngestMuxer) UnregisterPreprocessorStats(k string) {
+	im.mtx.Lock()
+	delete(im.ppStats, k)
+	im.ingesterStateUpdated = true
+	im.mtx.Unlock()
+}
+
+// gatherPreprocessorStats snapshots each of the registered sources, caller must hold the lock
+func (im *IngestMuxer) gatherPreprocessorStats() (r []PreprocessorStats) {
+	if len(im.ppStats) == 0 {
+		return
+	}
+	names := make([]string, 0, len(im.ppStats))
+	for k := range im.ppStats {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	for _, k := range names {
+		for _, v := range im.ppStats[k].PreprocessorStats() {
+			v.Set = k
+			r = append(r, v)
+		}
+	}
+	return
+}
+
+// RegisterTimestampStats attaches a source of timestamp extraction counters to the ingester state under the given name.
+// Registering a source with an existing name replaces it.
+func (im *IngestMuxer) RegisterTimestampStats(k string, src TimestampStatsSource) {
+	if src == nil {
+		return
+	}
+	im.mtx.Lock()
+	if im.tsStats == nil {
+		im.tsStats = map[string]TimestampStatsSource{}
+	}
+	im.tsStats[k] = src
+	im.ingesterStateUpdated = true
+	im.mtx.Unlock()
+}
+
+func (im *IngestMuxer) UnregisterTimestampStats(k string) {
+	im.mtx.Lock()
+	delete(im.tsStats, k)
+	im.ingesterStateUpdated = true
+	im.mtx.Unlock()
+}
+
+// gatherTimestampStats snapshots each of the registered sources, caller must hold the lock
+func (im *IngestMuxer) gatherTimestampStats() (r []TimestampStats) {
+	if len(im.tsStats) == 0 {
+		return
+	}
+	names := make([]string, 0, len(im.tsStats))
+	for k := range im.tsStats {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	for _, k := range names {
+		r = append(r, TimestampStats{Name: k, Stats: im.tsStats[k].Stats()})
+	}
+	return
+}
+
 func (im *IngestMuxer) UnregisterChild(k string) {
 	im.mtx.Lock()
 	delete(im.ingesterState.Children, k)
@@ -1127,6 +1248,10 @@ func (im *IngestMuxer) GetTag(tag string) (tg entry.EntryTag, err error) {
 // WriteEntry puts an entry into the queue to be sent out by the first available
 // entry writer routine, if all routines are dead, THIS WILL BLOCK once the
 // channel fills up.  We figure this is a natural "wait" mechanism
+//
+// On a nil error the muxer takes ownership of the entry and its Data; the caller must not
+// modify or reuse either.  Entries obtained from EntryPool are returned to the pool once an
+// indexer confirms them.
 func (im *IngestMuxer) WriteEntry(e *entry.Entry) error {
 	if e == nil {
 		return nil
@@ -1141,6 +1266,7 @@ func (im *IngestMuxer) WriteEntry(e *entry.Entry) error {
 	if im.attachActive {
 		im.attacher.Attach(e)
 	}
+	im.schemas.Validate(e)
 	select {
 	case im.eChan <- e:
 	case <-im.writeBarrier:
@@ -1155,6 +1281,7 @@ func (im *IngestMuxer) WriteEntry(e *entry.Entry) error {
 // entry writer routine, if all routines are dead, THIS WILL BLOCK once the
 // channel fills up.  We figure this is a natural "wait" mechanism
 // if not using a context, use WriteEntry as it is faster due to the lack of a select
+// Entry ownership follows the same rules as WriteEntry.
 func (im *IngestMuxer) WriteEntryContext(ctx context.Context, e *entry.Entry) error {
 	if e == nil {
 		return nil
@@ -1169,6 +1296,7 @@ func (im *IngestMuxer) WriteEntryContext(ctx context.Context, e *entry.Entry) er
 	if im.attachActive {
 		im.attacher.Attach(e)
 	}
+	im.schemas.Validate(e)
 	select {
 	case im.eChan <- e:
 		im.ingesterState.Entries++
@@ -1185,6 +1313,7 @@ func (im *IngestMuxer) WriteEntryContext(ctx context.Context, e *entry.Entry) er
 // of the first available writer routine.  This write is opportunistic and contains
 // a timeout.  It is therefor every expensive and shouldn't be used for normal writes
 // The typical use case is via the gravwell_log calls
+// Entry ownership follows the same rules as WriteEntry.
 func (im *IngestMuxer) WriteEntryTimeout(e *entry.Entry, d time.Duration) (err error) {
 	if e == nil {
 		return
@@ -1199,6 +1328,7 @@ func (im *IngestMuxer) WriteEntryTimeout(e *entry.Entry, d time.Duration) (err e
 	if im.attachActive {
 		im.attacher.Attach(e)
 	}
+	im.schemas.Validate(e)
 	tmr := time.NewTimer(d)
 	select {
 	case im.eChan <- e:
@@ -1215,6 +1345,10 @@ func (im *IngestMuxer) WriteEntryTimeout(e *entry.Entry, d time.Duration) (err e
 // WriteBatch puts a slice of entries into the queue to be sent out by the first
 // available entry writer routine.  The entry writer routines will consume the
 // entire slice, so extremely large slices will go to a single indexer.
+//
+// On a nil error the muxer takes ownership of the slice, every entry in it, and their Data; the
+// caller must not modify or reuse any of them.  Entries obtained from EntryPool are returned to the pool
+// once an indexer confirms them.
 func (im *IngestMuxer) WriteBatch(b []*entry.Entry) error {
 	if len(b) == 0 {
 		return nil
@@ -1240,6 +1374,11 @@ func (im *IngestMuxer) WriteBatch(b []*entry.Entry) error {
 			im.attacher.Attach(e)
 		}
 	}
+	if im.schemas != nil {
+		for _, e := range b {
+			im.schemas.Validate(e)
+		}
+	}
 	select {
 	case im.bChan <- b:
 	case <-im.writeBarrier:
@@ -1256,6 +1395,7 @@ func (im *IngestMuxer) WriteBatch(b []*entry.Entry) error {
 // available entry writer routine.  The entry writer routines will consume the
 // entire slice, so extremely large slices will go to a single indexer.
 // if a cancellation context isn't needed, use WriteBatch
+// Entry ownership follows the same rules as WriteBatch.
 func (im *IngestMuxer) WriteBatchContext(ctx context.Context, b []*entry.Entry) error {
 	if len(b) == 0 {
 		return nil
@@ -1283,6 +1423,11 @@ func (im *IngestMuxer) WriteBatchContext(ctx context.Context, b []*entry.Entry)
 			im.attacher.Attach(e)
 		}
 	}
+	if im.schemas != nil {
+		for _, e := range b {
+			im.schemas.Validate(e)
+		}
+	}
 	select {
 	case im.bChan <- b:
 		im.ingesterState.Entries += uint64(len(b))
@@ -1981,6 +2126,7 @@ loop:
 		if im.rateParent != nil {
 			ig.ew.setConn(im.rateParent.newThrottleConn(ig.ew.conn))
 		}
+		ig.ew.setPool(im.pool)
 
 		//no error, attempt to do a tag translation
 		//we have a good connection, build our tag map
@@ -2103,6 +2249,13 @@ func (im *IngestMuxer) newTagTrans(igst *IngestConnection) (*tagTrans, error) {
 	return tt, nil
 }
 
+// EntryPool returns the pool confirmed entries are returned to.  Ingesters that build entries
+// in a hot path can draw them from this pool; an entry handed to a Write call must not be
+// referenced again by the caller.
+func (im *IngestMuxer) EntryPool() *entry.Pool {
+	return im.pool
+}
+
 // SourceIP is a convenience function used to pull back a source value
 func (im *IngestMuxer) SourceIP() (net.IP, error) {
 	var ip net.IP
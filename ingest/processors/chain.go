@@ -0,0 +1,355 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package processors
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+
+	"github.com/gravwell/gravwell/v3/ingest"
+	"github.com/gravwell/gravwell/v3/ingest/config"
+	"github.com/gravwell/gravwell/v3/ingest/entry"
+)
+
+const (
+	ChainProcessor = `chain`
+)
+
+var (
+	ErrEmptyChain    = errors.New("chain requires at least one Preprocessor")
+	ErrChainRecursed = errors.New("preprocessor chain references itself")
+)
+
+// ChainConfig names an ordered list of preprocessor blocks which are executed as a single unit.
+// Chains are typically the target of a Jump, or listed directly with match conditions to
+// build a conditional branch, for example:
+//
+//	[Preprocessor "ise"]
+//		Type = chain
+//		Match-Tag = ise
+//		Preprocessor = ise-parse
+//		Preprocessor = ise-router
+type ChainConfig struct {
+	Preprocessor []string
+}
+
+func ChainLoadConfig(vc *config.VariableConfig) (c ChainConfig, err error) {
+	if err = vc.MapTo(&c); err == nil {
+		err = c.validate()
+	}
+	return
+}
+
+func (c ChainConfig) validate() error {
+	if len(c.Preprocessor) == 0 {
+		return ErrEmptyChain
+	}
+	for _, v := range c.Preprocessor {
+		if strings.TrimSpace(v) == `` {
+			return errors.New("empty Preprocessor name in chain")
+		}
+	}
+	return nil
+}
+
+// Chain executes a nested set of preprocessors; match conditions and jumps on the
+// members are honored exactly as they are on a top level set.
+type Chain struct {
+	ChainConfig
+	ps *ProcessorSet
+}
+
+// Process runs the entries through the chain, dead-lettered entries are left in the
+// buffer of the top level set so that they bypass the rest of the preprocessors
+func (c *Chain) Process(ents []*entry.Entry) ([]*entry.Entry, error) {
+	return c.ps.processFrom(0, ents)
+}
+
+func (c *Chain) Flush() []*entry.Entry {
+	ents, _ := c.ps.flushItems()
+	return ents
+}
+
+func (c *Chain) Close() error {
+	return c.ps.closeProcessors()
+}
+
+// processorControlConfig contains the options that are common to every preprocessor block.
+// Each populated Match option must be satisfied for an entry to be handed to the preprocessor,
+// multiple values within a single option are OR'd.  Entries that do not match pass through untouched.
+type processorControlConfig struct {
+	Match_Tag              []string // tag names
+	Match_Source           []string // IPs or CIDRs
+	Match_Regex            string   // regular expression applied to the entry data
+	Match_Enumerated_Value []string // field-router style tests on EVs, e.g. "severity >= 5" or "user"
+	Jump                   string   // preprocessor (usually a chain) that handles entries emitted by this block
+	Dead_Letter_Tag        string   // entries the preprocessor fails on are re-tagged here instead of dropped or passed
+}
+
+func loadProcessorControl(vc *config.VariableConfig) (c processorControlConfig, err error) {
+	if err = vc.MapTo(&c); err == nil {
+		_, err = c.compile()
+	}
+	return
+}
+
+func (c processorControlConfig) empty() bool {
+	return len(c.Match_Tag) == 0 && len(c.Match_Source) == 0 && c.Match_Regex == `` &&
+		len(c.Match_Enumerated_Value) == 0 && c.Jump == `` && c.Dead_Letter_Tag == ``
+}
+
+// compile builds the matcher, a nil control means the preprocessor applies to every entry
+func (c processorControlConfig) compile() (pc *processorControl, err error) {
+	if c.empty() {
+		return
+	}
+	pc = &processorControl{}
+	for _, v := range c.Match_Tag {
+		if v = strings.TrimSpace(v); v == `` {
+			err = errors.New("empty Match-Tag")
+			return
+		}
+		if pc.tagNames == nil {
+			pc.tagNames = make(map[string]struct{}, len(c.Match_Tag))
+		}
+		pc.tagNames[v] = empty
+	}
+	for _, v := range c.Match_Source {
+		var n *net.IPNet
+		if n, err = parseMatchSource(v); err != nil {
+			return
+		}
+		pc.nets = append(pc.nets, n)
+	}
+	if c.Match_Regex != `` {
+		if pc.rx, err = regexp.Compile(c.Match_Regex); err != nil {
+			err = fmt.Errorf("Invalid Match-Regex %q: %w", c.Match_Regex, err)
+			return
+		}
+	}
+	for _, v := range c.Match_Enumerated_Value {
+		var n fieldNode
+		if n, err = compileFieldExpr(fieldRouterEVPfx + strings.TrimSpace(v)); err != nil {
+			err = fmt.Errorf("Invalid Match-Enumerated-Value %q: %w", v, err)
+			return
+		}
+		pc.evs = append(pc.evs, n)
+	}
+	pc.jump = strings.TrimSpace(c.Jump)
+	if pc.dlTag = strings.TrimSpace(c.Dead_Letter_Tag); pc.dlTag != `` {
+		if err = ingest.CheckTag(pc.dlTag); err != nil {
+			err = fmt.Errorf("Invalid Dead-Letter-Tag %q: %w", pc.dlTag, err)
+			return
+		}
+		pc.dl = &deadLetterTarget{}
+	}
+	return
+}
+
+func parseMatchSource(v string) (n *net.IPNet, err error) {
+	v = strings.TrimSpace(v)
+	if strings.Contains(v, `/`) {
+		if _, n, err = net.ParseCIDR(v); err != nil {
+			err = fmt.Errorf("Invalid Match-Source %q: %w", v, err)
+		}
+		return
+	}
+	ip := net.ParseIP(v)
+	if ip == nil {
+		err = fmt.Errorf("Invalid Match-Source %q", v)
+	} else if ip4 := ip.To4(); ip4 != nil {
+		n = &net.IPNet{IP: ip4, Mask: net.CIDRMask(32, 32)}
+	} else {
+		n = &net.IPNet{IP: ip, Mask: net.CIDRMask(128, 128)}
+	}
+	return
+}
+
+// processorControl is the compiled form of processorControlConfig attached to an item in a ProcessorSet
+type processorControl struct {
+	tagNames map[string]struct{}
+	tags     map[entry.EntryTag]bool // resolved lazily through the tagger
+	tgr      Tagger
+	nets     []*net.IPNet
+	rx       *regexp.Regexp
+	evs      []fieldNode
+	ctx      fieldContext
+	jump     string
+	jumper   Processor
+	dlTag    string
+	dl       *deadLetterTarget
+	dlOrig   []*entry.Entry
+}
+
+func (pc *processorControl) conditional() bool {
+	return pc != nil && (pc.tagNames != nil || len(pc.nets) > 0 || pc.rx != nil || len(pc.evs) > 0)
+}
+
+func (pc *processorControl) match(ent *entry.Entry) bool {
+	if pc.tagNames != nil && !pc.matchTag(ent.Tag) {
+		return false
+	}
+	if len(pc.nets) > 0 {
+		var ok bool
+		for _, n := range pc.nets {
+			if ent.SRC != nil && n.Contains(ent.SRC) {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			return false
+		}
+	}
+	if pc.rx != nil && !pc.rx.Match(ent.Data) {
+		return false
+	}
+	if len(pc.evs) > 0 {
+		pc.ctx.reset(ent)
+		var ok bool
+		for _, n := range pc.evs {
+			if n.eval(&pc.ctx) {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func (pc *processorControl) matchTag(tg entry.EntryTag) (ok bool) {
+	var hit bool
+	if ok, hit = pc.tags[tg]; hit {
+		return
+	}
+	if pc.tgr != nil {
+		if name, found := pc.tgr.LookupTag(tg); found {
+			_, ok = pc.tagNames[name]
+		}
+	}
+	if pc.tags == nil {
+		pc.tags = make(map[entry.EntryTag]bool, len(pc.tagNames))
+	}
+	pc.tags[tg] = ok
+	return
+}
+
+// buildProcessor constructs the named preprocessor along with its control block, chains and jumps are
+// resolved recursively.  The stack is used to detect a chain that references itself.
+func (pc ProcessorConfig) buildProcessor(name string, tgr Tagger, stack []string) (p Processor, ctl *processorControl, err error) {
+	vc, ok := pc[name]
+	if !ok || vc == nil {
+		err = ErrNotFound
+		return
+	}
+	for _, v := range stack {
+		if v == name {
+			err = ErrChainRecursed
+			return
+		}
+	}
+	stack = append(stack, name)
+	var cc processorControlConfig
+	if err = vc.MapTo(&cc); err != nil {
+		return
+	} else if ctl, err = cc.compile(); err != nil {
+		return
+	}
+	var pb preprocessorBase
+	if err = vc.MapTo(&pb); err != nil {
+		return
+	}
+	if strings.TrimSpace(strings.ToLower(pb.Type)) == ChainProcessor {
+		p, err = pc.newChain(vc, tgr, stack)
+	} else {
+		p, err = newProcessor(name, vc, tgr)
+	}
+	if err != nil {
+		return
+	}
+	if ctl != nil {
+		ctl.tgr = tgr
+		if ctl.tagNames != nil && tgr == nil {
+			err = errors.New("Match-Tag requires a tagger")
+		} else if len(ctl.evs) > 0 {
+			ctl.ctx.kvp, err = NewKV(KVConfig{}, nil)
+		}
+		if err == nil && ctl.dl != nil {
+			if tgr == nil {
+				err = errors.New("Dead-Letter-Tag requires a tagger")
+			} else if ctl.dl.tag, err = tgr.NegotiateTag(ctl.dlTag); err != nil {
+				err = fmt.Errorf("Failed to negotiate Dead-Letter-Tag %s: %w", ctl.dlTag, err)
+			} else if fr, ok := p.(failureReporter); ok {
+				fr.setFailureHandler(ctl.dl.add)
+			}
+		}
+		if err == nil && ctl.jump != `` {
+			if ctl.jumper, err = pc.buildJump(ctl.jump, tgr, stack); err != nil {
+				err = fmt.Errorf("Jump %s %w", ctl.jump, err)
+			}
+		}
+		if err != nil {
+			p.Close()
+			p = nil
+		}
+	}
+	return
+}
+
+// buildJump constructs the target of a Jump.  A target with its own control block is wrapped in a
+// single item chain so that its match conditions, nested jump, and dead-letter tag are honored
+// exactly as they would be if the target were listed directly in a set.
+func (pc ProcessorConfig) buildJump(name string, tgr Tagger, stack []string) (p Processor, err error) {
+	var ctl *processorControl
+	if p, ctl, err = pc.buildProcessor(name, tgr, stack); err != nil || ctl == nil {
+		return
+	}
+	c := newChain(ChainConfig{Preprocessor: []string{name}}, tgr)
+	c.ps.addProcessor(p, ctl, name, pc.processorType(name))
+	p = c
+	return
+}
+
+func newChain(cfg ChainConfig, tgr Tagger) *Chain {
+	c := &Chain{
+		ChainConfig: cfg,
+		ps:          NewProcessorSet(nil),
+	}
+	c.ps.tgr = tgr
+	if lgr, ok := tgr.(ingest.IngestLogger); ok {
+		c.ps.lgr = lgr
+	}
+	return c
+}
+
+func (pc ProcessorConfig) newChain(vc *config.VariableConfig, tgr Tagger, stack []string) (p Processor, err error) {
+	var cfg ChainConfig
+	if cfg, err = ChainLoadConfig(vc); err != nil {
+		return
+	}
+	c := newChain(cfg, tgr)
+	for _, n := range cfg.Preprocessor {
+		var mp Processor
+		var ctl *processorControl
+		if mp, ctl, err = pc.buildProcessor(strings.TrimSpace(n), tgr, stack); err != nil {
+			err = fmt.Errorf("%s %w", n, err)
+			c.ps.closeProcessors()
+			return
+		}
+		c.ps.addProcessor(mp, ctl, strings.TrimSpace(n), pc.processorType(strings.TrimSpace(n)))
+	}
+	p = c
+	return
+}
@@ -0,0 +1,193 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package processors
+
+import (
+	"net"
+	"testing"
+
+	"github.com/gravwell/gravwell/v3/ingest/config"
+	"github.com/gravwell/gravwell/v3/ingest/entry"
+)
+
+const testChainConfig = `
+[preprocessor "kv"]
+	type = kv
+	Match-Tag = app
+	Jump = branch
+
+[preprocessor "dropdebug"]
+	type = drop
+	Match-Regex = "DEBUG"
+	Match-Source = 10.0.0.0/8
+	Match-Source = 172.16.1.1
+
+[preprocessor "branch"]
+	type = chain
+	Match-Enumerated-Value = "level == err"
+	Match-Enumerated-Value = "alert"
+	Preprocessor = route
+
+[preprocessor "route"]
+	type = field-router
+	Rule = "ev:level == err OR ev:alert => errors"
+`
+
+type testTagWriter struct {
+	testWriter
+	testTagger
+}
+
+func loadTestChain(t *testing.T, cfg string, names ...string) (*ProcessorSet, *testTagWriter) {
+	var tc testConfigStruct
+	if err := config.LoadConfigBytes(&tc, []byte(cfg)); err != nil {
+		t.Fatal(err)
+	} else if err = tc.Preprocessor.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	var tw testTagWriter
+	ps, err := tc.Preprocessor.ProcessorSet(&tw, names)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ps, &tw
+}
+
+func TestChainConfig(t *testing.T) {
+	b := `
+	[preprocessor "c"]
+		type = chain
+		Preprocessor = a
+		Preprocessor = b
+	[preprocessor "a"]
+		type = drop
+		Match-Source = 10.0.0.0/8
+	[preprocessor "b"]
+		type = drop
+		Match-Tag = foo
+	`
+	p, err := testLoadPreprocessor(b, `c`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, ok := p.(*Chain)
+	if !ok {
+		t.Fatalf("bad processor type %T", p)
+	} else if c.ps.Count() != 2 {
+		t.Fatalf("bad chain length %d", c.ps.Count())
+	} else if !c.ps.ctls[0].conditional() || !c.ps.ctls[1].conditional() {
+		t.Fatal("chain members lost their match conditions")
+	}
+}
+
+func TestChainBadConfig(t *testing.T) {
+	bad := []string{
+		`[preprocessor "c"]
+			type = chain`,
+		`[preprocessor "c"]
+			type = chain
+			Preprocessor = missing`,
+		`[preprocessor "c"]
+			type = chain
+			Preprocessor = d
+		[preprocessor "d"]
+			type = drop
+			Jump = c`,
+		`[preprocessor "d"]
+			type = drop
+			Match-Regex = "[a-"`,
+		`[preprocessor "d"]
+			type = drop
+			Match-Source = "10.0.0.0/33"`,
+		`[preprocessor "d"]
+			type = drop
+			Match-Enumerated-Value = "foo =="`,
+	}
+	for i, v := range bad {
+		var tc testConfigStruct
+		if err := config.LoadConfigBytes(&tc, []byte(v)); err != nil {
+			t.Fatal(err)
+		} else if err = tc.Preprocessor.Validate(); err == nil {
+			t.Fatalf("Failed to catch bad config %d", i)
+		}
+	}
+}
+
+func TestConditionalProcessorSet(t *testing.T) {
+	ps, tw := loadTestChain(t, testChainConfig, `kv`, `dropdebug`)
+	app, _ := tw.NegotiateTag(`app`)
+	other, _ := tw.NegotiateTag(`other`)
+	ents := []*entry.Entry{
+		&entry.Entry{Tag: app, SRC: net.ParseIP("10.1.1.1"), Data: []byte(`level=err msg=broken`)},
+		&entry.Entry{Tag: other, SRC: net.ParseIP("192.168.1.1"), Data: []byte(`level=err DEBUG`)},
+		&entry.Entry{Tag: app, SRC: net.ParseIP("10.0.0.5"), Data: []byte(`level=info DEBUG`)},
+		&entry.Entry{Tag: app, SRC: net.ParseIP("172.16.1.1"), Data: []byte(`level=info`)},
+		&entry.Entry{Tag: other, SRC: net.ParseIP("172.16.1.1"), Data: []byte(`DEBUG alert=1`)},
+		&entry.Entry{Tag: app, SRC: net.ParseIP("192.168.1.1"), Data: []byte(`alert=1`)},
+	}
+	if err := ps.ProcessBatch(ents); err != nil {
+		t.Fatal(err)
+	}
+	errTag, _ := tw.NegotiateTag(`errors`)
+	exp := []struct {
+		data string
+		tag  entry.EntryTag
+		evs  int
+	}{
+		{data: `level=err msg=broken`, tag: errTag, evs: 2},
+		{data: `level=err DEBUG`, tag: other, evs: 0},
+		{data: `level=info`, tag: app, evs: 1},
+		{data: `alert=1`, tag: errTag, evs: 1},
+	}
+	if len(tw.ents) != len(exp) {
+		t.Fatalf("bad output count %d != %d", len(tw.ents), len(exp))
+	}
+	for i, e := range exp {
+		ent := tw.ents[i]
+		if string(ent.Data) != e.data {
+			t.Fatalf("%d bad data %q != %q", i, ent.Data, e.data)
+		} else if ent.Tag != e.tag {
+			t.Fatalf("%d bad tag %d != %d", i, ent.Tag, e.tag)
+		} else if ent.EVB.Count() != e.evs {
+			t.Fatalf("%d bad EV count %d != %d", i, ent.EVB.Count(), e.evs)
+		}
+	}
+	if err := ps.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestConditionalSplitRuns(t *testing.T) {
+	// the json array splitter expands entries in place, make sure that doesn't clobber non-matching entries
+	b := `
+	[preprocessor "split"]
+		type = jsonarraysplit
+		Extraction = vals
+		Match-Regex = "vals"
+	`
+	ps, tw := loadTestChain(t, b, `split`)
+	ents := []*entry.Entry{
+		&entry.Entry{Data: []byte(`{"vals": [1, 2, 3]}`)},
+		&entry.Entry{Data: []byte(`first`)},
+		&entry.Entry{Data: []byte(`{"vals": [4, 5]}`)},
+		&entry.Entry{Data: []byte(`second`)},
+	}
+	if err := ps.ProcessBatch(ents); err != nil {
+		t.Fatal(err)
+	}
+	exp := []string{`1`, `2`, `3`, `first`, `4`, `5`, `second`}
+	if len(tw.ents) != len(exp) {
+		t.Fatalf("bad output count %d != %d", len(tw.ents), len(exp))
+	}
+	for i, v := range exp {
+		if string(tw.ents[i].Data) != v {
+			t.Fatalf("%d bad data %q != %q", i, tw.ents[i].Data, v)
+		}
+	}
+}
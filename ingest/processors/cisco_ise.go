@@ -28,6 +28,7 @@ var (
 	ErrInvalidISEHeader       = errors.New("Failed to match ISE header")
 	ErrInvalidRemoteISESeq    = errors.New("Invalid multipart message sequence")
 	ErrInvalidISESeq          = errors.New("Invalid ISE message sequence")
+	ErrISEFormat              = errors.New("Failed to format ISE message")
 )
 
 const (
@@ -123,6 +124,7 @@ func (c *CiscoISEConfig) validate() (err error) {
 type iseFormatter func(*entry.Entry, []glob.Glob, bool) bool
 
 type CiscoISE struct {
+	failureHook
 	CiscoISEConfig
 	fmt iseFormatter
 	ma  *multipartAssembler
@@ -193,8 +195,9 @@ func (p *CiscoISE) processEnt(ent *entry.Entry) (r *entry.Entry, err error) {
 		r, err = p.processReassemble(ent)
 	} else {
 		//just attempt to reformat the entry
-		if !p.fmt(ent, p.filters, p.Attribute_Strip_Header) && p.Drop_Misses {
-			//bad formatting and no passthrough, just skip it
+		if !p.fmt(ent, p.filters, p.Attribute_Strip_Header) {
+			//bad formatting, skip it or pass it through
+			r = p.miss(ent, ErrISEFormat, p.Drop_Misses)
 			return
 		}
 		r = ent
@@ -206,19 +209,17 @@ func (p *CiscoISE) processReassemble(ent *entry.Entry) (r *entry.Entry, err erro
 	//add the item to our re-assembler
 	var rmsg remoteISE
 	if err = rmsg.Parse(string(ent.Data)); err != nil {
+		r = p.miss(ent, err, p.Drop_Misses)
 		err = nil // do not pass parsing errors up
-		if !p.Drop_Misses {
-			r = ent
-		}
 	} else if msr, ejected, bad := p.ma.add(rmsg, ent); bad {
-		if !p.Drop_Misses {
-			r = ent
-		}
+		r = p.miss(ent, ErrInvalidRemoteISESeq, p.Drop_Misses)
 	} else if ejected {
 		if rent, ok := msr.meta.(*entry.Entry); ok {
 			rent.Data = []byte(msr.output)
-			if p.fmt(rent, p.filters, p.Attribute_Strip_Header) || !p.Drop_Misses {
+			if p.fmt(rent, p.filters, p.Attribute_Strip_Header) {
 				r = rent
+			} else {
+				r = p.miss(rent, ErrISEFormat, p.Drop_Misses)
 			}
 		}
 	}
@@ -231,7 +232,10 @@ func (p *CiscoISE) flush(force bool) (ents []*entry.Entry) {
 		for _, out := range outputs {
 			if rent, ok := out.meta.(*entry.Entry); ok {
 				rent.Data = []byte(out.output)
-				if p.fmt(rent, p.filters, p.Attribute_Strip_Header) || !p.Drop_Misses {
+				if !p.fmt(rent, p.filters, p.Attribute_Strip_Header) {
+					rent = p.miss(rent, ErrISEFormat, p.Drop_Misses)
+				}
+				if rent != nil {
 					ents = append(ents, rent)
 				}
 			}
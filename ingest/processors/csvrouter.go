@@ -34,6 +34,7 @@ type CSVRouteConfig struct {
 
 type CSVRouter struct {
 	nocloser
+	failureHook
 	CSVRouteConfig
 	routes map[string]entry.EntryTag
 	drops  map[string]struct{}
@@ -107,14 +108,19 @@ func (cr *CSVRouter) processItem(ent *entry.Entry) *entry.Entry {
 	r.LazyQuotes = false
 	r.TrimLeadingSpace = true
 
-	if fields, err := r.Read(); err == nil && cr.CSVRouteConfig.Route_Extraction < len(fields) {
-		if tag, drop, ok := cr.handleExtract(fields[cr.CSVRouteConfig.Route_Extraction]); drop {
-			return nil
-		} else if ok {
-			ent.Tag = tag
-		}
-	} else if cr.Drop_Misses {
+	fields, err := r.Read()
+	if err == nil && cr.CSVRouteConfig.Route_Extraction >= len(fields) {
+		err = ErrInvalidColumnIndex
+	}
+	if err != nil {
+		return cr.miss(ent, err, cr.Drop_Misses)
+	}
+	if tag, drop, ok := cr.handleExtract(fields[cr.CSVRouteConfig.Route_Extraction]); drop {
 		return nil
+	} else if ok {
+		ent.Tag = tag
+	} else {
+		return cr.miss(ent, ErrNoRoute, cr.Drop_Misses)
 	}
 	return ent
 }
@@ -123,11 +129,7 @@ func (cr *CSVRouter) handleExtract(v string) (tag entry.EntryTag, drop, ok bool)
 	//check if we have a tag
 	if tag, ok = cr.routes[string(v)]; !ok {
 		//check if it should be dropped
-		if _, drop = cr.drops[string(v)]; !drop {
-			if cr.Drop_Misses {
-				drop = true
-			}
-		}
+		_, drop = cr.drops[string(v)]
 	}
 
 	return
@@ -0,0 +1,118 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package processors
+
+import (
+	"errors"
+
+	"github.com/gravwell/gravwell/v3/ingest/entry"
+)
+
+const (
+	// enumerated values attached to dead-lettered entries
+	DeadLetterPreprocessorEV = `dlq_preprocessor`
+	DeadLetterErrorEV        = `dlq_error`
+)
+
+var (
+	// errors attached to entries that preprocessors miss
+	ErrNoMatch = errors.New("Entry did not match")
+	ErrNoRoute = errors.New("No route for entry")
+)
+
+// failureReporter is implemented by preprocessors that can hand individual failed entries to
+// a dead-letter handler rather than dropping them or passing them through untouched
+type failureReporter interface {
+	setFailureHandler(func(*entry.Entry, error))
+}
+
+// failureHook is embedded by preprocessors that implement failureReporter
+type failureHook struct {
+	failFn func(*entry.Entry, error)
+}
+
+func (fh *failureHook) setFailureHandler(fn func(*entry.Entry, error)) {
+	fh.failFn = fn
+}
+
+// miss handles an entry the preprocessor could not process.  If a failure handler is installed it takes the
+// entry and nil is returned, otherwise the entry is dropped or returned based on the drop flag.
+func (fh *failureHook) miss(ent *entry.Entry, err error, drop bool) *entry.Entry {
+	if fh.failFn != nil {
+		fh.failFn(ent, err)
+		return nil
+	} else if drop {
+		return nil
+	}
+	return ent
+}
+
+type deadLetter struct {
+	ent *entry.Entry
+	err error
+}
+
+// deadLetterTarget holds the dead-letter configuration of a single item in a ProcessorSet
+type deadLetterTarget struct {
+	name   string
+	tag    entry.EntryTag
+	failed []deadLetter
+}
+
+func (dl *deadLetterTarget) add(ent *entry.Entry, err error) {
+	if ent != nil {
+		dl.failed = append(dl.failed, deadLetter{ent: ent, err: err})
+	}
+}
+
+// drain re-tags the failed entries and hands them to the output buffer
+func (dl *deadLetterTarget) drain(out *[]*entry.Entry) {
+	for _, v := range dl.failed {
+		v.ent.Tag = dl.tag
+		v.ent.AddEnumeratedValue(entry.EnumeratedValue{
+			Name:  DeadLetterPreprocessorEV,
+			Value: entry.StringEnumData(dl.name),
+		})
+		var msg string
+		if v.err != nil {
+			msg = v.err.Error()
+		}
+		v.ent.AddEnumeratedValue(entry.EnumeratedValue{
+			Name:  DeadLetterErrorEV,
+			Value: entry.StringEnumData(msg),
+		})
+		*out = append(*out, v.ent)
+	}
+	dl.failed = dl.failed[:0]
+}
+
+// setDeadLetterBuffer points this set and any nested chains at a shared buffer so that
+// dead-lettered entries bypass the remainder of the preprocessors
+func (pr *ProcessorSet) setDeadLetterBuffer(buff *[]*entry.Entry) {
+	pr.dead = buff
+	for i, p := range pr.set {
+		if c, ok := p.(*Chain); ok {
+			c.ps.setDeadLetterBuffer(buff)
+		}
+		if ctl := pr.ctls[i]; ctl != nil {
+			if c, ok := ctl.jumper.(*Chain); ok {
+				c.ps.setDeadLetterBuffer(buff)
+			}
+		}
+	}
+}
+
+// drainDeadLetters appends any dead-lettered entries to the set
+func (pr *ProcessorSet) drainDeadLetters(set []*entry.Entry) []*entry.Entry {
+	if pr.dead != nil && len(*pr.dead) > 0 {
+		set = append(set, *pr.dead...)
+		*pr.dead = (*pr.dead)[:0]
+	}
+	return set
+}
@@ -0,0 +1,212 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package processors
+
+import (
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/gravwell/gravwell/v3/ingest/entry"
+	"github.com/gravwell/jsonparser"
+)
+
+func checkDeadLetter(t *testing.T, ent *entry.Entry, tag entry.EntryTag, name, errStr string) {
+	t.Helper()
+	if ent.Tag != tag {
+		t.Fatalf("bad dead-letter tag %d != %d", ent.Tag, tag)
+	}
+	checkEVs(t, ent, map[string]interface{}{
+		DeadLetterPreprocessorEV: name,
+		DeadLetterErrorEV:        errStr,
+	})
+}
+
+func TestDeadLetterJsonExtract(t *testing.T) {
+	b := `
+	[preprocessor "extract"]
+		type = jsonextract
+		Extractions = foo
+		Dead-Letter-Tag = dlq
+	[preprocessor "dropall"]
+		type = drop
+	`
+	// the drop processor ensures that dead-lettered entries skip the rest of the set
+	ps, tw := loadTestChain(t, b, `extract`, `dropall`)
+	ents := []*entry.Entry{
+		&entry.Entry{Data: []byte(`{"foo": 1}`)},
+		&entry.Entry{Data: []byte(`{"bar": 2}`)},
+		&entry.Entry{Data: []byte(`not json`)},
+	}
+	if err := ps.ProcessBatch(ents); err != nil {
+		t.Fatal(err)
+	} else if len(tw.ents) != 2 {
+		t.Fatalf("bad output count %d", len(tw.ents))
+	}
+	dlq, _ := tw.NegotiateTag(`dlq`)
+	checkDeadLetter(t, tw.ents[0], dlq, `extract`, ErrExtractionMiss.Error())
+	if string(tw.ents[0].Data) != `{"bar": 2}` {
+		t.Fatalf("dead-lettered entry was modified: %q", tw.ents[0].Data)
+	}
+	checkDeadLetter(t, tw.ents[1], dlq, `extract`, ErrExtractionMiss.Error())
+	stats := ps.PreprocessorStats()
+	if stats[0].EntriesIn != 3 || stats[0].EntriesOut != 1 || stats[1].EntriesIn != 1 {
+		t.Fatalf("bad stats: %+v", stats)
+	}
+}
+
+func TestDeadLetterCiscoISE(t *testing.T) {
+	b := `
+	[preprocessor "ise"]
+		type = cisco_ise
+		Enable-MultiPart-Reassembly=true
+		Output-format=json
+		Dead-Letter-Tag = dlq
+	`
+	ps, tw := loadTestChain(t, b, `ise`)
+	if err := ps.ProcessBatch([]*entry.Entry{&entry.Entry{Data: []byte(`this is not ISE`)}}); err != nil {
+		t.Fatal(err)
+	} else if len(tw.ents) != 1 {
+		t.Fatalf("bad output count %d", len(tw.ents))
+	}
+	dlq, _ := tw.NegotiateTag(`dlq`)
+	checkDeadLetter(t, tw.ents[0], dlq, `ise`, ErrInvalidRemoteISEHeader.Error())
+}
+
+func TestDeadLetterChain(t *testing.T) {
+	b := `
+	[preprocessor "c"]
+		type = chain
+		Preprocessor = extract
+		Preprocessor = dropall
+	[preprocessor "extract"]
+		type = jsonextract
+		Extractions = foo
+		Drop-Misses = true
+		Dead-Letter-Tag = dlq
+	[preprocessor "dropall"]
+		type = drop
+	`
+	ps, tw := loadTestChain(t, b, `c`)
+	if err := ps.ProcessBatch([]*entry.Entry{&entry.Entry{Data: []byte(`{}`)}, &entry.Entry{Data: []byte(`{"foo":1}`)}}); err != nil {
+		t.Fatal(err)
+	} else if len(tw.ents) != 1 {
+		t.Fatalf("bad output count %d", len(tw.ents))
+	}
+	dlq, _ := tw.NegotiateTag(`dlq`)
+	checkDeadLetter(t, tw.ents[0], dlq, `extract`, ErrExtractionMiss.Error())
+}
+
+func TestDeadLetterProcessorError(t *testing.T) {
+	var tw testWriter
+	ps := NewProcessorSet(&tw)
+	ctl, err := processorControlConfig{Dead_Letter_Tag: `dlq`}.compile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctl.dl.tag = 7
+	ps.addProcessor(&errProcessor{}, ctl, `broken`, ``)
+	if err := ps.ProcessBatch([]*entry.Entry{&entry.Entry{Data: []byte(`a`)}, &entry.Entry{Data: []byte(`b`)}}); err != nil {
+		t.Fatalf("dead-letter did not absorb error: %v", err)
+	} else if len(tw.ents) != 2 {
+		t.Fatalf("bad output count %d", len(tw.ents))
+	}
+	for _, ent := range tw.ents {
+		checkDeadLetter(t, ent, 7, `broken`, `broken`)
+	}
+	if stats := ps.PreprocessorStats(); stats[0].Errors != 1 {
+		t.Fatalf("bad stats: %+v", stats[0])
+	}
+}
+
+func TestDeadLetterBadConfig(t *testing.T) {
+	if _, err := (processorControlConfig{Dead_Letter_Tag: `bad tag!`}).compile(); err == nil {
+		t.Fatal("failed to catch bad tag")
+	}
+}
+
+func TestDeadLetterJumpTarget(t *testing.T) {
+	b := `
+	[preprocessor "kv"]
+		type = kv
+		Jump = extract
+	[preprocessor "extract"]
+		type = jsonextract
+		Extractions = foo
+		Match-Regex = "^\\{"
+		Dead-Letter-Tag = dlq
+	[preprocessor "dropall"]
+		type = drop
+		Match-Regex = "^[1{]"
+	`
+	// the jump target keeps its match conditions and dead-lettered entries skip the rest of the set
+	ps, tw := loadTestChain(t, b, `kv`, `dropall`)
+	ents := []*entry.Entry{
+		&entry.Entry{Data: []byte(`{"foo": 1}`)},
+		&entry.Entry{Data: []byte(`{"bar": 2}`)},
+		&entry.Entry{Data: []byte(`plain`)},
+	}
+	if err := ps.ProcessBatch(ents); err != nil {
+		t.Fatal(err)
+	} else if len(tw.ents) != 2 {
+		t.Fatalf("bad output count %d", len(tw.ents))
+	}
+	if string(tw.ents[0].Data) != `plain` || tw.ents[0].EVB.Populated() {
+		t.Fatalf("unmatched entry was modified: %q", tw.ents[0].Data)
+	}
+	dlq, _ := tw.NegotiateTag(`dlq`)
+	checkDeadLetter(t, tw.ents[1], dlq, `extract`, ErrExtractionMiss.Error())
+	if string(tw.ents[1].Data) != `{"bar": 2}` {
+		t.Fatalf("dead-lettered entry was modified: %q", tw.ents[1].Data)
+	}
+	if err := ps.Close(); err != nil {
+		t.Fatal(err)
+	} else if len(tw.ents) != 2 {
+		t.Fatalf("dead-letters left behind on close: %d", len(tw.ents))
+	}
+}
+
+func TestDeadLetterMisses(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  string
+		data string
+		err  error
+	}{
+		{`regexextract`, "type = regexextract\nRegex=\"(?P<foo>\\\\d+)\"\nTemplate=\"${foo}\"", `no digits`, ErrNoMatch},
+		{`jsonsplit`, "type = jsonarraysplit\nExtraction = vals", `{"other": [1]}`, jsonparser.KeyPathNotFoundError},
+		{`csvrouter`, "type = csvrouter\nRoute-Extraction = 1\nRoute = foo:footag", `a,bar,c`, ErrNoRoute},
+		{`regexrouter`, "type = regexrouter\nRegex=\"app=(?P<app>\\\\S+)\"\nRoute-Extraction = app\nRoute = foo:footag", `app=bar`, ErrNoRoute},
+		{`regexrouter`, "type = regexrouter\nRegex=\"app=(?P<app>\\\\S+)\"\nRoute-Extraction = app\nRoute = foo:footag", `nothing`, ErrNoMatch},
+		{`srcrouter`, "type = srcrouter\nRoute = 10.0.0.0/8:footag", `from 192.168.1.1`, ErrNoRoute},
+		{`syslogrouter`, "type = syslogrouter\nTemplate=\"${Hostname}\"", `not syslog`, ErrNotSyslog},
+		{`kv`, "type = kv\nRoute-Key = app\nRoute = foo:footag", `nothing here`, ErrKVNoPairs},
+		{`kv`, "type = kv\nRoute-Key = app\nRoute = foo:footag", `user=bob`, ErrKVNoRouteKey},
+		{`kv`, "type = kv\nRoute-Key = app\nRoute = foo:footag", `app=bar`, ErrNoRoute},
+		{`field-router`, "type = field-router\nRule = \"kv:app == foo => footag\"", `app=bar`, ErrNoRoute},
+	}
+	for _, tc := range tests {
+		// every miss must reach the dead-letter tag, regardless of Drop-Misses
+		for _, drop := range []bool{false, true} {
+			b := "[preprocessor \"p\"]\n" + tc.cfg + fmt.Sprintf("\nDrop-Misses = %v\nDead-Letter-Tag = dlq\n", drop)
+			ps, tw := loadTestChain(t, b, `p`)
+			ent := &entry.Entry{SRC: net.ParseIP(`192.168.1.1`), Data: []byte(tc.data)}
+			if err := ps.ProcessBatch([]*entry.Entry{ent}); err != nil {
+				t.Fatalf("%s: %v", tc.name, err)
+			} else if len(tw.ents) != 1 {
+				t.Fatalf("%s: bad output count %d", tc.name, len(tw.ents))
+			}
+			dlq, _ := tw.NegotiateTag(`dlq`)
+			checkDeadLetter(t, tw.ents[0], dlq, `p`, tc.err.Error())
+			if string(tw.ents[0].Data) != tc.data {
+				t.Fatalf("%s: dead-lettered entry was modified: %q", tc.name, tw.ents[0].Data)
+			}
+		}
+	}
+}
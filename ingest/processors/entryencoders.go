@@ -10,24 +10,59 @@ package processors
 
 import (
 	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/rand"
+	"encoding/binary"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"os"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/crewjam/rfc5424"
 	"github.com/gravwell/gravwell/v3/ingest/entry"
 )
 
 const (
-	encRaw    string = `raw`
-	encJSON   string = `json`
-	encSYSLOG string = `syslog`
+	encRaw     string = `raw`
+	encJSON    string = `json`
+	encSYSLOG  string = `syslog`
+	encGELF    string = `gelf`
+	encCEF     string = `cef`
+	encRFC5424 string = `rfc5424`
+
+	gelfVersion               = `1.1`
+	gelfLevelInfo             = 6
+	gelfChunkMagic0      byte = 0x1e
+	gelfChunkMagic1      byte = 0x0f
+	gelfChunkHdrSize          = 12
+	gelfMaxChunks             = 128
+	defaultGELFChunkSize      = 1420 //fits in a typical 1500 byte MTU
+
+	defaultCEFVendor   = `Gravwell`
+	defaultCEFProduct  = `gravwell`
+	defaultCEFVersion  = `1.0`
+	defaultCEFSeverity = 5
+	maxCEFSeverity     = 10
+
+	defaultSDID       = `gw@1`
+	maxSDNameLen      = 32
+	rfc5424MaxAppName = 48
+
+	rfc5424Priority rfc5424.Priority = 134 //local0.info, matching the syslog encoder
 )
 
 var (
-	ErrUnknownType   = errors.New("Unknown entry encoder type")
-	ErrInvalidWriter = errors.New("Writer is nil")
+	ErrUnknownType     = errors.New("Unknown entry encoder type")
+	ErrInvalidWriter   = errors.New("Writer is nil")
+	ErrGELFChunkSize   = errors.New("GELF-Chunk-Size is too small")
+	ErrGELFTooLarge    = errors.New("GELF message requires more than 128 chunks")
+	ErrInvalidSeverity = errors.New("CEF-Severity must be between 0 and 10")
+	ErrInvalidSDID     = errors.New("RFC5424-SD-ID is not a valid structured data ID")
 )
 
 type EntryEncoder interface {
@@ -171,3 +206,339 @@ func (se *syslogEncoder) Encode(ent *entry.Entry) (err error) {
 func (se *syslogEncoder) Reset(wtr io.Writer) {
 	se.wtr = wtr
 }
+
+// localHostname is used as the host for entries that do not carry a source address
+func localHostname() string {
+	if h, err := os.Hostname(); err == nil && h != `` {
+		return h
+	}
+	return `gravwell`
+}
+
+func entryHost(ent *entry.Entry, def string) string {
+	if len(ent.SRC) > 0 && !ent.SRC.IsUnspecified() {
+		return ent.SRC.String()
+	}
+	return def
+}
+
+// evNative returns numeric and boolean enumerated values as native types and everything else as a string
+func evNative(ed entry.EnumeratedData) interface{} {
+	switch v := ed.Interface().(type) {
+	case bool, uint8, int8, int16, uint16, int32, uint32, int64, uint64, float32, float64:
+		return v
+	}
+	return ed.String()
+}
+
+// sanitizeName replaces any byte not accepted by the valid function with an underscore
+func sanitizeName(s string, valid func(byte) bool) string {
+	b := []byte(s)
+	for i := range b {
+		if !valid(b[i]) {
+			b[i] = '_'
+		}
+	}
+	return string(b)
+}
+
+func isWordByte(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') || c == '_'
+}
+
+// gelfEncoder emits GELF 1.1 messages.  Stream transports delimit messages with a null byte,
+// UDP transports send each message as a datagram and chunk messages that exceed the chunk size.
+type gelfEncoder struct {
+	wtr      io.Writer
+	tt       *tagTrans
+	host     string
+	delim    []byte
+	udp      bool
+	chunk    int
+	compress bool
+	bb       bytes.Buffer
+	id       uint64
+}
+
+func newGELFEncoder(wtr io.Writer, tgr Tagger, delim []byte, udp bool, chunk int, compress bool) (*gelfEncoder, error) {
+	if wtr == nil {
+		return nil, ErrInvalidWriter
+	} else if chunk == 0 {
+		chunk = defaultGELFChunkSize
+	} else if chunk <= gelfChunkHdrSize {
+		return nil, ErrGELFChunkSize
+	}
+	ge := &gelfEncoder{
+		wtr:      wtr,
+		tt:       newTagTrans(tgr),
+		host:     localHostname(),
+		delim:    delim,
+		udp:      udp,
+		chunk:    chunk,
+		compress: compress,
+	}
+	//message IDs only need to be unique per sender, start from a random point
+	var seed [8]byte
+	rand.Read(seed[:])
+	ge.id = binary.LittleEndian.Uint64(seed[:])
+	return ge, nil
+}
+
+func gelfFieldName(name string) string {
+	name = sanitizeName(name, func(c byte) bool { return isWordByte(c) || c == '.' || c == '-' })
+	if name == `id` {
+		name = `ev_id` //_id is reserved by GELF
+	}
+	return `_` + name
+}
+
+func (ge *gelfEncoder) message(ent *entry.Entry) ([]byte, error) {
+	msg := map[string]interface{}{
+		`version`:       gelfVersion,
+		`host`:          entryHost(ent, ge.host),
+		`short_message`: string(ent.Data),
+		`timestamp`:     float64(ent.TS.StandardTime().UnixNano()) / 1e9,
+		`level`:         gelfLevelInfo,
+		`_tag`:          ge.tt.TagName(ent.Tag),
+	}
+	for _, ev := range ent.EnumeratedValues() {
+		msg[gelfFieldName(ev.Name)] = evNative(ev.Value)
+	}
+	return json.Marshal(msg)
+}
+
+func (ge *gelfEncoder) Encode(ent *entry.Entry) (err error) {
+	if ent == nil {
+		return
+	}
+	var b []byte
+	if b, err = ge.message(ent); err != nil {
+		return
+	}
+	if !ge.udp {
+		if ge.delim != nil {
+			b = append(b, ge.delim...)
+		}
+		return writeAll(ge.wtr, b)
+	}
+	if ge.compress {
+		ge.bb.Reset()
+		gz := gzip.NewWriter(&ge.bb)
+		if _, err = gz.Write(b); err == nil {
+			err = gz.Close()
+		}
+		if err != nil {
+			return
+		}
+		b = ge.bb.Bytes()
+	}
+	return ge.writeDatagrams(b)
+}
+
+// writeDatagrams sends a message as a single datagram or as a set of GELF chunks.
+// Messages that need more than 128 chunks cannot be reassembled by receivers, they are not sent
+// and ErrGELFTooLarge is returned.
+func (ge *gelfEncoder) writeDatagrams(b []byte) (err error) {
+	if len(b) <= ge.chunk {
+		_, err = ge.wtr.Write(b)
+		return
+	}
+	sz := ge.chunk - gelfChunkHdrSize
+	cnt := (len(b) + sz - 1) / sz
+	if cnt > gelfMaxChunks {
+		return ErrGELFTooLarge
+	}
+	ge.id++
+	buff := make([]byte, ge.chunk)
+	buff[0], buff[1] = gelfChunkMagic0, gelfChunkMagic1
+	binary.BigEndian.PutUint64(buff[2:], ge.id)
+	buff[11] = byte(cnt)
+	for i := 0; i < cnt; i++ {
+		buff[10] = byte(i)
+		n := copy(buff[gelfChunkHdrSize:], b[i*sz:])
+		if _, err = ge.wtr.Write(buff[:gelfChunkHdrSize+n]); err != nil {
+			return
+		}
+	}
+	return
+}
+
+func (ge *gelfEncoder) Reset(wtr io.Writer) {
+	ge.wtr = wtr
+}
+
+// writeAll handles writers that accept partial writes
+func writeAll(wtr io.Writer, b []byte) error {
+	for len(b) > 0 {
+		n, err := wtr.Write(b)
+		if err != nil {
+			return err
+		} else if n <= 0 {
+			return io.ErrShortWrite
+		}
+		b = b[n:]
+	}
+	return nil
+}
+
+// cefEncoder emits ArcSight Common Event Format records, the tag is used as the signature ID and name
+// and enumerated values are mapped into extension keys alongside rt, src, and msg
+type cefEncoder struct {
+	wtr    io.Writer
+	tt     *tagTrans
+	prefix string //pre-escaped version, vendor, product, and device version
+	sev    string
+	bb     bytes.Buffer
+}
+
+var (
+	cefHeaderEscaper = strings.NewReplacer(`\`, `\\`, `|`, `\|`, "\n", ` `, "\r", ` `)
+	cefExtEscaper    = strings.NewReplacer(`\`, `\\`, `=`, `\=`, "\n", `\n`, "\r", `\r`)
+)
+
+// extension keys populated by the encoder, enumerated values with these names are skipped
+var cefReservedKeys = map[string]bool{`rt`: true, `src`: true, `msg`: true}
+
+func newCEFEncoder(wtr io.Writer, tgr Tagger, vendor, product, version string, severity uint) (*cefEncoder, error) {
+	if wtr == nil {
+		return nil, ErrInvalidWriter
+	} else if severity > maxCEFSeverity {
+		return nil, ErrInvalidSeverity
+	}
+	return &cefEncoder{
+		wtr: wtr,
+		tt:  newTagTrans(tgr),
+		prefix: fmt.Sprintf("CEF:0|%s|%s|%s|", cefHeaderEscaper.Replace(vendor),
+			cefHeaderEscaper.Replace(product), cefHeaderEscaper.Replace(version)),
+		sev: strconv.FormatUint(uint64(severity), 10),
+	}, nil
+}
+
+func cefKey(name string) string {
+	b := make([]byte, 0, len(name))
+	for i := 0; i < len(name); i++ {
+		if c := name[i]; isWordByte(c) && c != '_' {
+			b = append(b, c)
+		}
+	}
+	return string(b)
+}
+
+func (ce *cefEncoder) Encode(ent *entry.Entry) (err error) {
+	if ent == nil {
+		return
+	}
+	tag := cefHeaderEscaper.Replace(ce.tt.TagName(ent.Tag))
+	ce.bb.Reset()
+	ce.bb.WriteString(ce.prefix)
+	ce.bb.WriteString(tag)
+	ce.bb.WriteByte('|')
+	ce.bb.WriteString(tag)
+	ce.bb.WriteByte('|')
+	ce.bb.WriteString(ce.sev)
+	ce.bb.WriteString(`|rt=`)
+	ce.bb.WriteString(strconv.FormatInt(ent.TS.StandardTime().UnixNano()/int64(time.Millisecond), 10))
+	if len(ent.SRC) > 0 {
+		ce.bb.WriteString(` src=`)
+		ce.bb.WriteString(ent.SRC.String())
+	}
+	for _, ev := range ent.EnumeratedValues() {
+		if k := cefKey(ev.Name); k != `` && !cefReservedKeys[k] {
+			ce.bb.WriteByte(' ')
+			ce.bb.WriteString(k)
+			ce.bb.WriteByte('=')
+			ce.bb.WriteString(cefExtEscaper.Replace(ev.Value.String()))
+		}
+	}
+	ce.bb.WriteString(` msg=`)
+	ce.bb.WriteString(cefExtEscaper.Replace(string(ent.Data)))
+	ce.bb.WriteByte('\n')
+	return writeAll(ce.wtr, ce.bb.Bytes())
+}
+
+func (ce *cefEncoder) Reset(wtr io.Writer) {
+	ce.wtr = wtr
+}
+
+// rfc5424Encoder emits RFC5424 syslog messages with the tag as the app name
+// and enumerated values rendered as SD-PARAMs in a single SD-ELEMENT
+type rfc5424Encoder struct {
+	wtr  io.Writer
+	tt   *tagTrans
+	host string
+	sdid string
+}
+
+func validSDName(s string) bool {
+	if s == `` || len(s) > maxSDNameLen {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; c < 33 || c > 126 || c == '=' || c == ']' || c == '"' || c == ' ' {
+			return false
+		}
+	}
+	return true
+}
+
+func sdParamName(name string) string {
+	name = sanitizeName(name, func(c byte) bool { return c > 32 && c < 127 && c != '=' && c != ']' && c != '"' })
+	if len(name) > maxSDNameLen {
+		name = name[:maxSDNameLen]
+	}
+	return name
+}
+
+func newRFC5424Encoder(wtr io.Writer, tgr Tagger, sdid string) (*rfc5424Encoder, error) {
+	if wtr == nil {
+		return nil, ErrInvalidWriter
+	} else if sdid == `` {
+		sdid = defaultSDID
+	} else if !validSDName(sdid) {
+		return nil, ErrInvalidSDID
+	}
+	return &rfc5424Encoder{
+		wtr:  wtr,
+		tt:   newTagTrans(tgr),
+		host: localHostname(),
+		sdid: sdid,
+	}, nil
+}
+
+func (re *rfc5424Encoder) Encode(ent *entry.Entry) (err error) {
+	if ent == nil {
+		return
+	}
+	app := re.tt.TagName(ent.Tag)
+	if len(app) > rfc5424MaxAppName {
+		app = app[:rfc5424MaxAppName]
+	}
+	m := rfc5424.Message{
+		Priority:  rfc5424Priority,
+		Timestamp: ent.TS.StandardTime(),
+		Hostname:  entryHost(ent, re.host),
+		AppName:   app,
+		Message:   ent.Data,
+	}
+	if evs := ent.EnumeratedValues(); len(evs) > 0 {
+		sd := rfc5424.StructuredData{
+			ID:         re.sdid,
+			Parameters: make([]rfc5424.SDParam, 0, len(evs)),
+		}
+		for _, ev := range evs {
+			if n := sdParamName(ev.Name); n != `` {
+				sd.Parameters = append(sd.Parameters, rfc5424.SDParam{Name: n, Value: ev.Value.String()})
+			}
+		}
+		m.StructuredData = []rfc5424.StructuredData{sd}
+	}
+	var b []byte
+	if b, err = m.MarshalBinary(); err != nil {
+		return
+	}
+	return writeAll(re.wtr, append(b, '\n'))
+}
+
+func (re *rfc5424Encoder) Reset(wtr io.Writer) {
+	re.wtr = wtr
+}
@@ -0,0 +1,268 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package processors
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/crewjam/rfc5424"
+	"github.com/gravwell/gravwell/v3/ingest/entry"
+)
+
+// datagramRecorder records each write as a separate datagram
+type datagramRecorder struct {
+	grams [][]byte
+}
+
+func (dr *datagramRecorder) Write(b []byte) (int, error) {
+	dr.grams = append(dr.grams, append([]byte(nil), b...))
+	return len(b), nil
+}
+
+func testEncoderEntry(tgr Tagger, data string) *entry.Entry {
+	tag, _ := tgr.NegotiateTag(`fw`)
+	ent := &entry.Entry{
+		TS:   entry.FromStandard(time.Date(2024, 3, 4, 5, 6, 7, 8000000, time.UTC)),
+		SRC:  net.ParseIP(`192.168.1.1`),
+		Tag:  tag,
+		Data: []byte(data),
+	}
+	ent.AddEnumeratedValueEx(`user`, `bob`)
+	ent.AddEnumeratedValueEx(`count`, int64(42))
+	ent.AddEnumeratedValueEx(`id`, `abc`)
+	return ent
+}
+
+func TestGELFEncoderStream(t *testing.T) {
+	tgr := &testTagger{}
+	var bb bytes.Buffer
+	enc, err := newGELFEncoder(&bb, tgr, []byte{0}, false, 0, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = enc.Encode(testEncoderEntry(tgr, `hello`)); err != nil {
+		t.Fatal(err)
+	}
+	b := bb.Bytes()
+	if b[len(b)-1] != 0 {
+		t.Fatal("missing null delimiter")
+	}
+	var msg map[string]interface{}
+	if err = json.Unmarshal(b[:len(b)-1], &msg); err != nil {
+		t.Fatal(err)
+	}
+	exp := map[string]interface{}{
+		`version`:       `1.1`,
+		`host`:          `192.168.1.1`,
+		`short_message`: `hello`,
+		`timestamp`:     1709528767.008,
+		`level`:         float64(6),
+		`_tag`:          `fw`,
+		`_user`:         `bob`,
+		`_count`:        float64(42),
+		`_ev_id`:        `abc`,
+	}
+	for k, v := range exp {
+		if msg[k] != v {
+			t.Fatalf("bad %s: %v != %v", k, msg[k], v)
+		}
+	}
+}
+
+func TestGELFEncoderChunked(t *testing.T) {
+	tgr := &testTagger{}
+	var dr datagramRecorder
+	enc, err := newGELFEncoder(&dr, tgr, nil, true, 64, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	//small messages are a single datagram
+	ent := testEncoderEntry(tgr, `x`)
+	ent.ClearEnumeratedValues()
+	enc.chunk = 512
+	if err = enc.Encode(ent); err != nil {
+		t.Fatal(err)
+	} else if len(dr.grams) != 1 || dr.grams[0][0] != '{' {
+		t.Fatalf("bad single datagram: %q", dr.grams)
+	}
+
+	dr.grams = nil
+	enc.chunk = 64
+	if err = enc.Encode(testEncoderEntry(tgr, strings.Repeat(`a`, 300))); err != nil {
+		t.Fatal(err)
+	} else if len(dr.grams) < 2 {
+		t.Fatalf("message was not chunked: %d", len(dr.grams))
+	}
+	var msg []byte
+	id := binary.BigEndian.Uint64(dr.grams[0][2:10])
+	for i, g := range dr.grams {
+		if len(g) > 64 || g[0] != gelfChunkMagic0 || g[1] != gelfChunkMagic1 {
+			t.Fatalf("bad chunk header %d", i)
+		} else if binary.BigEndian.Uint64(g[2:10]) != id || int(g[10]) != i || int(g[11]) != len(dr.grams) {
+			t.Fatalf("bad chunk sequence %d: %v", i, g[:12])
+		}
+		msg = append(msg, g[gelfChunkHdrSize:]...)
+	}
+	var v map[string]interface{}
+	if err = json.Unmarshal(msg, &v); err != nil {
+		t.Fatal(err)
+	} else if v[`short_message`] != strings.Repeat(`a`, 300) {
+		t.Fatalf("bad reassembled message: %v", v)
+	}
+
+	//messages that need too many chunks are refused
+	dr.grams = nil
+	if err = enc.Encode(testEncoderEntry(tgr, strings.Repeat(`a`, 64*gelfMaxChunks))); err != ErrGELFTooLarge {
+		t.Fatalf("bad error on oversized message: %v", err)
+	} else if len(dr.grams) != 0 {
+		t.Fatalf("oversized message was sent in %d chunks", len(dr.grams))
+	}
+}
+
+func TestGELFEncoderCompressed(t *testing.T) {
+	tgr := &testTagger{}
+	var dr datagramRecorder
+	enc, err := newGELFEncoder(&dr, tgr, nil, true, 0, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = enc.Encode(testEncoderEntry(tgr, `compressed`)); err != nil {
+		t.Fatal(err)
+	} else if len(dr.grams) != 1 {
+		t.Fatalf("bad datagram count %d", len(dr.grams))
+	}
+	gz, err := gzip.NewReader(bytes.NewReader(dr.grams[0]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := io.ReadAll(gz)
+	if err != nil {
+		t.Fatal(err)
+	} else if !bytes.Contains(b, []byte(`"short_message":"compressed"`)) {
+		t.Fatalf("bad message %s", b)
+	}
+}
+
+func TestCEFEncoder(t *testing.T) {
+	tgr := &testTagger{}
+	var bb bytes.Buffer
+	enc, err := newCEFEncoder(&bb, tgr, `Grav|well`, `gw`, `1.0`, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ent := testEncoderEntry(tgr, "a=b\\c\nd")
+	ent.AddEnumeratedValueEx(`msg`, `ignored`)
+	ent.AddEnumeratedValueEx(`dst-host`, `x=y`)
+	if err = enc.Encode(ent); err != nil {
+		t.Fatal(err)
+	}
+	exp := `CEF:0|Grav\|well|gw|1.0|fw|fw|7|rt=1709528767008 src=192.168.1.1 user=bob count=42 id=abc dsthost=x\=y msg=a\=b\\c\nd` + "\n"
+	if bb.String() != exp {
+		t.Fatalf("bad CEF output:\n%q\n%q", bb.String(), exp)
+	}
+	if _, err = newCEFEncoder(&bb, tgr, ``, ``, ``, 11); err != ErrInvalidSeverity {
+		t.Fatalf("failed to catch bad severity: %v", err)
+	}
+}
+
+func TestRFC5424Encoder(t *testing.T) {
+	tgr := &testTagger{}
+	var bb bytes.Buffer
+	enc, err := newRFC5424Encoder(&bb, tgr, `test@32473`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ent := testEncoderEntry(tgr, `hello world`)
+	ent.AddEnumeratedValueEx(`quoted"name`, `a "value"]`)
+	if err = enc.Encode(ent); err != nil {
+		t.Fatal(err)
+	}
+	exp := `<134>1 2024-03-04T05:06:07.008Z 192.168.1.1 fw - - [test@32473 user="bob" count="42" id="abc" quoted_name="a \"value\"\]"] hello world` + "\n"
+	if bb.String() != exp {
+		t.Fatalf("bad RFC5424 output:\n%q\n%q", bb.String(), exp)
+	}
+	var m rfc5424.Message
+	if err = m.UnmarshalBinary(bytes.TrimSpace(bb.Bytes())); err != nil {
+		t.Fatal(err)
+	} else if len(m.StructuredData) != 1 || len(m.StructuredData[0].Parameters) != 4 {
+		t.Fatalf("bad structured data: %+v", m.StructuredData)
+	} else if v := m.StructuredData[0].Parameters[3].Value; v != `a "value"]` {
+		t.Fatalf("bad SD-PARAM value %q", v)
+	}
+	if _, err = newRFC5424Encoder(&bb, tgr, `bad id`); err != ErrInvalidSDID {
+		t.Fatalf("failed to catch bad SD-ID: %v", err)
+	}
+}
+
+func TestForwarderFormatConfig(t *testing.T) {
+	good := []ForwarderConfig{
+		{Target: `127.0.0.1:12201`, Protocol: protoUDP, Format: `GELF`, GELF_Chunk_Size: 512, GELF_Compress: true},
+		{Target: `127.0.0.1:514`, Format: `cef`, CEF_Severity: `0`},
+		{Target: `127.0.0.1:514`, Format: `rfc5424`},
+	}
+	for i, c := range good {
+		if err := c.Validate(); err != nil {
+			t.Fatalf("config %d failed: %v", i, err)
+		}
+	}
+	bad := []ForwarderConfig{
+		{Target: `127.0.0.1:514`, Format: `xml`},
+		{Target: `127.0.0.1:12201`, Format: `gelf`, GELF_Chunk_Size: 8},
+		{Target: `127.0.0.1:514`, Format: `cef`, CEF_Severity: `11`},
+		{Target: `127.0.0.1:514`, Format: `rfc5424`, RFC5424_SD_ID: `a=b`},
+	}
+	for i, c := range bad {
+		if err := c.Validate(); err == nil {
+			t.Fatalf("failed to catch bad config %d", i)
+		}
+	}
+}
+
+func TestForwarderGELFUDP(t *testing.T) {
+	pc, err := net.ListenPacket(`udp`, `127.0.0.1:0`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pc.Close()
+	tgr := &testTagger{}
+	fwd, err := NewForwarder(ForwarderConfig{
+		Target:          pc.LocalAddr().String(),
+		Protocol:        protoUDP,
+		Format:          encGELF,
+		GELF_Chunk_Size: 512,
+	}, tgr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	//the oversized message is refused without stalling the forwarder
+	fwd.Process([]*entry.Entry{
+		testEncoderEntry(tgr, strings.Repeat(`a`, 512*gelfMaxChunks)),
+		testEncoderEntry(tgr, `over udp`),
+	})
+	buff := make([]byte, 2048)
+	pc.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, _, err := pc.ReadFrom(buff)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(buff[:n], []byte(`"short_message":"over udp"`)) || buff[n-1] != '}' {
+		t.Fatalf("bad datagram %q", buff[:n])
+	}
+	if err = fwd.Close(); err != ErrGELFTooLarge {
+		t.Fatalf("oversized message was not reported: %v", err)
+	}
+}
@@ -0,0 +1,591 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package processors
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gravwell/gravwell/v3/ingest"
+	"github.com/gravwell/gravwell/v3/ingest/config"
+	"github.com/gravwell/gravwell/v3/ingest/entry"
+	"github.com/gravwell/jsonparser"
+)
+
+const (
+	FieldRouterProcessor = `field-router`
+
+	fieldRouterDrop    = `drop`
+	fieldRouterArrow   = `=>`
+	fieldRouterJSONPfx = `json:`
+	fieldRouterEVPfx   = `ev:`
+	fieldRouterKVPfx   = `kv:`
+	fieldRouterSrc     = `src`
+	fieldRouterData    = `data`
+)
+
+var (
+	ErrMissingRules    = errors.New("Missing Rule specifications")
+	ErrUnexpectedToken = errors.New("Unexpected token")
+	ErrUnexpectedEnd   = errors.New("Unexpected end of expression")
+)
+
+// FieldRouterConfig defines an ordered set of rules, each rule is an expression
+// followed by => and either a tag name or drop.  For example:
+//
+//	Rule=`json:app == "web" AND src in 10.0.0.0/8 => webtag`
+//	Rule=`ev:severity >= 5 OR kv:level prefix err => alerts`
+//	Rule=`NOT json:user => drop`
+type FieldRouterConfig struct {
+	Rule              []string
+	Drop_Misses       bool   // drop entries that do not match any rule
+	KV_Pair_Delimiter string // pair delimiter used for kv: fields, default is any whitespace
+	KV_Delimiter      string // key/value delimiter used for kv: fields, default is =
+}
+
+func FieldRouterLoadConfig(vc *config.VariableConfig) (c FieldRouterConfig, err error) {
+	if err = vc.MapTo(&c); err == nil {
+		_, err = c.validate()
+	}
+	return
+}
+
+type fieldRule struct {
+	expr fieldNode
+	tag  string
+	drop bool
+}
+
+func (c FieldRouterConfig) validate() (rules []fieldRule, err error) {
+	if len(c.Rule) == 0 {
+		err = ErrMissingRules
+		return
+	}
+	for _, r := range c.Rule {
+		var fr fieldRule
+		if fr, err = compileFieldRule(r); err != nil {
+			err = fmt.Errorf("Invalid rule %q: %w", r, err)
+			return
+		}
+		rules = append(rules, fr)
+	}
+	kvc := KVConfig{Pair_Delimiter: c.KV_Pair_Delimiter, KV_Delimiter: c.KV_Delimiter}
+	_, err = kvc.validate()
+	return
+}
+
+func compileFieldRule(v string) (fr fieldRule, err error) {
+	idx := strings.LastIndex(v, fieldRouterArrow)
+	if idx == -1 {
+		err = errors.New("missing => action")
+		return
+	}
+	action := strings.TrimSpace(v[idx+len(fieldRouterArrow):])
+	if action == `` {
+		err = errors.New("missing action")
+		return
+	} else if strings.ToLower(action) == fieldRouterDrop {
+		fr.drop = true
+	} else if err = ingest.CheckTag(action); err != nil {
+		return
+	} else {
+		fr.tag = action
+	}
+	fr.expr, err = compileFieldExpr(v[:idx])
+	return
+}
+
+type FieldRouter struct {
+	nocloser
+	failureHook
+	FieldRouterConfig
+	rules []fieldRule
+	tags  []entry.EntryTag
+	ctx   fieldContext
+}
+
+func NewFieldRouter(cfg FieldRouterConfig, tagger Tagger) (*FieldRouter, error) {
+	fr := &FieldRouter{}
+	if err := fr.init(cfg, tagger); err != nil {
+		return nil, err
+	}
+	return fr, nil
+}
+
+func (fr *FieldRouter) Config(v interface{}, tagger Tagger) (err error) {
+	if v == nil {
+		err = ErrNilConfig
+	} else if cfg, ok := v.(FieldRouterConfig); ok {
+		err = fr.init(cfg, tagger)
+	} else {
+		err = fmt.Errorf("Invalid configuration, unknown type type %T", v)
+	}
+	return
+}
+
+func (fr *FieldRouter) init(cfg FieldRouterConfig, tagger Tagger) (err error) {
+	var rules []fieldRule
+	var kvp *KV
+	if rules, err = cfg.validate(); err != nil {
+		return
+	} else if kvp, err = NewKV(KVConfig{Pair_Delimiter: cfg.KV_Pair_Delimiter, KV_Delimiter: cfg.KV_Delimiter}, nil); err != nil {
+		return
+	}
+	tags := make([]entry.EntryTag, len(rules))
+	for i, r := range rules {
+		if r.drop {
+			continue
+		}
+		if tags[i], err = tagger.NegotiateTag(r.tag); err != nil {
+			err = fmt.Errorf("Failed to get tag %s: %v", r.tag, err)
+			return
+		}
+	}
+	fr.FieldRouterConfig = cfg
+	fr.rules = rules
+	fr.tags = tags
+	fr.ctx.kvp = kvp
+	return
+}
+
+func (fr *FieldRouter) Process(ents []*entry.Entry) (rset []*entry.Entry, err error) {
+	if len(ents) == 0 {
+		return
+	}
+	rset = ents[:0]
+	for _, ent := range ents {
+		if ent == nil {
+			continue
+		} else if ent = fr.processItem(ent); ent != nil {
+			rset = append(rset, ent)
+		}
+	}
+	return
+}
+
+func (fr *FieldRouter) processItem(ent *entry.Entry) *entry.Entry {
+	fr.ctx.reset(ent)
+	for i := range fr.rules {
+		if fr.rules[i].expr.eval(&fr.ctx) {
+			if fr.rules[i].drop {
+				return nil
+			}
+			ent.Tag = fr.tags[i]
+			return ent
+		}
+	}
+	return fr.miss(ent, ErrNoRoute, fr.Drop_Misses)
+}
+
+// fieldContext carries per-entry state so that kv parsing happens at most once per entry
+type fieldContext struct {
+	ent      *entry.Entry
+	kvp      *KV
+	kvParsed bool
+	kvs      []kvPair
+}
+
+func (fc *fieldContext) reset(ent *entry.Entry) {
+	fc.ent = ent
+	fc.kvParsed = false
+	fc.kvs = fc.kvs[:0]
+}
+
+func (fc *fieldContext) kv(key string) (v []byte, ok bool) {
+	if !fc.kvParsed {
+		fc.kvs = fc.kvp.parse(fc.ent.Data, fc.kvs[:0])
+		fc.kvParsed = true
+	}
+	for _, p := range fc.kvs {
+		if p.key == key {
+			v, ok = []byte(p.val), true
+		}
+	}
+	return
+}
+
+// fieldRef identifies where a value is pulled from
+type fieldRef struct {
+	kind byte // 'j'son, 'e'numerated value, 'k'v, 's'rc, 'd'ata
+	keys []string
+	name string
+}
+
+func parseFieldRef(v string) (fr fieldRef, err error) {
+	lv := strings.ToLower(v)
+	switch {
+	case strings.HasPrefix(lv, fieldRouterJSONPfx):
+		fr.kind = 'j'
+		if fr.keys = unquoteFields(splitRespectQuotes(v[len(fieldRouterJSONPfx):], dotSplitter)); len(fr.keys) == 0 {
+			err = fmt.Errorf("invalid JSON field %q", v)
+		}
+	case strings.HasPrefix(lv, fieldRouterEVPfx):
+		fr.kind = 'e'
+		if fr.name = v[len(fieldRouterEVPfx):]; fr.name == `` {
+			err = fmt.Errorf("invalid enumerated value field %q", v)
+		}
+	case strings.HasPrefix(lv, fieldRouterKVPfx):
+		fr.kind = 'k'
+		if fr.name = v[len(fieldRouterKVPfx):]; fr.name == `` {
+			err = fmt.Errorf("invalid kv field %q", v)
+		}
+	case lv == fieldRouterSrc:
+		fr.kind = 's'
+	case lv == fieldRouterData:
+		fr.kind = 'd'
+	default:
+		err = fmt.Errorf("unknown field %q, fields must be json:, ev:, kv:, src, or data", v)
+	}
+	return
+}
+
+func (fr fieldRef) get(fc *fieldContext) (v []byte, ok bool) {
+	switch fr.kind {
+	case 'j':
+		val, vt, _, err := jsonparser.Get(fc.ent.Data, fr.keys...)
+		if err != nil {
+			return
+		}
+		if vt == jsonparser.String {
+			if s, err := jsonparser.ParseString(val); err == nil {
+				val = []byte(s)
+			}
+		}
+		v, ok = val, true
+	case 'e':
+		var ev entry.EnumeratedValue
+		if ev, ok = fc.ent.EVB.Get(fr.name); ok {
+			v = []byte(ev.Value.String())
+		}
+	case 'k':
+		v, ok = fc.kv(fr.name)
+	case 's':
+		if fc.ent.SRC != nil {
+			v, ok = []byte(fc.ent.SRC.String()), true
+		}
+	case 'd':
+		v, ok = fc.ent.Data, true
+	}
+	return
+}
+
+// fieldNode is a compiled expression node
+type fieldNode interface {
+	eval(*fieldContext) bool
+}
+
+type andNode struct {
+	l, r fieldNode
+}
+
+func (n andNode) eval(fc *fieldContext) bool {
+	return n.l.eval(fc) && n.r.eval(fc)
+}
+
+type orNode struct {
+	l, r fieldNode
+}
+
+func (n orNode) eval(fc *fieldContext) bool {
+	return n.l.eval(fc) || n.r.eval(fc)
+}
+
+type notNode struct {
+	n fieldNode
+}
+
+func (n notNode) eval(fc *fieldContext) bool {
+	return !n.n.eval(fc)
+}
+
+// existsNode is true if the field is present
+type existsNode struct {
+	ref fieldRef
+}
+
+func (n existsNode) eval(fc *fieldContext) bool {
+	_, ok := n.ref.get(fc)
+	return ok
+}
+
+type cmpNode struct {
+	ref  fieldRef
+	op   string
+	val  []byte
+	num  float64
+	isNm bool
+	rx   *regexp.Regexp
+	nt   *net.IPNet
+}
+
+func newCmpNode(ref fieldRef, op, val string) (n *cmpNode, err error) {
+	n = &cmpNode{ref: ref, op: op, val: []byte(val)}
+	if f, lerr := strconv.ParseFloat(val, 64); lerr == nil {
+		n.num, n.isNm = f, true
+	}
+	switch op {
+	case `==`, `!=`, `prefix`, `suffix`, `contains`:
+	case `<`, `<=`, `>`, `>=`:
+		if !n.isNm {
+			err = fmt.Errorf("%s requires a numeric value, got %q", op, val)
+		}
+	case `~`, `!~`:
+		n.rx, err = regexp.Compile(val)
+	case `in`:
+		if _, n.nt, err = net.ParseCIDR(val); err != nil {
+			if ip := net.ParseIP(val); ip != nil {
+				bits := 128
+				if ip.To4() != nil {
+					ip, bits = ip.To4(), 32
+				}
+				n.nt = &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}
+				err = nil
+			}
+		}
+	default:
+		err = fmt.Errorf("unknown operator %q", op)
+	}
+	return
+}
+
+func (n *cmpNode) eval(fc *fieldContext) bool {
+	v, ok := n.ref.get(fc)
+	if !ok {
+		// a missing field is never equal to anything
+		return n.op == `!=` || n.op == `!~`
+	}
+	switch n.op {
+	case `==`:
+		return n.equal(v)
+	case `!=`:
+		return !n.equal(v)
+	case `prefix`:
+		return bytes.HasPrefix(v, n.val)
+	case `suffix`:
+		return bytes.HasSuffix(v, n.val)
+	case `contains`:
+		return bytes.Contains(v, n.val)
+	case `~`:
+		return n.rx.Match(v)
+	case `!~`:
+		return !n.rx.Match(v)
+	case `in`:
+		ip := net.ParseIP(strings.TrimSpace(string(v)))
+		return ip != nil && n.nt.Contains(ip)
+	}
+	f, err := strconv.ParseFloat(strings.TrimSpace(string(v)), 64)
+	if err != nil {
+		return false
+	}
+	switch n.op {
+	case `<`:
+		return f < n.num
+	case `<=`:
+		return f <= n.num
+	case `>`:
+		return f > n.num
+	case `>=`:
+		return f >= n.num
+	}
+	return false
+}
+
+func (n *cmpNode) equal(v []byte) bool {
+	if bytes.Equal(v, n.val) {
+		return true
+	} else if n.isNm {
+		if f, err := strconv.ParseFloat(string(v), 64); err == nil {
+			return f == n.num
+		}
+	}
+	return false
+}
+
+type fieldToken struct {
+	val    string
+	quoted bool
+}
+
+// tokenizeFieldExpr splits an expression into tokens, parens are their own tokens
+// and double quoted strings are unquoted.
+func tokenizeFieldExpr(v string) (toks []fieldToken, err error) {
+	for i := 0; i < len(v); {
+		c := v[i]
+		switch {
+		case c == ' ' || c == '\t':
+			i++
+		case c == '(' || c == ')':
+			toks = append(toks, fieldToken{val: string(c)})
+			i++
+		case c == '"':
+			end := i + 1
+			for ; end < len(v); end++ {
+				if v[end] == '\\' {
+					end++
+				} else if v[end] == '"' {
+					break
+				}
+			}
+			if end >= len(v) {
+				err = fmt.Errorf("unterminated string at offset %d", i)
+				return
+			}
+			var s string
+			if s, err = strconv.Unquote(v[i : end+1]); err != nil {
+				return
+			}
+			toks = append(toks, fieldToken{val: s, quoted: true})
+			i = end + 1
+		default:
+			end := i
+			inQuote := false
+			for ; end < len(v); end++ {
+				if v[end] == '"' {
+					inQuote = !inQuote
+				} else if !inQuote && (v[end] == ' ' || v[end] == '\t' || v[end] == '(' || v[end] == ')') {
+					break
+				}
+			}
+			toks = append(toks, fieldToken{val: v[i:end]})
+			i = end
+		}
+	}
+	return
+}
+
+type fieldParser struct {
+	toks []fieldToken
+	pos  int
+}
+
+func compileFieldExpr(v string) (n fieldNode, err error) {
+	var toks []fieldToken
+	if toks, err = tokenizeFieldExpr(v); err != nil {
+		return
+	} else if len(toks) == 0 {
+		err = errors.New("empty expression")
+		return
+	}
+	p := fieldParser{toks: toks}
+	if n, err = p.parseOr(); err != nil {
+		return
+	} else if p.pos != len(p.toks) {
+		err = fmt.Errorf("%w %q", ErrUnexpectedToken, p.toks[p.pos].val)
+	}
+	return
+}
+
+func (p *fieldParser) peekKeyword(kw ...string) bool {
+	if p.pos >= len(p.toks) || p.toks[p.pos].quoted {
+		return false
+	}
+	for _, k := range kw {
+		if strings.EqualFold(p.toks[p.pos].val, k) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *fieldParser) next() (t fieldToken, err error) {
+	if p.pos >= len(p.toks) {
+		err = ErrUnexpectedEnd
+		return
+	}
+	t = p.toks[p.pos]
+	p.pos++
+	return
+}
+
+func (p *fieldParser) parseOr() (n fieldNode, err error) {
+	if n, err = p.parseAnd(); err != nil {
+		return
+	}
+	for p.peekKeyword(`OR`, `||`) {
+		p.pos++
+		var r fieldNode
+		if r, err = p.parseAnd(); err != nil {
+			return
+		}
+		n = orNode{l: n, r: r}
+	}
+	return
+}
+
+func (p *fieldParser) parseAnd() (n fieldNode, err error) {
+	if n, err = p.parseNot(); err != nil {
+		return
+	}
+	for p.peekKeyword(`AND`, `&&`) {
+		p.pos++
+		var r fieldNode
+		if r, err = p.parseNot(); err != nil {
+			return
+		}
+		n = andNode{l: n, r: r}
+	}
+	return
+}
+
+func (p *fieldParser) parseNot() (n fieldNode, err error) {
+	if p.peekKeyword(`NOT`, `!`) {
+		p.pos++
+		if n, err = p.parseNot(); err == nil {
+			n = notNode{n: n}
+		}
+		return
+	}
+	return p.parsePrimary()
+}
+
+func (p *fieldParser) parsePrimary() (n fieldNode, err error) {
+	var t fieldToken
+	if t, err = p.next(); err != nil {
+		return
+	}
+	if !t.quoted && t.val == `(` {
+		if n, err = p.parseOr(); err != nil {
+			return
+		}
+		if t, err = p.next(); err != nil {
+			return
+		} else if t.quoted || t.val != `)` {
+			err = fmt.Errorf("%w %q, expected )", ErrUnexpectedToken, t.val)
+		}
+		return
+	} else if t.quoted || t.val == `)` {
+		err = fmt.Errorf("%w %q, expected a field", ErrUnexpectedToken, t.val)
+		return
+	}
+	var ref fieldRef
+	if ref, err = parseFieldRef(t.val); err != nil {
+		return
+	}
+	// a bare field is an existence check
+	if p.pos >= len(p.toks) || p.peekKeyword(`AND`, `&&`, `OR`, `||`, `)`) {
+		n = existsNode{ref: ref}
+		return
+	}
+	var op, val fieldToken
+	if op, err = p.next(); err != nil {
+		return
+	} else if op.quoted {
+		err = fmt.Errorf("%w %q, expected an operator", ErrUnexpectedToken, op.val)
+		return
+	} else if val, err = p.next(); err != nil {
+		return
+	}
+	n, err = newCmpNode(ref, strings.ToLower(op.val), val.val)
+	return
+}
@@ -0,0 +1,219 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package processors
+
+import (
+	"net"
+	"testing"
+
+	"github.com/gravwell/gravwell/v3/ingest/entry"
+)
+
+func TestFieldRouterConfig(t *testing.T) {
+	b := `
+	[preprocessor "fr"]
+		type = field-router
+		Rule = "json:app == web AND src in 10.0.0.0/8 => webtag"
+		Rule = "ev:severity >= 5 => alerts"
+		Rule = "kv:level prefix debug => drop"
+		Drop-Misses = true
+	`
+	p, err := testLoadPreprocessor(b, `fr`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fr, ok := p.(*FieldRouter)
+	if !ok {
+		t.Fatalf("bad processor type %T", p)
+	} else if len(fr.rules) != 3 || !fr.rules[2].drop {
+		t.Fatalf("bad rules: %+v", fr.rules)
+	}
+}
+
+func TestFieldRouterBadRules(t *testing.T) {
+	bad := []string{
+		`json:app == web`,                 // no action
+		`json:app == web =>`,              // empty action
+		`json:app == web => bad$tag`,      // bad tag
+		`foo == web => tag`,               // unknown field
+		`json:app == => tag`,              // missing value
+		`json:app >= web => tag`,          // non-numeric comparison
+		`src in 10.0.0.0/33 => tag`,       // bad CIDR
+		`data ~ "[a-" => tag`,             // bad regex
+		`json:app === web => tag`,         // bad operator
+		`(json:app == web => tag`,         // unbalanced
+		`json:app == web) => tag`,         // unbalanced
+		`json:app == web AND => tag`,      // dangling AND
+		`json:app == "web => tag`,         // unterminated string
+		`json:app == web src == x => tag`, // missing combinator
+		` => tag`,                         // empty expression
+	}
+	for _, r := range bad {
+		if _, err := compileFieldRule(r); err == nil {
+			t.Fatalf("Failed to catch bad rule %q", r)
+		}
+	}
+	if _, err := (FieldRouterConfig{}).validate(); err == nil {
+		t.Fatal("failed to catch missing rules")
+	}
+}
+
+func TestFieldRouterExpressions(t *testing.T) {
+	ent := &entry.Entry{
+		SRC:  net.ParseIP(`10.1.2.3`),
+		Data: []byte(`{"app": "web", "user": {"name": "bob smith", "id": 42}, "path": "/api/v1", "msg": "level=error code=500"}`),
+	}
+	ent.AddEnumeratedValueEx(`severity`, int64(7))
+	ent.AddEnumeratedValueEx(`host`, `web01.example.com`)
+	kvent := &entry.Entry{Data: []byte(`level=error code=500 msg="upstream timeout"`)}
+
+	tests := []struct {
+		expr string
+		ent  *entry.Entry
+		res  bool
+	}{
+		{`json:app == web`, ent, true},
+		{`json:app == "web"`, ent, true},
+		{`json:app != web`, ent, false},
+		{`json:user.name == "bob smith"`, ent, true},
+		{`json:user.id == 42.0`, ent, true},
+		{`json:user.id > 40 AND json:user.id <= 42`, ent, true},
+		{`json:user.id < 42`, ent, false},
+		{`json:path prefix /api`, ent, true},
+		{`json:path suffix v1`, ent, true},
+		{`json:path contains pi/v`, ent, true},
+		{`json:missing == web`, ent, false},
+		{`json:missing != web`, ent, true},
+		{`json:user`, ent, true},
+		{`json:nope`, ent, false},
+		{`NOT json:nope`, ent, true},
+		{`src in 10.0.0.0/8`, ent, true},
+		{`src in 10.1.2.3`, ent, true},
+		{`src in 192.168.0.0/16`, ent, false},
+		{`src == 10.1.2.3`, ent, true},
+		{`ev:severity >= 5`, ent, true},
+		{`ev:severity == 7`, ent, true},
+		{`ev:host ~ "^web[0-9]+\\."`, ent, true},
+		{`ev:host !~ "^db"`, ent, true},
+		{`ev:missing`, ent, false},
+		{`data contains "bob smith"`, ent, true},
+		{`json:app == db OR ev:severity > 5`, ent, true},
+		{`json:app == db OR ev:severity > 10`, ent, false},
+		{`json:app == web AND (ev:severity > 10 OR src in 10.0.0.0/8)`, ent, true},
+		{`json:app == web AND NOT (ev:severity > 10 OR src in 10.0.0.0/8)`, ent, false},
+		{`json:app == web && ev:severity > 1 || json:app == db`, ent, true},
+		{`kv:level == error AND kv:code >= 500`, kvent, true},
+		{`kv:msg == "upstream timeout"`, kvent, true},
+		{`kv:nothere`, kvent, false},
+	}
+	for _, tst := range tests {
+		n, err := compileFieldExpr(tst.expr)
+		if err != nil {
+			t.Fatalf("failed to compile %q: %v", tst.expr, err)
+		}
+		kvp, err := NewKV(KVConfig{}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fc := fieldContext{kvp: kvp}
+		fc.reset(tst.ent)
+		if r := n.eval(&fc); r != tst.res {
+			t.Fatalf("%q evaluated to %v, expected %v", tst.expr, r, tst.res)
+		}
+	}
+}
+
+func TestFieldRouterProcess(t *testing.T) {
+	var tg testTagger
+	if _, err := tg.NegotiateTag(`default`); err != nil {
+		t.Fatal(err)
+	}
+	cfg := FieldRouterConfig{
+		Rule: []string{
+			`json:app == web AND src in 10.0.0.0/8 => webtag`,
+			`ev:severity >= 5 => alerts`,
+			`kv:level prefix debug => drop`,
+			`json:app == web => webext`,
+		},
+	}
+	fr, err := NewFieldRouter(cfg, &tg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mk := func(src, data string, sev int64) *entry.Entry {
+		ent := &entry.Entry{SRC: net.ParseIP(src), Data: []byte(data)}
+		if sev > 0 {
+			ent.AddEnumeratedValueEx(`severity`, sev)
+		}
+		return ent
+	}
+	tests := []struct {
+		ent  *entry.Entry
+		tag  string
+		drop bool
+	}{
+		{ent: mk(`10.0.0.1`, `{"app":"web"}`, 9), tag: `webtag`}, // first match wins
+		{ent: mk(`8.8.8.8`, `{"app":"web"}`, 9), tag: `alerts`},
+		{ent: mk(`8.8.8.8`, `{"app":"web"}`, 0), tag: `webext`},
+		{ent: mk(`8.8.8.8`, `level=debug2 msg=hi`, 0), drop: true},
+		{ent: mk(`8.8.8.8`, `level=info msg=hi`, 0), tag: `default`},
+	}
+	for i, tst := range tests {
+		set, err := fr.Process([]*entry.Entry{tst.ent})
+		if err != nil {
+			t.Fatal(err)
+		} else if tst.drop {
+			if len(set) != 0 {
+				t.Fatalf("%d not dropped", i)
+			}
+			continue
+		} else if len(set) != 1 {
+			t.Fatalf("%d dropped", i)
+		}
+		if tag, ok := tg.LookupTag(set[0].Tag); !ok || tag != tst.tag {
+			t.Fatalf("%d bad tag %v != %v", i, tag, tst.tag)
+		}
+	}
+
+	cfg.Drop_Misses = true
+	if err = fr.Config(cfg, &tg); err != nil {
+		t.Fatal(err)
+	}
+	if set, err := fr.Process([]*entry.Entry{mk(`8.8.8.8`, `level=info`, 0)}); err != nil {
+		t.Fatal(err)
+	} else if len(set) != 0 {
+		t.Fatal("miss not dropped")
+	}
+}
+
+func BenchmarkFieldRouter(b *testing.B) {
+	var tg testTagger
+	cfg := FieldRouterConfig{
+		Rule: []string{
+			`json:app == db AND src in 192.168.0.0/16 => dbtag`,
+			`json:user.id > 100 OR json:path prefix /admin => admin`,
+			`json:app == web AND src in 10.0.0.0/8 => webtag`,
+		},
+	}
+	fr, err := NewFieldRouter(cfg, &tg)
+	if err != nil {
+		b.Fatal(err)
+	}
+	ent := &entry.Entry{
+		SRC:  net.ParseIP(`10.1.2.3`),
+		Data: []byte(`{"app": "web", "user": {"name": "bob", "id": 42}, "path": "/api/v1"}`),
+	}
+	ents := []*entry.Entry{ent}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := fr.Process(ents); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
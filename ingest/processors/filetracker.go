@@ -0,0 +1,78 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package processors
+
+import (
+	"os"
+	"time"
+)
+
+const (
+	defaultReloadInterval = 10 * time.Second
+)
+
+// fileTracker watches a data file used by a preprocessor so that it can be
+// reloaded when the file changes on disk. Checks are rate limited so that
+// calling changed on every Process call is cheap.
+type fileTracker struct {
+	path     string
+	interval time.Duration
+	last     time.Time
+	mod      time.Time
+	sz       int64
+}
+
+// newFileTracker stats the file and returns a tracker; a zero or negative interval disables change detection
+func newFileTracker(path string, interval time.Duration) (ft *fileTracker, err error) {
+	var fi os.FileInfo
+	if fi, err = os.Stat(path); err != nil {
+		return
+	}
+	ft = &fileTracker{
+		path:     path,
+		interval: interval,
+		last:     time.Now(),
+		mod:      fi.ModTime(),
+		sz:       fi.Size(),
+	}
+	return
+}
+
+// parseReloadInterval parses a Reload-Interval config value, an empty value returns the default
+func parseReloadInterval(v string) (d time.Duration, err error) {
+	if v == `` {
+		d = defaultReloadInterval
+	} else {
+		d, err = time.ParseDuration(v)
+	}
+	return
+}
+
+// changed returns true if the file has a new modification time or size since the last check.
+// Missing files are not considered a change so that the existing data stays in place while
+// a file is being replaced.
+func (ft *fileTracker) changed() bool {
+	if ft == nil || ft.interval <= 0 {
+		return false
+	}
+	now := time.Now()
+	if now.Sub(ft.last) < ft.interval {
+		return false
+	}
+	ft.last = now
+	fi, err := os.Stat(ft.path)
+	if err != nil {
+		return false
+	} else if fi.ModTime().Equal(ft.mod) && fi.Size() == ft.sz {
+		return false
+	}
+	ft.mod = fi.ModTime()
+	ft.sz = fi.Size()
+	return true
+}
@@ -18,6 +18,7 @@ import (
 	"os"
 	"regexp"
 	"runtime"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -67,6 +68,31 @@ type ForwarderConfig struct {
 	Buffer                   uint //number of entries in flight (basically channel buffer size)
 	Non_Blocking             bool
 	Insecure_Skip_TLS_Verify bool
+
+	//options for the http, kafka, and file sinks
+	Batch_Size       uint     //maximum number of entries in an HTTP request or Kafka produce call
+	Batch_Interval   string   //maximum time a partial batch waits before being sent
+	Retries          uint     //number of delivery attempts for a batch
+	HTTP_Header      []string //additional headers in the form "Name: Value"
+	Kafka_Topic      string
+	Kafka_Key        string //optional message key, tag or src
+	Kafka_Use_TLS    bool
+	File_Max_Size    uint //size in MB before the file is rotated
+	File_Max_Backups uint //number of rotated files to keep
+
+	//options for the gelf, cef, and rfc5424 formats
+	GELF_Chunk_Size    uint //maximum UDP datagram size, larger messages are chunked
+	GELF_Compress      bool //gzip compress UDP messages
+	CEF_Vendor         string
+	CEF_Product        string
+	CEF_Device_Version string
+	CEF_Severity       string //0 through 10, defaults to 5
+	RFC5424_SD_ID      string //structured data ID used for enumerated values
+
+	//spool mode queues entries on disk while the remote is unavailable, delivery is at least once.
+	//Stream and datagram remotes have no confirmation, so entries leave the spool once written to the connection.
+	Spool_Dir      string
+	Spool_Max_Size string //maximum size of the spool, e.g. 512MB
 }
 
 func ForwarderLoadConfig(vc *config.VariableConfig) (c ForwarderConfig, err error) {
@@ -87,6 +113,7 @@ type Forwarder struct {
 	ch           chan *entry.Entry
 	abrt         chan struct{} //used to abort blocked writes
 	conn         net.Conn
+	spl          *spool
 	enc          EntryEncoder
 	err          error
 	closed       bool
@@ -133,6 +160,30 @@ func NewForwarder(cfg ForwarderConfig, tgr Tagger) (nf *Forwarder, err error) {
 	}
 
 	nf.ctx, nf.cf = context.WithCancel(context.Background())
+	if nf.Spool_Dir != `` {
+		//spool mode never blocks startup, the routine connects in the background
+		var max int
+		if max, err = parseDataSize(nf.Spool_Max_Size); err != nil {
+			return
+		} else if nf.spl, err = openSpool(nf.Spool_Dir, int64(max)); err != nil {
+			err = fmt.Errorf("Failed to open spool %s: %v", nf.Spool_Dir, err)
+			return
+		}
+		nf.wg.Add(1)
+		go nf.spoolRoutine()
+		return
+	}
+	if isSinkProtocol(nf.Protocol) {
+		var s forwarderSink
+		if !nf.Non_Blocking {
+			if s, err = nf.newSink(false); err != nil {
+				return
+			}
+		}
+		nf.wg.Add(1)
+		go nf.sinkRoutine(s)
+		return
+	}
 	if !nf.Non_Blocking {
 		if conn, err = nf.newConnection(false); err != nil {
 			return
@@ -145,7 +196,9 @@ func NewForwarder(cfg ForwarderConfig, tgr Tagger) (nf *Forwarder, err error) {
 
 func (nf *Forwarder) Process(ents []*entry.Entry) ([]*entry.Entry, error) {
 	nf.Lock()
-	if !nf.closed {
+	if !nf.closed && nf.spl != nil {
+		nf.spoolProcess(ents)
+	} else if !nf.closed {
 		for _, ent := range ents {
 			if ent == nil {
 				continue
@@ -284,8 +337,12 @@ func (nf *Forwarder) routine(conn net.Conn) {
 	}
 
 	for ent, ok := nf.getEnt(); ok; ent, ok = nf.getEnt() {
-		if conn, nf.err = nf.sendEntry(ent, conn); nf.err != nil {
-			break
+		var err error
+		if conn, err = nf.sendEntry(ent, conn); err != nil {
+			nf.err = err
+			if err != ErrGELFTooLarge {
+				break
+			}
 		}
 	}
 }
@@ -307,6 +364,8 @@ func (nf *Forwarder) sendEntry(ent *entry.Entry, conn net.Conn) (nc net.Conn, er
 	for {
 		if err = nf.enc.Encode(ent); err == nil || err == context.Canceled {
 			break //all good or cancelled context
+		} else if err == ErrGELFTooLarge {
+			break //the entry can never be sent, a new connection won't help
 		}
 		//failed to send it, try to get a new connection
 		nc.Close()
@@ -338,6 +397,9 @@ func (nfc *ForwarderConfig) Validate() (err error) {
 	if nfc.Format == encRaw && nfc.Delimiter == `` {
 		nfc.Delimiter = defaultDelimiter
 	}
+	if err = nfc.validateFormat(); err != nil {
+		return
+	}
 	if nfc.Buffer == 0 {
 		nfc.Buffer = defaultBuffer
 	}
@@ -381,11 +443,33 @@ func (nfc *ForwarderConfig) Validate() (err error) {
 			err = fmt.Errorf("Unable to resolve host %s: %v", h, err)
 			return
 		}
+	case protoHTTP, protoKafka, protoFile:
+		if err = nfc.validateSink(); err != nil {
+			return
+		}
 	default: //everything else better be a host:port pair
 		err = ErrUnknownProtocol
 		return
 	}
 
+	if nfc.Spool_Dir != `` {
+		if nfc.Non_Blocking {
+			err = ErrSpoolNonBlocking
+			return
+		}
+		if nfc.Spool_Max_Size == `` {
+			nfc.Spool_Max_Size = defaultSpoolMaxSize
+		}
+		var sz int
+		if sz, err = parseDataSize(nfc.Spool_Max_Size); err != nil {
+			err = fmt.Errorf("Invalid Spool-Max-Size %q: %v", nfc.Spool_Max_Size, err)
+			return
+		} else if sz <= 0 {
+			err = fmt.Errorf("Invalid Spool-Max-Size %q", nfc.Spool_Max_Size)
+			return
+		}
+	}
+
 	//check the tags
 	for _, tagname := range nfc.Tag {
 		if err = ingest.CheckTag(tagname); err != nil {
@@ -451,12 +535,70 @@ func (nf *Forwarder) newEncoder(w io.Writer) (err error) {
 		nf.enc, err = newJSONEncoder(w, nf.tgr)
 	case encSYSLOG:
 		nf.enc, err = newSyslogEncoder(w, nf.tgr)
+	case encGELF:
+		//stream transports need a delimiter, datagrams and sink messages are already framed
+		var delim []byte
+		switch nf.Protocol {
+		case protoTCP, protoTLS, protoUnix:
+			delim = []byte{0}
+		}
+		nf.enc, err = newGELFEncoder(w, nf.tgr, delim, nf.Protocol == protoUDP, int(nf.GELF_Chunk_Size), nf.GELF_Compress)
+	case encCEF:
+		var sev uint
+		if sev, err = nf.cefSeverity(); err == nil {
+			nf.enc, err = newCEFEncoder(w, nf.tgr, nf.CEF_Vendor, nf.CEF_Product, nf.CEF_Device_Version, sev)
+		}
+	case encRFC5424:
+		nf.enc, err = newRFC5424Encoder(w, nf.tgr, nf.RFC5424_SD_ID)
 	default:
 		err = ErrUnknownFormat
 	}
 	return
 }
 
+// validateFormat checks the format and populates defaults for the format specific options
+func (nfc *ForwarderConfig) validateFormat() (err error) {
+	switch nfc.Format {
+	case encRaw, encJSON, encSYSLOG:
+	case encGELF:
+		if nfc.GELF_Chunk_Size != 0 && nfc.GELF_Chunk_Size <= gelfChunkHdrSize {
+			err = ErrGELFChunkSize
+		}
+	case encCEF:
+		if nfc.CEF_Vendor == `` {
+			nfc.CEF_Vendor = defaultCEFVendor
+		}
+		if nfc.CEF_Product == `` {
+			nfc.CEF_Product = defaultCEFProduct
+		}
+		if nfc.CEF_Device_Version == `` {
+			nfc.CEF_Device_Version = defaultCEFVersion
+		}
+		_, err = nfc.cefSeverity()
+	case encRFC5424:
+		if nfc.RFC5424_SD_ID != `` && !validSDName(nfc.RFC5424_SD_ID) {
+			err = ErrInvalidSDID
+		}
+	default:
+		err = ErrUnknownFormat
+	}
+	return
+}
+
+func (nfc *ForwarderConfig) cefSeverity() (sev uint, err error) {
+	if nfc.CEF_Severity == `` {
+		sev = defaultCEFSeverity
+		return
+	}
+	var v uint64
+	if v, err = strconv.ParseUint(strings.TrimSpace(nfc.CEF_Severity), 10, 8); err != nil || v > maxCEFSeverity {
+		err = ErrInvalidSeverity
+		return
+	}
+	sev = uint(v)
+	return
+}
+
 func (nf *Forwarder) dialer() (d *net.Dialer) {
 	if nf.Timeout > 0 {
 		d.Timeout = time.Duration(nf.Timeout) * time.Second
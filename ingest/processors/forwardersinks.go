@@ -0,0 +1,576 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package processors
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/gravwell/gravwell/v3/ingest/entry"
+)
+
+const (
+	protoHTTP  string = `http`
+	protoKafka string = `kafka`
+	protoFile  string = `file`
+
+	defaultBatchSize     uint = 64
+	defaultBatchInterval      = time.Second
+	defaultRetries       uint = 3
+	defaultFileMaxSize   uint = 100 //MB
+	defaultFileBackups   uint = 4
+
+	kafkaVersion = `2.1.1`
+
+	kafkaKeyTag = `tag`
+	kafkaKeySrc = `src`
+
+	retryBackoffBase = 250 * time.Millisecond
+	retryBackoffMax  = 10 * time.Second
+)
+
+var (
+	ErrMissingKafkaTopic = errors.New("Kafka-Topic is required for the kafka protocol")
+	ErrInvalidKafkaKey   = errors.New("Kafka-Key must be empty, tag, or src")
+	ErrInvalidHTTPHeader = errors.New("HTTP-Header must be in the form \"Name: Value\"")
+	// ErrSinkRejected marks a delivery failure that retrying cannot fix, such as a 4xx HTTP response
+	ErrSinkRejected = errors.New("remote rejected the entries")
+
+	// newKafkaProducer is overridden in tests to hand back a mock producer
+	newKafkaProducer = sarama.NewSyncProducer
+)
+
+// forwarderSink is a message oriented destination for the forwarder. Sinks are handed entries
+// along with their encoded form one at a time and are responsible for their own batching,
+// Flush delivers any partial batch.
+type forwarderSink interface {
+	Write(ent *entry.Entry, b []byte) error
+	Flush() error
+	Close() error
+}
+
+func isSinkProtocol(proto string) bool {
+	switch proto {
+	case protoHTTP, protoKafka, protoFile:
+		return true
+	}
+	return false
+}
+
+func (nfc *ForwarderConfig) validateSink() (err error) {
+	if nfc.Batch_Size == 0 {
+		nfc.Batch_Size = defaultBatchSize
+	}
+	if nfc.Retries == 0 {
+		nfc.Retries = defaultRetries
+	}
+	if _, err = nfc.batchInterval(); err != nil {
+		return
+	}
+	switch nfc.Protocol {
+	case protoHTTP:
+		var u *url.URL
+		if u, err = url.Parse(nfc.Target); err != nil {
+			return
+		} else if u.Scheme != `http` && u.Scheme != `https` {
+			err = fmt.Errorf("HTTP target %q must be an http or https URL", nfc.Target)
+			return
+		}
+		if _, err = parseHTTPHeaders(nfc.HTTP_Header); err != nil {
+			return
+		}
+	case protoKafka:
+		if nfc.Kafka_Topic == `` {
+			err = ErrMissingKafkaTopic
+			return
+		}
+		switch strings.ToLower(nfc.Kafka_Key) {
+		case ``, kafkaKeyTag, kafkaKeySrc:
+		default:
+			err = ErrInvalidKafkaKey
+			return
+		}
+		for _, b := range nfc.kafkaBrokers() {
+			if _, _, err = net.SplitHostPort(b); err != nil {
+				err = fmt.Errorf("Invalid Kafka broker %q: %v", b, err)
+				return
+			}
+		}
+	case protoFile:
+		if nfc.File_Max_Size == 0 {
+			nfc.File_Max_Size = defaultFileMaxSize
+		}
+		if nfc.File_Max_Backups == 0 {
+			nfc.File_Max_Backups = defaultFileBackups
+		}
+	}
+	return
+}
+
+func (nfc *ForwarderConfig) batchInterval() (d time.Duration, err error) {
+	if nfc.Batch_Interval == `` {
+		d = defaultBatchInterval
+	} else if d, err = time.ParseDuration(nfc.Batch_Interval); err == nil && d <= 0 {
+		err = fmt.Errorf("Invalid Batch-Interval %q", nfc.Batch_Interval)
+	}
+	return
+}
+
+// kafkaBrokers splits the target into a list of brokers, multiple brokers are comma separated
+func (nfc *ForwarderConfig) kafkaBrokers() (r []string) {
+	for _, v := range strings.Split(nfc.Target, `,`) {
+		if v = strings.TrimSpace(v); v != `` {
+			r = append(r, v)
+		}
+	}
+	return
+}
+
+func parseHTTPHeaders(specs []string) (h http.Header, err error) {
+	h = http.Header{}
+	for _, s := range specs {
+		bits := strings.SplitN(s, `:`, 2)
+		if len(bits) != 2 || strings.TrimSpace(bits[0]) == `` {
+			err = ErrInvalidHTTPHeader
+			return
+		}
+		h.Add(strings.TrimSpace(bits[0]), strings.TrimSpace(bits[1]))
+	}
+	return
+}
+
+// newSink builds the sink for the configured protocol, if retry is set creation is attempted
+// until it succeeds or the forwarder is closed
+func (nf *Forwarder) newSink(retry bool) (s forwarderSink, err error) {
+	for {
+		switch nf.Protocol {
+		case protoHTTP:
+			s, err = newHTTPSink(nf.ctx, nf.ForwarderConfig)
+		case protoKafka:
+			s, err = newKafkaSink(nf.ForwarderConfig, nf.tgr)
+		case protoFile:
+			s, err = newFileSink(nf.Target, nf.File_Max_Size*mb, nf.File_Max_Backups)
+		default:
+			err = ErrUnknownProtocol
+			return
+		}
+		if err == nil || !retry {
+			break
+		}
+		if nf.sleep(redialInterval) {
+			err = context.Canceled
+			break
+		}
+	}
+	return
+}
+
+// sinkRoutine feeds entries to a message oriented sink, partial batches are flushed every batch interval
+func (nf *Forwarder) sinkRoutine(s forwarderSink) {
+	defer nf.wg.Done()
+	if s == nil {
+		if s, nf.err = nf.newSink(true); nf.err != nil {
+			return
+		}
+	}
+	var bb bytes.Buffer
+	if nf.err = nf.newEncoder(&bb); nf.err != nil {
+		s.Close()
+		return
+	}
+	interval, _ := nf.batchInterval()
+	tckr := time.NewTicker(interval)
+	defer tckr.Stop()
+
+loop:
+	for {
+		select {
+		case ent, ok := <-nf.ch:
+			if !ok {
+				break loop
+			} else if ent == nil {
+				continue
+			}
+			bb.Reset()
+			if err := nf.enc.Encode(ent); err != nil {
+				nf.err = err
+				continue
+			}
+			if err := s.Write(ent, bb.Bytes()); err != nil {
+				nf.err = err
+			}
+		case <-tckr.C:
+			if err := s.Flush(); err != nil {
+				nf.err = err
+			}
+		case <-nf.ctx.Done():
+			break loop
+		}
+	}
+	if err := s.Close(); err != nil {
+		nf.err = err
+	}
+}
+
+// retryBackoff returns the wait before the given attempt, starting at retryBackoffBase and doubling up to retryBackoffMax
+func retryBackoff(attempt uint) (d time.Duration) {
+	d = retryBackoffBase
+	for i := uint(1); i < attempt && d < retryBackoffMax; i++ {
+		d *= 2
+	}
+	if d > retryBackoffMax {
+		d = retryBackoffMax
+	}
+	return
+}
+
+type httpSink struct {
+	ctx     context.Context
+	clnt    *http.Client
+	url     string
+	hdr     http.Header
+	ctype   string
+	batch   uint
+	retries uint
+	count   uint
+	bb      bytes.Buffer
+}
+
+func newHTTPSink(ctx context.Context, cfg ForwarderConfig) (hs *httpSink, err error) {
+	var hdr http.Header
+	if hdr, err = parseHTTPHeaders(cfg.HTTP_Header); err != nil {
+		return
+	}
+	tr := http.DefaultTransport.(*http.Transport).Clone()
+	tr.TLSClientConfig = &tls.Config{
+		InsecureSkipVerify: cfg.Insecure_Skip_TLS_Verify,
+	}
+	clnt := &http.Client{
+		Transport: tr,
+	}
+	if cfg.Timeout > 0 {
+		clnt.Timeout = time.Duration(cfg.Timeout) * time.Second
+	}
+	ctype := `text/plain`
+	if cfg.Format == encJSON {
+		ctype = `application/x-ndjson`
+	}
+	hs = &httpSink{
+		ctx:     ctx,
+		clnt:    clnt,
+		url:     cfg.Target,
+		hdr:     hdr,
+		ctype:   ctype,
+		batch:   cfg.Batch_Size,
+		retries: cfg.Retries,
+	}
+	return
+}
+
+func (hs *httpSink) Write(ent *entry.Entry, b []byte) error {
+	hs.bb.Write(b)
+	hs.count++
+	if hs.count >= hs.batch {
+		return hs.send()
+	}
+	return nil
+}
+
+func (hs *httpSink) Flush() error {
+	if hs.count == 0 {
+		return nil
+	}
+	return hs.send()
+}
+
+func (hs *httpSink) Close() (err error) {
+	if hs.count > 0 {
+		err = hs.send()
+	}
+	hs.clnt.CloseIdleConnections()
+	return
+}
+
+// send POSTs the current batch, retrying transport errors and retryable status codes with backoff.
+// The batch is discarded once the retries are exhausted.
+func (hs *httpSink) send() (err error) {
+	body := hs.bb.Bytes()
+	for attempt := uint(1); ; attempt++ {
+		var retry bool
+		if retry, err = hs.post(body); err != nil && !retry && hs.ctx.Err() == nil {
+			err = fmt.Errorf("%w: %v", ErrSinkRejected, err)
+			break
+		} else if err == nil || !retry || attempt >= hs.retries {
+			break
+		}
+		select {
+		case <-hs.ctx.Done():
+			err = hs.ctx.Err()
+		case <-time.After(retryBackoff(attempt)):
+			continue
+		}
+		break
+	}
+	if err != nil {
+		err = fmt.Errorf("HTTP forwarder dropped %d entries: %w", hs.count, err)
+	}
+	hs.bb.Reset()
+	hs.count = 0
+	return
+}
+
+func (hs *httpSink) post(body []byte) (retry bool, err error) {
+	var req *http.Request
+	if req, err = http.NewRequestWithContext(hs.ctx, http.MethodPost, hs.url, bytes.NewReader(body)); err != nil {
+		return
+	}
+	for k, v := range hs.hdr {
+		req.Header[k] = v
+	}
+	req.Header.Set(`Content-Type`, hs.ctype)
+	var resp *http.Response
+	if resp, err = hs.clnt.Do(req); err != nil {
+		retry = hs.ctx.Err() == nil
+		return
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		err = fmt.Errorf("bad status %s", resp.Status)
+		retry = resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests
+	}
+	return
+}
+
+type kafkaSink struct {
+	prod  sarama.SyncProducer
+	topic string
+	key   string
+	tt    *tagTrans
+	batch uint
+	msgs  []*sarama.ProducerMessage
+}
+
+func newKafkaSink(cfg ForwarderConfig, tgr Tagger) (ks *kafkaSink, err error) {
+	scfg := sarama.NewConfig()
+	if scfg.Version, err = sarama.ParseKafkaVersion(kafkaVersion); err != nil {
+		return
+	}
+	scfg.Producer.Return.Successes = true //required by the sync producer
+	scfg.Producer.RequiredAcks = sarama.WaitForAll
+	scfg.Producer.Retry.Max = int(cfg.Retries)
+	if cfg.Timeout > 0 {
+		scfg.Producer.Timeout = time.Duration(cfg.Timeout) * time.Second
+	}
+	if cfg.Kafka_Use_TLS {
+		scfg.Net.TLS.Enable = true
+		scfg.Net.TLS.Config = &tls.Config{
+			MinVersion:         tls.VersionTLS12,
+			InsecureSkipVerify: cfg.Insecure_Skip_TLS_Verify,
+		}
+	}
+	var prod sarama.SyncProducer
+	if prod, err = newKafkaProducer(cfg.kafkaBrokers(), scfg); err != nil {
+		return
+	}
+	ks = &kafkaSink{
+		prod:  prod,
+		topic: cfg.Kafka_Topic,
+		key:   strings.ToLower(cfg.Kafka_Key),
+		tt:    newTagTrans(tgr),
+		batch: cfg.Batch_Size,
+	}
+	return
+}
+
+func (ks *kafkaSink) Write(ent *entry.Entry, b []byte) error {
+	msg := &sarama.ProducerMessage{
+		Topic:     ks.topic,
+		Value:     sarama.ByteEncoder(append([]byte(nil), b...)),
+		Timestamp: ent.TS.StandardTime(),
+	}
+	switch ks.key {
+	case kafkaKeyTag:
+		msg.Key = sarama.StringEncoder(ks.tt.TagName(ent.Tag))
+	case kafkaKeySrc:
+		if ent.SRC != nil {
+			msg.Key = sarama.StringEncoder(ent.SRC.String())
+		}
+	}
+	ks.msgs = append(ks.msgs, msg)
+	if uint(len(ks.msgs)) >= ks.batch {
+		return ks.send()
+	}
+	return nil
+}
+
+func (ks *kafkaSink) Flush() error {
+	if len(ks.msgs) == 0 {
+		return nil
+	}
+	return ks.send()
+}
+
+func (ks *kafkaSink) Close() (err error) {
+	if len(ks.msgs) > 0 {
+		err = ks.send()
+	}
+	if lerr := ks.prod.Close(); err == nil {
+		err = lerr
+	}
+	return
+}
+
+// send produces the current batch, the producer handles retries internally
+func (ks *kafkaSink) send() (err error) {
+	if err = ks.prod.SendMessages(ks.msgs); err != nil {
+		if kafkaRejected(err) {
+			err = fmt.Errorf("Kafka forwarder failed to produce %d entries: %w: %v", len(ks.msgs), ErrSinkRejected, err)
+		} else {
+			err = fmt.Errorf("Kafka forwarder failed to produce %d entries: %w", len(ks.msgs), err)
+		}
+	}
+	for i := range ks.msgs {
+		ks.msgs[i] = nil
+	}
+	ks.msgs = ks.msgs[:0]
+	return
+}
+
+// kafkaRejected returns true if every message in a failed send was refused by the broker for
+// reasons that sending it again will not fix
+func kafkaRejected(err error) bool {
+	var perrs sarama.ProducerErrors
+	if !errors.As(err, &perrs) || len(perrs) == 0 {
+		return false
+	}
+	for _, pe := range perrs {
+		switch {
+		case errors.Is(pe.Err, sarama.ErrMessageSizeTooLarge),
+			errors.Is(pe.Err, sarama.ErrInvalidMessage),
+			errors.Is(pe.Err, sarama.ErrInvalidRecord),
+			errors.Is(pe.Err, sarama.ErrPolicyViolation):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// fileSink writes encoded entries to a local file, rotating it once it exceeds the maximum size.
+// Rotated files are named path.1 through path.N with path.1 being the most recent.
+type fileSink struct {
+	pth     string
+	maxSize uint
+	backups uint
+	fout    *os.File
+	size    uint
+}
+
+func newFileSink(pth string, maxSize, backups uint) (fs *fileSink, err error) {
+	fs = &fileSink{
+		pth:     pth,
+		maxSize: maxSize,
+		backups: backups,
+	}
+	if err = fs.open(); err != nil {
+		fs = nil
+	}
+	return
+}
+
+func (fs *fileSink) open() (err error) {
+	var fi os.FileInfo
+	var fout *os.File
+	if fout, err = os.OpenFile(fs.pth, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0640); err != nil {
+		return
+	} else if fi, err = fout.Stat(); err != nil {
+		fout.Close()
+		return
+	}
+	fs.fout, fs.size = fout, uint(fi.Size())
+	return
+}
+
+func (fs *fileSink) Write(ent *entry.Entry, b []byte) (err error) {
+	if fs.fout == nil {
+		//a failed rotation could not reopen the file, try again
+		if err = fs.open(); err != nil {
+			return
+		}
+	}
+	if fs.size > 0 && fs.size+uint(len(b)) > fs.maxSize {
+		if err = fs.rotate(); err != nil {
+			return
+		}
+	}
+	var n int
+	n, err = fs.fout.Write(b)
+	fs.size += uint(n)
+	return
+}
+
+// Flush is a no-op, entries are written straight to the file
+func (fs *fileSink) Flush() error {
+	return nil
+}
+
+func (fs *fileSink) Close() error {
+	if fs.fout == nil {
+		return nil
+	}
+	return fs.fout.Close()
+}
+
+// rotate closes the live file so it can be renamed on every platform and then reopens the file at pth.
+// If the backups cannot be shifted the current file is reopened instead, so the sink keeps working
+// and the next write tries the rotation again.
+func (fs *fileSink) rotate() (err error) {
+	err = fs.fout.Close()
+	fs.fout = nil
+	if err == nil {
+		err = fs.shift()
+	}
+	if oerr := fs.open(); err == nil {
+		err = oerr
+	}
+	return
+}
+
+// shift renames the live file and the backups up by one, the oldest falls off the end
+func (fs *fileSink) shift() (err error) {
+	for i := fs.backups; i > 1; i-- {
+		if err = os.Rename(fs.backupName(i-1), fs.backupName(i)); err != nil && !os.IsNotExist(err) {
+			return
+		}
+	}
+	if fs.backups > 0 {
+		if err = os.Rename(fs.pth, fs.backupName(1)); err != nil {
+			return
+		}
+	} else if err = os.Remove(fs.pth); err != nil {
+		return
+	}
+	return
+}
+
+func (fs *fileSink) backupName(i uint) string {
+	return fmt.Sprintf("%s.%d", fs.pth, i)
+}
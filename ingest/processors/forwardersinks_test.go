@@ -0,0 +1,319 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package processors
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/IBM/sarama"
+	"github.com/IBM/sarama/mocks"
+	"github.com/gravwell/gravwell/v3/ingest/config"
+	"github.com/gravwell/gravwell/v3/ingest/entry"
+)
+
+func TestForwarderSinkConfig(t *testing.T) {
+	b := `
+	[preprocessor "fwd"]
+		type = forwarder
+		Protocol = kafka
+		Target = "127.0.0.1:9092, 127.0.0.2:9092"
+		Kafka-Topic = lake
+		Kafka-Key = tag
+		Batch-Size = 10
+		Batch-Interval = 250ms
+	`
+	tc := struct {
+		Global struct {
+			Foo string
+		}
+		Preprocessor ProcessorConfig
+	}{}
+	if err := config.LoadConfigBytes(&tc, []byte(b)); err != nil {
+		t.Fatal(err)
+	}
+	vc := tc.Preprocessor[`fwd`]
+	cfg, err := ForwarderLoadConfig(vc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if brokers := cfg.kafkaBrokers(); len(brokers) != 2 || brokers[1] != `127.0.0.2:9092` {
+		t.Fatalf("bad brokers: %v", brokers)
+	} else if cfg.Batch_Size != 10 || cfg.Retries != defaultRetries {
+		t.Fatalf("bad batch config: %+v", cfg)
+	}
+
+	bad := []ForwarderConfig{
+		{Protocol: protoKafka, Target: `127.0.0.1:9092`},
+		{Protocol: protoKafka, Target: `127.0.0.1:9092`, Kafka_Topic: `x`, Kafka_Key: `foo`},
+		{Protocol: protoHTTP, Target: `ftp://foo/bar`},
+		{Protocol: protoHTTP, Target: `http://foo/bar`, HTTP_Header: []string{`nocolon`}},
+		{Protocol: protoHTTP, Target: `http://foo/bar`, Batch_Interval: `-1s`},
+	}
+	for i, c := range bad {
+		if err := c.Validate(); err == nil {
+			t.Fatalf("failed to catch bad config %d", i)
+		}
+	}
+}
+
+type testWebhook struct {
+	sync.Mutex
+	fail   int
+	reject string //bodies containing this are refused with a 400
+	bodies []string
+	hdrs   []http.Header
+}
+
+func (tw *testWebhook) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	tw.Lock()
+	defer tw.Unlock()
+	if tw.fail > 0 {
+		tw.fail--
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	b, _ := io.ReadAll(r.Body)
+	if tw.reject != `` && strings.Contains(string(b), tw.reject) {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	tw.bodies = append(tw.bodies, string(b))
+	tw.hdrs = append(tw.hdrs, r.Header.Clone())
+}
+
+func TestForwarderHTTPSink(t *testing.T) {
+	wh := &testWebhook{fail: 1}
+	srv := httptest.NewServer(wh)
+	defer srv.Close()
+
+	tgr := &testTagger{}
+	keep, _ := tgr.NegotiateTag(`keep`)
+	skip, _ := tgr.NegotiateTag(`skip`)
+	fwd, err := NewForwarder(ForwarderConfig{
+		Protocol:    protoHTTP,
+		Target:      srv.URL + `/hook`,
+		Tag:         []string{`keep`},
+		Batch_Size:  2,
+		HTTP_Header: []string{`Authorization: Bearer foo`},
+	}, tgr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ents []*entry.Entry
+	for i := 0; i < 5; i++ {
+		tag := keep
+		if i == 1 {
+			tag = skip
+		}
+		ents = append(ents, &entry.Entry{Tag: tag, Data: []byte(fmt.Sprintf("ent%d", i))})
+	}
+	if _, err := fwd.Process(ents); err != nil {
+		t.Fatal(err)
+	}
+	if err := fwd.Close(); err != nil {
+		t.Fatal(err)
+	}
+	wh.Lock()
+	defer wh.Unlock()
+	// the first batch was retried after a 503, the remaining entry was sent on close
+	if len(wh.bodies) != 2 {
+		t.Fatalf("bad request count %d", len(wh.bodies))
+	} else if wh.bodies[0] != "ent0\nent2\n" || wh.bodies[1] != "ent3\nent4\n" {
+		t.Fatalf("bad bodies: %q", wh.bodies)
+	} else if wh.hdrs[0].Get(`Authorization`) != `Bearer foo` {
+		t.Fatalf("missing header: %v", wh.hdrs[0])
+	}
+}
+
+func TestForwarderHTTPSinkRetriesExhausted(t *testing.T) {
+	wh := &testWebhook{fail: 100}
+	srv := httptest.NewServer(wh)
+	defer srv.Close()
+	fwd, err := NewForwarder(ForwarderConfig{
+		Protocol: protoHTTP,
+		Target:   srv.URL,
+		Retries:  2,
+	}, &testTagger{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fwd.Process([]*entry.Entry{&entry.Entry{Data: []byte(`foo`)}})
+	if err := fwd.Close(); err == nil || !strings.Contains(err.Error(), `dropped 1 entries`) {
+		t.Fatalf("bad close error: %v", err)
+	}
+	if wh.fail != 98 {
+		t.Fatalf("bad attempt count %d", 100-wh.fail)
+	}
+}
+
+func TestForwarderKafkaSink(t *testing.T) {
+	tgr := &testTagger{}
+	tag, _ := tgr.NegotiateTag(`lake`)
+	prod := mocks.NewSyncProducer(t, nil)
+	check := func(key string) mocks.MessageChecker {
+		return func(msg *sarama.ProducerMessage) error {
+			if msg.Topic != `data` {
+				return fmt.Errorf("bad topic %s", msg.Topic)
+			}
+			k, _ := msg.Key.Encode()
+			if string(k) != `lake` {
+				return fmt.Errorf("bad key %s", k)
+			}
+			v, _ := msg.Value.Encode()
+			if string(v) != key+"\n" {
+				return fmt.Errorf("bad value %q", v)
+			}
+			return nil
+		}
+	}
+	prod.ExpectSendMessageWithMessageCheckerFunctionAndSucceed(check(`a`))
+	prod.ExpectSendMessageWithMessageCheckerFunctionAndSucceed(check(`c`))
+
+	orig := newKafkaProducer
+	defer func() { newKafkaProducer = orig }()
+	newKafkaProducer = func(brokers []string, cfg *sarama.Config) (sarama.SyncProducer, error) {
+		if len(brokers) != 1 || brokers[0] != `127.0.0.1:9092` {
+			return nil, fmt.Errorf("bad brokers %v", brokers)
+		}
+		return prod, nil
+	}
+
+	fwd, err := NewForwarder(ForwarderConfig{
+		Protocol:    protoKafka,
+		Target:      `127.0.0.1:9092`,
+		Kafka_Topic: `data`,
+		Kafka_Key:   `tag`,
+		Regex:       []string{`^[ac]$`},
+	}, tgr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fwd.Process([]*entry.Entry{
+		&entry.Entry{Tag: tag, Data: []byte(`a`)},
+		&entry.Entry{Tag: tag, Data: []byte(`b`)},
+		&entry.Entry{Tag: tag, Data: []byte(`c`)},
+	})
+	if err := fwd.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestKafkaRejected(t *testing.T) {
+	tooBig := &sarama.ProducerError{Err: sarama.ErrMessageSizeTooLarge}
+	down := &sarama.ProducerError{Err: sarama.ErrNotLeaderForPartition}
+	if !kafkaRejected(sarama.ProducerErrors{tooBig}) {
+		t.Fatal("oversized message not treated as rejected")
+	} else if kafkaRejected(sarama.ProducerErrors{tooBig, down}) {
+		t.Fatal("batch with a retryable failure treated as rejected")
+	} else if kafkaRejected(sarama.ErrOutOfBrokers) {
+		t.Fatal("broker failure treated as rejected")
+	}
+}
+
+func TestForwarderFileSink(t *testing.T) {
+	pth := filepath.Join(t.TempDir(), `out.json`)
+	fwd, err := NewForwarder(ForwarderConfig{
+		Protocol: protoFile,
+		Target:   pth,
+		Format:   encJSON,
+		Source:   []string{`10.0.0.0/8`},
+	}, &testTagger{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fwd.Process([]*entry.Entry{
+		&entry.Entry{SRC: net.ParseIP(`10.1.2.3`), Data: []byte(`foo`)},
+		&entry.Entry{SRC: net.ParseIP(`192.168.1.1`), Data: []byte(`bar`)},
+	})
+	if err := fwd.Close(); err != nil {
+		t.Fatal(err)
+	}
+	bts, err := os.ReadFile(pth)
+	if err != nil {
+		t.Fatal(err)
+	} else if lines := strings.Split(strings.TrimSpace(string(bts)), "\n"); len(lines) != 1 || !strings.Contains(lines[0], `"SRC":"10.1.2.3"`) {
+		t.Fatalf("bad file contents: %q", bts)
+	}
+}
+
+func TestFileSinkRotate(t *testing.T) {
+	pth := filepath.Join(t.TempDir(), `out.log`)
+	fs, err := newFileSink(pth, 10, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 4; i++ {
+		if err := fs.Write(nil, []byte(fmt.Sprintf("entry%d\n", i))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := fs.Close(); err != nil {
+		t.Fatal(err)
+	}
+	exp := map[string]string{
+		pth:        "entry3\n",
+		pth + `.1`: "entry2\n",
+		pth + `.2`: "entry1\n",
+	}
+	for p, v := range exp {
+		if bts, err := os.ReadFile(p); err != nil {
+			t.Fatal(err)
+		} else if string(bts) != v {
+			t.Fatalf("bad contents of %s: %q != %q", p, bts, v)
+		}
+	}
+	if _, err := os.Stat(pth + `.3`); !os.IsNotExist(err) {
+		t.Fatal("too many backups kept")
+	}
+}
+
+func TestFileSinkRotateFailure(t *testing.T) {
+	pth := filepath.Join(t.TempDir(), `out.log`)
+	fs, err := newFileSink(pth, 10, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fs.Close()
+	//a non-empty directory in place of the backup makes the rename fail
+	if err = os.MkdirAll(filepath.Join(pth+`.1`, `blocker`), 0750); err != nil {
+		t.Fatal(err)
+	} else if err = fs.Write(nil, []byte("entry0\n")); err != nil {
+		t.Fatal(err)
+	} else if err = fs.Write(nil, []byte("entry1\n")); err == nil {
+		t.Fatal("failed to report rotation failure")
+	}
+	//the sink recovers once the backup can be written
+	if err = os.RemoveAll(pth + `.1`); err != nil {
+		t.Fatal(err)
+	} else if err = fs.Write(nil, []byte("entry1\n")); err != nil {
+		t.Fatalf("sink did not recover: %v", err)
+	} else if err = fs.Close(); err != nil {
+		t.Fatal(err)
+	}
+	exp := map[string]string{
+		pth:        "entry1\n",
+		pth + `.1`: "entry0\n",
+	}
+	for p, v := range exp {
+		if bts, err := os.ReadFile(p); err != nil {
+			t.Fatal(err)
+		} else if string(bts) != v {
+			t.Fatalf("bad contents of %s: %q != %q", p, bts, v)
+		}
+	}
+}
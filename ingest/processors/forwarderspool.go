@@ -0,0 +1,581 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package processors
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gravwell/gravwell/v3/ingest/entry"
+)
+
+const (
+	defaultSpoolMaxSize     = `1GB`
+	defaultSpoolSegmentSize = 4 * mb
+
+	spoolSegmentExt = `.spool`
+	spoolAckFile    = `ack`
+	spoolAckSize    = 16
+	spoolRecHdrSize = 4
+)
+
+var (
+	ErrSpoolNonBlocking = errors.New("Spool-Dir and Non-Blocking are mutually exclusive")
+	ErrSpoolCorrupt     = errors.New("spool segment is corrupt")
+)
+
+// spool is a bounded on-disk queue of entries.  The queue is stored as a set of numbered segment files
+// and an ack file holding the segment and offset of the oldest unacknowledged entry.  Entries are read
+// ahead of the ack offset and only released once the reader acknowledges them, a reader that fails to
+// deliver rewinds to the ack offset.  Fully acknowledged segments are removed.
+type spool struct {
+	mtx     sync.Mutex
+	dir     string
+	max     int64
+	segSize int64
+	segs    []uint64 //segment ids on disk, ascending
+
+	wid   uint64 //write segment
+	wfile *os.File
+	woff  int64
+
+	rid   uint64 //read cursor
+	rfile *os.File
+	roff  int64
+	rsize int64 //bytes read but not acked
+	rcnt  int64 //entries read but not acked
+
+	aid  uint64 //ack cursor
+	aoff int64
+
+	size    int64 //unacked bytes
+	count   int64 //unacked entries
+	dropped uint64
+
+	ready chan struct{}
+}
+
+func openSpool(dir string, max int64) (s *spool, err error) {
+	if err = os.MkdirAll(dir, 0750); err != nil {
+		return
+	}
+	s = &spool{
+		dir:     dir,
+		max:     max,
+		segSize: defaultSpoolSegmentSize,
+		ready:   make(chan struct{}, 1),
+	}
+	if max < s.segSize {
+		s.segSize = max
+	}
+	if err = s.load(); err != nil {
+		s.close()
+		s = nil
+	}
+	return
+}
+
+// load discovers the segments on disk, restores the ack cursor, and counts the unacknowledged entries
+func (s *spool) load() (err error) {
+	var dents []os.DirEntry
+	if dents, err = os.ReadDir(s.dir); err != nil {
+		return
+	}
+	for _, d := range dents {
+		if name := d.Name(); strings.HasSuffix(name, spoolSegmentExt) {
+			if id, lerr := strconv.ParseUint(strings.TrimSuffix(name, spoolSegmentExt), 10, 64); lerr == nil {
+				s.segs = append(s.segs, id)
+			}
+		}
+	}
+	sort.Slice(s.segs, func(i, j int) bool { return s.segs[i] < s.segs[j] })
+	if err = s.loadAck(); err != nil {
+		return
+	}
+	//drop any segments that were fully acked before a crash
+	for len(s.segs) > 0 && s.segs[0] < s.aid {
+		if err = os.Remove(s.segPath(s.segs[0])); err != nil && !os.IsNotExist(err) {
+			return
+		}
+		s.segs = s.segs[1:]
+	}
+	if len(s.segs) == 0 {
+		s.aid, s.aoff = 1, 0
+		if s.wfile, err = s.newSegment(1); err != nil {
+			return
+		}
+	} else {
+		if s.segs[0] != s.aid {
+			s.aid, s.aoff = s.segs[0], 0
+		}
+		for _, id := range s.segs {
+			var off int64
+			if id == s.aid {
+				off = s.aoff
+			}
+			if err = s.scan(id, off, id == s.segs[len(s.segs)-1]); err != nil {
+				return
+			}
+		}
+		s.wid = s.segs[len(s.segs)-1]
+		if s.wfile, err = os.OpenFile(s.segPath(s.wid), os.O_WRONLY|os.O_APPEND, 0640); err != nil {
+			return
+		}
+		var fi os.FileInfo
+		if fi, err = s.wfile.Stat(); err != nil {
+			return
+		}
+		s.woff = fi.Size()
+	}
+	s.rid, s.roff = s.aid, s.aoff
+	return
+}
+
+// scan counts the records in a segment starting at off, a partial record at the end of the last segment
+// is the result of a crash mid-write and is truncated away
+func (s *spool) scan(id uint64, off int64, last bool) (err error) {
+	var fin *os.File
+	if fin, err = os.Open(s.segPath(id)); err != nil {
+		return
+	}
+	defer fin.Close()
+	var fi os.FileInfo
+	if fi, err = fin.Stat(); err != nil {
+		return
+	}
+	hdr := make([]byte, spoolRecHdrSize)
+	for off < fi.Size() {
+		var n int
+		if n, err = fin.ReadAt(hdr, off); err != nil && !(err == io.EOF && n == len(hdr)) {
+			break
+		}
+		err = nil
+		l := int64(binary.LittleEndian.Uint32(hdr)) + spoolRecHdrSize
+		if off+l > fi.Size() {
+			break
+		}
+		off += l
+		s.size += l
+		s.count++
+	}
+	if off < fi.Size() {
+		if !last {
+			return fmt.Errorf("%w: %s", ErrSpoolCorrupt, s.segPath(id))
+		}
+		err = os.Truncate(s.segPath(id), off)
+	}
+	return
+}
+
+func (s *spool) loadAck() (err error) {
+	var bts []byte
+	if bts, err = os.ReadFile(filepath.Join(s.dir, spoolAckFile)); err != nil {
+		if os.IsNotExist(err) {
+			err = nil
+		}
+		return
+	} else if len(bts) != spoolAckSize {
+		return fmt.Errorf("%w: bad ack file", ErrSpoolCorrupt)
+	}
+	s.aid = binary.LittleEndian.Uint64(bts)
+	s.aoff = int64(binary.LittleEndian.Uint64(bts[8:]))
+	return
+}
+
+// saveAck persists the ack cursor, the file is replaced atomically so a crash leaves the previous cursor
+func (s *spool) saveAck() (err error) {
+	bts := make([]byte, spoolAckSize)
+	binary.LittleEndian.PutUint64(bts, s.aid)
+	binary.LittleEndian.PutUint64(bts[8:], uint64(s.aoff))
+	pth := filepath.Join(s.dir, spoolAckFile)
+	if err = os.WriteFile(pth+`.tmp`, bts, 0640); err == nil {
+		err = os.Rename(pth+`.tmp`, pth)
+	}
+	return
+}
+
+func (s *spool) segPath(id uint64) string {
+	return filepath.Join(s.dir, fmt.Sprintf("%016d%s", id, spoolSegmentExt))
+}
+
+func (s *spool) newSegment(id uint64) (f *os.File, err error) {
+	if f, err = os.OpenFile(s.segPath(id), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0640); err == nil {
+		s.segs = append(s.segs, id)
+		s.wid, s.woff = id, 0
+	}
+	return
+}
+
+// push appends entries to the spool, entries that do not fit are dropped and counted
+func (s *spool) push(ents []*entry.Entry) (err error) {
+	if len(ents) == 0 {
+		return
+	}
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	var buff []byte
+	for _, ent := range ents {
+		l := int64(ent.Size()) + spoolRecHdrSize
+		if s.size+l > s.max {
+			atomic.AddUint64(&s.dropped, 1)
+			continue
+		}
+		if s.woff > 0 && s.woff+l > s.segSize {
+			if err = s.wfile.Close(); err != nil {
+				return
+			} else if s.wfile, err = s.newSegment(s.wid + 1); err != nil {
+				return
+			}
+		}
+		if int64(cap(buff)) < l {
+			buff = make([]byte, l)
+		}
+		buff = buff[:l]
+		binary.LittleEndian.PutUint32(buff, uint32(l-spoolRecHdrSize))
+		if _, err = ent.Encode(buff[spoolRecHdrSize:]); err != nil {
+			return
+		} else if _, err = s.wfile.Write(buff); err != nil {
+			return
+		}
+		s.woff += l
+		s.size += l
+		s.count++
+	}
+	select {
+	case s.ready <- empty:
+	default:
+	}
+	return
+}
+
+// next reads up to cnt entries past the read cursor, the entries are not released until ack is called
+func (s *spool) next(cnt int) (ents []*entry.Entry, err error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	hdr := make([]byte, spoolRecHdrSize)
+	for len(ents) < cnt {
+		if s.rid == s.wid && s.roff >= s.woff {
+			break //caught up with the writer
+		}
+		if s.rfile == nil {
+			if s.rfile, err = os.Open(s.segPath(s.rid)); err != nil {
+				return
+			}
+		}
+		if s.rid != s.wid {
+			var fi os.FileInfo
+			if fi, err = s.rfile.Stat(); err != nil {
+				return
+			} else if s.roff >= fi.Size() {
+				//roll to the next segment
+				s.rfile.Close()
+				s.rfile = nil
+				s.rid, s.roff = s.nextSegment(s.rid), 0
+				continue
+			}
+		}
+		if _, err = s.rfile.ReadAt(hdr, s.roff); err != nil {
+			return
+		}
+		buff := make([]byte, binary.LittleEndian.Uint32(hdr))
+		if _, err = s.rfile.ReadAt(buff, s.roff+spoolRecHdrSize); err != nil {
+			return
+		}
+		ent := &entry.Entry{}
+		if _, err = ent.Decode(buff); err != nil {
+			err = fmt.Errorf("%w: %v", ErrSpoolCorrupt, err)
+			return
+		}
+		ents = append(ents, ent)
+		l := int64(len(buff)) + spoolRecHdrSize
+		s.roff += l
+		s.rsize += l
+		s.rcnt++
+	}
+	return
+}
+
+func (s *spool) nextSegment(id uint64) uint64 {
+	for _, v := range s.segs {
+		if v > id {
+			return v
+		}
+	}
+	return s.wid
+}
+
+// ack releases every entry handed out by next, segments behind the cursor are removed
+func (s *spool) ack() (err error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if s.rcnt == 0 {
+		return
+	}
+	s.aid, s.aoff = s.rid, s.roff
+	s.size -= s.rsize
+	s.count -= s.rcnt
+	s.rsize, s.rcnt = 0, 0
+	if err = s.wfile.Sync(); err != nil {
+		return
+	} else if err = s.saveAck(); err != nil {
+		return
+	}
+	for len(s.segs) > 0 && s.segs[0] < s.aid {
+		if err = os.Remove(s.segPath(s.segs[0])); err != nil && !os.IsNotExist(err) {
+			return
+		}
+		s.segs = s.segs[1:]
+	}
+	return
+}
+
+// rewind moves the read cursor back to the ack cursor so unacknowledged entries are read again
+func (s *spool) rewind() {
+	s.mtx.Lock()
+	if s.rid != s.aid && s.rfile != nil {
+		s.rfile.Close()
+		s.rfile = nil
+	}
+	s.rid, s.roff = s.aid, s.aoff
+	s.rsize, s.rcnt = 0, 0
+	s.mtx.Unlock()
+}
+
+// wait blocks until entries are pushed, the abort channel is closed, or the context expires
+func (s *spool) wait(ctx context.Context, abrt chan struct{}) (ok bool) {
+	select {
+	case <-s.ready:
+		ok = true
+	case <-abrt:
+	case <-ctx.Done():
+	}
+	return
+}
+
+// depth returns the number of unacknowledged entries and bytes along with the number of entries dropped because the spool was full
+func (s *spool) depth() (count, size, dropped uint64) {
+	s.mtx.Lock()
+	count, size = uint64(s.count), uint64(s.size)
+	s.mtx.Unlock()
+	dropped = atomic.LoadUint64(&s.dropped)
+	return
+}
+
+func (s *spool) close() (err error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if s.rfile != nil {
+		s.rfile.Close()
+		s.rfile = nil
+	}
+	if s.wfile != nil {
+		if err = s.wfile.Sync(); err == nil {
+			err = s.wfile.Close()
+		} else {
+			s.wfile.Close()
+		}
+		s.wfile = nil
+	}
+	return
+}
+
+// spoolProcess filters entries and appends them to the spool, the spool encodes entries immediately so
+// the caller retains ownership of the originals
+func (nf *Forwarder) spoolProcess(ents []*entry.Entry) {
+	set := make([]*entry.Entry, 0, len(ents))
+	for _, ent := range ents {
+		if ent != nil && !nf.filter(ent) {
+			set = append(set, ent)
+		}
+	}
+	if err := nf.spl.push(set); err != nil {
+		nf.err = err
+	}
+}
+
+// SpoolStats returns the number of entries and bytes waiting in the spool and the number of entries
+// dropped because the spool was full, all values are zero when spooling is not enabled
+func (nf *Forwarder) SpoolStats() (count, size, dropped uint64) {
+	if nf.spl != nil {
+		count, size, dropped = nf.spl.depth()
+	}
+	return
+}
+
+// spoolRoutine drains the spool to the remote, entries are acknowledged only once they have been delivered
+// so entries still in the spool when the forwarder is closed are sent after a restart.  Delivery is at least
+// once, a batch interrupted by a failure is sent again in full.
+func (nf *Forwarder) spoolRoutine() {
+	defer nf.wg.Done()
+	var err error
+	if isSinkProtocol(nf.Protocol) {
+		err = nf.spoolSinkLoop()
+	} else {
+		err = nf.spoolConnLoop()
+	}
+	if err == context.Canceled {
+		err = nil //closed while the remote was unavailable, the spool holds the remainder
+	}
+	if lerr := nf.spl.close(); err == nil {
+		err = lerr
+	}
+	if err != nil {
+		nf.err = err
+	}
+}
+
+// spoolNext reads the next batch from the spool, waiting for entries if it is empty.
+// An empty batch with no error means the forwarder is closing.
+func (nf *Forwarder) spoolNext(cnt int) (ents []*entry.Entry, err error) {
+	for {
+		if ents, err = nf.spl.next(cnt); err != nil || len(ents) > 0 {
+			return
+		}
+		select {
+		case <-nf.abrt:
+			return //closing and fully drained
+		default:
+		}
+		if !nf.spl.wait(nf.ctx, nf.abrt) && nf.ctx.Err() != nil {
+			return
+		}
+	}
+}
+
+// spoolConnLoop drains the spool to a TCP, TLS, UDP, or unix socket remote.  None of these protocols confirm
+// receipt, so a batch is acknowledged once every entry in it has been written to the connection.  If the
+// connection fails mid batch the entries already written to it may have been lost, so the spool is rewound
+// and the whole batch is sent again on a new connection.
+func (nf *Forwarder) spoolConnLoop() (err error) {
+	var conn net.Conn
+	if conn, err = nf.newConnection(true); err != nil {
+		return
+	}
+	nf.conn = conn
+	defer func() {
+		if conn != nil {
+			conn.Close()
+		}
+	}()
+	if err = nf.newEncoder(conn); err != nil {
+		return
+	}
+	for {
+		var ents []*entry.Entry
+		if ents, err = nf.spoolNext(int(nf.Buffer)); err != nil || len(ents) == 0 {
+			return
+		}
+		var werr error
+		for _, ent := range ents {
+			if werr = nf.enc.Encode(ent); werr == ErrGELFTooLarge {
+				nf.err, werr = werr, nil //skip it, retrying would wedge the spool
+			} else if werr != nil {
+				break
+			}
+		}
+		if werr == nil {
+			if err = nf.spl.ack(); err != nil {
+				return
+			}
+			continue
+		} else if werr == context.Canceled {
+			return werr
+		}
+		//the connection failed, read the batch again and send it on a new connection
+		nf.spl.rewind()
+		conn.Close()
+		if conn, err = nf.newConnection(true); err != nil {
+			return
+		}
+		nf.enc.Reset(conn)
+		nf.conn = conn
+	}
+}
+
+// spoolSinkLoop drains the spool to a message oriented sink.  Entries that cannot be encoded and batches
+// the remote rejects outright are skipped and recorded, any other failure rewinds the spool and the batch
+// is sent again after a backoff.
+func (nf *Forwarder) spoolSinkLoop() (err error) {
+	var s forwarderSink
+	if s, err = nf.newSink(true); err != nil {
+		return
+	}
+	defer s.Close()
+	var bb bytes.Buffer
+	if err = nf.newEncoder(&bb); err != nil {
+		return
+	}
+	interval, _ := nf.batchInterval()
+	var attempt uint
+	for {
+		var ents []*entry.Entry
+		if ents, err = nf.spoolNext(int(nf.Batch_Size)); err != nil || len(ents) == 0 {
+			return
+		}
+		var serr error
+		for _, ent := range ents {
+			bb.Reset()
+			if lerr := nf.enc.Encode(ent); lerr != nil {
+				nf.err = lerr //the entry can never be encoded, skip it
+				continue
+			} else if serr = s.Write(ent, bb.Bytes()); serr != nil {
+				if !errors.Is(serr, ErrSinkRejected) {
+					break
+				}
+				nf.err, serr = serr, nil //the sink dropped the rejected batch, carry on with the rest
+			}
+		}
+		if serr == nil {
+			if serr = s.Flush(); errors.Is(serr, ErrSinkRejected) {
+				nf.err, serr = serr, nil
+			}
+		}
+		if serr != nil {
+			//delivery failed, back off and read the batch again
+			nf.spl.rewind()
+			attempt++
+			if nf.spoolSleep(retryBackoff(attempt)) {
+				return
+			}
+			continue
+		}
+		attempt = 0
+		if err = nf.spl.ack(); err != nil {
+			return
+		}
+		if uint(len(ents)) < nf.Batch_Size && nf.spoolSleep(interval) {
+			return //let a partial batch fill unless we are closing
+		}
+	}
+}
+
+// spoolSleep waits for the duration, returning true if the forwarder is closing
+func (nf *Forwarder) spoolSleep(d time.Duration) bool {
+	select {
+	case <-nf.abrt:
+		return true
+	case <-nf.ctx.Done():
+		return true
+	case <-time.After(d):
+	}
+	return false
+}
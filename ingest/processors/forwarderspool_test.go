@@ -0,0 +1,410 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package processors
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gravwell/gravwell/v3/ingest/entry"
+)
+
+func makeSpoolEnts(start, cnt int) (r []*entry.Entry) {
+	for i := start; i < start+cnt; i++ {
+		ent := &entry.Entry{
+			TS:   entry.UnixTime(int64(i), 0),
+			SRC:  net.ParseIP("10.0.0.1"),
+			Tag:  entry.EntryTag(i % 4),
+			Data: []byte(fmt.Sprintf("entry %d", i)),
+		}
+		ent.AddEnumeratedValueEx(`idx`, int64(i))
+		r = append(r, ent)
+	}
+	return
+}
+
+func checkSpoolEnts(t *testing.T, ents []*entry.Entry, start, cnt int) {
+	t.Helper()
+	if len(ents) != cnt {
+		t.Fatalf("bad entry count %d != %d", len(ents), cnt)
+	}
+	exp := makeSpoolEnts(start, cnt)
+	for i := range ents {
+		if err := ents[i].Compare(exp[i]); err != nil {
+			t.Fatalf("entry %d mismatch: %v", i, err)
+		}
+	}
+}
+
+func TestSpoolAckRewind(t *testing.T) {
+	dir := t.TempDir()
+	s, err := openSpool(dir, mb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.push(makeSpoolEnts(0, 10)); err != nil {
+		t.Fatal(err)
+	}
+	ents, err := s.next(4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkSpoolEnts(t, ents, 0, 4)
+	//a failed delivery rewinds and reads the same entries again
+	s.rewind()
+	if ents, err = s.next(4); err != nil {
+		t.Fatal(err)
+	}
+	checkSpoolEnts(t, ents, 0, 4)
+	if err = s.ack(); err != nil {
+		t.Fatal(err)
+	}
+	//read ahead without acking, these are redelivered after a restart
+	if ents, err = s.next(2); err != nil {
+		t.Fatal(err)
+	}
+	checkSpoolEnts(t, ents, 4, 2)
+	if cnt, _, _ := s.depth(); cnt != 6 {
+		t.Fatalf("bad depth %d", cnt)
+	}
+	if err = s.close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if s, err = openSpool(dir, mb); err != nil {
+		t.Fatal(err)
+	}
+	defer s.close()
+	if cnt, _, _ := s.depth(); cnt != 6 {
+		t.Fatalf("bad depth after restart %d", cnt)
+	}
+	if ents, err = s.next(100); err != nil {
+		t.Fatal(err)
+	}
+	checkSpoolEnts(t, ents, 4, 6)
+	if err = s.ack(); err != nil {
+		t.Fatal(err)
+	}
+	if cnt, sz, _ := s.depth(); cnt != 0 || sz != 0 {
+		t.Fatalf("bad depth after drain %d %d", cnt, sz)
+	}
+}
+
+func TestSpoolSegments(t *testing.T) {
+	dir := t.TempDir()
+	s, err := openSpool(dir, mb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.close()
+	s.segSize = 256
+	for i := 0; i < 100; i += 10 {
+		if err := s.push(makeSpoolEnts(i, 10)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(s.segs) < 4 {
+		t.Fatalf("spool did not roll segments: %d", len(s.segs))
+	}
+	var got []*entry.Entry
+	for {
+		ents, err := s.next(7)
+		if err != nil {
+			t.Fatal(err)
+		} else if len(ents) == 0 {
+			break
+		}
+		got = append(got, ents...)
+		if err = s.ack(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	checkSpoolEnts(t, got, 0, 100)
+	if len(s.segs) != 1 {
+		t.Fatalf("acked segments were not removed: %v", s.segs)
+	}
+	if dents, _ := os.ReadDir(dir); len(dents) != 2 {
+		t.Fatalf("bad spool directory contents: %d", len(dents))
+	}
+}
+
+func TestSpoolFull(t *testing.T) {
+	s, err := openSpool(t.TempDir(), 200)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.close()
+	if err := s.push(makeSpoolEnts(0, 10)); err != nil {
+		t.Fatal(err)
+	}
+	cnt, sz, dropped := s.depth()
+	if cnt == 0 || cnt+dropped != 10 || dropped == 0 || sz > 200 {
+		t.Fatalf("bad depth %d %d %d", cnt, sz, dropped)
+	}
+}
+
+func TestSpoolTruncatedTail(t *testing.T) {
+	dir := t.TempDir()
+	s, err := openSpool(dir, mb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.push(makeSpoolEnts(0, 3))
+	s.close()
+	//simulate a crash in the middle of a write
+	fout, err := os.OpenFile(s.segPath(1), os.O_WRONLY|os.O_APPEND, 0640)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fout.Write([]byte{0xff, 0x00, 0x00, 0x00, 0x01, 0x02})
+	fout.Close()
+
+	if s, err = openSpool(dir, mb); err != nil {
+		t.Fatal(err)
+	}
+	defer s.close()
+	s.push(makeSpoolEnts(3, 1))
+	ents, err := s.next(10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkSpoolEnts(t, ents, 0, 4)
+}
+
+func TestForwarderSpoolConfig(t *testing.T) {
+	cfg := ForwarderConfig{
+		Target:       `127.0.0.1:9999`,
+		Spool_Dir:    t.TempDir(),
+		Non_Blocking: true,
+	}
+	if err := cfg.Validate(); err != ErrSpoolNonBlocking {
+		t.Fatalf("failed to catch non-blocking spool: %v", err)
+	}
+	cfg.Non_Blocking = false
+	cfg.Spool_Max_Size = `foobar`
+	if err := cfg.Validate(); err == nil {
+		t.Fatal("failed to catch bad spool size")
+	}
+	cfg.Spool_Max_Size = ``
+	if err := cfg.Validate(); err != nil {
+		t.Fatal(err)
+	} else if cfg.Spool_Max_Size != defaultSpoolMaxSize {
+		t.Fatalf("bad default spool size %s", cfg.Spool_Max_Size)
+	}
+}
+
+func TestForwarderSpoolHTTP(t *testing.T) {
+	dir := t.TempDir()
+	wh := &testWebhook{fail: 1 << 20}
+	srv := httptest.NewServer(wh)
+	defer srv.Close()
+	cfg := ForwarderConfig{
+		Protocol:       protoHTTP,
+		Target:         srv.URL,
+		Spool_Dir:      dir,
+		Retries:        1,
+		Batch_Size:     4,
+		Batch_Interval: `10ms`,
+	}
+
+	//the remote is down, entries are held in the spool
+	fwd, err := NewForwarder(cfg, &testTagger{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ents := makeSpoolEnts(0, 10)
+	if _, err = fwd.Process(ents); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if cnt, _, _ := fwd.SpoolStats(); cnt != 10 {
+		t.Fatalf("bad spool depth %d", cnt)
+	}
+	ps := NewProcessorSet(&testWriter{})
+	ps.AddProcessor(fwd)
+	if st := ps.PreprocessorStats(); len(st) != 1 || st[0].SpoolEntries != 10 || st[0].SpoolBytes == 0 {
+		t.Fatalf("bad preprocessor stats: %+v", st)
+	}
+	if err = fwd.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	//the remote comes back and a new forwarder picks up where the last left off
+	wh.Lock()
+	wh.fail = 0
+	wh.Unlock()
+	if fwd, err = NewForwarder(cfg, &testTagger{}); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for cnt, _, _ := fwd.SpoolStats(); cnt != 0; cnt, _, _ = fwd.SpoolStats() {
+		if time.Now().After(deadline) {
+			t.Fatalf("spool did not drain: %d", cnt)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if err = fwd.Close(); err != nil {
+		t.Fatal(err)
+	}
+	wh.Lock()
+	defer wh.Unlock()
+	var body string
+	for _, b := range wh.bodies {
+		body += b
+	}
+	var exp string
+	for i := 0; i < 10; i++ {
+		exp += fmt.Sprintf("entry %d\n", i)
+	}
+	if body != exp {
+		t.Fatalf("bad delivered data:\n%q\n%q", body, exp)
+	}
+}
+
+func TestForwarderSpoolTCP(t *testing.T) {
+	lst, err := net.Listen(`tcp`, `127.0.0.1:0`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lst.Close()
+	lines := make(chan string, 16)
+	go func() {
+		conn, err := lst.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		sc := bufio.NewScanner(conn)
+		for sc.Scan() {
+			lines <- sc.Text()
+		}
+	}()
+	fwd, err := NewForwarder(ForwarderConfig{
+		Target:    lst.Addr().String(),
+		Spool_Dir: filepath.Join(t.TempDir(), `spool`),
+	}, &testTagger{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fwd.Process(makeSpoolEnts(0, 5))
+	for i := 0; i < 5; i++ {
+		select {
+		case l := <-lines:
+			if exp := fmt.Sprintf("entry %d", i); l != exp {
+				t.Fatalf("bad line %q != %q", l, exp)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for forwarded entries")
+		}
+	}
+	if err = fwd.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if cnt, _, _ := fwd.SpoolStats(); cnt != 0 {
+		t.Fatalf("bad spool depth %d", cnt)
+	}
+}
+
+func TestForwarderSpoolRejected(t *testing.T) {
+	wh := &testWebhook{reject: `entry 2`}
+	srv := httptest.NewServer(wh)
+	defer srv.Close()
+	fwd, err := NewForwarder(ForwarderConfig{
+		Protocol:       protoHTTP,
+		Target:         srv.URL,
+		Spool_Dir:      t.TempDir(),
+		Retries:        1,
+		Batch_Size:     2,
+		Batch_Interval: `10ms`,
+	}, &testTagger{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	//the batch holding the poison entry is skipped rather than wedging the spool
+	if _, err = fwd.Process(makeSpoolEnts(0, 6)); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for cnt, _, _ := fwd.SpoolStats(); cnt != 0; cnt, _, _ = fwd.SpoolStats() {
+		if time.Now().After(deadline) {
+			t.Fatalf("spool did not drain: %d", cnt)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if err = fwd.Close(); !errors.Is(err, ErrSinkRejected) {
+		t.Fatalf("rejection was not reported: %v", err)
+	}
+	wh.Lock()
+	defer wh.Unlock()
+	exp := []string{"entry 0\nentry 1\n", "entry 4\nentry 5\n"}
+	if len(wh.bodies) != len(exp) || wh.bodies[0] != exp[0] || wh.bodies[1] != exp[1] {
+		t.Fatalf("bad delivered batches %q", wh.bodies)
+	}
+}
+
+func TestForwarderSpoolTCPReconnect(t *testing.T) {
+	lst, err := net.Listen(`tcp`, `127.0.0.1:0`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lst.Close()
+	reset := make(chan struct{})
+	lines := make(chan string, 16)
+	go func() {
+		//reset the first connection so the next write on it fails
+		conn, err := lst.Accept()
+		if err != nil {
+			return
+		}
+		conn.(*net.TCPConn).SetLinger(0)
+		conn.Close()
+		close(reset)
+		if conn, err = lst.Accept(); err != nil {
+			return
+		}
+		defer conn.Close()
+		sc := bufio.NewScanner(conn)
+		for sc.Scan() {
+			lines <- sc.Text()
+		}
+	}()
+	fwd, err := NewForwarder(ForwarderConfig{
+		Target:    lst.Addr().String(),
+		Spool_Dir: filepath.Join(t.TempDir(), `spool`),
+	}, &testTagger{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-reset
+	time.Sleep(50 * time.Millisecond)
+	fwd.Process(makeSpoolEnts(0, 3))
+	for i := 0; i < 3; i++ {
+		select {
+		case l := <-lines:
+			if exp := fmt.Sprintf("entry %d", i); l != exp {
+				t.Fatalf("bad line %q != %q", l, exp)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for forwarded entries")
+		}
+	}
+	if err = fwd.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if cnt, _, _ := fwd.SpoolStats(); cnt != 0 {
+		t.Fatalf("bad spool depth %d", cnt)
+	}
+}
@@ -0,0 +1,400 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package processors
+
+import (
+	"container/list"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gravwell/gravwell/v3/ingest/config"
+	"github.com/gravwell/gravwell/v3/ingest/entry"
+	"github.com/oschwald/maxminddb-golang"
+)
+
+const (
+	IPEnrichProcessor = `ip-enrich`
+
+	defaultIPEnrichCacheSize int    = 1024
+	defaultIPEnrichLanguage  string = `en`
+
+	ipEnrichCountry     = `country`
+	ipEnrichCountryName = `country_name`
+	ipEnrichCity        = `city`
+	ipEnrichASN         = `asn`
+	ipEnrichOrg         = `org`
+)
+
+var (
+	ErrMissingMMDB       = errors.New("At least one of City-DB or ASN-DB must be specified")
+	ErrIPEnrichInvalidIP = errors.New("Extracted value is not an IP address")
+	ErrIPEnrichNotFound  = errors.New("IP address not found in any database")
+)
+
+type IPEnrichConfig struct {
+	City_DB          string   // path to a MaxMind City or Country database
+	ASN_DB           string   // path to a MaxMind ASN database
+	JSON_Field       string   // dotted JSON path containing the IP
+	Regex            string   // regular expression used to extract the IP
+	Regex_Name       string   // named capture group in Regex, defaults to the first group
+	Enumerated_Value string   // name of an existing enumerated value containing the IP
+	Use_SRC          bool     // use the entry SRC as the IP
+	Attach           []string // country, country_name, city, asn, org; defaults to all available
+	EV_Prefix        string   // prefix applied to the names of attached enumerated values
+	Language         string   // language used for country and city names, default is en
+	Cache_Size       int      // number of IP lookups to cache, default is 1024
+	Reload_Interval  string   // how often to check the databases for changes, default is 10s
+	Drop_Misses      bool     // drop entries where no IP could be extracted or nothing was found
+}
+
+func IPEnrichLoadConfig(vc *config.VariableConfig) (c IPEnrichConfig, err error) {
+	if err = vc.MapTo(&c); err == nil {
+		_, _, err = c.validate()
+	}
+	return
+}
+
+func (c *IPEnrichConfig) validate() (ve valueExtractor, interval time.Duration, err error) {
+	if c.City_DB == `` && c.ASN_DB == `` {
+		err = ErrMissingMMDB
+		return
+	}
+	if ve, err = newValueExtractor(c.JSON_Field, c.Regex, c.Regex_Name, c.Enumerated_Value, c.Use_SRC); err != nil {
+		return
+	}
+	if c.Cache_Size < 0 {
+		err = errors.New("Cache-Size cannot be negative")
+		return
+	} else if c.Cache_Size == 0 {
+		c.Cache_Size = defaultIPEnrichCacheSize
+	}
+	if c.Language == `` {
+		c.Language = defaultIPEnrichLanguage
+	}
+	if interval, err = parseReloadInterval(c.Reload_Interval); err != nil {
+		return
+	}
+	if len(c.Attach) == 0 {
+		if c.City_DB != `` {
+			c.Attach = append(c.Attach, ipEnrichCountry, ipEnrichCity)
+		}
+		if c.ASN_DB != `` {
+			c.Attach = append(c.Attach, ipEnrichASN, ipEnrichOrg)
+		}
+	}
+	for i, a := range c.Attach {
+		a = strings.ToLower(strings.TrimSpace(a))
+		switch a {
+		case ipEnrichCountry, ipEnrichCountryName, ipEnrichCity:
+			if c.City_DB == `` {
+				err = fmt.Errorf("Attaching %s requires City-DB", a)
+				return
+			}
+		case ipEnrichASN, ipEnrichOrg:
+			if c.ASN_DB == `` {
+				err = fmt.Errorf("Attaching %s requires ASN-DB", a)
+				return
+			}
+		default:
+			err = fmt.Errorf("Unknown Attach value %q", a)
+			return
+		}
+		c.Attach[i] = a
+	}
+	return
+}
+
+type mmdbCityRecord struct {
+	City struct {
+		Names map[string]string `maxminddb:"names"`
+	} `maxminddb:"city"`
+	Country struct {
+		ISOCode string            `maxminddb:"iso_code"`
+		Names   map[string]string `maxminddb:"names"`
+	} `maxminddb:"country"`
+}
+
+type mmdbASNRecord struct {
+	ASN uint   `maxminddb:"autonomous_system_number"`
+	Org string `maxminddb:"autonomous_system_organization"`
+}
+
+// mmdb wraps a MaxMind database reader along with its file tracker
+type mmdb struct {
+	rdr *maxminddb.Reader
+	ft  *fileTracker
+}
+
+// openMMDB reads the entire database into memory rather than mapping it so that
+// a database being overwritten in place cannot fault the ingester.
+func openMMDB(pth string, interval time.Duration) (db *mmdb, err error) {
+	var ft *fileTracker
+	var buff []byte
+	var rdr *maxminddb.Reader
+	if ft, err = newFileTracker(pth, interval); err != nil {
+		return
+	} else if buff, err = os.ReadFile(pth); err != nil {
+		return
+	} else if rdr, err = maxminddb.FromBytes(buff); err != nil {
+		err = fmt.Errorf("Failed to load %s: %w", pth, err)
+		return
+	}
+	db = &mmdb{
+		rdr: rdr,
+		ft:  ft,
+	}
+	return
+}
+
+// reload checks if the backing file changed and swaps the reader if so.
+// If the new file cannot be loaded the existing database is retained.
+func (db *mmdb) reload() (reloaded bool) {
+	if db == nil || !db.ft.changed() {
+		return
+	}
+	buff, err := os.ReadFile(db.ft.path)
+	if err != nil {
+		return
+	}
+	rdr, err := maxminddb.FromBytes(buff)
+	if err != nil {
+		return
+	}
+	db.rdr.Close()
+	db.rdr = rdr
+	return true
+}
+
+func (db *mmdb) lookup(ip net.IP, v interface{}) error {
+	return db.rdr.Lookup(ip, v)
+}
+
+func (db *mmdb) Close() (err error) {
+	if db != nil && db.rdr != nil {
+		err = db.rdr.Close()
+	}
+	return
+}
+
+type IPEnrich struct {
+	nocloser
+	failureHook
+	IPEnrichConfig
+	ve    valueExtractor
+	city  *mmdb
+	asn   *mmdb
+	cache *evCache
+}
+
+func NewIPEnrich(cfg IPEnrichConfig) (*IPEnrich, error) {
+	ipe := &IPEnrich{}
+	if err := ipe.init(cfg); err != nil {
+		return nil, err
+	}
+	return ipe, nil
+}
+
+func (ipe *IPEnrich) Config(v interface{}) (err error) {
+	if v == nil {
+		err = ErrNilConfig
+	} else if cfg, ok := v.(IPEnrichConfig); ok {
+		err = ipe.init(cfg)
+	} else {
+		err = fmt.Errorf("Invalid configuration, unknown type type %T", v)
+	}
+	return
+}
+
+func (ipe *IPEnrich) init(cfg IPEnrichConfig) (err error) {
+	var ve valueExtractor
+	var interval time.Duration
+	var city, asn *mmdb
+	if ve, interval, err = cfg.validate(); err != nil {
+		return
+	}
+	if cfg.City_DB != `` {
+		if city, err = openMMDB(cfg.City_DB, interval); err != nil {
+			return
+		}
+	}
+	if cfg.ASN_DB != `` {
+		if asn, err = openMMDB(cfg.ASN_DB, interval); err != nil {
+			city.Close()
+			return
+		}
+	}
+	ipe.city.Close()
+	ipe.asn.Close()
+	ipe.IPEnrichConfig = cfg
+	ipe.ve = ve
+	ipe.city = city
+	ipe.asn = asn
+	ipe.cache = newEVCache(cfg.Cache_Size)
+	return
+}
+
+func (ipe *IPEnrich) Close() (err error) {
+	err = addError(ipe.city.Close(), err)
+	err = addError(ipe.asn.Close(), err)
+	return
+}
+
+func (ipe *IPEnrich) Process(ents []*entry.Entry) (rset []*entry.Entry, err error) {
+	if len(ents) == 0 {
+		return
+	}
+	// a reload of either database invalidates the cache
+	cityReload := ipe.city.reload()
+	if asnReload := ipe.asn.reload(); cityReload || asnReload {
+		ipe.cache.reset()
+	}
+	rset = ents[:0]
+	for _, ent := range ents {
+		if ent == nil {
+			continue
+		} else if ent = ipe.processItem(ent); ent != nil {
+			rset = append(rset, ent)
+		}
+	}
+	return
+}
+
+func (ipe *IPEnrich) processItem(ent *entry.Entry) *entry.Entry {
+	v, ok := ipe.ve.extract(ent)
+	if !ok {
+		return ipe.miss(ent, ErrExtractionMiss, ipe.Drop_Misses)
+	}
+	ip := net.ParseIP(strings.TrimSpace(string(v)))
+	if ip == nil {
+		return ipe.miss(ent, ErrIPEnrichInvalidIP, ipe.Drop_Misses)
+	}
+	key := string(ip.To16())
+	evs, ok := ipe.cache.get(key)
+	if !ok {
+		evs = ipe.lookup(ip)
+		ipe.cache.add(key, evs)
+	}
+	if len(evs) == 0 {
+		return ipe.miss(ent, ErrIPEnrichNotFound, ipe.Drop_Misses)
+	}
+	ent.AddEnumeratedValues(evs)
+	return ent
+}
+
+// lookup resolves the IP against the configured databases and builds the set of enumerated values to attach
+func (ipe *IPEnrich) lookup(ip net.IP) (evs []entry.EnumeratedValue) {
+	var cr mmdbCityRecord
+	var ar mmdbASNRecord
+	if ipe.city != nil {
+		if err := ipe.city.lookup(ip, &cr); err != nil {
+			cr = mmdbCityRecord{}
+		}
+	}
+	if ipe.asn != nil {
+		if err := ipe.asn.lookup(ip, &ar); err != nil {
+			ar = mmdbASNRecord{}
+		}
+	}
+	for _, a := range ipe.Attach {
+		var ed entry.EnumeratedData
+		switch a {
+		case ipEnrichCountry:
+			if cr.Country.ISOCode == `` {
+				continue
+			}
+			ed = entry.StringEnumData(cr.Country.ISOCode)
+		case ipEnrichCountryName:
+			n, ok := cr.Country.Names[ipe.Language]
+			if !ok {
+				continue
+			}
+			ed = entry.StringEnumData(n)
+		case ipEnrichCity:
+			n, ok := cr.City.Names[ipe.Language]
+			if !ok {
+				continue
+			}
+			ed = entry.StringEnumData(n)
+		case ipEnrichASN:
+			if ar.ASN == 0 {
+				continue
+			}
+			ed = entry.UintEnumData(ar.ASN)
+		case ipEnrichOrg:
+			if ar.Org == `` {
+				continue
+			}
+			ed = entry.StringEnumData(ar.Org)
+		default:
+			continue
+		}
+		evs = append(evs, entry.EnumeratedValue{
+			Name:  ipe.EV_Prefix + a,
+			Value: ed,
+		})
+	}
+	return
+}
+
+// evCache is a small LRU cache of enumerated value sets keyed by a lookup value
+type evCache struct {
+	max int
+	ll  *list.List
+	mp  map[string]*list.Element
+}
+
+type evCacheItem struct {
+	key string
+	evs []entry.EnumeratedValue
+}
+
+func newEVCache(max int) *evCache {
+	return &evCache{
+		max: max,
+		ll:  list.New(),
+		mp:  make(map[string]*list.Element, max),
+	}
+}
+
+func (c *evCache) get(key string) (evs []entry.EnumeratedValue, ok bool) {
+	var el *list.Element
+	if el, ok = c.mp[key]; ok {
+		c.ll.MoveToFront(el)
+		evs = el.Value.(*evCacheItem).evs
+	}
+	return
+}
+
+func (c *evCache) add(key string, evs []entry.EnumeratedValue) {
+	if el, ok := c.mp[key]; ok {
+		c.ll.MoveToFront(el)
+		el.Value.(*evCacheItem).evs = evs
+		return
+	}
+	c.mp[key] = c.ll.PushFront(&evCacheItem{key: key, evs: evs})
+	for c.ll.Len() > c.max {
+		if el := c.ll.Back(); el != nil {
+			c.ll.Remove(el)
+			delete(c.mp, el.Value.(*evCacheItem).key)
+		}
+	}
+}
+
+func (c *evCache) reset() {
+	c.ll.Init()
+	c.mp = make(map[string]*list.Element, c.max)
+}
+
+func (c *evCache) len() int {
+	return c.ll.Len()
+}
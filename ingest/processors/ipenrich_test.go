@@ -0,0 +1,250 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package processors
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gravwell/gravwell/v3/ingest/entry"
+)
+
+const (
+	testCityDB    = `test_data/ipenrich/city.mmdb`
+	testCityAltDB = `test_data/ipenrich/city_alt.mmdb`
+	testASNDB     = `test_data/ipenrich/asn.mmdb`
+)
+
+func TestIPEnrichConfig(t *testing.T) {
+	b := `
+	[preprocessor "geo"]
+		type = ip-enrich
+		City-DB = "` + testCityDB + `"
+		ASN-DB = "` + testASNDB + `"
+		JSON-Field = "src.ip"
+		EV-Prefix = "src_"
+		Cache-Size = 16
+	`
+	p, err := testLoadPreprocessor(b, `geo`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ipe, ok := p.(*IPEnrich)
+	if !ok {
+		t.Fatalf("bad processor type %T", p)
+	}
+	if len(ipe.Attach) != 4 || ipe.Cache_Size != 16 || ipe.Language != `en` {
+		t.Fatalf("bad config defaults: %+v", ipe.IPEnrichConfig)
+	}
+	if err = p.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestIPEnrichBadConfig(t *testing.T) {
+	bad := []IPEnrichConfig{
+		// no databases
+		IPEnrichConfig{Use_SRC: true},
+		// no extraction
+		IPEnrichConfig{City_DB: testCityDB},
+		// multiple extractions
+		IPEnrichConfig{City_DB: testCityDB, Use_SRC: true, JSON_Field: `foo`},
+		// asn attach without an ASN database
+		IPEnrichConfig{City_DB: testCityDB, Use_SRC: true, Attach: []string{`asn`}},
+		// unknown attach
+		IPEnrichConfig{City_DB: testCityDB, Use_SRC: true, Attach: []string{`stuff`}},
+		// bad regex name
+		IPEnrichConfig{City_DB: testCityDB, Regex: `ip=(?P<ip>\S+)`, Regex_Name: `foo`},
+		// bad interval
+		IPEnrichConfig{City_DB: testCityDB, Use_SRC: true, Reload_Interval: `soon`},
+	}
+	for i, c := range bad {
+		if _, _, err := c.validate(); err == nil {
+			t.Fatalf("Failed to catch bad config %d (%+v)", i, c)
+		}
+	}
+	// missing file
+	if _, err := NewIPEnrich(IPEnrichConfig{City_DB: `test_data/ipenrich/nothere.mmdb`, Use_SRC: true}); err == nil {
+		t.Fatal("failed to catch missing database")
+	}
+}
+
+func TestIPEnrichExtractions(t *testing.T) {
+	cfgs := []IPEnrichConfig{
+		IPEnrichConfig{JSON_Field: `src.ip`},
+		IPEnrichConfig{Regex: `src=(?P<ip>\S+)`, Regex_Name: `ip`},
+		IPEnrichConfig{Enumerated_Value: `src_ip`},
+		IPEnrichConfig{Use_SRC: true},
+	}
+	for i, cfg := range cfgs {
+		cfg.City_DB = testCityDB
+		cfg.ASN_DB = testASNDB
+		ipe, err := NewIPEnrich(cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ent := &entry.Entry{
+			TS:   entry.Now(),
+			SRC:  net.ParseIP(`8.8.8.8`),
+			Data: []byte(`{"src": {"ip": "8.8.8.8"}, "msg": "src=8.8.8.8 dst=1.1.1.1"}`),
+		}
+		ent.AddEnumeratedValueEx(`src_ip`, net.ParseIP(`8.8.8.8`))
+		set, err := ipe.Process([]*entry.Entry{ent})
+		if err != nil {
+			t.Fatal(err)
+		} else if len(set) != 1 {
+			t.Fatalf("%d: bad result count %d", i, len(set))
+		}
+		checkEVs(t, set[0], map[string]interface{}{
+			`country`: `US`,
+			`city`:    `Mountain View`,
+			`asn`:     uint64(15169),
+			`org`:     `Google LLC`,
+		})
+		if err = ipe.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestIPEnrichMisses(t *testing.T) {
+	cfg := IPEnrichConfig{
+		City_DB:    testCityDB,
+		ASN_DB:     testASNDB,
+		JSON_Field: `ip`,
+		Attach:     []string{`country`, `country_name`, `asn`},
+		EV_Prefix:  `src_`,
+	}
+	ipe, err := NewIPEnrich(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ipe.Close()
+	ents := []*entry.Entry{
+		&entry.Entry{Data: []byte(`{"ip": "81.2.69.160"}`)}, // city database only
+		&entry.Entry{Data: []byte(`{"ip": "10.0.0.1"}`)},    // not present
+		&entry.Entry{Data: []byte(`{"ip": "notanip"}`)},
+		&entry.Entry{Data: []byte(`{"foo": "bar"}`)},
+		&entry.Entry{Data: []byte(`{"ip": "2001:db8::1"}`)},
+	}
+	set, err := ipe.Process(ents)
+	if err != nil {
+		t.Fatal(err)
+	} else if len(set) != 5 {
+		t.Fatalf("bad passthrough count: %d", len(set))
+	}
+	checkEVs(t, set[0], map[string]interface{}{`src_country`: `GB`, `src_country_name`: `United Kingdom`})
+	if _, ok := set[0].GetEnumeratedValue(`src_asn`); ok {
+		t.Fatal("unexpected asn")
+	}
+	for _, ent := range set[1:4] {
+		if ent.EVB.Count() != 0 {
+			t.Fatalf("unexpected enumerated values: %v", ent.EnumeratedValues())
+		}
+	}
+	checkEVs(t, set[4], map[string]interface{}{`src_country`: `DE`, `src_asn`: uint64(64496)})
+
+	// now with drop misses
+	cfg.Drop_Misses = true
+	if err = ipe.Config(cfg); err != nil {
+		t.Fatal(err)
+	}
+	ents = []*entry.Entry{
+		&entry.Entry{Data: []byte(`{"ip": "10.0.0.1"}`)},
+		&entry.Entry{Data: []byte(`{"ip": "1.1.1.1"}`)},
+		&entry.Entry{Data: []byte(`{"foo": "bar"}`)},
+	}
+	if set, err = ipe.Process(ents); err != nil {
+		t.Fatal(err)
+	} else if len(set) != 1 {
+		t.Fatalf("bad drop count: %d", len(set))
+	}
+	checkEVs(t, set[0], map[string]interface{}{`src_country`: `AU`, `src_asn`: uint64(13335)})
+}
+
+func TestIPEnrichReload(t *testing.T) {
+	dir := t.TempDir()
+	pth := filepath.Join(dir, `city.mmdb`)
+	copyTestFile(t, testCityDB, pth)
+	cfg := IPEnrichConfig{
+		City_DB:         pth,
+		Use_SRC:         true,
+		Reload_Interval: `1ms`,
+	}
+	ipe, err := NewIPEnrich(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ipe.Close()
+	ents := []*entry.Entry{&entry.Entry{SRC: net.ParseIP(`1.1.1.1`)}}
+	if ents, err = ipe.Process(ents); err != nil || len(ents) != 1 {
+		t.Fatal(err, len(ents))
+	}
+	checkEVs(t, ents[0], map[string]interface{}{`country`: `AU`, `city`: `Sydney`})
+	if ipe.cache.len() != 1 {
+		t.Fatalf("bad cache size %d", ipe.cache.len())
+	}
+
+	//swap the file out from under it
+	copyTestFile(t, testCityAltDB, pth)
+	ts := time.Now().Add(time.Minute)
+	if err = os.Chtimes(pth, ts, ts); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(5 * time.Millisecond)
+	ents = []*entry.Entry{&entry.Entry{SRC: net.ParseIP(`1.1.1.1`)}}
+	if ents, err = ipe.Process(ents); err != nil || len(ents) != 1 {
+		t.Fatal(err, len(ents))
+	}
+	checkEVs(t, ents[0], map[string]interface{}{`country`: `NZ`, `city`: `Auckland`})
+}
+
+func TestEVCache(t *testing.T) {
+	c := newEVCache(2)
+	c.add(`a`, nil)
+	c.add(`b`, nil)
+	if _, ok := c.get(`a`); !ok {
+		t.Fatal("missing a")
+	}
+	c.add(`c`, nil) // evicts b
+	if _, ok := c.get(`b`); ok {
+		t.Fatal("b not evicted")
+	} else if _, ok = c.get(`a`); !ok {
+		t.Fatal("a evicted")
+	} else if c.len() != 2 {
+		t.Fatal("bad length", c.len())
+	}
+	c.reset()
+	if c.len() != 0 {
+		t.Fatal("bad reset")
+	}
+}
+
+func checkEVs(t *testing.T, ent *entry.Entry, evs map[string]interface{}) {
+	t.Helper()
+	for k, v := range evs {
+		if val, ok := ent.GetEnumeratedValue(k); !ok {
+			t.Fatalf("missing enumerated value %s: %v", k, ent.EnumeratedValues())
+		} else if val != v {
+			t.Fatalf("bad %s enumerated value: %v(%T) != %v(%T)", k, val, val, v, v)
+		}
+	}
+}
+
+func copyTestFile(t *testing.T, src, dst string) {
+	t.Helper()
+	if bts, err := os.ReadFile(src); err != nil {
+		t.Fatal(err)
+	} else if err = os.WriteFile(dst, bts, 0640); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -33,6 +33,9 @@ var (
 	ErrDuplicateKey         = errors.New("Duplicate extraction key")
 	ErrDuplicateKeyname     = errors.New("Duplicate keys")
 	ErrSingleArraySplitOnly = errors.New("jsonarraysplit only supports a single extraction")
+	ErrInvalidJSON          = errors.New("Invalid JSON")
+	ErrExtractionMiss       = errors.New("No extractions found")
+	ErrStrictExtractionMiss = errors.New("Not all extractions found")
 )
 
 type JsonExtractConfig struct {
@@ -67,6 +70,7 @@ func (c *JsonExtractConfig) validate() (err error) {
 
 type JsonExtractor struct {
 	nocloser
+	failureHook
 	JsonExtractConfig
 	bldr builder
 }
@@ -119,20 +123,17 @@ func (je *JsonExtractor) Process(ents []*entry.Entry) (rset []*entry.Entry, err
 func (je *JsonExtractor) processItem(ent *entry.Entry) *entry.Entry {
 	// if we have drop misses or strict extraction, validate the JSON first
 	if (je.Drop_Misses || je.Strict_Extraction) && !json.Valid(ent.Data) {
-		return nil
+		return je.miss(ent, ErrInvalidJSON, true)
 	}
 	if err := je.bldr.extract(ent.Data); err != nil {
 		je.bldr.reset()
-		if !je.Drop_Misses {
-			return ent
-		}
-		return nil
+		return je.miss(ent, err, je.Drop_Misses)
 	}
 	data, cnt := je.bldr.render()
 	if je.Strict_Extraction && cnt != len(je.bldr.keynames) {
-		return nil //just dropping the entry
-	} else if cnt == 0 && !je.Drop_Misses {
-		return ent
+		return je.miss(ent, ErrStrictExtractionMiss, true) //just dropping the entry
+	} else if cnt == 0 && (!je.Drop_Misses || je.failFn != nil) {
+		return je.miss(ent, ErrExtractionMiss, false)
 	} else if len(data) > 0 {
 		ent.Data = data
 	}
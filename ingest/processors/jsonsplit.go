@@ -76,6 +76,7 @@ func (jasc *JsonArraySplitConfig) getKeyData() (key []string, keyname string, er
 
 type JsonArraySplitter struct {
 	nocloser
+	failureHook
 	JsonArraySplitConfig
 	key        []string
 	keyname    string
@@ -141,7 +142,9 @@ func (je *JsonArraySplitter) Process(ents []*entry.Entry) ([]*entry.Entry, error
 			continue
 		}
 		if set, err := je.processItem(ent); err != nil {
-			continue
+			if ent = je.miss(ent, err, true); ent != nil {
+				r = append(r, ent)
+			}
 		} else if len(set) > 0 {
 			r = append(r, set...)
 		}
@@ -180,8 +183,10 @@ func (je *JsonArraySplitter) processItem(ent *entry.Entry) (rset []*entry.Entry,
 	}
 	if _, err = jsonparser.ArrayEach(ent.Data, cb, je.key...); err != nil {
 		if err == jsonparser.KeyPathNotFoundError {
-			if !je.Drop_Misses && rset == nil {
-				rset = []*entry.Entry{ent}
+			if rset == nil {
+				if ent = je.miss(ent, err, je.Drop_Misses); ent != nil {
+					rset = []*entry.Entry{ent}
+				}
 			}
 			err = nil
 		}
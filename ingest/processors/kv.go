@@ -0,0 +1,371 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package processors
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gravwell/gravwell/v3/ingest/config"
+	"github.com/gravwell/gravwell/v3/ingest/entry"
+)
+
+const (
+	KVProcessor = `kv`
+
+	kvOutputEnumerated = `enumerated`
+	kvOutputJSON       = `json`
+
+	defaultKVDelimiter = `=`
+	defaultKVQuote     = `"`
+)
+
+var (
+	ErrKVOutput          = errors.New("Output must be either 'enumerated' or 'json' (default enumerated)")
+	ErrKVAllowDenyConfig = errors.New("Allow-Key and Deny-Key cannot both be specified")
+	ErrKVRouteKey        = errors.New("Route requires Route-Key")
+	ErrKVQuote           = errors.New("Quote-Char must be a single character")
+	ErrKVNoPairs         = errors.New("No key value pairs found")
+	ErrKVNoRouteKey      = errors.New("Route-Key not found")
+)
+
+type KVConfig struct {
+	Pair_Delimiter string   // separator between pairs, default is any whitespace
+	KV_Delimiter   string   // separator between a key and its value, default is =
+	Quote_Char     string   // character used to quote values, default is "
+	Allow_Key      []string // if set only these keys are extracted
+	Deny_Key       []string // keys that are never extracted
+	Output         string   // enumerated or json
+	EV_Prefix      string   // prefix applied to the names of attached enumerated values
+	Route_Key      string   // optional key whose value is used to route entries
+	Route          []string // routes specified as value:tag, an empty tag drops the entry
+	Drop_Misses    bool     // drop entries whose Route-Key value has no route or that contain no pairs
+}
+
+func KVLoadConfig(vc *config.VariableConfig) (c KVConfig, err error) {
+	if err = vc.MapTo(&c); err == nil {
+		_, err = c.validate()
+	}
+	return
+}
+
+func (c *KVConfig) validate() (rts []route, err error) {
+	if c.KV_Delimiter == `` {
+		c.KV_Delimiter = defaultKVDelimiter
+	}
+	if c.Quote_Char == `` {
+		c.Quote_Char = defaultKVQuote
+	} else if len(c.Quote_Char) != 1 {
+		err = ErrKVQuote
+		return
+	}
+	if c.Pair_Delimiter != `` && strings.Contains(c.Pair_Delimiter, c.KV_Delimiter) {
+		err = errors.New("Pair-Delimiter and KV-Delimiter cannot overlap")
+		return
+	}
+	if len(c.Allow_Key) > 0 && len(c.Deny_Key) > 0 {
+		err = ErrKVAllowDenyConfig
+		return
+	}
+	switch c.Output = strings.ToLower(strings.TrimSpace(c.Output)); c.Output {
+	case ``:
+		c.Output = kvOutputEnumerated
+	case kvOutputEnumerated, kvOutputJSON:
+	default:
+		err = ErrKVOutput
+		return
+	}
+	if len(c.Route) > 0 && c.Route_Key == `` {
+		err = ErrKVRouteKey
+		return
+	}
+	for _, v := range c.Route {
+		var r route
+		if r.val, r.tag, err = getRoute(v); err != nil {
+			return
+		}
+		if r.tag == `` {
+			r.drop = true
+		}
+		rts = append(rts, r)
+	}
+	return
+}
+
+type kvPair struct {
+	key string
+	val string
+}
+
+type KV struct {
+	nocloser
+	failureHook
+	KVConfig
+	allow  map[string]struct{}
+	deny   map[string]struct{}
+	routes map[string]entry.EntryTag
+	drops  map[string]struct{}
+	bb     *bytes.Buffer
+	pairs  []kvPair
+}
+
+func NewKV(cfg KVConfig, tagger Tagger) (*KV, error) {
+	kv := &KV{
+		bb: bytes.NewBuffer(nil),
+	}
+	if err := kv.init(cfg, tagger); err != nil {
+		return nil, err
+	}
+	return kv, nil
+}
+
+func (kv *KV) Config(v interface{}, tagger Tagger) (err error) {
+	if v == nil {
+		err = ErrNilConfig
+	} else if cfg, ok := v.(KVConfig); ok {
+		err = kv.init(cfg, tagger)
+	} else {
+		err = fmt.Errorf("Invalid configuration, unknown type type %T", v)
+	}
+	return
+}
+
+func (kv *KV) init(cfg KVConfig, tagger Tagger) (err error) {
+	var rts []route
+	if rts, err = cfg.validate(); err != nil {
+		return
+	}
+	kv.KVConfig = cfg
+	kv.allow = makeKeySet(cfg.Allow_Key)
+	kv.deny = makeKeySet(cfg.Deny_Key)
+	kv.routes = make(map[string]entry.EntryTag)
+	kv.drops = make(map[string]struct{})
+	for _, r := range rts {
+		if r.drop {
+			kv.drops[r.val] = empty
+		} else {
+			var tg entry.EntryTag
+			if tg, err = tagger.NegotiateTag(r.tag); err != nil {
+				err = fmt.Errorf("Failed to get tag %s for %s: %v", r.tag, r.val, err)
+				return
+			}
+			kv.routes[r.val] = tg
+		}
+	}
+	return
+}
+
+func makeKeySet(keys []string) (mp map[string]struct{}) {
+	if len(keys) == 0 {
+		return
+	}
+	mp = make(map[string]struct{}, len(keys))
+	for _, k := range keys {
+		mp[strings.TrimSpace(k)] = empty
+	}
+	return
+}
+
+func (kv *KV) Process(ents []*entry.Entry) (rset []*entry.Entry, err error) {
+	if len(ents) == 0 {
+		return
+	}
+	rset = ents[:0]
+	for _, ent := range ents {
+		if ent == nil {
+			continue
+		} else if ent = kv.processItem(ent); ent != nil {
+			rset = append(rset, ent)
+		}
+	}
+	return
+}
+
+func (kv *KV) processItem(ent *entry.Entry) *entry.Entry {
+	kv.pairs = kv.parse(ent.Data, kv.pairs[:0])
+	if len(kv.pairs) == 0 {
+		return kv.miss(ent, ErrKVNoPairs, kv.Drop_Misses)
+	}
+	var routeVal string
+	var routeFound bool
+	for _, p := range kv.pairs {
+		if kv.Route_Key != `` && p.key == kv.Route_Key {
+			routeVal, routeFound = p.val, true
+		}
+	}
+	if kv.Output == kvOutputJSON {
+		if data, err := kv.renderJSON(); err == nil {
+			ent.Data = data
+		}
+	} else {
+		for _, p := range kv.pairs {
+			ent.AddEnumeratedValue(entry.EnumeratedValue{
+				Name:  kv.EV_Prefix + p.key,
+				Value: entry.StringEnumData(p.val),
+			})
+		}
+	}
+	if kv.Route_Key != `` {
+		if !routeFound {
+			return kv.miss(ent, ErrKVNoRouteKey, kv.Drop_Misses)
+		} else if tag, ok := kv.routes[routeVal]; ok {
+			ent.Tag = tag
+		} else if _, drop := kv.drops[routeVal]; drop {
+			return nil
+		} else {
+			return kv.miss(ent, ErrNoRoute, kv.Drop_Misses)
+		}
+	}
+	return ent
+}
+
+func (kv *KV) renderJSON() ([]byte, error) {
+	kv.bb.Reset()
+	kv.bb.WriteByte('{')
+	seen := make(map[string]int, len(kv.pairs))
+	var cnt int
+	// duplicate keys take the last value, but keep the position of the first
+	vals := make([]string, 0, len(kv.pairs))
+	keys := make([]string, 0, len(kv.pairs))
+	for _, p := range kv.pairs {
+		if idx, ok := seen[p.key]; ok {
+			vals[idx] = p.val
+			continue
+		}
+		seen[p.key] = len(keys)
+		keys = append(keys, p.key)
+		vals = append(vals, p.val)
+	}
+	for i := range keys {
+		k, err := json.Marshal(kv.EV_Prefix + keys[i])
+		if err != nil {
+			return nil, err
+		}
+		v, err := json.Marshal(vals[i])
+		if err != nil {
+			return nil, err
+		}
+		if cnt > 0 {
+			kv.bb.WriteByte(',')
+		}
+		kv.bb.Write(k)
+		kv.bb.WriteByte(':')
+		kv.bb.Write(v)
+		cnt++
+	}
+	kv.bb.WriteByte('}')
+	return append([]byte{}, kv.bb.Bytes()...), nil
+}
+
+// parse walks the buffer extracting key/value pairs.  Tokens without a key/value delimiter are skipped,
+// and quoted values may contain the pair delimiter and backslash escaped quotes.
+func (kv *KV) parse(data []byte, pairs []kvPair) []kvPair {
+	kvd := []byte(kv.KV_Delimiter)
+	quote := kv.Quote_Char[0]
+	for len(data) > 0 {
+		data = kv.skipPairDelimiters(data)
+		if len(data) == 0 {
+			break
+		}
+		// read the key
+		end := kv.pairEnd(data)
+		kidx := bytes.Index(data[:end], kvd)
+		if kidx == -1 {
+			// bare token, skip it
+			data = data[end:]
+			continue
+		}
+		key := strings.TrimSpace(string(data[:kidx]))
+		data = data[kidx+len(kvd):]
+		if kv.Pair_Delimiter != `` {
+			// whitespace isn't a delimiter, so allow it ahead of a quoted value
+			data = bytes.TrimLeft(data, " \t")
+		}
+
+		var val string
+		if len(data) > 0 && data[0] == quote {
+			var n int
+			val, n = unquoteKV(data, quote)
+			data = data[n:]
+		} else {
+			end = kv.pairEnd(data)
+			val = strings.TrimSpace(string(data[:end]))
+			data = data[end:]
+		}
+		if key == `` || !kv.keyAllowed(key) {
+			continue
+		}
+		pairs = append(pairs, kvPair{key: key, val: val})
+	}
+	return pairs
+}
+
+func (kv *KV) keyAllowed(k string) (ok bool) {
+	if kv.allow != nil {
+		_, ok = kv.allow[k]
+	} else if kv.deny != nil {
+		_, ok = kv.deny[k]
+		ok = !ok
+	} else {
+		ok = true
+	}
+	return
+}
+
+func (kv *KV) skipPairDelimiters(data []byte) []byte {
+	if kv.Pair_Delimiter == `` {
+		return bytes.TrimLeft(data, " \t\r\n")
+	}
+	for {
+		data = bytes.TrimLeft(data, " \t\r\n")
+		if !bytes.HasPrefix(data, []byte(kv.Pair_Delimiter)) {
+			return data
+		}
+		data = data[len(kv.Pair_Delimiter):]
+	}
+}
+
+// pairEnd returns the offset of the next pair delimiter or the end of the buffer
+func (kv *KV) pairEnd(data []byte) int {
+	var idx int
+	if kv.Pair_Delimiter == `` {
+		idx = bytes.IndexAny(data, " \t\r\n")
+	} else {
+		idx = bytes.Index(data, []byte(kv.Pair_Delimiter))
+	}
+	if idx == -1 {
+		idx = len(data)
+	}
+	return idx
+}
+
+// unquoteKV consumes a quoted value starting at data[0], returning the value and the number of bytes consumed.
+// An unterminated quote consumes the rest of the buffer.
+func unquoteKV(data []byte, quote byte) (string, int) {
+	var sb strings.Builder
+	for i := 1; i < len(data); i++ {
+		switch c := data[i]; c {
+		case '\\':
+			if i+1 < len(data) && (data[i+1] == quote || data[i+1] == '\\') {
+				i++
+				sb.WriteByte(data[i])
+			} else {
+				sb.WriteByte(c)
+			}
+		case quote:
+			return sb.String(), i + 1
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return sb.String(), len(data)
+}
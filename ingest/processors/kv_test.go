@@ -0,0 +1,199 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package processors
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/gravwell/gravwell/v3/ingest/entry"
+)
+
+func TestKVConfig(t *testing.T) {
+	b := `
+	[preprocessor "kv"]
+		type = kv
+		Pair-Delimiter = ","
+		KV-Delimiter = ":"
+		Output = json
+		Route-Key = app
+		Route = "web:webtag"
+		Route = "noise:"
+	`
+	p, err := testLoadPreprocessor(b, `kv`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	kv, ok := p.(*KV)
+	if !ok {
+		t.Fatalf("bad processor type %T", p)
+	} else if len(kv.routes) != 1 || len(kv.drops) != 1 {
+		t.Fatalf("bad routes: %v %v", kv.routes, kv.drops)
+	}
+}
+
+func TestKVBadConfig(t *testing.T) {
+	bad := []KVConfig{
+		KVConfig{Output: `xml`},
+		KVConfig{Quote_Char: `""`},
+		KVConfig{Allow_Key: []string{`a`}, Deny_Key: []string{`b`}},
+		KVConfig{Route: []string{`a:b`}},
+		KVConfig{Route_Key: `a`, Route: []string{`ab`}},
+		KVConfig{Pair_Delimiter: `==`},
+	}
+	for i, c := range bad {
+		if _, err := c.validate(); err == nil {
+			t.Fatalf("Failed to catch bad config %d (%+v)", i, c)
+		}
+	}
+}
+
+func TestKVParse(t *testing.T) {
+	tests := []struct {
+		cfg   KVConfig
+		in    string
+		pairs []kvPair
+	}{
+		{
+			in:    `ts=2024-01-01T00:00:00Z level=info msg="hello \"world\"" bare dur=12ms`,
+			pairs: []kvPair{{`ts`, `2024-01-01T00:00:00Z`}, {`level`, `info`}, {`msg`, `hello "world"`}, {`dur`, `12ms`}},
+		},
+		{
+			cfg:   KVConfig{Pair_Delimiter: `,`, KV_Delimiter: `:`},
+			in:    `user:bob, action: "login, remote", status:ok`,
+			pairs: []kvPair{{`user`, `bob`}, {`action`, `login, remote`}, {`status`, `ok`}},
+		},
+		{
+			cfg:   KVConfig{Pair_Delimiter: `|`, Quote_Char: `'`},
+			in:    `a=1|b='x|y'|c=|d=4`,
+			pairs: []kvPair{{`a`, `1`}, {`b`, `x|y`}, {`c`, ``}, {`d`, `4`}},
+		},
+		{
+			cfg:   KVConfig{Allow_Key: []string{`a`, `c`}},
+			in:    `a=1 b=2 c=3`,
+			pairs: []kvPair{{`a`, `1`}, {`c`, `3`}},
+		},
+		{
+			cfg:   KVConfig{Deny_Key: []string{`a`, `c`}},
+			in:    `a=1 b=2 c=3`,
+			pairs: []kvPair{{`b`, `2`}},
+		},
+		{
+			in: `no pairs here`,
+		},
+	}
+	var tg testTagger
+	for i, tst := range tests {
+		kv, err := NewKV(tst.cfg, &tg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pairs := kv.parse([]byte(tst.in), nil)
+		if len(pairs) != len(tst.pairs) {
+			t.Fatalf("%d: bad pair count: %v != %v", i, pairs, tst.pairs)
+		}
+		for j := range pairs {
+			if pairs[j] != tst.pairs[j] {
+				t.Fatalf("%d: bad pair %d: %v != %v", i, j, pairs[j], tst.pairs[j])
+			}
+		}
+	}
+}
+
+func TestKVEnumerated(t *testing.T) {
+	var tg testTagger
+	kv, err := NewKV(KVConfig{EV_Prefix: `kv_`}, &tg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ents := []*entry.Entry{&entry.Entry{Data: []byte(`level=warn msg="disk full" host=web1`)}}
+	if ents, err = kv.Process(ents); err != nil {
+		t.Fatal(err)
+	} else if len(ents) != 1 {
+		t.Fatalf("bad count %d", len(ents))
+	}
+	checkEVs(t, ents[0], map[string]interface{}{`kv_level`: `warn`, `kv_msg`: `disk full`, `kv_host`: `web1`})
+	if string(ents[0].Data) != `level=warn msg="disk full" host=web1` {
+		t.Fatalf("data modified: %q", ents[0].Data)
+	}
+}
+
+func TestKVJSON(t *testing.T) {
+	var tg testTagger
+	kv, err := NewKV(KVConfig{Output: `json`}, &tg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ents := []*entry.Entry{&entry.Entry{Data: []byte(`level=warn msg="disk \"sda\" full" level=error`)}}
+	if ents, err = kv.Process(ents); err != nil {
+		t.Fatal(err)
+	}
+	if string(ents[0].Data) != `{"level":"error","msg":"disk \"sda\" full"}` {
+		t.Fatalf("bad JSON output: %s", ents[0].Data)
+	}
+	var mp map[string]string
+	if err = json.Unmarshal(ents[0].Data, &mp); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestKVRoute(t *testing.T) {
+	var tg testTagger
+	if _, err := tg.NegotiateTag(`default`); err != nil {
+		t.Fatal(err)
+	}
+	cfg := KVConfig{
+		Route_Key: `app`,
+		Route:     []string{`web:webtag`, `db:dbtag`, `noise:`},
+	}
+	kv, err := NewKV(cfg, &tg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		in   string
+		tag  string
+		drop bool
+	}{
+		{in: `app=web status=200`, tag: `webtag`},
+		{in: `app=db query=select`, tag: `dbtag`},
+		{in: `app=noise x=1`, drop: true},
+		{in: `app=other x=1`, tag: `default`},
+		{in: `x=1`, tag: `default`},
+	}
+	for _, tst := range tests {
+		set, err := kv.Process([]*entry.Entry{&entry.Entry{Data: []byte(tst.in)}})
+		if err != nil {
+			t.Fatal(err)
+		} else if tst.drop {
+			if len(set) != 0 {
+				t.Fatalf("%q not dropped", tst.in)
+			}
+			continue
+		} else if len(set) != 1 {
+			t.Fatalf("%q dropped", tst.in)
+		}
+		if tag, ok := tg.LookupTag(set[0].Tag); !ok || tag != tst.tag {
+			t.Fatalf("%q bad tag %v != %v", tst.in, tag, tst.tag)
+		}
+	}
+
+	// now drop misses
+	cfg.Drop_Misses = true
+	if err = kv.Config(cfg, &tg); err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range []string{`app=other x=1`, `x=1`, `nothing`} {
+		if set, err := kv.Process([]*entry.Entry{&entry.Entry{Data: []byte(v)}}); err != nil {
+			t.Fatal(err)
+		} else if len(set) != 0 {
+			t.Fatalf("%q not dropped", v)
+		}
+	}
+}
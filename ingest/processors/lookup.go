@@ -0,0 +1,317 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package processors
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/asergeyev/nradix"
+	"github.com/gravwell/gravwell/v3/ingest/config"
+	"github.com/gravwell/gravwell/v3/ingest/entry"
+)
+
+const (
+	LookupProcessor = `lookup`
+
+	lookupMissPass    = `pass`
+	lookupMissDrop    = `drop`
+	lookupMissDefault = `default`
+)
+
+var (
+	ErrMissingLookupFile = errors.New("Lookup-File is required")
+	ErrMissingKeyColumn  = errors.New("Key-Column is required")
+	ErrMissAction        = errors.New("Miss-Action must be 'pass', 'drop', or 'default' (default pass)")
+	ErrLookupMiss        = errors.New("Key not found in lookup table")
+)
+
+type LookupConfig struct {
+	Lookup_File      string   // path to a CSV file with a header row
+	Key_Column       string   // name of the column the extracted value is matched against
+	Attach           []string // columns to attach as enumerated values, defaults to all other columns
+	CIDR_Keys        bool     // treat the key column as IPs and CIDR ranges
+	JSON_Field       string   // dotted JSON path containing the lookup value
+	Regex            string   // regular expression used to extract the lookup value
+	Regex_Name       string   // named capture group in Regex, defaults to the first group
+	Enumerated_Value string   // name of an existing enumerated value containing the lookup value
+	Use_SRC          bool     // use the entry SRC as the lookup value
+	EV_Prefix        string   // prefix applied to the names of attached enumerated values
+	Miss_Action      string   // pass, drop, or default
+	Default_Value    string   // value attached for each column on a miss when Miss-Action is default
+	Reload_Interval  string   // how often to check the file for changes, default is 10s
+}
+
+func LookupLoadConfig(vc *config.VariableConfig) (c LookupConfig, err error) {
+	if err = vc.MapTo(&c); err == nil {
+		_, _, err = c.validate()
+	}
+	return
+}
+
+func (c *LookupConfig) validate() (ve valueExtractor, interval time.Duration, err error) {
+	if c.Lookup_File == `` {
+		err = ErrMissingLookupFile
+		return
+	} else if c.Key_Column = strings.TrimSpace(c.Key_Column); c.Key_Column == `` {
+		err = ErrMissingKeyColumn
+		return
+	}
+	if ve, err = newValueExtractor(c.JSON_Field, c.Regex, c.Regex_Name, c.Enumerated_Value, c.Use_SRC); err != nil {
+		return
+	}
+	switch c.Miss_Action = strings.ToLower(strings.TrimSpace(c.Miss_Action)); c.Miss_Action {
+	case ``:
+		c.Miss_Action = lookupMissPass
+	case lookupMissPass, lookupMissDrop, lookupMissDefault:
+	default:
+		err = ErrMissAction
+		return
+	}
+	interval, err = parseReloadInterval(c.Reload_Interval)
+	return
+}
+
+// lookupTable is a loaded CSV file, rows are referenced by index from either the key map or the CIDR tree
+type lookupTable struct {
+	names []string // enumerated value names
+	rows  [][]string
+	keys  map[string]int
+	tree  *nradix.Tree
+}
+
+// loadLookupTable reads the CSV file and indexes it on the key column.
+func (c *LookupConfig) loadLookupTable() (lt *lookupTable, err error) {
+	var fin *os.File
+	if fin, err = os.Open(c.Lookup_File); err != nil {
+		return
+	}
+	defer fin.Close()
+	rdr := csv.NewReader(fin)
+	rdr.FieldsPerRecord = 0 // every row must match the header
+	rdr.TrimLeadingSpace = true
+	rdr.ReuseRecord = false
+
+	var header []string
+	if header, err = rdr.Read(); err != nil {
+		err = fmt.Errorf("Failed to read header from %s: %w", c.Lookup_File, err)
+		return
+	}
+	keyIdx := -1
+	colIdx := map[string]int{}
+	for i, h := range header {
+		h = strings.TrimSpace(h)
+		if h == c.Key_Column {
+			keyIdx = i
+		}
+		colIdx[h] = i
+	}
+	if keyIdx == -1 {
+		err = fmt.Errorf("Key-Column %q not found in %s", c.Key_Column, c.Lookup_File)
+		return
+	}
+	// figure out which columns we attach
+	var idxs []int
+	lt = &lookupTable{}
+	if len(c.Attach) == 0 {
+		for i, h := range header {
+			if i != keyIdx {
+				idxs = append(idxs, i)
+				lt.names = append(lt.names, c.EV_Prefix+strings.TrimSpace(h))
+			}
+		}
+	} else {
+		for _, a := range c.Attach {
+			a = strings.TrimSpace(a)
+			idx, ok := colIdx[a]
+			if !ok {
+				err = fmt.Errorf("Attach column %q not found in %s", a, c.Lookup_File)
+				return
+			}
+			idxs = append(idxs, idx)
+			lt.names = append(lt.names, c.EV_Prefix+a)
+		}
+	}
+	if c.CIDR_Keys {
+		lt.tree = nradix.NewTree(32)
+	} else {
+		lt.keys = map[string]int{}
+	}
+	for {
+		var rec []string
+		if rec, err = rdr.Read(); err != nil {
+			if err == io.EOF {
+				err = nil
+				break
+			}
+			err = fmt.Errorf("Failed to read %s: %w", c.Lookup_File, err)
+			return
+		}
+		row := make([]string, len(idxs))
+		for i, idx := range idxs {
+			row[i] = rec[idx]
+		}
+		key := strings.TrimSpace(rec[keyIdx])
+		if c.CIDR_Keys {
+			var cidr string
+			if cidr, err = normalizeCIDR(key); err != nil {
+				return
+			} else if err = lt.tree.AddCIDR(cidr, len(lt.rows)); err != nil {
+				err = fmt.Errorf("Failed to add %q: %v", key, err)
+				return
+			}
+		} else {
+			lt.keys[key] = len(lt.rows)
+		}
+		lt.rows = append(lt.rows, row)
+	}
+	return
+}
+
+// normalizeCIDR returns a CIDR for either an IP or a CIDR, single IPs are converted to a /32 or /128
+func normalizeCIDR(v string) (string, error) {
+	if _, _, err := net.ParseCIDR(v); err == nil {
+		return v, nil
+	}
+	ip := net.ParseIP(v)
+	if ip == nil {
+		return ``, fmt.Errorf("Invalid IP specification: %v", v)
+	} else if ip.To4() != nil {
+		return ip.String() + "/32", nil
+	}
+	return ip.String() + "/128", nil
+}
+
+// find returns the row for a given key, ok is false on a miss
+func (lt *lookupTable) find(key string) (row []string, ok bool) {
+	var idx int
+	if lt.tree != nil {
+		ip := net.ParseIP(key)
+		if ip == nil {
+			return
+		}
+		var r interface{}
+		if r, _ = lt.tree.FindCIDR(ip.String()); r != nil {
+			idx, ok = r.(int)
+		}
+	} else {
+		idx, ok = lt.keys[key]
+	}
+	if ok && idx >= 0 && idx < len(lt.rows) {
+		row = lt.rows[idx]
+	} else {
+		ok = false
+	}
+	return
+}
+
+type Lookup struct {
+	nocloser
+	failureHook
+	LookupConfig
+	ve    valueExtractor
+	ft    *fileTracker
+	table *lookupTable
+}
+
+func NewLookup(cfg LookupConfig) (*Lookup, error) {
+	lu := &Lookup{}
+	if err := lu.init(cfg); err != nil {
+		return nil, err
+	}
+	return lu, nil
+}
+
+func (lu *Lookup) Config(v interface{}) (err error) {
+	if v == nil {
+		err = ErrNilConfig
+	} else if cfg, ok := v.(LookupConfig); ok {
+		err = lu.init(cfg)
+	} else {
+		err = fmt.Errorf("Invalid configuration, unknown type type %T", v)
+	}
+	return
+}
+
+func (lu *Lookup) init(cfg LookupConfig) (err error) {
+	var ve valueExtractor
+	var interval time.Duration
+	var ft *fileTracker
+	var lt *lookupTable
+	if ve, interval, err = cfg.validate(); err != nil {
+		return
+	} else if ft, err = newFileTracker(cfg.Lookup_File, interval); err != nil {
+		return
+	} else if lt, err = cfg.loadLookupTable(); err != nil {
+		return
+	}
+	lu.LookupConfig = cfg
+	lu.ve = ve
+	lu.ft = ft
+	lu.table = lt
+	return
+}
+
+func (lu *Lookup) Process(ents []*entry.Entry) (rset []*entry.Entry, err error) {
+	if len(ents) == 0 {
+		return
+	}
+	if lu.ft.changed() {
+		// keep the existing table if the new file is bad
+		if lt, lerr := lu.loadLookupTable(); lerr == nil {
+			lu.table = lt
+		}
+	}
+	rset = ents[:0]
+	for _, ent := range ents {
+		if ent == nil {
+			continue
+		} else if ent = lu.processItem(ent); ent != nil {
+			rset = append(rset, ent)
+		}
+	}
+	return
+}
+
+func (lu *Lookup) processItem(ent *entry.Entry) *entry.Entry {
+	var row []string
+	v, ok := lu.ve.extract(ent)
+	if !ok {
+		return lu.missed(ent, ErrExtractionMiss)
+	} else if row, ok = lu.table.find(strings.TrimSpace(string(v))); !ok {
+		return lu.missed(ent, ErrLookupMiss)
+	}
+	for i, name := range lu.table.names {
+		ent.AddEnumeratedValue(entry.EnumeratedValue{
+			Name:  name,
+			Value: entry.StringEnumData(row[i]),
+		})
+	}
+	return ent
+}
+
+// missed applies the Miss-Action, a default fill is a configured answer and is never reported as a failure
+func (lu *Lookup) missed(ent *entry.Entry, err error) *entry.Entry {
+	if lu.Miss_Action != lookupMissDefault {
+		return lu.miss(ent, err, lu.Miss_Action == lookupMissDrop)
+	}
+	for _, name := range lu.table.names {
+		ent.AddEnumeratedValue(entry.EnumeratedValue{
+			Name:  name,
+			Value: entry.StringEnumData(lu.Default_Value),
+		})
+	}
+	return ent
+}
@@ -0,0 +1,236 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package processors
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gravwell/gravwell/v3/ingest/entry"
+)
+
+const (
+	testLookupUsers  = `test_data/lookup/users.csv`
+	testLookupAssets = `test_data/lookup/assets.csv`
+)
+
+func TestLookupConfig(t *testing.T) {
+	b := `
+	[preprocessor "users"]
+		type = lookup
+		Lookup-File = "` + testLookupUsers + `"
+		Key-Column = user
+		JSON-Field = "user.name"
+		Miss-Action = drop
+	`
+	p, err := testLoadPreprocessor(b, `users`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lu, ok := p.(*Lookup)
+	if !ok {
+		t.Fatalf("bad processor type %T", p)
+	}
+	if len(lu.table.rows) != 3 || len(lu.table.names) != 2 {
+		t.Fatalf("bad table: %+v", lu.table)
+	}
+}
+
+func TestLookupBadConfig(t *testing.T) {
+	bad := []LookupConfig{
+		LookupConfig{Key_Column: `user`, Use_SRC: true},
+		LookupConfig{Lookup_File: testLookupUsers, Use_SRC: true},
+		LookupConfig{Lookup_File: testLookupUsers, Key_Column: `user`},
+		LookupConfig{Lookup_File: testLookupUsers, Key_Column: `user`, Use_SRC: true, Miss_Action: `maybe`},
+	}
+	for i, c := range bad {
+		if _, _, err := c.validate(); err == nil {
+			t.Fatalf("Failed to catch bad config %d (%+v)", i, c)
+		}
+	}
+	badLoad := []LookupConfig{
+		// missing key column
+		LookupConfig{Lookup_File: testLookupUsers, Key_Column: `name`, Use_SRC: true},
+		// missing attach column
+		LookupConfig{Lookup_File: testLookupUsers, Key_Column: `user`, Use_SRC: true, Attach: []string{`office`}},
+		// keys aren't CIDRs
+		LookupConfig{Lookup_File: testLookupUsers, Key_Column: `user`, Use_SRC: true, CIDR_Keys: true},
+		// missing file
+		LookupConfig{Lookup_File: `test_data/lookup/nothere.csv`, Key_Column: `user`, Use_SRC: true},
+	}
+	for i, c := range badLoad {
+		if _, err := NewLookup(c); err == nil {
+			t.Fatalf("Failed to catch bad config %d (%+v)", i, c)
+		}
+	}
+}
+
+func TestLookupProcess(t *testing.T) {
+	cfg := LookupConfig{
+		Lookup_File: testLookupUsers,
+		Key_Column:  `user`,
+		Regex:       `user=(\S+)`,
+		EV_Prefix:   `user_`,
+	}
+	lu, err := NewLookup(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ents := []*entry.Entry{
+		&entry.Entry{Data: []byte(`action=login user=bob`)},
+		&entry.Entry{Data: []byte(`action=login user=mallory`)},
+		&entry.Entry{Data: []byte(`action=logout`)},
+	}
+	set, err := lu.Process(ents)
+	if err != nil {
+		t.Fatal(err)
+	} else if len(set) != 3 {
+		t.Fatalf("bad count %d", len(set))
+	}
+	checkEVs(t, set[0], map[string]interface{}{`user_department`: `finance`, `user_title`: `analyst, senior`})
+	if set[1].EVB.Count() != 0 || set[2].EVB.Count() != 0 {
+		t.Fatal("misses got enumerated values")
+	}
+
+	// check the default miss action with a restricted attach set
+	cfg.Miss_Action = `default`
+	cfg.Default_Value = `unknown`
+	cfg.Attach = []string{`department`}
+	if err = lu.Config(cfg); err != nil {
+		t.Fatal(err)
+	}
+	ents = []*entry.Entry{
+		&entry.Entry{Data: []byte(`action=login user=alice`)},
+		&entry.Entry{Data: []byte(`action=login user=mallory`)},
+	}
+	if set, err = lu.Process(ents); err != nil {
+		t.Fatal(err)
+	} else if len(set) != 2 {
+		t.Fatalf("bad count %d", len(set))
+	}
+	checkEVs(t, set[0], map[string]interface{}{`user_department`: `engineering`})
+	checkEVs(t, set[1], map[string]interface{}{`user_department`: `unknown`})
+	if _, ok := set[0].GetEnumeratedValue(`user_title`); ok {
+		t.Fatal("attached unrequested column")
+	}
+
+	// and drops
+	cfg.Miss_Action = `drop`
+	if err = lu.Config(cfg); err != nil {
+		t.Fatal(err)
+	}
+	ents = []*entry.Entry{
+		&entry.Entry{Data: []byte(`action=login user=mallory`)},
+		&entry.Entry{Data: []byte(`action=login user=carol`)},
+	}
+	if set, err = lu.Process(ents); err != nil {
+		t.Fatal(err)
+	} else if len(set) != 1 {
+		t.Fatalf("bad count %d", len(set))
+	}
+	checkEVs(t, set[0], map[string]interface{}{`user_department`: `security`})
+}
+
+func TestLookupCIDR(t *testing.T) {
+	cfg := LookupConfig{
+		Lookup_File: testLookupAssets,
+		Key_Column:  `network`,
+		CIDR_Keys:   true,
+		Use_SRC:     true,
+	}
+	lu, err := NewLookup(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		src   string
+		site  string
+		owner string
+	}{
+		{src: `10.1.2.3`, site: `datacenter`, owner: `ops`},
+		{src: `10.10.2.3`, site: `lab`, owner: `research`},
+		{src: `192.168.1.1`, site: `office`, owner: `it`},
+		{src: `2001:db8::5`, site: `cloud`, owner: `platform`},
+		{src: `192.168.1.2`},
+	}
+	for _, tst := range tests {
+		ent := &entry.Entry{SRC: net.ParseIP(tst.src)}
+		set, err := lu.Process([]*entry.Entry{ent})
+		if err != nil {
+			t.Fatal(err)
+		} else if len(set) != 1 {
+			t.Fatalf("bad count %d", len(set))
+		}
+		if tst.site == `` {
+			if set[0].EVB.Count() != 0 {
+				t.Fatalf("%s got enumerated values on a miss", tst.src)
+			}
+			continue
+		}
+		checkEVs(t, set[0], map[string]interface{}{`site`: tst.site, `owner`: tst.owner})
+	}
+}
+
+func TestLookupReload(t *testing.T) {
+	pth := filepath.Join(t.TempDir(), `users.csv`)
+	copyTestFile(t, testLookupUsers, pth)
+	cfg := LookupConfig{
+		Lookup_File:      pth,
+		Key_Column:       `user`,
+		Enumerated_Value: `user`,
+		Reload_Interval:  `1ms`,
+	}
+	lu, err := NewLookup(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ent := &entry.Entry{}
+	ent.AddEnumeratedValueEx(`user`, `dave`)
+	if set, err := lu.Process([]*entry.Entry{ent}); err != nil || len(set) != 1 {
+		t.Fatal(err)
+	} else if _, ok := set[0].GetEnumeratedValue(`department`); ok {
+		t.Fatal("found unknown user")
+	}
+
+	if err = os.WriteFile(pth, []byte("user,department\ndave,marketing\n"), 0640); err != nil {
+		t.Fatal(err)
+	}
+	ts := time.Now().Add(time.Minute)
+	if err = os.Chtimes(pth, ts, ts); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(5 * time.Millisecond)
+	ent = &entry.Entry{}
+	ent.AddEnumeratedValueEx(`user`, `dave`)
+	if set, err := lu.Process([]*entry.Entry{ent}); err != nil || len(set) != 1 {
+		t.Fatal(err)
+	} else {
+		checkEVs(t, set[0], map[string]interface{}{`department`: `marketing`})
+	}
+
+	// a broken file retains the existing table
+	if err = os.WriteFile(pth, []byte("name,department\n"), 0640); err != nil {
+		t.Fatal(err)
+	}
+	ts = ts.Add(time.Minute)
+	if err = os.Chtimes(pth, ts, ts); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(5 * time.Millisecond)
+	ent = &entry.Entry{}
+	ent.AddEnumeratedValueEx(`user`, `dave`)
+	if set, err := lu.Process([]*entry.Entry{ent}); err != nil || len(set) != 1 {
+		t.Fatal(err)
+	} else {
+		checkEVs(t, set[0], map[string]interface{}{`department`: `marketing`})
+	}
+}
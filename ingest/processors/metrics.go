@@ -0,0 +1,148 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package processors
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/gravwell/gravwell/v3/ingest"
+	"github.com/gravwell/gravwell/v3/ingest/entry"
+	"github.com/gravwell/gravwell/v3/ingest/log"
+)
+
+const (
+	// a broken preprocessor can fail on every entry, only log an error once per interval
+	errorLogInterval = 30 * time.Second
+
+	errorLogMsg = `preprocessor error`
+)
+
+// spoolReporter is implemented by preprocessors that queue entries on disk, such as a spooling forwarder
+type spoolReporter interface {
+	SpoolStats() (count, size, dropped uint64)
+}
+
+// processorMetrics holds the counters for a single item in a ProcessorSet, the counters are updated
+// with the set lock held but read atomically so that stats can be gathered without blocking ingest
+type processorMetrics struct {
+	name       string
+	typ        string
+	in         uint64
+	out        uint64
+	dropped    uint64
+	errs       uint64
+	nanos      uint64
+	lastLog    time.Time
+	suppressed uint64
+}
+
+func newProcessorMetrics(name, typ string, p Processor) *processorMetrics {
+	if typ == `` {
+		typ = processorTypeName(p)
+	}
+	return &processorMetrics{
+		name: name,
+		typ:  typ,
+	}
+}
+
+// processorTypeName produces a reasonable type name for processors that were not built from a config
+func processorTypeName(p Processor) string {
+	if p == nil {
+		return ``
+	}
+	s := fmt.Sprintf("%T", p)
+	if idx := strings.LastIndexByte(s, '.'); idx != -1 {
+		s = s[idx+1:]
+	}
+	return strings.ToLower(s)
+}
+
+func (pm *processorMetrics) update(in, out int, dur time.Duration, err error) {
+	atomic.AddUint64(&pm.in, uint64(in))
+	atomic.AddUint64(&pm.out, uint64(out))
+	if out < in {
+		atomic.AddUint64(&pm.dropped, uint64(in-out))
+	}
+	atomic.AddUint64(&pm.nanos, uint64(dur))
+	if err != nil {
+		atomic.AddUint64(&pm.errs, 1)
+	}
+}
+
+func (pm *processorMetrics) stats() ingest.PreprocessorStats {
+	return ingest.PreprocessorStats{
+		Name:           pm.name,
+		Type:           pm.typ,
+		EntriesIn:      atomic.LoadUint64(&pm.in),
+		EntriesOut:     atomic.LoadUint64(&pm.out),
+		Dropped:        atomic.LoadUint64(&pm.dropped),
+		Errors:         atomic.LoadUint64(&pm.errs),
+		ProcessingTime: time.Duration(atomic.LoadUint64(&pm.nanos)),
+	}
+}
+
+// logError emits a structured error naming the preprocessor and tag, errors that arrive
+// within errorLogInterval of the last log are counted and reported with the next one
+func (pm *processorMetrics) logError(lgr ingest.IngestLogger, tgr Tagger, tag entry.EntryTag, err error) {
+	if lgr == nil || err == nil {
+		return
+	}
+	now := time.Now()
+	if now.Sub(pm.lastLog) < errorLogInterval {
+		pm.suppressed++
+		return
+	}
+	tagName, ok := ``, false
+	if tgr != nil {
+		tagName, ok = tgr.LookupTag(tag)
+	}
+	if !ok {
+		tagName = fmt.Sprintf("%d", tag)
+	}
+	lgr.Error(errorLogMsg,
+		log.KV("preprocessor", pm.name),
+		log.KV("type", pm.typ),
+		log.KV("tag", tagName),
+		log.KV("suppressed", pm.suppressed),
+		log.KVErr(err))
+	pm.lastLog = now
+	pm.suppressed = 0
+}
+
+// PreprocessorStats returns a snapshot of the counters for each preprocessor in the set.
+// Members of chains are reported individually with the chain name as a prefix.
+func (pr *ProcessorSet) PreprocessorStats() (r []ingest.PreprocessorStats) {
+	pr.statsMtx.Lock()
+	defer pr.statsMtx.Unlock()
+	for i, m := range pr.metrics {
+		st := m.stats()
+		if sr, ok := pr.set[i].(spoolReporter); ok {
+			st.SpoolEntries, st.SpoolBytes, st.SpoolDropped = sr.SpoolStats()
+		}
+		r = append(r, st)
+		if c, ok := pr.set[i].(*Chain); ok {
+			for _, v := range c.ps.PreprocessorStats() {
+				v.Name = m.name + `/` + v.Name
+				r = append(r, v)
+			}
+		}
+	}
+	return
+}
+
+// SetLogger sets the logger used to report preprocessor errors, errors are rate limited per preprocessor
+func (pr *ProcessorSet) SetLogger(lgr ingest.IngestLogger) {
+	pr.Lock()
+	pr.lgr = lgr
+	pr.Unlock()
+}
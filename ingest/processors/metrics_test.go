@@ -0,0 +1,134 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package processors
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/crewjam/rfc5424"
+	"github.com/gravwell/gravwell/v3/ingest/entry"
+)
+
+type errProcessor struct {
+	nocloser
+}
+
+func (ep *errProcessor) Process(ents []*entry.Entry) ([]*entry.Entry, error) {
+	return nil, errors.New("broken")
+}
+
+type testErrLogger struct {
+	msgs []string
+}
+
+func (tl *testErrLogger) Errorf(f string, args ...interface{}) error {
+	tl.msgs = append(tl.msgs, fmt.Sprintf(f, args...))
+	return nil
+}
+
+func (tl *testErrLogger) Warnf(f string, args ...interface{}) error { return nil }
+func (tl *testErrLogger) Infof(f string, args ...interface{}) error { return nil }
+func (tl *testErrLogger) Warn(string, ...rfc5424.SDParam) error     { return nil }
+func (tl *testErrLogger) Info(string, ...rfc5424.SDParam) error     { return nil }
+
+func (tl *testErrLogger) Error(msg string, params ...rfc5424.SDParam) error {
+	for _, p := range params {
+		msg += ` ` + p.Name + `=` + p.Value
+	}
+	tl.msgs = append(tl.msgs, msg)
+	return nil
+}
+
+func TestProcessorSetStats(t *testing.T) {
+	b := `
+	[preprocessor "localdrop"]
+		type = drop
+		Match-Source = 10.0.0.0/8
+	[preprocessor "kv"]
+		type = kv
+	`
+	ps, tw := loadTestChain(t, b, `localdrop`, `kv`)
+	ents := []*entry.Entry{
+		&entry.Entry{SRC: net.ParseIP("10.0.0.1"), Data: []byte(`a=1`)},
+		&entry.Entry{SRC: net.ParseIP("192.168.0.1"), Data: []byte(`a=2`)},
+		&entry.Entry{SRC: net.ParseIP("10.0.0.2"), Data: []byte(`a=3`)},
+	}
+	if err := ps.ProcessBatch(ents); err != nil {
+		t.Fatal(err)
+	} else if len(tw.ents) != 1 {
+		t.Fatalf("bad output count %d", len(tw.ents))
+	}
+	stats := ps.PreprocessorStats()
+	if len(stats) != 2 {
+		t.Fatalf("bad stats count %d", len(stats))
+	}
+	if s := stats[0]; s.Name != `localdrop` || s.Type != DropProcessor || s.EntriesIn != 2 || s.EntriesOut != 0 || s.Dropped != 2 || s.Errors != 0 {
+		t.Fatalf("bad drop stats: %+v", s)
+	}
+	if s := stats[1]; s.Name != `kv` || s.Type != KVProcessor || s.EntriesIn != 1 || s.EntriesOut != 1 || s.Dropped != 0 {
+		t.Fatalf("bad kv stats: %+v", s)
+	}
+}
+
+func TestProcessorSetChainStats(t *testing.T) {
+	ps, tw := loadTestChain(t, testChainConfig, `kv`, `branch`)
+	app, _ := tw.NegotiateTag(`app`)
+	ents := []*entry.Entry{
+		&entry.Entry{Tag: app, Data: []byte(`alert=1`)},
+	}
+	if err := ps.ProcessBatch(ents); err != nil {
+		t.Fatal(err)
+	}
+	var found bool
+	for _, s := range ps.PreprocessorStats() {
+		if s.Name == `branch/route` {
+			found = true
+			if s.Type != FieldRouterProcessor || s.EntriesIn != 1 {
+				t.Fatalf("bad chain member stats: %+v", s)
+			}
+		}
+	}
+	if !found {
+		t.Fatal("chain member stats missing")
+	}
+}
+
+func TestProcessorSetErrorLog(t *testing.T) {
+	var tw testWriter
+	var lgr testErrLogger
+	ps := NewProcessorSet(&tw)
+	ps.AddProcessor(&errProcessor{})
+	ps.SetLogger(&lgr)
+	for i := 0; i < 10; i++ {
+		if err := ps.Process(&entry.Entry{Data: []byte(`foo`)}); err == nil {
+			t.Fatal("failed to get error")
+		}
+	}
+	stats := ps.PreprocessorStats()
+	if len(stats) != 1 {
+		t.Fatalf("bad stats count %d", len(stats))
+	} else if stats[0].Errors != 10 || stats[0].EntriesIn != 10 || stats[0].Type != `errprocessor` {
+		t.Fatalf("bad stats: %+v", stats[0])
+	}
+	// errors are rate limited
+	if len(lgr.msgs) != 1 {
+		t.Fatalf("bad log count %d", len(lgr.msgs))
+	}
+	// the next log reports the suppressed errors
+	ps.metrics[0].lastLog = ps.metrics[0].lastLog.Add(-2 * errorLogInterval)
+	ps.Process(&entry.Entry{Data: []byte(`foo`)})
+	if len(lgr.msgs) != 2 {
+		t.Fatalf("bad log count %d", len(lgr.msgs))
+	} else if exp := `preprocessor error preprocessor=errprocessor-0 type=errprocessor tag=0 suppressed=9 error=broken`; lgr.msgs[1] != exp {
+		t.Fatalf("bad log message:\n%q\n%q", lgr.msgs[1], exp)
+	}
+}
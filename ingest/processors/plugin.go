@@ -18,11 +18,14 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
+	"github.com/gravwell/gravwell/v3/ingest"
 	"github.com/gravwell/gravwell/v3/ingest/config"
 	"github.com/gravwell/gravwell/v3/ingest/entry"
+	"github.com/gravwell/gravwell/v3/ingest/log"
 	"github.com/gravwell/gravwell/v3/ingest/processors/plugin"
 	"github.com/open2b/scriggo"
 )
@@ -39,6 +42,12 @@ const (
 var (
 	ErrNoPlugins     = errors.New("No plugins provided in Plugin-Path")
 	ErrDuplicateFile = errors.New("dupclicate plugin file")
+	ErrMaxAlloc      = errors.New("Max-Alloc is not supported, plugins have no memory ceiling")
+
+	// PluginStateDir holds the key value stores of plugin preprocessors that do not set State-Store-Location,
+	// each preprocessor gets a file named after it.  It is empty by default so stores are kept in memory
+	// unless the ingester points it at its own state directory.
+	PluginStateDir string
 )
 
 // PluginData implements the fs.FS interface
@@ -47,16 +56,25 @@ type PluginData struct {
 }
 
 type PluginConfig struct {
-	Plugin_Path   []string               //path to the plugin files (this may support multifile plugins later
-	Plugin_Engine string                 // defaults to scriggo
-	Debug         bool                   // defaults to false
-	vc            *config.VariableConfig // we keep a handle on the variable to config to pass to the underlying plugin script
-	pd            PluginData
+	Plugin_Path   []string //path to the plugin files (this may support multifile plugins later
+	Plugin_Engine string   // defaults to scriggo
+	Debug         bool     // defaults to false
+	// sandbox policy, by default plugins may import any package and are not limited
+	Allowed_Package []string // import paths the plugin may use, "encoding/*" allows a package tree
+	Process_Timeout string   // maximum duration of a single Process or Flush call
+	// file used to persist the key value store available to the plugin, defaults to a file under PluginStateDir
+	State_Store_Location string
+	name                 string                 // preprocessor name, used to key the default store location
+	vc                   *config.VariableConfig // we keep a handle on the variable to config to pass to the underlying plugin script
+	pd                   PluginData
 	// all other config items are dynamic and passed to the underlying plugin
 }
 
 func PluginLoadConfig(vc *config.VariableConfig) (pc PluginConfig, err error) {
-	if err = vc.MapTo(&pc); err == nil {
+	if v, _ := vc.GetStringSlice(`Max-Alloc`); len(v) > 0 {
+		//refuse rather than silently hand a safety limit to the plugin as an ordinary value
+		err = ErrMaxAlloc
+	} else if err = vc.MapTo(&pc); err == nil {
 		if err = pc.validate(); err == nil {
 			pc.vc = vc //grab a handle on the variable config
 		}
@@ -83,6 +101,10 @@ func (pc *PluginConfig) validate() (err error) {
 		return
 	}
 
+	if _, err = pc.policy(); err != nil {
+		return
+	}
+
 	if pc.pd.count() == 0 {
 		for _, p := range pc.Plugin_Path {
 			if err = pc.pd.add(p); err != nil {
@@ -94,21 +116,45 @@ func (pc *PluginConfig) validate() (err error) {
 
 }
 
+// policy builds the sandbox policy for the plugin
+func (pc *PluginConfig) policy() (pol plugin.Policy, err error) {
+	pol.AllowedPackages = pc.Allowed_Package
+	if pc.Process_Timeout != `` {
+		if pol.ProcessTimeout, err = time.ParseDuration(pc.Process_Timeout); err != nil {
+			err = fmt.Errorf("Invalid Process-Timeout %q: %v", pc.Process_Timeout, err)
+			return
+		} else if pol.ProcessTimeout < 0 {
+			err = fmt.Errorf("Invalid Process-Timeout %q", pc.Process_Timeout)
+			return
+		}
+	}
+	err = pol.Validate()
+	return
+}
+
 type Plugin struct {
 	PluginConfig
-	pp *plugin.PluginProgram
+	pp  *plugin.PluginProgram
+	lgr ingest.IngestLogger // may be nil
 }
 
 func NewPluginProcessor(cfg PluginConfig, tg Tagger) (p *Plugin, err error) {
 	if err = cfg.validate(); err == nil {
 		var pp *plugin.PluginProgram
-		if pp, err = plugin.NewPlugin(cfg.pd, cfg.Debug); err == nil {
-			if err = pp.Run(registerTimeout); err == nil {
-				if err = pp.Config(cfg.vc, tg); err == nil {
-					if err = pp.Start(); err == nil {
-						p = &Plugin{
-							PluginConfig: cfg,
-							pp:           pp,
+		var pol plugin.Policy
+		if pol, err = cfg.policy(); err != nil {
+			return
+		}
+		if pp, err = plugin.NewPluginWithPolicy(cfg.pd, cfg.Debug, pol); err == nil {
+			if err = cfg.attachStore(pp); err == nil {
+				if err = pp.Run(registerTimeout); err == nil {
+					if err = pp.Config(cfg.vc, tg); err == nil {
+						if err = pp.Start(); err == nil {
+							p = &Plugin{
+								PluginConfig: cfg,
+								pp:           pp,
+							}
+							p.lgr, _ = tg.(ingest.IngestLogger)
 						}
 					}
 				}
@@ -121,6 +167,66 @@ func NewPluginProcessor(cfg PluginConfig, tg Tagger) (p *Plugin, err error) {
 	return
 }
 
+// attachStore hands the plugin a persistent key value store, instances of the same preprocessor share a store
+func (pc *PluginConfig) attachStore(pp *plugin.PluginProgram) (err error) {
+	loc := pc.storeLocation()
+	if loc == `` {
+		return
+	}
+	var st *plugin.Store
+	if st, err = plugin.SharedStore(loc); err == nil {
+		if err = pp.SetStore(st); err != nil {
+			st.Close()
+		}
+	}
+	return
+}
+
+// storeLocation returns the file backing the key value store, plugins built outside of a preprocessor
+// set have no name and are only persisted if State-Store-Location is set
+func (pc *PluginConfig) storeLocation() string {
+	if pc.State_Store_Location != `` {
+		return filepath.Clean(pc.State_Store_Location)
+	} else if pc.name == `` || PluginStateDir == `` {
+		return ``
+	}
+	name := strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '-' || r == '.' {
+			return r
+		}
+		return '_'
+	}, pc.name)
+	return filepath.Join(PluginStateDir, name+`.state`)
+}
+
+// checkPluginStores ensures that no two plugin preprocessors persist their key value stores to the same file
+func (pc ProcessorConfig) checkPluginStores() error {
+	names := make([]string, 0, len(pc))
+	for k := range pc {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	owners := map[string]string{}
+	for _, name := range names {
+		if pc[name] == nil || pc.processorType(name) != PluginProcessor {
+			continue
+		}
+		var cfg PluginConfig
+		if err := pc[name].MapTo(&cfg); err != nil {
+			return err
+		}
+		cfg.name = name
+		loc := cfg.storeLocation()
+		if loc == `` {
+			continue
+		} else if prev, ok := owners[loc]; ok {
+			return fmt.Errorf("Preprocessors %s and %s share the key value store %s", prev, name, loc)
+		}
+		owners[loc] = name
+	}
+	return nil
+}
+
 func (p *Plugin) Close() (err error) {
 	if p == nil || p.pp == nil {
 		err = ErrNotReady
@@ -137,12 +243,19 @@ func (p *Plugin) Flush() []*entry.Entry {
 	return p.pp.Flush()
 }
 
-func (p *Plugin) Process(ents []*entry.Entry) ([]*entry.Entry, error) {
+func (p *Plugin) Process(ents []*entry.Entry) (set []*entry.Entry, err error) {
 	if p == nil || p.pp == nil {
 		return nil, ErrNotReady
 	}
-	return p.pp.Process(ents)
-
+	set, err = p.pp.Process(ents)
+	// the entries are already processed, so a failed write is logged and retried at the next interval
+	if serr := p.pp.SyncStore(); serr != nil && p.lgr != nil {
+		p.lgr.Error("failed to sync plugin key value store",
+			log.KV("preprocessor", p.name),
+			log.KV("path", p.storeLocation()),
+			log.KVErr(serr))
+	}
+	return
 }
 
 func (pd PluginData) count() int {
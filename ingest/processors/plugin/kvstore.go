@@ -0,0 +1,370 @@
+//go:build !386 && !arm && !mips && !mipsle && !s390x
+// +build !386,!arm,!mips,!mipsle,!s390x
+
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package plugin
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	KVStoreName       string = `KVStore`
+	OpenStoreFuncName string = `OpenStore`
+
+	storeVersion      = 1
+	storeSyncInterval = 10 * time.Second
+	storeMaxKeyLen    = 1024
+)
+
+var (
+	ErrStoreClosed      = errors.New("key value store is closed")
+	ErrInvalidNamespace = errors.New("invalid key value store namespace")
+	ErrInvalidKey       = errors.New("invalid key value store key")
+	ErrNotInteger       = errors.New("value is not an integer")
+
+	sharedMtx    sync.Mutex
+	sharedStores = map[string]*Store{}
+)
+
+// KVStore is the key value API handed to plugins by gravwell.OpenStore.  Every namespace is
+// isolated, keys set with a TTL greater than zero expire once the TTL elapses.
+type KVStore interface {
+	Get(key string) ([]byte, bool)
+	GetString(key string) (string, bool)
+	Set(key string, val []byte, ttl time.Duration) error
+	SetString(key, val string, ttl time.Duration) error
+	Increment(key string, delta int64, ttl time.Duration) (int64, error)
+	Delete(key string) error
+	Keys() []string
+}
+
+type storeItem struct {
+	V []byte
+	X int64 `json:",omitempty"` // expiration in unix nanoseconds, zero never expires
+}
+
+func (si storeItem) expired(now int64) bool {
+	return si.X != 0 && si.X <= now
+}
+
+type storeFile struct {
+	Version    int
+	Namespaces map[string]map[string]storeItem
+}
+
+// Store is a namespaced key value store that is persisted to a single file.  Changes are
+// written to disk by Sync, an empty path produces a store that is never persisted.
+type Store struct {
+	mtx      sync.Mutex
+	path     string
+	ns       map[string]map[string]storeItem
+	dirty    bool
+	closed   bool
+	lastSync time.Time
+	refs     int // owners of a shared store, zero if the store is not shared
+}
+
+// NewStore opens the store at path, loading any previously persisted state
+func NewStore(path string) (s *Store, err error) {
+	s = &Store{
+		path:     path,
+		ns:       map[string]map[string]storeItem{},
+		lastSync: time.Now(),
+	}
+	if path == `` {
+		return
+	}
+	var b []byte
+	if b, err = os.ReadFile(path); err != nil {
+		if os.IsNotExist(err) {
+			err = nil
+		} else {
+			s = nil
+		}
+		return
+	}
+	var sf storeFile
+	if err = json.Unmarshal(b, &sf); err != nil {
+		s = nil
+		err = fmt.Errorf("corrupt key value store %s: %w", path, err)
+		return
+	} else if sf.Version != storeVersion {
+		s = nil
+		err = fmt.Errorf("unsupported key value store version %d", sf.Version)
+		return
+	}
+	if sf.Namespaces != nil {
+		s.ns = sf.Namespaces
+	}
+	s.purge(time.Now().UnixNano())
+	return
+}
+
+// SharedStore opens the store at path, every caller that opens the same path gets the same store so that
+// plugin instances built from one configuration do not overwrite each other's state.  The store is closed
+// once every owner has closed it.
+func SharedStore(path string) (s *Store, err error) {
+	if path == `` {
+		return NewStore(path)
+	}
+	if path, err = filepath.Abs(path); err != nil {
+		return
+	}
+	sharedMtx.Lock()
+	defer sharedMtx.Unlock()
+	if s = sharedStores[path]; s == nil {
+		if s, err = NewStore(path); err != nil {
+			return
+		}
+		sharedStores[path] = s
+	}
+	s.refs++
+	return
+}
+
+// Namespace returns a handle to a namespace within the store
+func (s *Store) Namespace(name string) (KVStore, error) {
+	if name == `` || len(name) > storeMaxKeyLen {
+		return nil, ErrInvalidNamespace
+	}
+	return &storeNamespace{s: s, name: name}, nil
+}
+
+// Sync writes the store to disk if it has changed
+func (s *Store) Sync() error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if s.closed {
+		return ErrStoreClosed
+	}
+	return s.sync()
+}
+
+// syncIfDue writes the store to disk if it has changed and the sync interval has elapsed
+func (s *Store) syncIfDue() error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if s.closed || time.Since(s.lastSync) < storeSyncInterval {
+		return nil
+	}
+	return s.sync()
+}
+
+// Close syncs the store and prevents further use, shared stores are only closed by their last owner
+func (s *Store) Close() (err error) {
+	if s.release() {
+		return s.Sync()
+	}
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if s.closed {
+		return
+	}
+	err = s.sync()
+	s.closed = true
+	return
+}
+
+// release drops an owner of a shared store, returning true if other owners remain
+func (s *Store) release() bool {
+	sharedMtx.Lock()
+	defer sharedMtx.Unlock()
+	if s.refs == 0 {
+		return false
+	} else if s.refs--; s.refs > 0 {
+		return true
+	}
+	delete(sharedStores, s.path)
+	return false
+}
+
+func (s *Store) sync() (err error) {
+	s.lastSync = time.Now()
+	if !s.dirty || s.path == `` {
+		return
+	}
+	s.purge(s.lastSync.UnixNano())
+	var b []byte
+	if b, err = json.Marshal(storeFile{Version: storeVersion, Namespaces: s.ns}); err != nil {
+		return
+	}
+	tmp := s.path + `.tmp`
+	if err = os.MkdirAll(filepath.Dir(s.path), 0750); err != nil {
+		return
+	} else if err = writeFileSync(tmp, b); err != nil {
+		os.Remove(tmp)
+		return
+	} else if err = os.Rename(tmp, s.path); err != nil {
+		os.Remove(tmp)
+		return
+	}
+	s.dirty = false
+	return
+}
+
+// purge removes expired items and empty namespaces, the caller must hold the lock
+func (s *Store) purge(now int64) {
+	for name, items := range s.ns {
+		for k, v := range items {
+			if v.expired(now) {
+				delete(items, k)
+				s.dirty = true
+			}
+		}
+		if len(items) == 0 {
+			delete(s.ns, name)
+		}
+	}
+}
+
+func writeFileSync(pth string, b []byte) (err error) {
+	var fout *os.File
+	if fout, err = os.OpenFile(pth, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0640); err != nil {
+		return
+	}
+	if _, err = fout.Write(b); err == nil {
+		err = fout.Sync()
+	}
+	if lerr := fout.Close(); err == nil {
+		err = lerr
+	}
+	return
+}
+
+// storeNamespace implements the KVStore interface for a single namespace
+type storeNamespace struct {
+	s    *Store
+	name string
+}
+
+func (sn *storeNamespace) Get(key string) (v []byte, ok bool) {
+	sn.s.mtx.Lock()
+	defer sn.s.mtx.Unlock()
+	var si storeItem
+	if si, ok = sn.get(key); ok {
+		v = append([]byte(nil), si.V...)
+	}
+	return
+}
+
+func (sn *storeNamespace) GetString(key string) (v string, ok bool) {
+	sn.s.mtx.Lock()
+	defer sn.s.mtx.Unlock()
+	var si storeItem
+	if si, ok = sn.get(key); ok {
+		v = string(si.V)
+	}
+	return
+}
+
+func (sn *storeNamespace) Set(key string, val []byte, ttl time.Duration) error {
+	sn.s.mtx.Lock()
+	defer sn.s.mtx.Unlock()
+	return sn.set(key, storeItem{V: append([]byte(nil), val...), X: expiration(ttl)})
+}
+
+func (sn *storeNamespace) SetString(key, val string, ttl time.Duration) error {
+	sn.s.mtx.Lock()
+	defer sn.s.mtx.Unlock()
+	return sn.set(key, storeItem{V: []byte(val), X: expiration(ttl)})
+}
+
+// Increment adds delta to the integer stored at key and returns the result.  A missing key is
+// created with the provided TTL, an existing key retains its expiration.
+func (sn *storeNamespace) Increment(key string, delta int64, ttl time.Duration) (v int64, err error) {
+	sn.s.mtx.Lock()
+	defer sn.s.mtx.Unlock()
+	si, ok := sn.get(key)
+	if ok {
+		if v, err = strconv.ParseInt(string(si.V), 10, 64); err != nil {
+			err = ErrNotInteger
+			return
+		}
+	} else {
+		si.X = expiration(ttl)
+	}
+	v += delta
+	si.V = strconv.AppendInt(nil, v, 10)
+	err = sn.set(key, si)
+	return
+}
+
+func (sn *storeNamespace) Delete(key string) error {
+	sn.s.mtx.Lock()
+	defer sn.s.mtx.Unlock()
+	if sn.s.closed {
+		return ErrStoreClosed
+	}
+	if items, ok := sn.s.ns[sn.name]; ok {
+		if _, ok = items[key]; ok {
+			delete(items, key)
+			sn.s.dirty = true
+		}
+	}
+	return nil
+}
+
+// Keys returns the sorted set of unexpired keys in the namespace
+func (sn *storeNamespace) Keys() (r []string) {
+	sn.s.mtx.Lock()
+	defer sn.s.mtx.Unlock()
+	now := time.Now().UnixNano()
+	for k, v := range sn.s.ns[sn.name] {
+		if !v.expired(now) {
+			r = append(r, k)
+		}
+	}
+	sort.Strings(r)
+	return
+}
+
+// get returns an unexpired item, the caller must hold the lock
+func (sn *storeNamespace) get(key string) (si storeItem, ok bool) {
+	if sn.s.closed {
+		return
+	}
+	if si, ok = sn.s.ns[sn.name][key]; ok && si.expired(time.Now().UnixNano()) {
+		si, ok = storeItem{}, false
+	}
+	return
+}
+
+// set stores an item, the caller must hold the lock
+func (sn *storeNamespace) set(key string, si storeItem) error {
+	if sn.s.closed {
+		return ErrStoreClosed
+	} else if key == `` || len(key) > storeMaxKeyLen {
+		return ErrInvalidKey
+	}
+	items, ok := sn.s.ns[sn.name]
+	if !ok {
+		items = map[string]storeItem{}
+		sn.s.ns[sn.name] = items
+	}
+	items[key] = si
+	sn.s.dirty = true
+	return nil
+}
+
+func expiration(ttl time.Duration) int64 {
+	if ttl <= 0 {
+		return 0
+	}
+	return time.Now().Add(ttl).UnixNano()
+}
@@ -0,0 +1,284 @@
+//go:build !386 && !arm && !mips && !mipsle && !s390x
+// +build !386,!arm,!mips,!mipsle,!s390x
+
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package plugin
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gravwell/gravwell/v3/ingest/entry"
+)
+
+const counterPlugin = `
+package main
+
+import (
+	"gravwell"
+	"strconv"
+	"time"
+
+	"github.com/gravwell/gravwell/v3/ingest/entry"
+)
+
+var kv gravwell.KVStore
+
+func main() {
+	gravwell.Execute("counter", cf, nop, nop, pf, ff)
+}
+
+func cf(cm gravwell.ConfigMap, tg gravwell.Tagger) (err error) {
+	kv, err = gravwell.OpenStore("counter")
+	return
+}
+
+func ff() []*entry.Entry {
+	return nil
+}
+
+func pf(ents []*entry.Entry) ([]*entry.Entry, error) {
+	for _, ent := range ents {
+		v, err := kv.Increment("seen", 1, 0)
+		if err != nil {
+			return nil, err
+		}
+		ent.Data = append(ent.Data, []byte(" " + strconv.FormatInt(v, 10))...)
+		kv.SetString("last", string(ent.Data), time.Hour)
+	}
+	return ents, nil
+}
+
+func nop() error {
+	return nil
+}
+`
+
+func TestStore(t *testing.T) {
+	pth := filepath.Join(t.TempDir(), `state`, `plugin.state`)
+	s, err := NewStore(pth)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err := s.Namespace(`a`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := s.Namespace(`b`)
+	if err != nil {
+		t.Fatal(err)
+	} else if _, err = s.Namespace(``); err != ErrInvalidNamespace {
+		t.Fatalf("failed to catch bad namespace: %v", err)
+	}
+
+	if err = a.SetString(`foo`, `bar`, 0); err != nil {
+		t.Fatal(err)
+	} else if err = a.Set(`raw`, []byte{0, 1, 2}, 0); err != nil {
+		t.Fatal(err)
+	} else if err = a.SetString(``, `bar`, 0); err != ErrInvalidKey {
+		t.Fatalf("failed to catch bad key: %v", err)
+	}
+	if v, ok := a.GetString(`foo`); !ok || v != `bar` {
+		t.Fatalf("bad get: %v %v", v, ok)
+	} else if _, ok = b.GetString(`foo`); ok {
+		t.Fatal("namespaces are not isolated")
+	}
+	for i := int64(1); i <= 3; i++ {
+		if v, err := b.Increment(`cnt`, 1, 0); err != nil || v != i {
+			t.Fatalf("bad increment %d: %d %v", i, v, err)
+		}
+	}
+	if _, err = a.Increment(`foo`, 1, 0); err != ErrNotInteger {
+		t.Fatalf("failed to catch non-integer increment: %v", err)
+	}
+
+	//expiring items
+	if err = a.SetString(`short`, `x`, time.Millisecond); err != nil {
+		t.Fatal(err)
+	} else if err = a.SetString(`long`, `x`, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(5 * time.Millisecond)
+	if _, ok := a.Get(`short`); ok {
+		t.Fatal("expired item was returned")
+	} else if keys := a.Keys(); strings.Join(keys, `,`) != `foo,long,raw` {
+		t.Fatalf("bad keys: %v", keys)
+	}
+	if err = a.Delete(`foo`); err != nil {
+		t.Fatal(err)
+	}
+
+	//reopen and make sure everything survived
+	if err = s.Close(); err != nil {
+		t.Fatal(err)
+	} else if err = a.SetString(`foo`, `bar`, 0); err != ErrStoreClosed {
+		t.Fatalf("closed store accepted a write: %v", err)
+	}
+	if s, err = NewStore(pth); err != nil {
+		t.Fatal(err)
+	}
+	a, _ = s.Namespace(`a`)
+	b, _ = s.Namespace(`b`)
+	if v, ok := a.Get(`raw`); !ok || string(v) != "\x00\x01\x02" {
+		t.Fatalf("bad persisted value: %v", v)
+	} else if _, ok = a.Get(`foo`); ok {
+		t.Fatal("deleted item was persisted")
+	} else if v, ok := b.GetString(`cnt`); !ok || v != `3` {
+		t.Fatalf("bad persisted counter: %v", v)
+	} else if len(s.ns[`a`]) != 2 {
+		t.Fatalf("expired items were persisted: %v", s.ns[`a`])
+	}
+	if err = s.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = os.WriteFile(pth, []byte(`not json`), 0600); err != nil {
+		t.Fatal(err)
+	} else if _, err = NewStore(pth); err == nil {
+		t.Fatal("failed to catch corrupt store")
+	}
+}
+
+func TestSharedStore(t *testing.T) {
+	pth := filepath.Join(t.TempDir(), `shared.state`)
+	s1, err := SharedStore(pth)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s2, err := SharedStore(pth)
+	if err != nil {
+		t.Fatal(err)
+	} else if s1 != s2 {
+		t.Fatal("instances did not share a store")
+	}
+	a, _ := s1.Namespace(`a`)
+	b, _ := s2.Namespace(`a`)
+	if err = a.SetString(`foo`, `bar`, 0); err != nil {
+		t.Fatal(err)
+	} else if v, ok := b.GetString(`foo`); !ok || v != `bar` {
+		t.Fatalf("write was not shared: %v %v", v, ok)
+	}
+
+	//the first close syncs but leaves the store open for the other owner
+	if err = s1.Close(); err != nil {
+		t.Fatal(err)
+	} else if err = b.SetString(`baz`, `x`, 0); err != nil {
+		t.Fatalf("store was closed under its remaining owner: %v", err)
+	} else if err = s2.Close(); err != nil {
+		t.Fatal(err)
+	} else if err = b.SetString(`baz`, `y`, 0); err != ErrStoreClosed {
+		t.Fatalf("last close did not close the store: %v", err)
+	}
+
+	//a fresh open loads everything both owners wrote
+	s3, err := SharedStore(pth)
+	if err != nil {
+		t.Fatal(err)
+	} else if s3 == s1 {
+		t.Fatal("closed store was handed out again")
+	}
+	defer s3.Close()
+	c, _ := s3.Namespace(`a`)
+	if v, _ := c.GetString(`foo`); v != `bar` {
+		t.Fatalf("lost first write: %q", v)
+	} else if v, _ = c.GetString(`baz`); v != `x` {
+		t.Fatalf("lost second write: %q", v)
+	}
+}
+
+func TestPluginStore(t *testing.T) {
+	pth := filepath.Join(t.TempDir(), `plugin.state`)
+	run := func(exp string) {
+		pp, err := NewPluginProgram([]byte(counterPlugin), false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		st, err := NewStore(pth)
+		if err != nil {
+			t.Fatal(err)
+		} else if err = pp.SetStore(st); err != nil {
+			t.Fatal(err)
+		} else if err = pp.Run(time.Second); err != nil {
+			t.Fatal(err)
+		} else if err = pp.Config(nil, NewTestTagger()); err != nil {
+			t.Fatal(err)
+		} else if err = pp.Start(); err != nil {
+			t.Fatal(err)
+		}
+		ents, err := pp.Process([]*entry.Entry{&entry.Entry{Data: []byte(`a`)}, &entry.Entry{Data: []byte(`b`)}})
+		if err != nil {
+			t.Fatal(err)
+		} else if len(ents) != 2 || string(ents[1].Data) != exp {
+			t.Fatalf("bad output %q != %q", ents[1].Data, exp)
+		} else if err = pp.SetStore(st); err == nil {
+			t.Fatal("store was replaced on a running plugin")
+		} else if err = pp.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	run(`b 2`)
+	//the counter persists across restarts
+	run(`b 4`)
+
+	//plugins without a store are handed a store that is not persisted
+	pp, err := NewPluginProgram([]byte(counterPlugin), false)
+	if err != nil {
+		t.Fatal(err)
+	} else if err = pp.Run(time.Second); err != nil {
+		t.Fatal(err)
+	} else if err = pp.Config(nil, NewTestTagger()); err != nil {
+		t.Fatal(err)
+	}
+	if err = pp.Start(); err != nil {
+		t.Fatal(err)
+	} else if ents, err := pp.Process([]*entry.Entry{&entry.Entry{Data: []byte(`a`)}}); err != nil || string(ents[0].Data) != `a 1` {
+		t.Fatalf("bad output: %v", err)
+	} else if err = pp.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPluginSyncStoreError(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), `state`)
+	pp, err := NewPluginProgram([]byte(counterPlugin), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	st, err := NewStore(filepath.Join(dir, `plugin.state`))
+	if err != nil {
+		t.Fatal(err)
+	} else if err = pp.SetStore(st); err != nil {
+		t.Fatal(err)
+	} else if err = pp.Run(time.Second); err != nil {
+		t.Fatal(err)
+	} else if err = pp.Config(nil, NewTestTagger()); err != nil {
+		t.Fatal(err)
+	} else if err = pp.Start(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = pp.Process([]*entry.Entry{&entry.Entry{Data: []byte(`a`)}}); err != nil {
+		t.Fatal(err)
+	} else if err = pp.SyncStore(); err != nil {
+		t.Fatalf("synced before the interval elapsed: %v", err)
+	}
+	//a regular file where the state directory should be makes every write fail
+	if err = os.WriteFile(dir, nil, 0640); err != nil {
+		t.Fatal(err)
+	}
+	st.lastSync = time.Time{}
+	if err = pp.SyncStore(); err == nil {
+		t.Fatal("failed to report sync error")
+	} else if err = pp.Close(); err == nil {
+		t.Fatal("failed to report sync error on close")
+	}
+}
@@ -91,6 +91,11 @@ func (fe *FaultError) RawError() error {
 	return fe.err
 }
 
+// Unwrap allows errors.Is and errors.As to inspect the underlying error
+func (fe *FaultError) Unwrap() error {
+	return fe.RawError()
+}
+
 func (fe *FaultError) Backtrace() string {
 	if fe == nil || fe.err == nil {
 		return ``
@@ -109,10 +114,20 @@ func NewPluginProgram(content []byte, debug bool) (pp *PluginProgram, err error)
 }
 
 func NewPlugin(fsys fs.FS, debug bool) (pp *PluginProgram, err error) {
+	return NewPluginWithPolicy(fsys, debug, Policy{})
+}
+
+// NewPluginWithPolicy builds a plugin that is restricted by the provided policy
+func NewPluginWithPolicy(fsys fs.FS, debug bool, pol Policy) (pp *PluginProgram, err error) {
 	ppTemp := &PluginProgram{
-		debug: debug,
-		rc:    make(chan error, 1),
-		dc:    make(chan error, 1),
+		debug:  debug,
+		policy: pol,
+		rc:     make(chan error, 1),
+		dc:     make(chan error, 1),
+	}
+	//plugins always get a key value store, it is only persisted if the owner provides one via SetStore
+	if ppTemp.store, err = NewStore(``); err != nil {
+		return
 	}
 	ppTemp.ctx, ppTemp.cancel = context.WithCancel(context.Background())
 	if err = buildProgram(fsys, ppTemp); err != nil {
@@ -124,6 +139,11 @@ func NewPlugin(fsys fs.FS, debug bool) (pp *PluginProgram, err error) {
 
 func buildProgram(fsys fs.FS, pp *PluginProgram) (err error) {
 	defer buildCatcher(&err) //catch the nasties
+	var pkgs native.Packages
+	if pkgs, err = pp.policy.packages(); err != nil {
+		pp.setState(bad)
+		return
+	}
 	local := native.Packages{
 		BuiltinPackageName: native.Package{
 			Name:         BuiltinPackageName,
@@ -132,7 +152,7 @@ func buildProgram(fsys fs.FS, pp *PluginProgram) (err error) {
 	}
 	opts := scriggo.BuildOptions{
 		AllowGoStmt: true,
-		Packages:    native.CombinedImporter{packages, local},
+		Packages:    native.CombinedImporter{pkgs, local},
 	}
 
 	// Build the program.
@@ -186,6 +206,10 @@ type PluginProgram struct {
 	state      pluginState
 	err        error
 	registered bool
+	policy     Policy
+	fault      *FaultError // set when the plugin is stopped for violating its policy
+	released   bool
+	store      *Store
 }
 
 func (pp *PluginProgram) setState(v pluginState) {
@@ -266,7 +290,43 @@ func (pp *PluginProgram) Start() (err error) {
 	return
 }
 
+// SyncStore writes the key value store to disk if it has changed and the sync interval has elapsed,
+// the owner should call it after each Process call so errors can be reported
+func (pp *PluginProgram) SyncStore() error {
+	if pp == nil || pp.store == nil {
+		return nil
+	}
+	return pp.store.syncIfDue()
+}
+
+// SetStore sets the key value store handed to the plugin by gravwell.OpenStore, it must be called before Run.
+// The plugin takes ownership of the store and closes it when the plugin is closed.
+func (pp *PluginProgram) SetStore(s *Store) error {
+	if s == nil {
+		return errors.New("nil store")
+	} else if st := pp.getState(); st != built {
+		return fmt.Errorf("bad state, %s != %s", st, built)
+	}
+	pp.store.Close()
+	pp.store = s
+	return nil
+}
+
+func (pp *PluginProgram) openStore(namespace string) (KVStore, error) {
+	return pp.store.Namespace(namespace)
+}
+
 func (pp *PluginProgram) Close() (err error) {
+	defer func() {
+		if serr := pp.store.Close(); err == nil && serr != nil {
+			err = fmt.Errorf("failed to sync key value store: %w", serr)
+		}
+	}()
+	if fe := pp.faulted(); fe != nil {
+		//the plugin was already stopped when it violated its policy
+		err = fe
+		return
+	}
 	if s := pp.getState(); s != running {
 		if s == bad || s == done {
 			err = pp.err
@@ -285,7 +345,7 @@ func (pp *PluginProgram) Close() (err error) {
 		perr = cf()
 	}
 
-	pp.Done()
+	pp.release()
 	time.Sleep(250 * time.Millisecond) //let the program close out
 	pp.cancel()                        //go down hard
 	if err = <-pp.dc; err == nil {
@@ -316,9 +376,18 @@ func (pp *PluginProgram) Config(vc *config.VariableConfig, tg Tagger) error {
 func (pp *PluginProgram) Flush() []*entry.Entry {
 	if pp == nil || pp.cf == nil {
 		return nil
+	} else if pp.faulted() != nil {
+		return nil
 	} else if st := pp.getState(); st != running {
 		return nil
 	}
+	// sync errors are reported when the plugin is closed
+	defer pp.store.Sync()
+	if pp.policy.guarded() {
+		// we can't propagate the error up here, a violation stops the plugin and is reported by Process and Close
+		ents, _ := pp.guard(FlushFuncName, func() ([]*entry.Entry, error) { return pp.ff(), nil })
+		return ents
+	}
 
 	defer func() {
 		// we can't propagate the error up here and we don't have a logger... :(
@@ -331,9 +400,17 @@ func (pp *PluginProgram) Flush() []*entry.Entry {
 func (pp *PluginProgram) Process(ents []*entry.Entry) (pents []*entry.Entry, err error) {
 	if pp == nil || pp.cf == nil {
 		return nil, ErrNotReady
+	} else if fe := pp.faulted(); fe != nil {
+		return nil, fe
 	} else if st := pp.getState(); st != running {
 		return nil, fmt.Errorf("bad state, %s != %s", st, running)
 	}
+	if pp.policy.guarded() {
+		// an abandoned call can keep running after guard returns, so the plugin works on copies and
+		// the caller's entries are never touched by a call that faults
+		cp := copyEntries(ents)
+		return pp.guard(ProcessFuncName, func() ([]*entry.Entry, error) { return pp.pf(cp) })
+	}
 
 	defer func() {
 		if r := recover(); r != nil {
@@ -345,6 +422,17 @@ func (pp *PluginProgram) Process(ents []*entry.Entry) (pents []*entry.Entry, err
 	return
 }
 
+// release lets the registration call return so the plugin main function can exit, it is safe to call more than once
+func (pp *PluginProgram) release() {
+	pp.Lock()
+	r := pp.released
+	pp.released = true
+	pp.Unlock()
+	if !r {
+		pp.Done()
+	}
+}
+
 // Ready indicates if the program is running and has registered all the things we need
 func (pp *PluginProgram) Ready() (r bool) {
 	r = pp.getState() == registered
@@ -381,8 +469,10 @@ func (pp *PluginProgram) execute() {
 }
 
 func builtinItems(pp *PluginProgram) native.Declarations {
-	decs := make(native.Declarations, 2)
+	decs := make(native.Declarations, 8)
 	decs[ExecuteFuncName] = pp.register
+	decs[OpenStoreFuncName] = pp.openStore
+	decs[KVStoreName] = reflect.TypeOf((*KVStore)(nil)).Elem()
 	decs[ConfigFuncName] = reflect.TypeOf((*ConfigFunc)(nil)).Elem()
 	decs[FlushFuncName] = reflect.TypeOf((*FlushFunc)(nil)).Elem()
 	decs[ProcessFuncName] = reflect.TypeOf((*ProcessFunc)(nil)).Elem()
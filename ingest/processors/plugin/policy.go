@@ -0,0 +1,172 @@
+//go:build !386 && !arm && !mips && !mipsle && !s390x
+// +build !386,!arm,!mips,!mipsle,!s390x
+
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package plugin
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gravwell/gravwell/v3/ingest/entry"
+	"github.com/open2b/scriggo/native"
+)
+
+var (
+	ErrUnknownPackage = errors.New("unknown package")
+	ErrProcessTimeout = errors.New("plugin call exceeded the process timeout")
+)
+
+// Policy restricts what a plugin may do, the zero value applies no restrictions.
+//
+// AllowedPackages lists the import paths a plugin may use, entries ending in "/*" allow an entire
+// package tree (e.g. "encoding/*").  The gravwell builtin package is always available.
+//
+// ProcessTimeout applies to each call to Process and Flush.  A call that exceeds it is abandoned, the
+// plugin is stopped, and the call and all subsequent calls return a FaultError.  The abandoned call is
+// still running inside the plugin, so the plugin cannot safely be called again.  When a timeout is set
+// Process hands the plugin deep copies of the entries so the original entries are left untouched for
+// the caller to pass along.
+//
+// There is no memory ceiling, the runtime has no per-goroutine allocation accounting and a process wide
+// measure would charge the plugin for allocations made by the rest of the ingester.
+type Policy struct {
+	AllowedPackages []string
+	ProcessTimeout  time.Duration
+}
+
+// Validate checks that every allowed package exists in the plugin symbol table
+func (p Policy) Validate() (err error) {
+	_, err = p.packages()
+	return
+}
+
+func (p Policy) guarded() bool {
+	return p.ProcessTimeout > 0
+}
+
+// packages returns the symbol table visible to the plugin
+func (p Policy) packages() (r native.Packages, err error) {
+	if len(p.AllowedPackages) == 0 {
+		r = packages
+		return
+	}
+	r = native.Packages{}
+	for _, v := range p.AllowedPackages {
+		v = strings.TrimSpace(v)
+		if pfx := strings.TrimSuffix(v, `/*`); pfx != v {
+			var found bool
+			for name, pkg := range packages {
+				if strings.HasPrefix(name, pfx+`/`) {
+					r[name] = pkg
+					found = true
+				}
+			}
+			if !found {
+				err = fmt.Errorf("%w %q", ErrUnknownPackage, v)
+				return
+			}
+		} else if pkg, ok := packages[v]; ok {
+			r[v] = pkg
+		} else {
+			err = fmt.Errorf("%w %q", ErrUnknownPackage, v)
+			return
+		}
+	}
+	return
+}
+
+// AvailablePackages returns the sorted list of packages that can be placed in a policy allowlist
+func AvailablePackages() (r []string) {
+	r = make([]string, 0, len(packages))
+	for k := range packages {
+		r = append(r, k)
+	}
+	sort.Strings(r)
+	return
+}
+
+type callResult struct {
+	ents []*entry.Entry
+	err  error
+}
+
+// guard executes fn in its own goroutine and enforces the process timeout.  If the timeout is hit the
+// plugin is stopped and the fault is returned, the abandoned goroutine is left to exit on its own
+// once the scriggo VM observes the cancelled context.  A call that completes is never abandoned.
+func (pp *PluginProgram) guard(name string, fn func() ([]*entry.Entry, error)) (ents []*entry.Entry, err error) {
+	rc := make(chan callResult, 1)
+	go func() {
+		var r callResult
+		defer func() {
+			if v := recover(); v != nil {
+				r.err = newFaultError(fmt.Errorf("failed to call %s", name), v)
+			}
+			rc <- r
+		}()
+		r.ents, r.err = fn()
+	}()
+
+	tmr := time.NewTimer(pp.policy.ProcessTimeout)
+	defer tmr.Stop()
+	var r callResult
+	select {
+	case r = <-rc:
+	case <-tmr.C:
+		//the call may have finished as the timer fired, only abandon it if it is still running
+		select {
+		case r = <-rc:
+		default:
+			err = pp.abort(name, ErrProcessTimeout)
+			return
+		}
+	}
+	ents, err = r.ents, r.err
+	return
+}
+
+// abort stops a plugin that violated its policy, the fault is retained and returned by every subsequent call
+func (pp *PluginProgram) abort(name string, cause error) (fe *FaultError) {
+	fe = newFaultError(fmt.Errorf("%s: %w", name, cause), fmt.Sprintf("plugin %s stopped", pp.name))
+	pp.Lock()
+	if pp.fault == nil {
+		pp.fault = fe
+		pp.state = bad
+	} else {
+		fe = pp.fault
+	}
+	pp.Unlock()
+	pp.cancel()
+	pp.release()
+	return
+}
+
+// faulted returns the policy violation that stopped the plugin, if any
+func (pp *PluginProgram) faulted() (fe *FaultError) {
+	pp.Lock()
+	fe = pp.fault
+	pp.Unlock()
+	return
+}
+
+// copyEntries deep copies a batch of entries so an abandoned plugin call cannot modify the originals
+func copyEntries(ents []*entry.Entry) (r []*entry.Entry) {
+	r = make([]*entry.Entry, 0, len(ents))
+	for _, ent := range ents {
+		if ent != nil {
+			c := ent.DeepCopy()
+			r = append(r, &c)
+		}
+	}
+	return
+}
@@ -0,0 +1,162 @@
+//go:build !386 && !arm && !mips && !mipsle && !s390x
+// +build !386,!arm,!mips,!mipsle,!s390x
+
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package plugin
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gravwell/gravwell/v3/ingest/entry"
+	"github.com/open2b/scriggo"
+)
+
+const osPlugin = `
+package main
+
+import (
+	"gravwell"
+	"os"
+	"strings"
+
+	"github.com/gravwell/gravwell/v3/ingest/entry"
+)
+
+func main() {
+	gravwell.Execute("test", cf, nop, nop, pf, ff)
+}
+
+func cf(cm gravwell.ConfigMap, tg gravwell.Tagger) error {
+	return nil
+}
+
+func ff() []*entry.Entry {
+	return nil
+}
+
+func pf(ents []*entry.Entry) ([]*entry.Entry, error) {
+	os.Getenv(strings.ToUpper("home"))
+	return ents, nil
+}
+
+func nop() error {
+	return nil
+}
+`
+
+const spinPlugin = `
+package main
+
+import (
+	"gravwell"
+
+	"github.com/gravwell/gravwell/v3/ingest/entry"
+)
+
+func main() {
+	gravwell.Execute("spin", cf, nop, nop, pf, ff)
+}
+
+func cf(cm gravwell.ConfigMap, tg gravwell.Tagger) error {
+	return nil
+}
+
+func ff() []*entry.Entry {
+	return nil
+}
+
+func pf(ents []*entry.Entry) ([]*entry.Entry, error) {
+	if len(ents) > 0 && string(ents[0].Data) == "spin" {
+		ents[0].Data = []byte("mutated")
+		for {
+		}
+	}
+	return ents, nil
+}
+
+func nop() error {
+	return nil
+}
+`
+
+func startPolicyPlugin(t *testing.T, prog string, pol Policy) *PluginProgram {
+	t.Helper()
+	pp, err := NewPluginWithPolicy(scriggo.Files{`main.go`: []byte(prog)}, false, pol)
+	if err != nil {
+		t.Fatal(err)
+	} else if err = pp.Run(time.Second); err != nil {
+		t.Fatal(err)
+	} else if err = pp.Config(nil, NewTestTagger()); err != nil {
+		t.Fatal(err)
+	} else if err = pp.Start(); err != nil {
+		t.Fatal(err)
+	}
+	return pp
+}
+
+func TestPolicyImports(t *testing.T) {
+	//os is not in the allowlist
+	pol := Policy{AllowedPackages: []string{`strings`, `github.com/gravwell/gravwell/v3/ingest/entry`}}
+	if _, err := NewPluginWithPolicy(scriggo.Files{`main.go`: []byte(osPlugin)}, false, pol); err == nil {
+		t.Fatal("failed to block disallowed import")
+	}
+	//allowing os lets the plugin build
+	pol.AllowedPackages = append(pol.AllowedPackages, `os`)
+	pp := startPolicyPlugin(t, osPlugin, pol)
+	if err := pp.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := (Policy{AllowedPackages: []string{`encoding/*`}}).Validate(); err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range []string{`not/a/package`, `nothere/*`} {
+		if err := (Policy{AllowedPackages: []string{v}}).Validate(); !errors.Is(err, ErrUnknownPackage) {
+			t.Fatalf("failed to catch unknown package %s: %v", v, err)
+		}
+	}
+	//no allowlist means everything is available
+	pp = startPolicyPlugin(t, osPlugin, Policy{})
+	if err := pp.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPolicyTimeout(t *testing.T) {
+	pp := startPolicyPlugin(t, spinPlugin, Policy{ProcessTimeout: 100 * time.Millisecond})
+	ents := []*entry.Entry{&entry.Entry{Data: []byte(`ok`)}}
+	if r, err := pp.Process(ents); err != nil {
+		t.Fatal(err)
+	} else if len(r) != 1 {
+		t.Fatalf("bad output count %d", len(r))
+	}
+
+	start := time.Now()
+	spin := &entry.Entry{Data: []byte(`spin`)}
+	_, err := pp.Process([]*entry.Entry{spin})
+	var fe *FaultError
+	if !errors.As(err, &fe) || !errors.Is(err, ErrProcessTimeout) {
+		t.Fatalf("bad timeout error: %v", err)
+	} else if time.Since(start) > 5*time.Second {
+		t.Fatal("timeout was not enforced")
+	} else if string(spin.Data) != `spin` {
+		//the abandoned call is still running, it must only ever see a copy
+		t.Fatalf("abandoned call modified the original entry: %q", spin.Data)
+	}
+	//the plugin is stopped, every call reports the fault
+	if _, err = pp.Process(ents); !errors.Is(err, ErrProcessTimeout) {
+		t.Fatalf("stopped plugin did not report fault: %v", err)
+	} else if r := pp.Flush(); r != nil {
+		t.Fatal("stopped plugin flushed entries")
+	} else if err = pp.Close(); !errors.Is(err, ErrProcessTimeout) {
+		t.Fatalf("bad close error: %v", err)
+	}
+}
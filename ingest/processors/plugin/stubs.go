@@ -27,6 +27,19 @@ func NewPlugin(fsys fs.FS) (*PluginProgram, error) {
 	return nil, ErrNotSupported
 }
 
+type Policy struct {
+	AllowedPackages []string
+	ProcessTimeout  time.Duration
+}
+
+func (p Policy) Validate() error {
+	return ErrNotSupported
+}
+
+func NewPluginWithPolicy(fsys fs.FS, debug bool, pol Policy) (*PluginProgram, error) {
+	return nil, ErrNotSupported
+}
+
 func (pp *PluginProgram) Run(to time.Duration) error {
 	return ErrNotSupported
 }
@@ -43,6 +56,10 @@ func (pp *PluginProgram) Process(ents []*entry.Entry) ([]*entry.Entry, error) {
 	return nil, ErrNotSupported
 }
 
+func (pp *PluginProgram) SyncStore() error {
+	return nil
+}
+
 func (pp *PluginProgram) Ready() bool {
 	return false
 }
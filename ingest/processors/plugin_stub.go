@@ -26,7 +26,9 @@ var (
 	ErrNotSupported = errors.New("plugins are not supported on 32bit architectures")
 )
 
-type PluginConfig struct{}
+type PluginConfig struct {
+	name string
+}
 type Plugin struct{}
 
 func PluginLoadConfig(vc *config.VariableConfig) (pc PluginConfig, err error) {
@@ -39,6 +41,10 @@ func NewPluginProcessor(cfg PluginConfig, tg Tagger) (p *Plugin, err error) {
 	return
 }
 
+func (pc ProcessorConfig) checkPluginStores() error {
+	return nil
+}
+
 func (p *Plugin) Close() error {
 	return ErrNotSupported
 }
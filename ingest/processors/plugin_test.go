@@ -14,6 +14,8 @@ package processors
 import (
 	"bytes"
 	"fmt"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/gravwell/gravwell/v3/ingest/config"
@@ -266,6 +268,106 @@ var (
 	testPluginCase = []byte(`This Is A Test Case`)
 )
 
+func TestPluginPolicyConfig(t *testing.T) {
+	base := `
+	[preprocessor "p2"]
+		type = plugin
+		Plugin-Path = "test_data/plugins/case_adjust.go"
+		Upper=true
+	`
+	good := []string{
+		``,
+		`Allowed-Package = bytes
+		Allowed-Package = errors
+		Allowed-Package = fmt
+		Allowed-Package = "github.com/gravwell/gravwell/v3/ingest/*"
+		Process-Timeout = 5s`,
+	}
+	bad := []string{
+		`Allowed-Package = bytes`, //missing imports used by the plugin
+		`Allowed-Package = "not/a/package"`,
+		`Process-Timeout = "soon"`,
+		`Max-Alloc = 64MB`, //memory ceilings are not supported
+	}
+	for i, v := range append(good, bad...) {
+		tc := struct {
+			Preprocessor ProcessorConfig
+		}{}
+		if err := config.LoadConfigBytes(&tc, []byte(base+v)); err != nil {
+			t.Fatal(err)
+		}
+		var tt testTagger
+		p, err := tc.Preprocessor.getProcessor(`p2`, &tt)
+		if i < len(good) {
+			if err != nil {
+				t.Fatalf("config %d failed: %v", i, err)
+			}
+			set := makeEntrySet(testPluginCase, 123, 16)
+			if rset, err := p.Process(set); err != nil || len(rset) != len(set) {
+				t.Fatalf("config %d bad process: %d %v", i, len(rset), err)
+			} else if err = p.Close(); err != nil {
+				t.Fatal(err)
+			}
+		} else if err == nil {
+			t.Fatalf("failed to catch bad config %d", i)
+		}
+	}
+}
+
+func TestPluginStoreLocation(t *testing.T) {
+	//persistence is opt-in, named plugins are only given a default file once the ingester sets a directory
+	if loc := (&PluginConfig{name: `p1`}).storeLocation(); loc != `` {
+		t.Fatalf("got a default location %q without a PluginStateDir", loc)
+	}
+	defer func(dir string) { PluginStateDir = dir }(PluginStateDir)
+	PluginStateDir = t.TempDir()
+	base := `
+	[preprocessor "p1"]
+		type = plugin
+		Plugin-Path = "test_data/plugins/case_adjust.go"
+	[preprocessor "p2"]
+		type = plugin
+		Plugin-Path = "test_data/plugins/case_adjust.go"
+	`
+	tc := struct {
+		Preprocessor ProcessorConfig
+	}{}
+	if err := config.LoadConfigBytes(&tc, []byte(base)); err != nil {
+		t.Fatal(err)
+	} else if err = tc.Preprocessor.Validate(); err != nil {
+		t.Fatalf("default store locations collided: %v", err)
+	}
+	var cfg PluginConfig
+	cfg.name = `a/b`
+	if loc := cfg.storeLocation(); loc != filepath.Join(PluginStateDir, `a_b.state`) {
+		t.Fatalf("bad default location %q", loc)
+	}
+	cfg.name = ``
+	if loc := cfg.storeLocation(); loc != `` {
+		t.Fatalf("unnamed plugin got a default location %q", loc)
+	}
+
+	//two preprocessors cannot share a file
+	shared := filepath.Join(PluginStateDir, `shared.state`)
+	tc.Preprocessor = nil
+	cfgb := strings.ReplaceAll(base, `case_adjust.go"`, `case_adjust.go"
+		State-Store-Location = "`+shared+`"`)
+	if err := config.LoadConfigBytes(&tc, []byte(cfgb)); err != nil {
+		t.Fatal(err)
+	} else if err = tc.Preprocessor.Validate(); err == nil {
+		t.Fatal("failed to catch shared State-Store-Location")
+	}
+	//nor can an explicit location collide with another preprocessor's default
+	tc.Preprocessor = nil
+	cfgb = strings.Replace(base, `case_adjust.go"`, `case_adjust.go"
+		State-Store-Location = "`+filepath.Join(PluginStateDir, `p2.state`)+`"`, 1)
+	if err := config.LoadConfigBytes(&tc, []byte(cfgb)); err != nil {
+		t.Fatal(err)
+	} else if err = tc.Preprocessor.Validate(); err == nil {
+		t.Fatal("failed to catch State-Store-Location colliding with a default")
+	}
+}
+
 func makeEntrySet(base []byte, tag entry.EntryTag, count int) (r []*entry.Entry) {
 	r = make([]*entry.Entry, count)
 	for i := range r {
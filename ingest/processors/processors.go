@@ -19,7 +19,9 @@ import (
 	"fmt"
 	"strings"
 	"sync"
+	"time"
 
+	"github.com/gravwell/gravwell/v3/ingest"
 	"github.com/gravwell/gravwell/v3/ingest/config"
 	"github.com/gravwell/gravwell/v3/ingest/entry"
 	"github.com/gravwell/gravwell/v3/ingest/processors/plugin"
@@ -42,8 +44,17 @@ var (
 
 type ProcessorSet struct {
 	sync.Mutex
-	wtr entWriter
-	set []Processor
+	wtr     entWriter
+	set     []Processor
+	ctls    []*processorControl // optional match conditions and jumps, parallel to set
+	metrics []*processorMetrics // per processor counters, parallel to set
+	matches []bool
+	tgr     Tagger              // used to name tags in error logs, may be nil
+	lgr     ingest.IngestLogger // may be nil
+	dead    *[]*entry.Entry     // dead-lettered entries, shared with nested chains
+	deadBuf []*entry.Entry
+
+	statsMtx sync.Mutex // protects appends to set and metrics so stats can be read while processing
 }
 
 type ProcessorConfig map[string]*config.VariableConfig
@@ -78,6 +89,13 @@ func CheckProcessor(id string) error {
 	case SyslogRouterProcessor:
 	case TagSrcRouterProcessor:
 	case RegexReplaceProcessor:
+	case IPEnrichProcessor:
+	case LookupProcessor:
+	case RedactProcessor:
+	case KVProcessor:
+	case FieldRouterProcessor:
+	case ScriptProcessor:
+	case ChainProcessor:
 	default:
 		return checkProcessorOS(id)
 	}
@@ -147,9 +165,26 @@ func ProcessorLoadConfig(vc *config.VariableConfig) (cfg interface{}, err error)
 		cfg, err = TagSrcRouterLoadConfig(vc)
 	case RegexReplaceProcessor:
 		cfg, err = RegexReplaceLoadConfig(vc)
+	case IPEnrichProcessor:
+		cfg, err = IPEnrichLoadConfig(vc)
+	case LookupProcessor:
+		cfg, err = LookupLoadConfig(vc)
+	case RedactProcessor:
+		cfg, err = RedactLoadConfig(vc)
+	case KVProcessor:
+		cfg, err = KVLoadConfig(vc)
+	case FieldRouterProcessor:
+		cfg, err = FieldRouterLoadConfig(vc)
+	case ScriptProcessor:
+		cfg, err = ScriptLoadConfig(vc)
+	case ChainProcessor:
+		cfg, err = ChainLoadConfig(vc)
 	default:
 		cfg, err = processorLoadConfigOS(vc)
 	}
+	if err == nil {
+		_, err = loadProcessorControl(vc)
+	}
 	return
 }
 
@@ -179,16 +214,25 @@ func (pc ProcessorConfig) MarshalJSON() ([]byte, error) {
 	return json.Marshal(mp)
 }
 
-func (pc ProcessorConfig) getProcessor(name string, tgr Tagger) (p Processor, err error) {
-	if vc, ok := pc[name]; !ok || vc == nil {
-		err = ErrNotFound
-	} else {
-		p, err = newProcessor(vc, tgr)
+// processorType returns the normalized type of the named preprocessor block
+func (pc ProcessorConfig) processorType(name string) string {
+	var pb preprocessorBase
+	if vc, ok := pc[name]; ok && vc != nil {
+		if err := vc.MapTo(&pb); err != nil {
+			return ``
+		}
 	}
+	return strings.TrimSpace(strings.ToLower(pb.Type))
+}
+
+// getProcessor builds a single named preprocessor, match conditions and jumps are only honored
+// when the preprocessor is part of a ProcessorSet
+func (pc ProcessorConfig) getProcessor(name string, tgr Tagger) (p Processor, err error) {
+	p, _, err = pc.buildProcessor(name, tgr, nil)
 	return
 }
 
-func newProcessor(vc *config.VariableConfig, tgr Tagger) (p Processor, err error) {
+func newProcessor(name string, vc *config.VariableConfig, tgr Tagger) (p Processor, err error) {
 	var pb preprocessorBase
 	if err = vc.MapTo(&pb); err != nil {
 		return
@@ -289,6 +333,7 @@ func newProcessor(vc *config.VariableConfig, tgr Tagger) (p Processor, err error
 		var cfg PluginConfig
 		// PluginLoadConfig needs to be called instead of MapTo so that it can do some additional validation
 		if cfg, err = PluginLoadConfig(vc); err == nil {
+			cfg.name = name
 			p, err = NewPluginProcessor(cfg, tgr)
 		}
 		return
@@ -316,6 +361,44 @@ func newProcessor(vc *config.VariableConfig, tgr Tagger) (p Processor, err error
 			return
 		}
 		p, err = NewRegexReplacer(cfg)
+	case IPEnrichProcessor:
+		var cfg IPEnrichConfig
+		if cfg, err = IPEnrichLoadConfig(vc); err != nil {
+			return
+		}
+		p, err = NewIPEnrich(cfg)
+	case LookupProcessor:
+		var cfg LookupConfig
+		if cfg, err = LookupLoadConfig(vc); err != nil {
+			return
+		}
+		p, err = NewLookup(cfg)
+	case RedactProcessor:
+		var cfg RedactConfig
+		if cfg, err = RedactLoadConfig(vc); err != nil {
+			return
+		}
+		p, err = NewRedact(cfg)
+	case KVProcessor:
+		var cfg KVConfig
+		if cfg, err = KVLoadConfig(vc); err != nil {
+			return
+		}
+		p, err = NewKV(cfg, tgr)
+	case FieldRouterProcessor:
+		var cfg FieldRouterConfig
+		if cfg, err = FieldRouterLoadConfig(vc); err != nil {
+			return
+		}
+		p, err = NewFieldRouter(cfg, tgr)
+	case ScriptProcessor:
+		var cfg ScriptConfig
+		if cfg, err = ScriptLoadConfig(vc); err != nil {
+			return
+		}
+		p, err = NewScript(cfg, tgr)
+	case ChainProcessor:
+		err = errors.New("chain preprocessors must be built from a ProcessorConfig")
 	default:
 		p, err = newProcessorOS(vc, tgr)
 	}
@@ -323,9 +406,11 @@ func newProcessor(vc *config.VariableConfig, tgr Tagger) (p Processor, err error
 }
 
 func NewProcessorSet(wtr entWriter) *ProcessorSet {
-	return &ProcessorSet{
+	ps := &ProcessorSet{
 		wtr: wtr,
 	}
+	ps.dead = &ps.deadBuf
+	return ps
 }
 
 func (pr *ProcessorSet) Enabled() bool {
@@ -343,7 +428,30 @@ func (pr *ProcessorSet) Count() int {
 func (pr *ProcessorSet) AddProcessor(p Processor) {
 	pr.Lock()
 	defer pr.Unlock()
+	pr.addProcessor(p, nil, ``, ``)
+}
+
+func (pr *ProcessorSet) addProcessor(p Processor, ctl *processorControl, name, typ string) {
+	m := newProcessorMetrics(name, typ, p)
+	if name == `` {
+		m.name = fmt.Sprintf("%s-%d", m.typ, len(pr.set))
+	}
+	if c, ok := p.(*Chain); ok {
+		c.ps.setDeadLetterBuffer(pr.dead)
+	}
+	if ctl != nil {
+		if c, ok := ctl.jumper.(*Chain); ok {
+			c.ps.setDeadLetterBuffer(pr.dead)
+		}
+		if ctl.dl != nil {
+			ctl.dl.name = m.name
+		}
+	}
+	pr.statsMtx.Lock()
 	pr.set = append(pr.set, p)
+	pr.ctls = append(pr.ctls, ctl)
+	pr.metrics = append(pr.metrics, m)
+	pr.statsMtx.Unlock()
 }
 
 func (pr *ProcessorSet) Process(ent *entry.Entry) (err error) {
@@ -442,61 +550,198 @@ func (pr *ProcessorSet) writeSetContext(ents []*entry.Entry, ctx context.Context
 
 // processItem recurses into each processor generating entries and writing them out
 func (pr *ProcessorSet) processItems(ents []*entry.Entry) (set []*entry.Entry, err error) {
+	if set, err = pr.processFrom(0, ents); err == nil {
+		set = pr.drainDeadLetters(set)
+	}
+	return
+}
+
+// processFrom pushes the entries through the processors starting at index start
+func (pr *ProcessorSet) processFrom(start int, ents []*entry.Entry) (set []*entry.Entry, err error) {
 	set = ents
-	if len(pr.set) == 0 {
-		return
+	for i := start; i < len(pr.set) && len(set) > 0; i++ {
+		if set, err = pr.runProcessor(i, set); err != nil {
+			break // something intentionally returned an error, break out
+		}
+	}
+	return
+}
+
+// runProcessor hands the entries that satisfy the match conditions of the processor at index i
+// to the processor and its jump target.  Entries that do not match pass through in their original order.
+func (pr *ProcessorSet) runProcessor(i int, ents []*entry.Entry) (set []*entry.Entry, err error) {
+	ctl := pr.ctls[i]
+	if !ctl.conditional() {
+		return pr.invoke(i, ents)
+	}
+	if cap(pr.matches) < len(ents) {
+		pr.matches = make([]bool, len(ents))
+	}
+	matches := pr.matches[:len(ents)]
+	var cnt int
+	for j, ent := range ents {
+		if matches[j] = ent != nil && ctl.match(ent); matches[j] {
+			cnt++
+		}
 	}
-	for i := 0; i < len(pr.set) && len(set) > 0; i++ {
-		orig := set
-		if set, err = pr.set[i].Process(orig); err != nil {
-			//TODO FIXME Issue #1225 - https://github.com/gravwell/gravwell/issues/1225
-			if _, ok := err.(*plugin.FaultError); ok {
-				// LOG THIS for issue #1225 and put in some logic
-				// to throttle the frequency of the logs in case the plugin is completely broken
-				set = orig //ignore what the plugin tried to do
-				err = nil  // clear the error
-				continue
+	if cnt == 0 {
+		return ents, nil
+	} else if cnt == len(ents) {
+		return pr.invoke(i, ents)
+	}
+
+	// mixed set, processors are allowed to reuse the slice they are handed so each
+	// matching run is copied out before it is processed
+	set = make([]*entry.Entry, 0, len(ents))
+	for s := 0; s < len(ents); {
+		e := s + 1
+		for e < len(ents) && matches[e] == matches[s] {
+			e++
+		}
+		if !matches[s] {
+			set = append(set, ents[s:e]...)
+		} else {
+			var out []*entry.Entry
+			if out, err = pr.invoke(i, append([]*entry.Entry(nil), ents[s:e]...)); err != nil {
+				return
 			}
-			break // something intentionally returned an error, break out
+			set = append(set, out...)
 		}
+		s = e
 	}
 	return
 }
 
-// processItemsOnFlush is just a processors that allows us to hand in the set of Processors as a parameter
-// we need to be able to do this as we force a flush and process on preprocessors
-func (pr *ProcessorSet) processItemsOnFlush(prs []Processor, ents []*entry.Entry) (set []*entry.Entry, err error) {
-	set = ents
-	if len(set) == 0 || len(prs) == 0 {
-		return
+// invoke runs the processor at index i and then its jump target if there is one, updating the metrics.
+// If the item has a dead-letter tag, failed entries are moved to the dead-letter buffer and errors are absorbed.
+func (pr *ProcessorSet) invoke(i int, ents []*entry.Entry) (set []*entry.Entry, err error) {
+	var fault error
+	tag := firstTag(ents)
+	cnt := len(ents)
+	ctl := pr.ctls[i]
+	var dl *deadLetterTarget
+	if ctl != nil && ctl.dl != nil {
+		// processors may reuse the slice, keep a copy in case the whole set fails
+		dl = ctl.dl
+		ctl.dlOrig = append(ctl.dlOrig[:0], ents...)
+	}
+	ts := time.Now()
+	if set, fault, err = invokeProcessor(pr.set[i], ents); err == nil && ctl != nil && ctl.jumper != nil && len(set) > 0 {
+		var jfault error
+		if set, jfault, err = invokeProcessor(ctl.jumper, set); jfault != nil {
+			fault = jfault
+		}
+	}
+	m := pr.metrics[i]
+	if err != nil {
+		m.update(cnt, 0, time.Since(ts), err)
+		m.logError(pr.lgr, pr.tgr, tag, err)
+	} else {
+		m.update(cnt, len(set), time.Since(ts), fault)
+		m.logError(pr.lgr, pr.tgr, tag, fault)
 	}
-	for i := 0; i < len(prs) && len(set) > 0; i++ {
-		if set, err = prs[i].Process(set); err != nil {
+	if dl != nil {
+		if err != nil || fault != nil {
+			if err == nil {
+				err = fault
+			}
+			dl.failed = dl.failed[:0]
+			for _, ent := range ctl.dlOrig {
+				dl.add(ent, err)
+			}
+			set, err = nil, nil
+		}
+		dl.drain(pr.dead)
+	}
+	return
+}
+
+func firstTag(ents []*entry.Entry) (tg entry.EntryTag) {
+	for _, ent := range ents {
+		if ent != nil {
+			tg = ent.Tag
 			break
 		}
 	}
 	return
 }
 
-// Close will close the underlying preprocessors within the set.
-// This function DOES NOT close the ingest muxer handle.
-// It is ONLY for shutting down preprocessors
-func (pr *ProcessorSet) Close() (err error) {
+// invokeProcessor runs a single processor, plugin faults are returned separately and the
+// original entries are passed along as if the processor was not there
+func invokeProcessor(p Processor, ents []*entry.Entry) (set []*entry.Entry, fault, err error) {
+	orig := ents
+	if set, err = p.Process(orig); err != nil {
+		//TODO FIXME Issue #1225 - https://github.com/gravwell/gravwell/issues/1225
+		if _, ok := err.(*plugin.FaultError); ok {
+			set = orig //ignore what the plugin tried to do
+			fault = err
+			err = nil // clear the error
+		}
+	}
+	return
+}
+
+// flushItems forces a flush on each processor in the set, pushing flushed entries
+// through the jump target and the remaining processors
+func (pr *ProcessorSet) flushItems() (set []*entry.Entry, err error) {
 	for i, v := range pr.set {
-		if v != nil {
-			if ents := v.Flush(); len(ents) > 0 {
-				if ents, lerr := pr.processItemsOnFlush(pr.set[i+1:], ents); lerr != nil {
+		if v == nil {
+			continue
+		}
+		ents := v.Flush()
+		if ctl := pr.ctls[i]; ctl != nil && ctl.dl != nil {
+			ctl.dl.drain(pr.dead)
+		}
+		if ctl := pr.ctls[i]; ctl != nil && ctl.jumper != nil {
+			if len(ents) > 0 {
+				var lerr error
+				if ents, _, lerr = invokeProcessor(ctl.jumper, ents); lerr != nil {
 					err = addError(lerr, err)
-				} else if len(ents) > 0 {
-					if lerr := pr.writeSet(ents); lerr != nil {
-						err = addError(lerr, err)
-					}
+					ents = nil
 				}
 			}
+			ents = append(ents, ctl.jumper.Flush()...)
+		}
+		if len(ents) > 0 {
+			if ents, lerr := pr.processFrom(i+1, ents); lerr != nil {
+				err = addError(lerr, err)
+			} else {
+				set = append(set, ents...)
+			}
+		}
+	}
+	return
+}
+
+func (pr *ProcessorSet) closeProcessors() (err error) {
+	for i, v := range pr.set {
+		if v != nil {
 			if lerr := v.Close(); lerr != nil {
 				err = addError(lerr, err)
 			}
 		}
+		if ctl := pr.ctls[i]; ctl != nil && ctl.jumper != nil {
+			if lerr := ctl.jumper.Close(); lerr != nil {
+				err = addError(lerr, err)
+			}
+		}
+	}
+	return
+}
+
+// Close will close the underlying preprocessors within the set.
+// This function DOES NOT close the ingest muxer handle.
+// It is ONLY for shutting down preprocessors
+func (pr *ProcessorSet) Close() (err error) {
+	ents, err := pr.flushItems()
+	ents = pr.drainDeadLetters(ents)
+	if len(ents) > 0 && pr.wtr != nil {
+		if lerr := pr.writeSet(ents); lerr != nil {
+			err = addError(lerr, err)
+		}
+	}
+	if lerr := pr.closeProcessors(); lerr != nil {
+		err = addError(lerr, err)
 	}
 	return
 }
@@ -521,13 +766,18 @@ func (pc ProcessorConfig) ProcessorSet(t tagWriter, names []string) (pr *Process
 		return
 	}
 	pr = NewProcessorSet(t)
+	pr.tgr = t
+	if lgr, ok := t.(ingest.IngestLogger); ok {
+		pr.lgr = lgr
+	}
 	var p Processor
+	var ctl *processorControl
 	for _, n := range names {
-		if p, err = pc.getProcessor(n, t); err != nil {
+		if p, ctl, err = pc.buildProcessor(n, t, nil); err != nil {
 			err = fmt.Errorf("%s %v", n, err)
 			return
 		}
-		pr.AddProcessor(p)
+		pr.addProcessor(p, ctl, n, pc.processorType(n))
 	}
 	return
 }
@@ -537,6 +787,49 @@ func (pc ProcessorConfig) Validate() (err error) {
 		if _, err = ProcessorLoadConfig(v); err != nil {
 			err = fmt.Errorf("Preprocessor %s config invalid: %v", k, err)
 			return
+		} else if err = pc.checkReferences(k, nil); err != nil {
+			err = fmt.Errorf("Preprocessor %s config invalid: %v", k, err)
+			return
+		}
+	}
+	err = pc.checkPluginStores()
+	return
+}
+
+// checkReferences ensures that chain members and jump targets exist and do not loop back on themselves
+func (pc ProcessorConfig) checkReferences(name string, stack []string) (err error) {
+	vc, ok := pc[name]
+	if !ok || vc == nil {
+		return fmt.Errorf("Preprocessor %v not defined", name)
+	}
+	for _, v := range stack {
+		if v == name {
+			return ErrChainRecursed
+		}
+	}
+	stack = append(stack, name)
+	var refs []string
+	var pb preprocessorBase
+	var cc processorControlConfig
+	var ch ChainConfig
+	if err = vc.MapTo(&pb); err != nil {
+		return
+	} else if err = vc.MapTo(&cc); err != nil {
+		return
+	} else if cc.Jump = strings.TrimSpace(cc.Jump); cc.Jump != `` {
+		refs = append(refs, cc.Jump)
+	}
+	if strings.TrimSpace(strings.ToLower(pb.Type)) == ChainProcessor {
+		if err = vc.MapTo(&ch); err != nil {
+			return
+		}
+		for _, v := range ch.Preprocessor {
+			refs = append(refs, strings.TrimSpace(v))
+		}
+	}
+	for _, v := range refs {
+		if err = pc.checkReferences(v, stack); err != nil {
+			return
 		}
 	}
 	return
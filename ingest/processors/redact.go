@@ -0,0 +1,424 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package processors
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/gravwell/gravwell/v3/ingest/config"
+	"github.com/gravwell/gravwell/v3/ingest/entry"
+	"github.com/gravwell/jsonparser"
+)
+
+const (
+	RedactProcessor = `redact`
+
+	redactActionMask      = `mask`
+	redactActionDropField = `drop-field`
+	redactActionHMAC      = `hmac`
+	redactActionTokenize  = `tokenize`
+
+	redactEmail      = `email`
+	redactCreditCard = `credit-card`
+	redactSSN        = `ssn`
+	redactIPv4       = `ipv4`
+	redactIPv6       = `ipv6`
+	redactPhone      = `phone`
+
+	defaultRedactMask = `[REDACTED]`
+)
+
+var (
+	ErrMissingDetectors = errors.New("At least one Detector or Custom-Regex is required")
+	ErrMissingHMACKey   = errors.New("hmac and tokenize actions require HMAC-Key, HMAC-Key-File, or HMAC-Key-Env")
+)
+
+type RedactConfig struct {
+	Detector        []string // built-in detectors: email, credit-card, ssn, ipv4, ipv6, phone
+	Custom_Regex    []string // custom detectors specified as name:regex
+	Action          string   // default action: mask, drop-field, hmac, or tokenize; default is mask
+	Detector_Action []string // per-detector action overrides specified as name:action
+	Mask_Value      string   // replacement used by the mask action, default is [REDACTED]
+	HMAC_Key        string   // key used by the hmac and tokenize actions
+	HMAC_Key_File   string   // file containing the key
+	HMAC_Key_Env    string   // environment variable containing the key
+	JSON_Field      []string // optional dotted JSON paths to redact, the entire payload is redacted if empty
+}
+
+func RedactLoadConfig(vc *config.VariableConfig) (c RedactConfig, err error) {
+	if err = vc.MapTo(&c); err == nil {
+		_, _, _, err = c.validate()
+	}
+	return
+}
+
+type redactValidator func([]byte) bool
+
+type redactDetector struct {
+	name   string
+	rx     *regexp.Regexp
+	valid  redactValidator
+	action string
+}
+
+var builtinRedactDetectors = map[string]struct {
+	rx    string
+	valid redactValidator
+}{
+	redactEmail:      {rx: `[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`},
+	redactCreditCard: {rx: `\b(?:\d[ \-]?){12,18}\d\b`, valid: luhnValid},
+	redactSSN:        {rx: `\b\d{3}-\d{2}-\d{4}\b`, valid: ssnValid},
+	redactIPv4:       {rx: `\b(?:(?:25[0-5]|2[0-4]\d|1\d\d|[1-9]?\d)\.){3}(?:25[0-5]|2[0-4]\d|1\d\d|[1-9]?\d)\b`},
+	redactIPv6:       {rx: `(?:[0-9A-Fa-f]{0,4}:){2,7}[0-9A-Fa-f]{0,4}`, valid: ipv6Valid},
+	redactPhone:      {rx: `(?:\+\d{1,3}[ .\-]?)?(?:\(\d{3}\)|\b\d{3})[ .\-]?\d{3}[ .\-]\d{4}\b`, valid: phoneValid},
+}
+
+func (c *RedactConfig) validate() (dets []redactDetector, jkeys [][]string, key []byte, err error) {
+	if len(c.Detector) == 0 && len(c.Custom_Regex) == 0 {
+		err = ErrMissingDetectors
+		return
+	}
+	if c.Action = strings.ToLower(strings.TrimSpace(c.Action)); c.Action == `` {
+		c.Action = redactActionMask
+	} else if err = checkRedactAction(c.Action); err != nil {
+		return
+	}
+	if c.Mask_Value == `` {
+		c.Mask_Value = defaultRedactMask
+	}
+	names := map[string]int{}
+	for _, d := range c.Detector {
+		d = strings.ToLower(strings.TrimSpace(d))
+		bd, ok := builtinRedactDetectors[d]
+		if !ok {
+			err = fmt.Errorf("Unknown detector %q", d)
+			return
+		} else if _, ok = names[d]; ok {
+			err = fmt.Errorf("Duplicate detector %q", d)
+			return
+		}
+		names[d] = len(dets)
+		dets = append(dets, redactDetector{
+			name:   d,
+			rx:     regexp.MustCompile(bd.rx),
+			valid:  bd.valid,
+			action: c.Action,
+		})
+	}
+	for _, cr := range c.Custom_Regex {
+		name, rx, ok := strings.Cut(cr, `:`)
+		if name = strings.TrimSpace(name); !ok || name == `` || rx == `` {
+			err = fmt.Errorf("Custom-Regex %q must be specified as name:regex", cr)
+			return
+		} else if _, ok = names[name]; ok {
+			err = fmt.Errorf("Duplicate detector %q", name)
+			return
+		}
+		var crx *regexp.Regexp
+		if crx, err = regexp.Compile(rx); err != nil {
+			err = fmt.Errorf("Custom-Regex %s is invalid: %w", name, err)
+			return
+		}
+		names[name] = len(dets)
+		dets = append(dets, redactDetector{
+			name:   name,
+			rx:     crx,
+			action: c.Action,
+		})
+	}
+	for _, da := range c.Detector_Action {
+		name, action, ok := strings.Cut(da, `:`)
+		name = strings.TrimSpace(name)
+		action = strings.ToLower(strings.TrimSpace(action))
+		if !ok {
+			err = fmt.Errorf("Detector-Action %q must be specified as name:action", da)
+			return
+		} else if err = checkRedactAction(action); err != nil {
+			return
+		}
+		idx, ok := names[strings.ToLower(name)]
+		if !ok {
+			if idx, ok = names[name]; !ok {
+				err = fmt.Errorf("Detector-Action references unknown detector %q", name)
+				return
+			}
+		}
+		dets[idx].action = action
+	}
+	var needKey bool
+	for _, d := range dets {
+		if d.action == redactActionHMAC || d.action == redactActionTokenize {
+			needKey = true
+		}
+	}
+	var s string
+	if s, err = config.LoadSecret(c.HMAC_Key, c.HMAC_Key_File, c.HMAC_Key_Env); err != nil {
+		return
+	} else if needKey && s == `` {
+		err = ErrMissingHMACKey
+		return
+	}
+	key = []byte(s)
+	for _, f := range c.JSON_Field {
+		keys := unquoteFields(splitRespectQuotes(f, dotSplitter))
+		if len(keys) == 0 {
+			err = fmt.Errorf("Invalid JSON field %q", f)
+			return
+		}
+		jkeys = append(jkeys, keys)
+	}
+	return
+}
+
+func checkRedactAction(v string) error {
+	switch v {
+	case redactActionMask, redactActionDropField, redactActionHMAC, redactActionTokenize:
+		return nil
+	}
+	return fmt.Errorf("Unknown redaction action %q", v)
+}
+
+type Redact struct {
+	nocloser
+	RedactConfig
+	dets  []redactDetector
+	jkeys [][]string
+	key   []byte
+	bb    *bytes.Buffer
+}
+
+func NewRedact(cfg RedactConfig) (*Redact, error) {
+	dets, jkeys, key, err := cfg.validate()
+	if err != nil {
+		return nil, err
+	}
+	return &Redact{
+		RedactConfig: cfg,
+		dets:         dets,
+		jkeys:        jkeys,
+		key:          key,
+		bb:           bytes.NewBuffer(nil),
+	}, nil
+}
+
+func (r *Redact) Config(v interface{}) (err error) {
+	if v == nil {
+		err = ErrNilConfig
+	} else if cfg, ok := v.(RedactConfig); ok {
+		if r.dets, r.jkeys, r.key, err = cfg.validate(); err == nil {
+			r.RedactConfig = cfg
+		}
+	} else {
+		err = fmt.Errorf("Invalid configuration, unknown type type %T", v)
+	}
+	return
+}
+
+func (r *Redact) Process(ents []*entry.Entry) ([]*entry.Entry, error) {
+	for _, ent := range ents {
+		if ent == nil {
+			continue
+		}
+		if len(r.jkeys) == 0 {
+			ent.Data, _ = r.redact(ent.Data)
+		} else {
+			ent.Data = r.redactJSON(ent.Data)
+		}
+	}
+	return ents, nil
+}
+
+// redactJSON redacts each of the configured fields, fields with a drop-field match are removed entirely
+func (r *Redact) redactJSON(data []byte) []byte {
+	for _, keys := range r.jkeys {
+		v, vt, _, err := jsonparser.Get(data, keys...)
+		if err != nil {
+			continue
+		}
+		var val []byte
+		switch vt {
+		case jsonparser.String:
+			s, err := jsonparser.ParseString(v)
+			if err != nil {
+				continue
+			}
+			val = []byte(s)
+		case jsonparser.Number:
+			val = v
+		default:
+			continue // objects, arrays, bools, and nulls are not redacted
+		}
+		nv, drop := r.redact(val)
+		if drop {
+			data = jsonparser.Delete(data, keys...)
+			continue
+		} else if bytes.Equal(nv, val) {
+			continue
+		}
+		enc, err := json.Marshal(string(nv))
+		if err != nil {
+			continue
+		}
+		if nd, err := jsonparser.Set(data, enc, keys...); err == nil {
+			data = nd
+		}
+	}
+	return data
+}
+
+type redactSpan struct {
+	start, end int
+	det        *redactDetector
+}
+
+// redact runs all detectors over the value and replaces matches.  Matches are collected from the original
+// value so that the output of one detector is never fed into another, overlapping matches are resolved by
+// taking the earliest and then longest match.  drop is true if a drop-field detector matched.
+func (r *Redact) redact(v []byte) (out []byte, drop bool) {
+	var spans []redactSpan
+	for i := range r.dets {
+		d := &r.dets[i]
+		for _, idx := range d.rx.FindAllIndex(v, -1) {
+			if idx[1] == idx[0] {
+				continue
+			} else if d.valid != nil && !d.valid(v[idx[0]:idx[1]]) {
+				continue
+			}
+			spans = append(spans, redactSpan{start: idx[0], end: idx[1], det: d})
+		}
+	}
+	if len(spans) == 0 {
+		return v, false
+	}
+	sort.SliceStable(spans, func(i, j int) bool {
+		if spans[i].start == spans[j].start {
+			return (spans[i].end - spans[i].start) > (spans[j].end - spans[j].start)
+		}
+		return spans[i].start < spans[j].start
+	})
+	r.bb.Reset()
+	var last int
+	for _, s := range spans {
+		if s.start < last {
+			continue // overlaps a previous match
+		}
+		r.bb.Write(v[last:s.start])
+		switch s.det.action {
+		case redactActionMask:
+			r.bb.WriteString(r.Mask_Value)
+		case redactActionDropField:
+			drop = true
+		case redactActionHMAC:
+			r.bb.WriteString(r.hmac(v[s.start:s.end]))
+		case redactActionTokenize:
+			r.bb.Write(r.tokenize(v[s.start:s.end]))
+		}
+		last = s.end
+	}
+	r.bb.Write(v[last:])
+	out = append([]byte{}, r.bb.Bytes()...)
+	return
+}
+
+func (r *Redact) hmac(v []byte) string {
+	mac := hmac.New(sha256.New, r.key)
+	mac.Write(v)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// tokenize performs a keyed, deterministic, format-preserving substitution.
+// Digits are replaced with digits, letters with letters of the same case, and everything
+// else is left intact so that the token retains the shape of the original value.
+func (r *Redact) tokenize(v []byte) (out []byte) {
+	mac := hmac.New(sha256.New, r.key)
+	var stream []byte
+	var ctr [4]byte
+	out = make([]byte, len(v))
+	for i, c := range v {
+		if len(stream) == 0 {
+			mac.Reset()
+			mac.Write(ctr[:])
+			mac.Write(v)
+			stream = mac.Sum(nil)
+			binary.BigEndian.PutUint32(ctr[:], binary.BigEndian.Uint32(ctr[:])+1)
+		}
+		b := stream[0]
+		switch {
+		case c >= '0' && c <= '9':
+			c = '0' + b%10
+			stream = stream[1:]
+		case c >= 'a' && c <= 'z':
+			c = 'a' + b%26
+			stream = stream[1:]
+		case c >= 'A' && c <= 'Z':
+			c = 'A' + b%26
+			stream = stream[1:]
+		}
+		out[i] = c
+	}
+	return
+}
+
+// luhnValid checks that the digits in a candidate credit card number pass the Luhn checksum
+func luhnValid(v []byte) bool {
+	var sum, cnt int
+	for i := len(v) - 1; i >= 0; i-- {
+		c := v[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+		d := int(c - '0')
+		if cnt%2 == 1 {
+			if d *= 2; d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		cnt++
+	}
+	return cnt >= 13 && cnt <= 19 && sum%10 == 0
+}
+
+// ssnValid rejects SSNs with area, group, or serial numbers that are never issued
+func ssnValid(v []byte) bool {
+	if len(v) != 11 {
+		return false
+	}
+	area, group, serial := string(v[0:3]), string(v[4:6]), string(v[7:11])
+	if area == `000` || area == `666` || area[0] == '9' || group == `00` || serial == `0000` {
+		return false
+	}
+	return true
+}
+
+func ipv6Valid(v []byte) bool {
+	ip := net.ParseIP(string(v))
+	return ip != nil && ip.To4() == nil
+}
+
+func phoneValid(v []byte) bool {
+	var cnt int
+	for _, c := range v {
+		if c >= '0' && c <= '9' {
+			cnt++
+		}
+	}
+	return cnt >= 10 && cnt <= 15
+}
@@ -0,0 +1,204 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package processors
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/gravwell/gravwell/v3/ingest/entry"
+)
+
+const (
+	testRedactKey = `supersecretkey`
+)
+
+func TestRedactConfig(t *testing.T) {
+	b := `
+	[preprocessor "pii"]
+		type = redact
+		Detector = email
+		Detector = credit-card
+		Custom-Regex = "empid:EMP-[0-9]{6}"
+		Detector-Action = "email:hmac"
+		Detector-Action = "empid:tokenize"
+		HMAC-Key = "` + testRedactKey + `"
+	`
+	p, err := testLoadPreprocessor(b, `pii`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, ok := p.(*Redact)
+	if !ok {
+		t.Fatalf("bad processor type %T", p)
+	} else if len(r.dets) != 3 {
+		t.Fatalf("bad detector count %d", len(r.dets))
+	} else if r.dets[0].action != `hmac` || r.dets[1].action != `mask` || r.dets[2].action != `tokenize` {
+		t.Fatalf("bad actions: %+v", r.dets)
+	}
+}
+
+func TestRedactBadConfig(t *testing.T) {
+	bad := []RedactConfig{
+		RedactConfig{},
+		RedactConfig{Detector: []string{`stuff`}},
+		RedactConfig{Detector: []string{`email`, `email`}},
+		RedactConfig{Detector: []string{`email`}, Action: `shred`},
+		RedactConfig{Detector: []string{`email`}, Action: `hmac`},
+		RedactConfig{Detector: []string{`email`}, Detector_Action: []string{`ssn:mask`}},
+		RedactConfig{Detector: []string{`email`}, Detector_Action: []string{`email`}},
+		RedactConfig{Custom_Regex: []string{`foo`}},
+		RedactConfig{Custom_Regex: []string{`foo:[a-`}},
+		RedactConfig{Detector: []string{`email`}, HMAC_Key_File: `test_data/nothere`},
+	}
+	for i, c := range bad {
+		if _, _, _, err := c.validate(); err == nil {
+			t.Fatalf("Failed to catch bad config %d (%+v)", i, c)
+		}
+	}
+}
+
+func TestRedactDetectors(t *testing.T) {
+	cfg := RedactConfig{
+		Detector:   []string{`email`, `credit-card`, `ssn`, `ipv4`, `ipv6`, `phone`},
+		Mask_Value: `X`,
+	}
+	r, err := NewRedact(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		in  string
+		out string
+	}{
+		{in: `user bob@example.com logged in`, out: `user X logged in`},
+		{in: `card 4111 1111 1111 1111 charged`, out: `card X charged`},
+		{in: `card 4111-1111-1111-1112 failed luhn`, out: `card 4111-1111-1111-1112 failed luhn`},
+		{in: `ssn=123-45-6789`, out: `ssn=X`},
+		{in: `ssn=666-45-6789`, out: `ssn=666-45-6789`},
+		{in: `from 192.168.1.1 to 10.0.0.256`, out: `from X to 10.0.0.256`},
+		{in: `from fe80::1 at 12:30:45`, out: `from X at 12:30:45`},
+		{in: `call (555) 123-4567 or +1 555.123.4567`, out: `call X or X`},
+		{in: `nothing to see here`, out: `nothing to see here`},
+	}
+	for _, tst := range tests {
+		ents := []*entry.Entry{&entry.Entry{Data: []byte(tst.in)}}
+		if ents, err = r.Process(ents); err != nil {
+			t.Fatal(err)
+		} else if len(ents) != 1 {
+			t.Fatalf("bad count %d", len(ents))
+		} else if string(ents[0].Data) != tst.out {
+			t.Fatalf("bad redaction of %q: %q != %q", tst.in, ents[0].Data, tst.out)
+		}
+	}
+}
+
+func TestRedactActions(t *testing.T) {
+	pth := filepath.Join(t.TempDir(), `key`)
+	if err := os.WriteFile(pth, []byte(testRedactKey+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	cfg := RedactConfig{
+		Detector:        []string{`email`, `ssn`, `phone`},
+		Custom_Regex:    []string{`empid:EMP-[0-9]{6}`},
+		Detector_Action: []string{`email:hmac`, `empid:tokenize`, `phone:drop-field`},
+		HMAC_Key_File:   pth,
+	}
+	r, err := NewRedact(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mac := hmac.New(sha256.New, []byte(testRedactKey))
+	mac.Write([]byte(`bob@example.com`))
+	hsh := hex.EncodeToString(mac.Sum(nil))
+
+	in := `bob@example.com EMP-123456 123-45-6789 555-123-4567 end`
+	ents := []*entry.Entry{&entry.Entry{Data: []byte(in)}, &entry.Entry{Data: []byte(in)}}
+	if ents, err = r.Process(ents); err != nil {
+		t.Fatal(err)
+	}
+	flds := strings.Split(string(ents[0].Data), ` `)
+	if len(flds) != 5 {
+		t.Fatalf("bad output %q", ents[0].Data)
+	} else if flds[0] != hsh {
+		t.Fatalf("bad hmac %q != %q", flds[0], hsh)
+	} else if flds[1] == `EMP-123456` || !regexp.MustCompile(`^[A-Z]{3}-[0-9]{6}$`).MatchString(flds[1]) {
+		t.Fatalf("bad token %q", flds[1])
+	} else if flds[2] != defaultRedactMask {
+		t.Fatalf("bad mask %q", flds[2])
+	} else if flds[3] != `` || flds[4] != `end` {
+		t.Fatalf("bad drop %q", ents[0].Data)
+	}
+	// pseudonymization must be consistent
+	if string(ents[0].Data) != string(ents[1].Data) {
+		t.Fatalf("inconsistent output: %q != %q", ents[0].Data, ents[1].Data)
+	}
+}
+
+func TestRedactJSON(t *testing.T) {
+	cfg := RedactConfig{
+		Detector:        []string{`email`, `credit-card`, `phone`},
+		Detector_Action: []string{`credit-card:drop-field`},
+		JSON_Field:      []string{`user.email`, `payment.card`, `phone`, `notes`},
+	}
+	r, err := NewRedact(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	in := `{"user": {"email": "bob@example.com", "name": "bob"}, "payment": {"card": 4111111111111111, "amount": 10}, "phone": "555-123-4567", "other": "alice@example.com"}`
+	ents := []*entry.Entry{&entry.Entry{Data: []byte(in)}}
+	if ents, err = r.Process(ents); err != nil {
+		t.Fatal(err)
+	}
+	var out struct {
+		User struct {
+			Email string
+			Name  string
+		}
+		Payment map[string]interface{}
+		Phone   interface{}
+		Other   string
+	}
+	if err = json.Unmarshal(ents[0].Data, &out); err != nil {
+		t.Fatalf("invalid JSON %q: %v", ents[0].Data, err)
+	}
+	if out.User.Email != defaultRedactMask || out.User.Name != `bob` {
+		t.Fatalf("bad user redaction: %+v", out.User)
+	} else if _, ok := out.Payment[`card`]; ok {
+		t.Fatalf("card not dropped: %+v", out.Payment)
+	} else if out.Payment[`amount`] != float64(10) {
+		t.Fatalf("bad payment: %+v", out.Payment)
+	} else if out.Phone != defaultRedactMask {
+		t.Fatalf("phone not redacted: %v", out.Phone)
+	} else if out.Other != `alice@example.com` {
+		t.Fatalf("unlisted field redacted: %v", out.Other)
+	}
+}
+
+func TestLuhn(t *testing.T) {
+	good := []string{`4111111111111111`, `5500 0000 0000 0004`, `3400-0000-0000-009`}
+	bad := []string{`4111111111111112`, `1234`, `0000000000000000000000`}
+	for _, v := range good {
+		if !luhnValid([]byte(v)) {
+			t.Fatalf("%s should be valid", v)
+		}
+	}
+	for _, v := range bad {
+		if luhnValid([]byte(v)) {
+			t.Fatalf("%s should be invalid", v)
+		}
+	}
+}
@@ -104,6 +104,7 @@ func (c *RegexExtractConfig) validate() (rx *regexp.Regexp, tmp *formatter, af [
 
 type RegexExtractor struct {
 	nocloser
+	failureHook
 	RegexExtractConfig
 	tmp       *formatter
 	rx        *regexp.Regexp
@@ -168,9 +169,8 @@ func (re *RegexExtractor) processEntry(ent *entry.Entry) (*entry.Entry, error) {
 		if len(re.attachSet) > 0 {
 			re.performAttaches(ent, mtchs)
 		}
-	} else if re.Drop_Misses {
-		//NOT passing through misses, so set ent to nil, this is a DROP
-		ent = nil
+	} else {
+		ent = re.miss(ent, ErrNoMatch, re.Drop_Misses)
 	}
 	return ent, nil
 }
@@ -47,6 +47,7 @@ type route struct {
 
 type RegexRouter struct {
 	nocloser
+	failureHook
 	RegexRouteConfig
 	routes   map[string]entry.EntryTag
 	drops    map[string]struct{}
@@ -122,9 +123,11 @@ func (rr *RegexRouter) processItem(ent *entry.Entry) *entry.Entry {
 			return nil
 		} else if ok {
 			ent.Tag = tag
+		} else {
+			return rr.miss(ent, ErrNoRoute, rr.Drop_Misses)
 		}
-	} else if rr.Drop_Misses {
-		return nil
+	} else {
+		return rr.miss(ent, ErrNoMatch, rr.Drop_Misses)
 	}
 	return ent
 }
@@ -133,11 +136,7 @@ func (rr *RegexRouter) handleExtract(v []byte) (tag entry.EntryTag, drop, ok boo
 	//check if we have a tag
 	if tag, ok = rr.routes[string(v)]; !ok {
 		//check if it should be dropped
-		if _, drop = rr.drops[string(v)]; !drop {
-			if rr.Drop_Misses {
-				drop = true
-			}
-		}
+		_, drop = rr.drops[string(v)]
 	}
 
 	return
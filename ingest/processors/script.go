@@ -0,0 +1,561 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package processors
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/gravwell/gravwell/v3/ingest/config"
+	"github.com/gravwell/gravwell/v3/ingest/entry"
+	starjson "go.starlark.net/lib/json"
+	starmath "go.starlark.net/lib/math"
+	startime "go.starlark.net/lib/time"
+	"go.starlark.net/starlark"
+	"go.starlark.net/starlarkstruct"
+	"go.starlark.net/syntax"
+)
+
+const (
+	ScriptProcessor = `script`
+
+	scriptProcessFunc   = `process`
+	scriptEntryType     = `entry`
+	scriptCurrentKey    = `current`
+	defaultScriptSteps  = 100000
+	scriptSourceDefault = `script.star`
+)
+
+var (
+	ErrMissingScript   = errors.New("Script or Script-Path is required")
+	ErrMultipleScripts = errors.New("Script and Script-Path cannot both be specified")
+	ErrInvalidMaxSteps = errors.New("Max-Steps must be positive")
+	ErrMissingProcess  = errors.New("script does not define a process function")
+	ErrScriptLoad      = errors.New("scripts cannot load modules")
+)
+
+// ScriptConfig configures a preprocessor that runs a Starlark script against every entry.
+//
+// The script must define a process function which accepts a single entry.  The entry exposes
+// the data, tag, src, and ts attributes along with methods to manipulate enumerated values.
+// Data is presented as a string and may be assigned either a string or bytes.
+// The return value of process determines what is emitted:
+//
+//	None or True - the (possibly modified) entry
+//	False        - nothing, the entry is dropped
+//	an entry     - the returned entry
+//	a list       - every entry in the list
+//
+// Scripts cannot load modules, read the clock, or keep state between entries; global values are
+// frozen once the script is loaded so the same entry always produces the same output.
+type ScriptConfig struct {
+	Script      string // inline Starlark source
+	Script_Path string // path to a Starlark source file
+	Max_Steps   int    // maximum execution steps for a single entry, default is 100000
+	Debug       bool   // send script print output to stdout
+}
+
+func ScriptLoadConfig(vc *config.VariableConfig) (c ScriptConfig, err error) {
+	if err = vc.MapTo(&c); err == nil {
+		_, _, err = c.validate()
+	}
+	return
+}
+
+// validate loads and compiles the script, returning the source file name and its contents
+func (c *ScriptConfig) validate() (name string, src []byte, err error) {
+	if c.Script == `` && c.Script_Path == `` {
+		err = ErrMissingScript
+		return
+	} else if c.Script != `` && c.Script_Path != `` {
+		err = ErrMultipleScripts
+		return
+	}
+	if c.Max_Steps == 0 {
+		c.Max_Steps = defaultScriptSteps
+	} else if c.Max_Steps < 0 {
+		err = ErrInvalidMaxSteps
+		return
+	}
+	if c.Script_Path != `` {
+		name = c.Script_Path
+		if src, err = os.ReadFile(c.Script_Path); err != nil {
+			err = fmt.Errorf("Failed to read Script-Path %q: %w", c.Script_Path, err)
+			return
+		}
+	} else {
+		name = scriptSourceDefault
+		src = []byte(c.Script)
+	}
+	var prog *starlark.Program
+	if _, prog, err = starlark.SourceProgramOptions(scriptFileOptions, name, src, scriptPredeclared.Has); err != nil {
+		err = fmt.Errorf("Invalid script: %w", err)
+	} else if prog.NumLoads() > 0 {
+		err = ErrScriptLoad
+	}
+	return
+}
+
+var (
+	scriptFileOptions = &syntax.FileOptions{
+		Set:             true,
+		While:           true,
+		TopLevelControl: true,
+	}
+	scriptPredeclared = starlark.StringDict{
+		`json`:          starjson.Module,
+		`math`:          starmath.Module,
+		`time`:          scriptTimeModule(),
+		scriptEntryType: starlark.NewBuiltin(scriptEntryType, scriptNewEntry),
+	}
+)
+
+// scriptTimeModule is the standard time module without access to the wall clock
+func scriptTimeModule() *starlarkstruct.Module {
+	mod := &starlarkstruct.Module{
+		Name:    startime.Module.Name,
+		Members: starlark.StringDict{},
+	}
+	for k, v := range startime.Module.Members {
+		if k != `now` {
+			mod.Members[k] = v
+		}
+	}
+	return mod
+}
+
+type Script struct {
+	nocloser
+	failureHook
+	ScriptConfig
+	tgr     Tagger
+	tags    map[string]entry.EntryTag
+	thread  *starlark.Thread
+	process starlark.Callable
+	args    starlark.Tuple
+}
+
+func NewScript(cfg ScriptConfig, tgr Tagger) (*Script, error) {
+	if tgr == nil {
+		return nil, errors.New("nil tagger")
+	}
+	name, src, err := cfg.validate()
+	if err != nil {
+		return nil, err
+	}
+	s := &Script{
+		ScriptConfig: cfg,
+		tgr:          tgr,
+		tags:         map[string]entry.EntryTag{},
+		args:         make(starlark.Tuple, 1),
+	}
+	s.thread = &starlark.Thread{
+		Name:  ScriptProcessor,
+		Print: s.print,
+	}
+	s.thread.SetLocal(scriptCurrentKey, s)
+	globals, err := starlark.ExecFileOptions(scriptFileOptions, s.thread, name, src, scriptPredeclared)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to load script: %w", err)
+	}
+	globals.Freeze()
+	if fn, ok := globals[scriptProcessFunc].(starlark.Callable); !ok {
+		return nil, ErrMissingProcess
+	} else {
+		s.process = fn
+	}
+	return s, nil
+}
+
+func (s *Script) Config(v interface{}, tgr Tagger) (err error) {
+	if v == nil {
+		err = ErrNilConfig
+	} else if cfg, ok := v.(ScriptConfig); ok {
+		var ns *Script
+		if ns, err = NewScript(cfg, tgr); err == nil {
+			ns.failFn = s.failFn
+			*s = *ns
+			s.thread.SetLocal(scriptCurrentKey, s)
+		}
+	} else {
+		err = fmt.Errorf("Invalid configuration, unknown type type %T", v)
+	}
+	return
+}
+
+func (s *Script) print(_ *starlark.Thread, msg string) {
+	if s.Debug {
+		fmt.Println(msg)
+	}
+}
+
+func (s *Script) Process(ents []*entry.Entry) (rset []*entry.Entry, err error) {
+	if len(ents) == 0 {
+		return
+	}
+	for _, ent := range ents {
+		if ent == nil {
+			continue
+		}
+		rset = s.processItem(ent, rset)
+	}
+	return
+}
+
+func (s *Script) processItem(ent *entry.Entry, rset []*entry.Entry) []*entry.Entry {
+	se := &scriptEntry{ent: ent, s: s}
+	s.args[0] = se
+	s.thread.Steps = 0
+	s.thread.SetMaxExecutionSteps(uint64(s.Max_Steps))
+	v, err := starlark.Call(s.thread, s.process, s.args, nil)
+	s.args[0] = nil
+	s.thread.Uncancel()
+	if err != nil {
+		if ent = s.miss(ent, err, false); ent != nil {
+			rset = append(rset, ent)
+		}
+		return rset
+	}
+	switch r := v.(type) {
+	case starlark.NoneType:
+		rset = append(rset, ent)
+	case starlark.Bool:
+		if r {
+			rset = append(rset, ent)
+		}
+	case *scriptEntry:
+		rset = append(rset, r.ent)
+	case *starlark.List:
+		rset = s.appendList(ent, r, rset)
+	case starlark.Tuple:
+		rset = s.appendList(ent, r, rset)
+	default:
+		if ent = s.miss(ent, fmt.Errorf("%s returned invalid type %s", scriptProcessFunc, v.Type()), false); ent != nil {
+			rset = append(rset, ent)
+		}
+	}
+	return rset
+}
+
+// appendList appends every entry in a list returned by the script, the original entry is
+// treated as a failure if the list contains anything other than entries
+func (s *Script) appendList(ent *entry.Entry, l starlark.Indexable, rset []*entry.Entry) []*entry.Entry {
+	for i := 0; i < l.Len(); i++ {
+		if _, ok := l.Index(i).(*scriptEntry); !ok {
+			if ent = s.miss(ent, fmt.Errorf("%s returned a list containing %s", scriptProcessFunc, l.Index(i).Type()), false); ent != nil {
+				rset = append(rset, ent)
+			}
+			return rset
+		}
+	}
+	for i := 0; i < l.Len(); i++ {
+		rset = append(rset, l.Index(i).(*scriptEntry).ent)
+	}
+	return rset
+}
+
+// getTag resolves a tag name, negotiating it with the tagger the first time it is seen
+func (s *Script) getTag(name string) (tg entry.EntryTag, err error) {
+	var ok bool
+	if tg, ok = s.tags[name]; !ok {
+		if tg, err = s.tgr.NegotiateTag(name); err == nil {
+			s.tags[name] = tg
+		}
+	}
+	return
+}
+
+// scriptNewEntry implements the entry builtin, unspecified fields are copied from the entry being processed
+func scriptNewEntry(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var data, tag, src, ts starlark.Value
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs, `data`, &data, `tag?`, &tag, `src?`, &src, `ts?`, &ts); err != nil {
+		return nil, err
+	}
+	s, ok := thread.Local(scriptCurrentKey).(*Script)
+	if !ok {
+		return nil, fmt.Errorf("%s: called outside of a script preprocessor", b.Name())
+	}
+	se := &scriptEntry{ent: &entry.Entry{}, s: s}
+	if cur, ok := s.args[0].(*scriptEntry); ok {
+		se.ent.Tag = cur.ent.Tag
+		se.ent.SRC = cur.ent.SRC
+		se.ent.TS = cur.ent.TS
+	} else {
+		se.ent.TS = entry.Now()
+	}
+	for _, f := range []struct {
+		name string
+		v    starlark.Value
+	}{{`data`, data}, {`tag`, tag}, {`src`, src}, {`ts`, ts}} {
+		if f.v == nil {
+			continue
+		} else if err := se.SetField(f.name, f.v); err != nil {
+			return nil, fmt.Errorf("%s: %w", b.Name(), err)
+		}
+	}
+	return se, nil
+}
+
+// scriptEntry exposes an entry to a script
+type scriptEntry struct {
+	ent    *entry.Entry
+	s      *Script
+	frozen bool
+}
+
+var scriptEntryMethods = map[string]*starlark.Builtin{
+	`get_ev`: starlark.NewBuiltin(`get_ev`, scriptGetEV),
+	`set_ev`: starlark.NewBuiltin(`set_ev`, scriptSetEV),
+	`del_ev`: starlark.NewBuiltin(`del_ev`, scriptDelEV),
+	`evs`:    starlark.NewBuiltin(`evs`, scriptEVs),
+	`copy`:   starlark.NewBuiltin(`copy`, scriptCopy),
+}
+
+var scriptEntryFields = []string{`data`, `src`, `tag`, `ts`}
+
+func (se *scriptEntry) String() string {
+	return fmt.Sprintf("entry(tag=%q, src=%q, ts=%s, data=%q)", se.tagName(), se.ent.SRC.String(), se.ent.TS, se.ent.Data)
+}
+
+func (se *scriptEntry) Type() string         { return scriptEntryType }
+func (se *scriptEntry) Freeze()              { se.frozen = true }
+func (se *scriptEntry) Truth() starlark.Bool { return starlark.True }
+func (se *scriptEntry) Hash() (uint32, error) {
+	return 0, fmt.Errorf("unhashable type: %s", scriptEntryType)
+}
+
+func (se *scriptEntry) AttrNames() (r []string) {
+	r = append(r, scriptEntryFields...)
+	for k := range scriptEntryMethods {
+		r = append(r, k)
+	}
+	sort.Strings(r)
+	return
+}
+
+func (se *scriptEntry) Attr(name string) (starlark.Value, error) {
+	switch name {
+	case `data`:
+		return starlark.String(se.ent.Data), nil
+	case `tag`:
+		return starlark.String(se.tagName()), nil
+	case `src`:
+		if se.ent.SRC == nil {
+			return starlark.None, nil
+		}
+		return starlark.String(se.ent.SRC.String()), nil
+	case `ts`:
+		return startime.Time(se.ent.TS.StandardTime()), nil
+	}
+	if m, ok := scriptEntryMethods[name]; ok {
+		return m.BindReceiver(se), nil
+	}
+	return nil, nil
+}
+
+func (se *scriptEntry) SetField(name string, v starlark.Value) error {
+	if se.frozen {
+		return fmt.Errorf("cannot set %s of frozen entry", name)
+	}
+	switch name {
+	case `data`:
+		switch x := v.(type) {
+		case starlark.Bytes:
+			se.ent.Data = []byte(x)
+		case starlark.String:
+			se.ent.Data = []byte(x)
+		default:
+			return fmt.Errorf("data must be bytes or string, got %s", v.Type())
+		}
+	case `tag`:
+		x, ok := starlark.AsString(v)
+		if !ok {
+			return fmt.Errorf("tag must be a string, got %s", v.Type())
+		}
+		tg, err := se.s.getTag(x)
+		if err != nil {
+			return fmt.Errorf("invalid tag %q: %v", x, err)
+		}
+		se.ent.Tag = tg
+	case `src`:
+		if v == starlark.None {
+			se.ent.SRC = nil
+			return nil
+		}
+		x, ok := starlark.AsString(v)
+		if !ok {
+			return fmt.Errorf("src must be a string, got %s", v.Type())
+		}
+		ip := net.ParseIP(x)
+		if ip == nil {
+			return fmt.Errorf("invalid src %q", x)
+		}
+		se.ent.SRC = ip
+	case `ts`:
+		x, ok := v.(startime.Time)
+		if !ok {
+			return fmt.Errorf("ts must be a time, got %s", v.Type())
+		}
+		se.ent.TS = entry.FromStandard(time.Time(x))
+	default:
+		return starlark.NoSuchAttrError(fmt.Sprintf("entry has no attribute %s", name))
+	}
+	return nil
+}
+
+func (se *scriptEntry) tagName() string {
+	if name, ok := se.s.tgr.LookupTag(se.ent.Tag); ok {
+		return name
+	}
+	return ``
+}
+
+func scriptGetEV(_ *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var name string
+	var def starlark.Value = starlark.None
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs, `name`, &name, `default?`, &def); err != nil {
+		return nil, err
+	}
+	se := b.Receiver().(*scriptEntry)
+	if ev, ok := se.ent.EVB.Get(name); ok {
+		return evToStarlark(ev.Value), nil
+	}
+	return def, nil
+}
+
+func scriptSetEV(_ *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var name string
+	var v starlark.Value
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs, `name`, &name, `value`, &v); err != nil {
+		return nil, err
+	}
+	se := b.Receiver().(*scriptEntry)
+	if se.frozen {
+		return nil, fmt.Errorf("%s: entry is frozen", b.Name())
+	}
+	nv, err := starlarkToNative(v)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", b.Name(), err)
+	}
+	if err = se.ent.AddEnumeratedValueEx(name, nv); err != nil {
+		return nil, fmt.Errorf("%s: %w", b.Name(), err)
+	}
+	return starlark.None, nil
+}
+
+func scriptDelEV(_ *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var name string
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs, `name`, &name); err != nil {
+		return nil, err
+	}
+	se := b.Receiver().(*scriptEntry)
+	if se.frozen {
+		return nil, fmt.Errorf("%s: entry is frozen", b.Name())
+	}
+	if _, ok := se.ent.EVB.Get(name); !ok {
+		return starlark.False, nil
+	}
+	evs := se.ent.EnumeratedValues()
+	se.ent.ClearEnumeratedValues()
+	for _, ev := range evs {
+		if ev.Name != name {
+			se.ent.AddEnumeratedValue(ev)
+		}
+	}
+	return starlark.True, nil
+}
+
+func scriptEVs(_ *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs); err != nil {
+		return nil, err
+	}
+	se := b.Receiver().(*scriptEntry)
+	evs := se.ent.EnumeratedValues()
+	d := starlark.NewDict(len(evs))
+	for _, ev := range evs {
+		d.SetKey(starlark.String(ev.Name), evToStarlark(ev.Value))
+	}
+	return d, nil
+}
+
+func scriptCopy(_ *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs); err != nil {
+		return nil, err
+	}
+	se := b.Receiver().(*scriptEntry)
+	c := se.ent.DeepCopy()
+	return &scriptEntry{ent: &c, s: se.s}, nil
+}
+
+// evToStarlark converts enumerated value data to the closest starlark type
+func evToStarlark(ed entry.EnumeratedData) starlark.Value {
+	switch v := ed.Interface().(type) {
+	case bool:
+		return starlark.Bool(v)
+	case uint8:
+		return starlark.MakeUint64(uint64(v))
+	case int8:
+		return starlark.MakeInt64(int64(v))
+	case int16:
+		return starlark.MakeInt64(int64(v))
+	case uint16:
+		return starlark.MakeUint64(uint64(v))
+	case int32:
+		return starlark.MakeInt64(int64(v))
+	case uint32:
+		return starlark.MakeUint64(uint64(v))
+	case int64:
+		return starlark.MakeInt64(v)
+	case uint64:
+		return starlark.MakeUint64(v)
+	case float32:
+		return starlark.Float(v)
+	case float64:
+		return starlark.Float(v)
+	case string:
+		return starlark.String(v)
+	case []byte:
+		return starlark.Bytes(v)
+	case entry.Timestamp:
+		return startime.Time(v.StandardTime())
+	case time.Duration:
+		return startime.Duration(v)
+	}
+	return starlark.String(ed.String())
+}
+
+// starlarkToNative converts a starlark value to a type accepted as enumerated value data
+func starlarkToNative(v starlark.Value) (interface{}, error) {
+	switch x := v.(type) {
+	case starlark.Bool:
+		return bool(x), nil
+	case starlark.Int:
+		if i, ok := x.Int64(); ok {
+			return i, nil
+		} else if u, ok := x.Uint64(); ok {
+			return u, nil
+		}
+		return nil, fmt.Errorf("integer %s out of range", x)
+	case starlark.Float:
+		return float64(x), nil
+	case starlark.String:
+		return string(x), nil
+	case starlark.Bytes:
+		return []byte(x), nil
+	case startime.Time:
+		return time.Time(x), nil
+	case startime.Duration:
+		return time.Duration(x), nil
+	}
+	return nil, fmt.Errorf("unsupported enumerated value type %s", v.Type())
+}
@@ -0,0 +1,237 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package processors
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gravwell/gravwell/v3/ingest/entry"
+)
+
+const testScript = `
+ROUTES = {"web": "webtag", "db": "dbtag"}
+
+def process(e):
+	app = e.get_ev("app", "")
+	if app == "noise":
+		return False
+	if app in ROUTES:
+		e.tag = ROUTES[app]
+	e.data = e.data.upper()
+	e.set_ev("len", len(e.data))
+	e.del_ev("app")
+	if app == "split":
+		return [entry(data=x) for x in e.data.split(" ")]
+	if app == "dup":
+		c = e.copy()
+		c.src = "10.0.0.1"
+		c.ts = e.ts + time.hour
+		return [e, c]
+`
+
+func TestScriptConfig(t *testing.T) {
+	b := `
+	[preprocessor "s"]
+		type = script
+		Script = "def process(e):\n\te.data = e.data.upper()\n"
+	`
+	p, err := testLoadPreprocessor(b, `s`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, ok := p.(*Script)
+	if !ok {
+		t.Fatalf("bad processor type %T", p)
+	} else if s.Max_Steps != defaultScriptSteps {
+		t.Fatalf("bad default max steps %d", s.Max_Steps)
+	}
+
+	pth := filepath.Join(t.TempDir(), `test.star`)
+	if err = os.WriteFile(pth, []byte(testScript), 0600); err != nil {
+		t.Fatal(err)
+	}
+	bad := []ScriptConfig{
+		ScriptConfig{},
+		ScriptConfig{Script: `x = 1`, Script_Path: pth},
+		ScriptConfig{Script_Path: pth, Max_Steps: -1},
+		ScriptConfig{Script_Path: filepath.Join(t.TempDir(), `missing.star`)},
+		ScriptConfig{Script: `def process(e)`},
+		ScriptConfig{Script: `load("foo.star", "bar")`},
+	}
+	for i, c := range bad {
+		if _, _, err := c.validate(); err == nil {
+			t.Fatalf("Failed to catch bad config %d (%+v)", i, c)
+		}
+	}
+	if _, err = NewScript(ScriptConfig{Script: `x = 1`}, &testTagger{}); err != ErrMissingProcess {
+		t.Fatalf("failed to catch missing process function: %v", err)
+	}
+	if _, err = NewScript(ScriptConfig{Script_Path: pth}, &testTagger{}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestScriptProcess(t *testing.T) {
+	tgr := &testTagger{}
+	s, err := NewScript(ScriptConfig{Script: testScript}, tgr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defTag, _ := tgr.NegotiateTag(`default`)
+	ts := entry.FromStandard(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+	mk := func(data, app string) *entry.Entry {
+		ent := &entry.Entry{TS: ts, Tag: defTag, SRC: net.ParseIP(`192.168.1.1`), Data: []byte(data)}
+		if app != `` {
+			ent.AddEnumeratedValueEx(`app`, app)
+		}
+		return ent
+	}
+	ents := []*entry.Entry{
+		mk(`plain`, ``),
+		mk(`web`, `web`),
+		mk(`noise`, `noise`),
+		mk(`a b c`, `split`),
+		mk(`dup`, `dup`),
+	}
+	rset, err := s.Process(ents)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := []struct {
+		data string
+		tag  string
+		src  string
+		ts   entry.Timestamp
+		evs  int
+	}{
+		{`PLAIN`, `default`, `192.168.1.1`, ts, 1},
+		{`WEB`, `webtag`, `192.168.1.1`, ts, 1},
+		{`A`, `default`, `192.168.1.1`, ts, 0},
+		{`B`, `default`, `192.168.1.1`, ts, 0},
+		{`C`, `default`, `192.168.1.1`, ts, 0},
+		{`DUP`, `default`, `192.168.1.1`, ts, 1},
+		{`DUP`, `default`, `10.0.0.1`, ts.Add(time.Hour), 1},
+	}
+	if len(rset) != len(exp) {
+		t.Fatalf("bad output count %d != %d", len(rset), len(exp))
+	}
+	for i, e := range exp {
+		ent := rset[i]
+		tag, _ := tgr.LookupTag(ent.Tag)
+		if string(ent.Data) != e.data || tag != e.tag || ent.SRC.String() != e.src || ent.TS != e.ts || ent.EVCount() != e.evs {
+			t.Fatalf("bad entry %d: %s %s %v %v %d", i, ent.Data, tag, ent.SRC, ent.TS, ent.EVCount())
+		}
+		if e.evs == 1 {
+			if v, ok := ent.GetEnumeratedValue(`len`); !ok || v != int64(len(e.data)) {
+				t.Fatalf("bad len EV on %d: %v", i, v)
+			}
+		}
+	}
+}
+
+func TestScriptEVTypes(t *testing.T) {
+	script := `
+def process(e):
+	for k, v in e.evs().items():
+		e.set_ev(k + "_type", type(v))
+	e.set_ev("now", e.ts)
+	e.set_ev("pi", 3.14)
+	e.set_ev("ok", True)
+	e.set_ev("raw", b"\x00\x01")
+	e.set_ev("big", 18446744073709551615)
+`
+	s, err := NewScript(ScriptConfig{Script: script}, &testTagger{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ent := &entry.Entry{TS: entry.Now(), Data: []byte(`foo`)}
+	ent.AddEnumeratedValueEx(`i`, int32(-5))
+	ent.AddEnumeratedValueEx(`u`, uint16(5))
+	ent.AddEnumeratedValueEx(`f`, float32(1.5))
+	ent.AddEnumeratedValueEx(`s`, `str`)
+	ent.AddEnumeratedValueEx(`ip`, net.ParseIP(`10.0.0.1`))
+	ent.AddEnumeratedValueEx(`d`, time.Second)
+	if _, err = s.Process([]*entry.Entry{ent}); err != nil {
+		t.Fatal(err)
+	}
+	checks := map[string]interface{}{
+		`i_type`:  `int`,
+		`u_type`:  `int`,
+		`f_type`:  `float`,
+		`s_type`:  `string`,
+		`ip_type`: `string`,
+		`d_type`:  `time.duration`,
+		`now`:     ent.TS,
+		`pi`:      3.14,
+		`ok`:      true,
+		`big`:     uint64(18446744073709551615),
+	}
+	for k, exp := range checks {
+		if v, ok := ent.GetEnumeratedValue(k); !ok || v != exp {
+			t.Fatalf("bad %s: %v (%T) != %v", k, v, v, exp)
+		}
+	}
+	if v, ok := ent.GetEnumeratedValue(`raw`); !ok || string(v.([]byte)) != "\x00\x01" {
+		t.Fatalf("bad raw: %v", v)
+	}
+}
+
+func TestScriptFailures(t *testing.T) {
+	script := `
+SEEN = []
+
+def process(e):
+	if e.data == "spin":
+		while True:
+			pass
+	elif e.data == "state":
+		SEEN.append(e.data)
+	elif e.data == "fail":
+		fail("bad entry")
+	elif e.data == "type":
+		return "type"
+	elif e.data == "list":
+		return [e, 5]
+	elif e.data == "now":
+		e.ts = time.now()
+`
+	s, err := NewScript(ScriptConfig{Script: script, Max_Steps: 1000}, &testTagger{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range []string{`spin`, `state`, `fail`, `type`, `list`, `now`} {
+		//failed entries pass through untouched
+		rset, err := s.Process([]*entry.Entry{&entry.Entry{Data: []byte(v)}, &entry.Entry{Data: []byte(`ok`)}})
+		if err != nil {
+			t.Fatal(err)
+		} else if len(rset) != 2 || string(rset[0].Data) != v || string(rset[1].Data) != `ok` {
+			t.Fatalf("bad output for %s: %d", v, len(rset))
+		}
+	}
+
+	//with a failure handler installed the failed entries are handed off
+	var failed []error
+	s.setFailureHandler(func(ent *entry.Entry, err error) {
+		failed = append(failed, err)
+	})
+	rset, err := s.Process([]*entry.Entry{&entry.Entry{Data: []byte(`spin`)}, &entry.Entry{Data: []byte(`ok`)}})
+	if err != nil {
+		t.Fatal(err)
+	} else if len(rset) != 1 || len(failed) != 1 {
+		t.Fatalf("bad failure handling: %d %d", len(rset), len(failed))
+	}
+	if !strings.Contains(failed[0].Error(), `too many steps`) {
+		t.Fatalf("bad failure %v", failed[0])
+	}
+}
@@ -39,6 +39,7 @@ type srcroute struct {
 
 type SrcRouter struct {
 	nocloser
+	failureHook
 	SrcRouteConfig
 	tree *nradix.Tree
 }
@@ -117,6 +118,8 @@ func (sr *SrcRouter) processItem(ent *entry.Entry) *entry.Entry {
 	} else if ok {
 		// We found a tag to send it to
 		ent.Tag = tag
+	} else {
+		return sr.miss(ent, ErrNoRoute, sr.Drop_Misses)
 	}
 	return ent
 }
@@ -128,12 +131,10 @@ func (sr *SrcRouter) handleExtract(v net.IP) (tag entry.EntryTag, drop, ok bool)
 		r, _ = sr.tree.FindCIDR(v.String())
 	}
 	if r == nil {
-		drop = sr.Drop_Misses //straight not found
-	} else if _, ok = r.(bool); ok {
-		drop = true
-	} else if tag, ok = r.(entry.EntryTag); !ok {
-		//found, but we can't convert it, this REALLY should never happen
-		drop = sr.Drop_Misses
+		return //straight not found
+	} else if _, drop = r.(bool); !drop {
+		//found, but we can't convert it, this REALLY should never happen so treat it as a miss
+		tag, ok = r.(entry.EntryTag)
 	}
 	return
 }
@@ -25,6 +25,10 @@ const (
 	SyslogRouterProcessor = `syslogrouter`
 )
 
+var (
+	ErrNotSyslog = errors.New("Entry is not RFC3164 or RFC5424 syslog")
+)
+
 type SyslogRouterConfig struct {
 	Drop_Misses bool
 	Template    string
@@ -60,6 +64,7 @@ func SyslogRouterLoadConfig(vc *config.VariableConfig) (c SyslogRouterConfig, er
 
 type SyslogRouter struct {
 	nocloser
+	failureHook
 	SyslogRouterConfig
 	tagger Tagger
 	routes map[string]entry.EntryTag
@@ -105,22 +110,21 @@ func (sr *SyslogRouter) Process(ents []*entry.Entry) (rset []*entry.Entry, err e
 	}
 	rset = ents[:0]
 	for _, ent := range ents {
-		parts := crackData(ent.Data)
-		if parts == nil {
-			if !sr.Drop_Misses {
-				rset = append(rset, ent)
-			}
+		if ent == nil {
 			continue
 		}
-		//got a good crack, process it
-		if tag, err := sr.processEntry(ent, parts); err != nil {
-			if sr.Drop_Misses {
-				continue
-			}
+		parts := crackData(ent.Data)
+		if parts == nil {
+			ent = sr.miss(ent, ErrNotSyslog, sr.Drop_Misses)
+		} else if tag, err := sr.processEntry(ent, parts); err != nil {
+			//got a good crack but could not build a tag
+			ent = sr.miss(ent, err, sr.Drop_Misses)
 		} else {
 			ent.Tag = tag
 		}
-		rset = append(rset, ent)
+		if ent != nil {
+			rset = append(rset, ent)
+		}
 	}
 	return
 }
@@ -0,0 +1,106 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package processors
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/gravwell/gravwell/v3/ingest/entry"
+	"github.com/gravwell/jsonparser"
+)
+
+var (
+	ErrMissingExtraction   = errors.New("No extraction method specified (JSON-Field, Regex, Enumerated-Value, or Use-SRC)")
+	ErrMultipleExtractions = errors.New("Only one extraction method may be specified")
+)
+
+// valueExtractor pulls a single value out of an entry using exactly one of
+// a JSON field, a regular expression capture, an existing enumerated value,
+// or the entry SRC.
+type valueExtractor struct {
+	keys   []string
+	rx     *regexp.Regexp
+	rxIdx  int
+	evName string
+	useSrc bool
+}
+
+// newValueExtractor builds an extractor; if regexName is empty the first capture group
+// of the regular expression is used, and if there are no capture groups the entire match is used.
+func newValueExtractor(jsonField, regex, regexName, evName string, useSrc bool) (ve valueExtractor, err error) {
+	var cnt int
+	if jsonField != `` {
+		cnt++
+		if ve.keys = unquoteFields(splitRespectQuotes(jsonField, dotSplitter)); len(ve.keys) == 0 {
+			err = fmt.Errorf("Invalid JSON field %q", jsonField)
+			return
+		}
+	}
+	if regex != `` {
+		cnt++
+		if ve.rx, err = regexp.Compile(regex); err != nil {
+			return
+		}
+		if regexName != `` {
+			if ve.rxIdx = ve.rx.SubexpIndex(regexName); ve.rxIdx == -1 {
+				err = fmt.Errorf("%s is not extracted in the regular expression", regexName)
+				return
+			}
+		} else if ve.rx.NumSubexp() > 0 {
+			ve.rxIdx = 1
+		}
+	} else if regexName != `` {
+		err = errors.New("Regex-Name requires Regex")
+		return
+	}
+	if evName != `` {
+		cnt++
+		ve.evName = evName
+	}
+	if useSrc {
+		cnt++
+		ve.useSrc = true
+	}
+	if cnt == 0 {
+		err = ErrMissingExtraction
+	} else if cnt > 1 {
+		err = ErrMultipleExtractions
+	}
+	return
+}
+
+// extract returns the extracted value, ok is false if the value could not be found
+func (ve *valueExtractor) extract(ent *entry.Entry) (v []byte, ok bool) {
+	if ent == nil {
+		return
+	}
+	switch {
+	case ve.keys != nil:
+		var err error
+		if v, _, _, err = jsonparser.Get(ent.Data, ve.keys...); err == nil {
+			ok = len(v) > 0
+		}
+	case ve.rx != nil:
+		if mtchs := ve.rx.FindSubmatch(ent.Data); ve.rxIdx < len(mtchs) && mtchs[ve.rxIdx] != nil {
+			v, ok = mtchs[ve.rxIdx], true
+		}
+	case ve.evName != ``:
+		var ev entry.EnumeratedValue
+		if ev, ok = ent.EVB.Get(ve.evName); ok {
+			v = []byte(ev.Value.String())
+		}
+	case ve.useSrc:
+		if ent.SRC != nil {
+			v, ok = []byte(ent.SRC.String()), true
+		}
+	}
+	return
+}
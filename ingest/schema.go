@@ -0,0 +1,328 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package ingest
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync/atomic"
+
+	"github.com/gravwell/gravwell/v3/ingest/config"
+	"github.com/gravwell/gravwell/v3/ingest/entry"
+)
+
+const (
+	// SchemaViolationEV is attached to entries routed to a dead-letter tag, it names the offending enumerated value
+	SchemaViolationEV = `schema_violation`
+
+	schemaModeCount      = `count`
+	schemaModeCoerce     = `coerce`
+	schemaModeDrop       = `drop`
+	schemaModeDeadLetter = `dead-letter`
+)
+
+var (
+	ErrInvalidSchemaMode  = errors.New("invalid schema mode")
+	ErrMissingSchemaTag   = errors.New("schema does not declare any tags")
+	ErrInvalidSchemaField = errors.New("invalid schema field, expected \"<name> <type>\"")
+	ErrDuplicateSchemaTag = errors.New("tag is declared in multiple schemas")
+)
+
+// SchemaMode dictates what happens to an entry carrying an enumerated value that violates its tag's schema
+type SchemaMode int
+
+const (
+	SchemaCount      SchemaMode = iota // count the violation and send the entry untouched
+	SchemaCoerce                       // convert the value to the declared type, dropping it if that fails
+	SchemaDrop                         // drop the offending enumerated value
+	SchemaDeadLetter                   // re-tag the entry to the dead-letter tag
+)
+
+// SchemaConfig is the config file representation of a set of schemas, for example:
+//
+//	[Schema "netflow"]
+//		Tag=netflow
+//		Tag=ipfix
+//		Mode=coerce
+//		Field="src_ip ip"
+//		Field="dst_port uint16"
+//
+//	[Schema "firewall"]
+//		Tag=fw
+//		Mode=dead-letter
+//		Dead-Letter-Tag=schema-errors
+//		Field="action string"
+type SchemaConfig struct {
+	Schema map[string]*TagSchemaConfig
+}
+
+type TagSchemaConfig struct {
+	Tag             []string
+	Mode            string // count, coerce, drop, or dead-letter, default is count
+	Dead_Letter_Tag string
+	Field           []string // "<enumerated value name> <type name>"
+}
+
+// SchemaStats holds the validation counters for a single tag
+type SchemaStats struct {
+	Tag          string
+	Checked      uint64 // entries checked against the schema
+	Violations   uint64 // enumerated values that did not match the declared type
+	Coerced      uint64 // enumerated values successfully converted to the declared type
+	Dropped      uint64 // enumerated values removed from entries
+	DeadLettered uint64 // entries routed to the dead-letter tag
+}
+
+// SchemaRegistry holds the declared enumerated value schemas for a set of tags.
+// A registry is bound to the tag IDs of a muxer when the muxer is created and is then read only,
+// only the counters are updated so it is safe to validate concurrently.
+type SchemaRegistry struct {
+	schemas map[string]*tagSchema
+	bound   map[entry.EntryTag]*tagSchema
+}
+
+type tagSchema struct {
+	//counters are updated atomically and must stay 8 byte aligned for 32bit architectures
+	checked  uint64
+	violated uint64
+	coerced  uint64
+	dropped  uint64
+	dlqd     uint64
+	tag      string
+	mode     SchemaMode
+	dlqName  string
+	dlq      entry.EntryTag
+	fields   map[string]uint8
+}
+
+// LoadSchemaFiles reads a set of schema config files into a single registry.
+// A nil registry is returned if no paths are provided.
+func LoadSchemaFiles(paths ...string) (*SchemaRegistry, error) {
+	if len(paths) == 0 {
+		return nil, nil
+	}
+	var sc SchemaConfig
+	for _, p := range paths {
+		var c SchemaConfig
+		if err := config.LoadConfigFile(&c, p); err != nil {
+			return nil, fmt.Errorf("failed to load schema file %q %w", p, err)
+		}
+		for k, v := range c.Schema {
+			if sc.Schema == nil {
+				sc.Schema = map[string]*TagSchemaConfig{}
+			} else if _, ok := sc.Schema[k]; ok {
+				return nil, fmt.Errorf("schema %q in %q is declared in multiple files", k, p)
+			}
+			sc.Schema[k] = v
+		}
+	}
+	return NewSchemaRegistry(sc)
+}
+
+// NewSchemaRegistry validates a schema configuration and builds a registry
+func NewSchemaRegistry(sc SchemaConfig) (*SchemaRegistry, error) {
+	sr := &SchemaRegistry{
+		schemas: map[string]*tagSchema{},
+	}
+	for name, v := range sc.Schema {
+		if v == nil {
+			continue
+		}
+		mode, err := parseSchemaMode(v.Mode)
+		if err != nil {
+			return nil, fmt.Errorf("schema %q %w", name, err)
+		}
+		if mode == SchemaDeadLetter {
+			if err = CheckTag(v.Dead_Letter_Tag); err != nil {
+				return nil, fmt.Errorf("schema %q invalid Dead-Letter-Tag %w", name, err)
+			}
+		} else if v.Dead_Letter_Tag != `` {
+			return nil, fmt.Errorf("schema %q Dead-Letter-Tag requires the dead-letter mode", name)
+		}
+		fields := make(map[string]uint8, len(v.Field))
+		for _, f := range v.Field {
+			flds := strings.Fields(f)
+			if len(flds) != 2 {
+				return nil, fmt.Errorf("schema %q %q %w", name, f, ErrInvalidSchemaField)
+			}
+			id, err := entry.ParseEVType(flds[1])
+			if err != nil {
+				return nil, fmt.Errorf("schema %q field %q type %q %w", name, flds[0], flds[1], err)
+			}
+			fields[flds[0]] = id
+		}
+		if len(v.Tag) == 0 {
+			return nil, fmt.Errorf("schema %q %w", name, ErrMissingSchemaTag)
+		}
+		for _, tag := range v.Tag {
+			if err = CheckTag(tag); err != nil {
+				return nil, fmt.Errorf("schema %q invalid tag %q %w", name, tag, err)
+			} else if _, ok := sr.schemas[tag]; ok {
+				return nil, fmt.Errorf("%q %w", tag, ErrDuplicateSchemaTag)
+			}
+			sr.schemas[tag] = &tagSchema{
+				tag:     tag,
+				mode:    mode,
+				dlqName: v.Dead_Letter_Tag,
+				fields:  fields,
+			}
+		}
+	}
+	return sr, nil
+}
+
+// Tags returns every tag the registry references, including dead-letter tags, so they can be negotiated up front
+func (sr *SchemaRegistry) Tags() (r []string) {
+	if sr == nil {
+		return
+	}
+	set := map[string]bool{}
+	for k, v := range sr.schemas {
+		set[k] = true
+		if v.dlqName != `` {
+			set[v.dlqName] = true
+		}
+	}
+	for k := range set {
+		r = append(r, k)
+	}
+	sort.Strings(r)
+	return
+}
+
+// bind resolves the tag names in the registry against a muxer tag map, all tags returned by Tags must be present
+func (sr *SchemaRegistry) bind(tagMap map[string]entry.EntryTag) error {
+	bound := make(map[entry.EntryTag]*tagSchema, len(sr.schemas))
+	for k, v := range sr.schemas {
+		tg, ok := tagMap[k]
+		if !ok {
+			return fmt.Errorf("schema tag %q %w", k, ErrTagNotFound)
+		}
+		if v.mode == SchemaDeadLetter {
+			if v.dlq, ok = tagMap[v.dlqName]; !ok {
+				return fmt.Errorf("schema dead-letter tag %q %w", v.dlqName, ErrTagNotFound)
+			}
+		}
+		bound[tg] = v
+	}
+	sr.bound = bound
+	return nil
+}
+
+// Validate checks the enumerated values on an entry against the schema for its tag, applying the schema mode
+// to any violations.  The number of violations is returned, entries with tags that have no schema are ignored.
+func (sr *SchemaRegistry) Validate(e *entry.Entry) (violations int) {
+	if sr == nil || e == nil {
+		return
+	}
+	ts, ok := sr.bound[e.Tag]
+	if !ok {
+		return
+	}
+	atomic.AddUint64(&ts.checked, 1)
+	if !e.EVB.Populated() {
+		return
+	}
+	for _, ev := range e.EVB.Values() {
+		if id, ok := ts.fields[ev.Name]; ok && ev.TypeID() != id {
+			violations++
+		}
+	}
+	if violations == 0 {
+		return
+	}
+	atomic.AddUint64(&ts.violated, uint64(violations))
+	if ts.mode == SchemaCount {
+		return
+	}
+
+	// fixing values up modifies the block, so work from a copy
+	var dlqName string
+	for _, ev := range append([]entry.EnumeratedValue(nil), e.EVB.Values()...) {
+		id, ok := ts.fields[ev.Name]
+		if !ok || ev.TypeID() == id {
+			continue
+		}
+		switch ts.mode {
+		case SchemaCoerce:
+			if v, err := ev.Value.Coerce(id); err == nil {
+				e.EVB.Add(entry.EnumeratedValue{Name: ev.Name, Value: v})
+				atomic.AddUint64(&ts.coerced, 1)
+			} else {
+				e.EVB.Delete(ev.Name)
+				atomic.AddUint64(&ts.dropped, 1)
+			}
+		case SchemaDrop:
+			e.EVB.Delete(ev.Name)
+			atomic.AddUint64(&ts.dropped, 1)
+		case SchemaDeadLetter:
+			if dlqName == `` {
+				dlqName = ev.Name
+			}
+		}
+	}
+	if dlqName != `` {
+		e.Tag = ts.dlq
+		e.AddEnumeratedValue(entry.EnumeratedValue{
+			Name:  SchemaViolationEV,
+			Value: entry.StringEnumData(dlqName),
+		})
+		atomic.AddUint64(&ts.dlqd, 1)
+	}
+	return
+}
+
+// Stats returns a snapshot of the per tag validation counters, sorted by tag
+func (sr *SchemaRegistry) Stats() (r []SchemaStats) {
+	if sr == nil {
+		return
+	}
+	for k, v := range sr.schemas {
+		r = append(r, SchemaStats{
+			Tag:          k,
+			Checked:      atomic.LoadUint64(&v.checked),
+			Violations:   atomic.LoadUint64(&v.violated),
+			Coerced:      atomic.LoadUint64(&v.coerced),
+			Dropped:      atomic.LoadUint64(&v.dropped),
+			DeadLettered: atomic.LoadUint64(&v.dlqd),
+		})
+	}
+	sort.Slice(r, func(i, j int) bool { return r[i].Tag < r[j].Tag })
+	return
+}
+
+func parseSchemaMode(v string) (SchemaMode, error) {
+	switch strings.ToLower(strings.TrimSpace(v)) {
+	case ``, schemaModeCount:
+		return SchemaCount, nil
+	case schemaModeCoerce:
+		return SchemaCoerce, nil
+	case schemaModeDrop:
+		return SchemaDrop, nil
+	case schemaModeDeadLetter:
+		return SchemaDeadLetter, nil
+	}
+	return SchemaCount, fmt.Errorf("%q %w", v, ErrInvalidSchemaMode)
+}
+
+func (m SchemaMode) String() string {
+	switch m {
+	case SchemaCount:
+		return schemaModeCount
+	case SchemaCoerce:
+		return schemaModeCoerce
+	case SchemaDrop:
+		return schemaModeDrop
+	case SchemaDeadLetter:
+		return schemaModeDeadLetter
+	}
+	return `unknown`
+}
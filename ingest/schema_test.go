@@ -0,0 +1,191 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package ingest
+
+import (
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/gravwell/gravwell/v3/ingest/entry"
+)
+
+const testSchemaConfig = `
+[Schema "netflow"]
+	Tag=netflow
+	Tag=ipfix
+	Mode=coerce
+	Field="src_ip ip"
+	Field="dst_port uint16"
+
+[Schema "firewall"]
+	Tag=fw
+	Mode=dead-letter
+	Dead-Letter-Tag=schema-errors
+	Field="action string"
+	Field="src_ip ip"
+
+[Schema "dns"]
+	Tag=dns
+	Mode=drop
+	Field="query string"
+
+[Schema "syslog"]
+	Tag=syslog
+	Field="host string"
+`
+
+func loadTestSchemas(t *testing.T) (*SchemaRegistry, map[string]entry.EntryTag) {
+	p := filepath.Join(t.TempDir(), `schemas.conf`)
+	if err := os.WriteFile(p, []byte(testSchemaConfig), 0640); err != nil {
+		t.Fatal(err)
+	}
+	sr, err := LoadSchemaFiles(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tagMap := map[string]entry.EntryTag{}
+	for i, v := range sr.Tags() {
+		tagMap[v] = entry.EntryTag(i + 1)
+	}
+	if err = sr.bind(tagMap); err != nil {
+		t.Fatal(err)
+	}
+	return sr, tagMap
+}
+
+func TestSchemaLoad(t *testing.T) {
+	sr, _ := loadTestSchemas(t)
+	exp := []string{`dns`, `fw`, `ipfix`, `netflow`, `schema-errors`, `syslog`}
+	if tags := sr.Tags(); !reflect.DeepEqual(tags, exp) {
+		t.Fatalf("bad tags: %v != %v", tags, exp)
+	}
+	if sr, err := LoadSchemaFiles(); err != nil || sr != nil {
+		t.Fatalf("empty load returned %v %v", sr, err)
+	}
+
+	bad := []struct {
+		cfg TagSchemaConfig
+		err error
+	}{
+		{TagSchemaConfig{Field: []string{`a string`}}, ErrMissingSchemaTag},
+		{TagSchemaConfig{Tag: []string{`a`}, Mode: `bogus`}, ErrInvalidSchemaMode},
+		{TagSchemaConfig{Tag: []string{`a`}, Field: []string{`a`}}, ErrInvalidSchemaField},
+		{TagSchemaConfig{Tag: []string{`a`}, Field: []string{`a bogus`}}, entry.ErrUnknownType},
+		{TagSchemaConfig{Tag: []string{`a`}, Mode: `dead-letter`}, ErrEmptyTag},
+	}
+	for _, v := range bad {
+		cfg := v.cfg
+		if _, err := NewSchemaRegistry(SchemaConfig{Schema: map[string]*TagSchemaConfig{`test`: &cfg}}); !errors.Is(err, v.err) {
+			t.Fatalf("%+v: bad error %v != %v", v.cfg, err, v.err)
+		}
+	}
+	dup := SchemaConfig{Schema: map[string]*TagSchemaConfig{
+		`a`: {Tag: []string{`x`}},
+		`b`: {Tag: []string{`x`}},
+	}}
+	if _, err := NewSchemaRegistry(dup); !errors.Is(err, ErrDuplicateSchemaTag) {
+		t.Fatalf("failed to catch duplicate tag: %v", err)
+	}
+
+	//binding must fail if the muxer does not know about a tag
+	if err := sr.bind(map[string]entry.EntryTag{`netflow`: 1}); !errors.Is(err, ErrTagNotFound) {
+		t.Fatalf("bad bind error: %v", err)
+	}
+}
+
+func TestSchemaValidate(t *testing.T) {
+	sr, tags := loadTestSchemas(t)
+	newEnt := func(tag string, evs map[string]interface{}) *entry.Entry {
+		e := &entry.Entry{Tag: tags[tag], Data: []byte(`test`)}
+		for k, v := range evs {
+			if err := e.AddEnumeratedValueEx(k, v); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return e
+	}
+
+	//coerce fixes what it can and drops the rest
+	e := newEnt(`netflow`, map[string]interface{}{`src_ip`: `10.0.0.1`, `dst_port`: `not a port`, `other`: 5})
+	if n := sr.Validate(e); n != 2 {
+		t.Fatalf("bad violation count %d", n)
+	} else if ev, ok := e.EVB.Get(`src_ip`); !ok || ev.TypeID() != mustEVType(t, `ip`) {
+		t.Fatalf("src_ip not coerced: %v", ev)
+	} else if _, ok = e.EVB.Get(`dst_port`); ok {
+		t.Fatal("invalid dst_port not dropped")
+	} else if _, ok = e.EVB.Get(`other`); !ok {
+		t.Fatal("undeclared value was removed")
+	}
+
+	//conforming entries are untouched
+	e = newEnt(`ipfix`, map[string]interface{}{`src_ip`: net.ParseIP(`10.0.0.1`).To4(), `dst_port`: uint16(53)})
+	if n := sr.Validate(e); n != 0 {
+		t.Fatalf("bad violation count %d", n)
+	}
+
+	//dead-letter re-tags the entry and names the bad value
+	e = newEnt(`fw`, map[string]interface{}{`action`: `allow`, `src_ip`: `10.0.0.1`})
+	if n := sr.Validate(e); n != 1 {
+		t.Fatalf("bad violation count %d", n)
+	} else if e.Tag != tags[`schema-errors`] {
+		t.Fatalf("entry not dead-lettered: %v", e.Tag)
+	} else if ev, ok := e.EVB.Get(SchemaViolationEV); !ok || ev.Value.String() != `src_ip` {
+		t.Fatalf("bad violation EV: %v", ev)
+	}
+
+	//drop removes the value
+	e = newEnt(`dns`, map[string]interface{}{`query`: 1234})
+	if n := sr.Validate(e); n != 1 {
+		t.Fatalf("bad violation count %d", n)
+	} else if e.EVB.Populated() {
+		t.Fatal("value not dropped")
+	}
+
+	//count leaves everything alone
+	e = newEnt(`syslog`, map[string]interface{}{`host`: 1234})
+	if n := sr.Validate(e); n != 1 {
+		t.Fatalf("bad violation count %d", n)
+	} else if ev, ok := e.EVB.Get(`host`); !ok || ev.Value.String() != `1234` {
+		t.Fatal("count mode modified the entry")
+	}
+
+	//tags without a schema are ignored
+	if n := sr.Validate(&entry.Entry{Tag: 100}); n != 0 {
+		t.Fatalf("bad violation count %d", n)
+	}
+
+	exp := map[string]SchemaStats{
+		`dns`:     {Tag: `dns`, Checked: 1, Violations: 1, Dropped: 1},
+		`fw`:      {Tag: `fw`, Checked: 1, Violations: 1, DeadLettered: 1},
+		`ipfix`:   {Tag: `ipfix`, Checked: 1},
+		`netflow`: {Tag: `netflow`, Checked: 1, Violations: 2, Coerced: 1, Dropped: 1},
+		`syslog`:  {Tag: `syslog`, Checked: 1, Violations: 1},
+	}
+	stats := sr.Stats()
+	if len(stats) != len(exp) {
+		t.Fatalf("bad stats count %d != %d", len(stats), len(exp))
+	}
+	for _, v := range stats {
+		if v != exp[v.Tag] {
+			t.Fatalf("bad stats %+v != %+v", v, exp[v.Tag])
+		}
+	}
+}
+
+func mustEVType(t *testing.T, name string) uint8 {
+	id, err := entry.ParseEVType(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return id
+}
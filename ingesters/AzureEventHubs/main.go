@@ -159,6 +159,9 @@ func main() {
 			if err != nil {
 				lg.Fatal("preprocessor construction failed", log.KVErr(err))
 			}
+			if err = ib.RegisterPreprocessorStats(hubname, procset); err != nil {
+				lg.Warn("failed to register preprocessor stats", log.KV("hub", hubname), log.KVErr(err))
+			}
 			defer procset.Close()
 
 			// Set up authentication
@@ -208,6 +211,7 @@ func main() {
 			tcfg := timegrinder.Config{
 				TSWindow:           window,
 				EnableLeftMostSeed: true,
+				Locale:             cfg.Global.Timestamp_Locale,
 			}
 			tg, err := timegrinder.NewTimeGrinder(tcfg)
 			if err != nil {
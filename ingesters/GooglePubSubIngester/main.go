@@ -98,6 +98,9 @@ func main() {
 		if err != nil {
 			lg.Fatal("preprocessor construction failed", log.KVErr(err))
 		}
+		if err = ib.RegisterPreprocessorStats(psv.Topic_Name, procset); err != nil {
+			lg.Warn("failed to register preprocessor stats", log.KV("topic", psv.Topic_Name), log.KVErr(err))
+		}
 
 		// Get the subscription, creating if needed
 		subname := psv.Subscription_Name
@@ -171,6 +174,7 @@ func main() {
 			tcfg := timegrinder.Config{
 				TSWindow:           window,
 				EnableLeftMostSeed: true,
+				Locale:             cfg.Global.Timestamp_Locale,
 			}
 			tg, err := timegrinder.NewTimeGrinder(tcfg)
 			if err != nil {
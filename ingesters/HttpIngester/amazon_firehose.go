@@ -161,7 +161,7 @@ func includeAFHListeners(hnd *handler, igst *ingest.IngestMuxer, cfg *cfgType, l
 		if v.Ignore_Timestamps {
 			hcfg.ignoreTs = true
 		} else {
-			if hcfg.tg, err = timegrinder.New(timegrinder.Config{}); err != nil {
+			if hcfg.tg, err = timegrinder.New(timegrinder.Config{Locale: cfg.Timestamp_Locale}); err != nil {
 				return fmt.Errorf("Failed to create timegrinder %w", err)
 			}
 		}
@@ -169,6 +169,7 @@ func includeAFHListeners(hnd *handler, igst *ingest.IngestMuxer, cfg *cfgType, l
 		if hcfg.pproc, err = cfg.Preprocessor.ProcessorSet(igst, v.Preprocessor); err != nil {
 			return fmt.Errorf("preprocessor construction error %w", err)
 		}
+		hnd.registerPreprocessorStats(http.MethodPost+` `+v.URL, hcfg.pproc)
 		if hcfg.auth, err = newPresharedHeaderTokenHandler(afhAuthTokenHeader, v.TokenValue, lgr); err != nil {
 			return fmt.Errorf("failed to generate Amazon Firehose auth %w", err)
 		}
@@ -26,6 +26,7 @@ import (
 	"github.com/gravwell/gravwell/v3/ingest/entry"
 	"github.com/gravwell/gravwell/v3/ingest/log"
 	"github.com/gravwell/gravwell/v3/ingest/processors"
+	"github.com/gravwell/gravwell/v3/ingesters/base"
 	"github.com/gravwell/gravwell/v3/ingesters/utils"
 	"github.com/gravwell/gravwell/v3/timegrinder"
 )
@@ -53,6 +54,7 @@ type routeHandler struct {
 type handler struct {
 	sync.RWMutex
 	igst                  *ingest.IngestMuxer
+	ib                    *base.IngesterBase // nil on the temporary handlers built during a hot reload
 	lgr                   *log.Logger
 	reqSI                 *utils.StatsItem // per request SI
 	entSI                 *utils.StatsItem // per entry SI
@@ -65,6 +67,18 @@ type handler struct {
 	activeRequests        int64
 }
 
+// registerPreprocessorStats publishes the stats of a listener's preprocessor set.  Hot reloads only
+// update the muxer, the stats log counters were registered when the ingester started.
+func (h *handler) registerPreprocessorStats(name string, pproc *processors.ProcessorSet) {
+	if pproc == nil || pproc.Count() == 0 {
+		return
+	} else if h.ib == nil {
+		h.igst.RegisterPreprocessorStats(name, pproc)
+	} else if err := h.ib.RegisterPreprocessorStats(name, pproc); err != nil {
+		h.lgr.Warn("failed to register preprocessor stats", log.KV("listener", name), log.KVErr(err))
+	}
+}
+
 func (rh routeHandler) handle(h *handler, w http.ResponseWriter, req *http.Request, rdr io.Reader, ip net.IP) {
 	if w == nil {
 		return
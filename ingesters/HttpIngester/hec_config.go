@@ -256,7 +256,7 @@ func includeHecListeners(hnd *handler, igst *ingest.IngestMuxer, cfg *cfgType) (
 			if err != nil {
 				return fmt.Errorf("Failed to get global timestamp window %w", err)
 			}
-			if hcfg.tg, err = timegrinder.New(timegrinder.Config{TSWindow: window}); err != nil {
+			if hcfg.tg, err = timegrinder.New(timegrinder.Config{TSWindow: window, Locale: cfg.Timestamp_Locale}); err != nil {
 				return fmt.Errorf("Failed to create timegrinder %w", err)
 			} else if err = cfg.TimeFormat.LoadFormats(hcfg.tg); err != nil {
 				return fmt.Errorf("failed to load custom time formats %w", err)
@@ -271,6 +271,7 @@ func includeHecListeners(hnd *handler, igst *ingest.IngestMuxer, cfg *cfgType) (
 		if hcfg.pproc, err = cfg.Preprocessor.ProcessorSet(igst, v.Preprocessor); err != nil {
 			return fmt.Errorf("preprocessor construction error %w", err)
 		}
+		hnd.registerPreprocessorStats(http.MethodPost+` `+v.URL, hcfg.pproc)
 		bp := v.URL
 		// detect if you're specifying `URL=/services/collector/event` in the old way and handle it sneakily
 		if path.Base(bp) == "event" {
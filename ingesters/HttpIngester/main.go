@@ -106,6 +106,7 @@ func main() {
 	if err != nil {
 		lg.FatalCode(0, "Failed to create new handler")
 	}
+	hnd.ib = &ib
 
 	if err = hnd.loadConfig(cfg); err != nil {
 		lg.Fatal("failed to load configuration", log.KVErr(err))
@@ -318,6 +319,7 @@ func includeStdListeners(hnd *handler, igst *ingest.IngestMuxer, cfg *cfgType) (
 			tcfg := timegrinder.Config{
 				EnableLeftMostSeed: true,
 				TSWindow:           window,
+				Locale:             cfg.Timestamp_Locale,
 			}
 			if hcfg.tg, err = timegrinder.NewTimeGrinder(tcfg); err != nil {
 				return fmt.Errorf("failed to generate new timegrinder %w", err)
@@ -346,6 +348,7 @@ func includeStdListeners(hnd *handler, igst *ingest.IngestMuxer, cfg *cfgType) (
 		if err != nil {
 			return fmt.Errorf("preprocessor construction error %w", err)
 		}
+		hnd.registerPreprocessorStats(v.Method+` `+v.URL, hcfg.pproc)
 		//check if authentication is enabled for this URL
 		if pth, ah, err := v.NewAuthHandler(lg); err != nil {
 			return fmt.Errorf("failed to get a new authentication handler %w", err)
@@ -138,6 +138,9 @@ func main() {
 			if hcfg.proc, err = cfg.Preprocessor.ProcessorSet(igst, v.Preprocessor); err != nil {
 				lg.FatalCode(0, "preprocessor construction error", log.KVErr(err))
 			}
+			if err = ib.RegisterPreprocessorStats(k+x, hcfg.proc); err != nil {
+				lg.Warn("failed to register preprocessor stats", log.KV("target", k+x), log.KVErr(err))
+			}
 
 			ipmiConns[k+x] = hcfg
 		}
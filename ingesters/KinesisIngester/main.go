@@ -196,6 +196,7 @@ func main() {
 			tcfg := timegrinder.Config{
 				TSWindow:           window,
 				EnableLeftMostSeed: true,
+				Locale:             cfg.Global.Timestamp_Locale,
 			}
 			tgr, err := timegrinder.NewTimeGrinder(tcfg)
 			if err != nil {
@@ -233,6 +234,9 @@ func main() {
 				if err != nil {
 					lg.Fatal("preprocessor construction error", log.KVErr(err))
 				}
+				if err = ib.RegisterPreprocessorStats(stream.Stream_Name+`.`+*shard.ShardId, procset); err != nil {
+					lg.Warn("failed to register preprocessor stats", log.KV("stream", stream.Stream_Name), log.KV("shard", *shard.ShardId), log.KVErr(err))
+				}
 
 				// make the shardMetrics and add it to the array
 				tracker := shardMetrics{}
@@ -124,6 +124,9 @@ func main() {
 		if err != nil {
 			lg.Fatal("preprocessor failure", log.KVErr(err))
 		}
+		if err = ib.RegisterPreprocessorStats(k, procset); err != nil {
+			lg.Warn("failed to register preprocessor stats", log.KV("contenttype", k), log.KVErr(err))
+		}
 
 		// set up time extraction rules
 		var window timegrinder.TimestampWindow
@@ -134,6 +137,7 @@ func main() {
 		tcfg := timegrinder.Config{
 			TSWindow:           window,
 			EnableLeftMostSeed: true,
+			Locale:             cfg.Global.Timestamp_Locale,
 		}
 		tg, err := timegrinder.NewTimeGrinder(tcfg)
 		if err != nil {
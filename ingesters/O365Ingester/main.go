@@ -134,6 +134,7 @@ func main() {
 		tcfg := timegrinder.Config{
 			TSWindow:           window,
 			EnableLeftMostSeed: true,
+			Locale:             cfg.Global.Timestamp_Locale,
 		}
 		tgr, err := timegrinder.NewTimeGrinder(tcfg)
 		if err != nil {
@@ -168,6 +169,9 @@ func main() {
 			if err != nil {
 				lg.Fatal("preprocessor failure", log.KVErr(err))
 			}
+			if err = ib.RegisterPreprocessorStats(name, procset); err != nil {
+				lg.Warn("failed to register preprocessor stats", log.KV("contenttype", name), log.KVErr(err))
+			}
 
 			// we'll do a sliding window, they warn it can take a long time for some logs to show up
 			for running {
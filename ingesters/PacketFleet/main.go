@@ -178,6 +178,9 @@ func main() {
 		if hcfg.proc, err = cfg.Preprocessor.ProcessorSet(igst, v.Preprocessor); err != nil {
 			lg.Fatal("preprocessor error", log.KV("handler", k), log.KV("preprocessor", v.Preprocessor), log.KVErr(err))
 		}
+		if err = ib.RegisterPreprocessorStats(k, hcfg.proc); err != nil {
+			lg.Warn("failed to register preprocessor stats", log.KV("handler", k), log.KVErr(err))
+		}
 
 		// Load client cert
 		cert, err := tls.LoadX509KeyPair(hcfg.clientCert, hcfg.clientKey)
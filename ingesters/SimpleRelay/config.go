@@ -20,6 +20,7 @@ import (
 	"github.com/gravwell/gravwell/v3/ingest/config"
 	"github.com/gravwell/gravwell/v3/ingest/entry"
 	"github.com/gravwell/gravwell/v3/ingest/processors"
+	"github.com/gravwell/gravwell/v3/timegrinder"
 )
 
 const (
@@ -55,6 +56,7 @@ type baseConfig struct {
 	Timezone_Override         string
 	Source_Override           string
 	Timestamp_Format_Override string //override the timestamp format
+	Timestamp_Anchor          string //restrict timestamp extraction to a field, e.g. json:meta.ts, csv:2, or kv:ts
 	Cert_File                 string
 	Key_File                  string
 	Preprocessor              []string
@@ -298,6 +300,8 @@ func checkListenerSettings(l *listener) (err error) {
 func (l baseConfig) Validate() error {
 	if len(l.Bind_String) == 0 {
 		return errors.New("No Bind-String provided")
+	} else if err := timegrinder.ValidateAnchor(l.Timestamp_Anchor); err != nil {
+		return err
 	}
 	return nil
 }
@@ -26,6 +26,7 @@ import (
 	"github.com/gravwell/gravwell/v3/ingest/entry"
 	"github.com/gravwell/gravwell/v3/ingest/log"
 	"github.com/gravwell/gravwell/v3/ingest/processors"
+	"github.com/gravwell/gravwell/v3/ingesters/base"
 	"github.com/gravwell/gravwell/v3/ingesters/utils"
 	"github.com/gravwell/gravwell/v3/timegrinder"
 
@@ -49,9 +50,13 @@ type jsonHandlerConfig struct {
 	maxObjectSize    int64
 	disableCompact   bool
 	tsWindow         timegrinder.TimestampWindow
+	tsLocale         string
+	tsAnchor         string
+	tsCounters       *timegrinder.Counters
+	tsZones          *timegrinder.SourceZones
 }
 
-func startJSONListeners(cfg *cfgType, igst *ingest.IngestMuxer, wg *sync.WaitGroup, f *flusher, ctx context.Context) error {
+func startJSONListeners(cfg *cfgType, igst *ingest.IngestMuxer, ib *base.IngesterBase, wg *sync.WaitGroup, f *flusher, ctx context.Context) error {
 	var err error
 	//short circuit out on empty
 	if len(cfg.JSONListener) == 0 {
@@ -63,6 +68,10 @@ func startJSONListeners(cfg *cfgType, igst *ingest.IngestMuxer, wg *sync.WaitGro
 		err = fmt.Errorf("Failed to get global timestamp window: %v", err)
 		return err
 	}
+	zones, err := cfg.SourceZones()
+	if err != nil {
+		return fmt.Errorf("Failed to build source timezones: %v", err)
+	}
 	for k, v := range cfg.JSONListener {
 		if err := v.Validate(); err != nil {
 			return fmt.Errorf("JSONListener %s configuration is invalid: %w", k, err)
@@ -80,11 +89,21 @@ func startJSONListeners(cfg *cfgType, igst *ingest.IngestMuxer, wg *sync.WaitGro
 			maxObjectSize:    int64(v.Max_Object_Size),
 			disableCompact:   v.Disable_Compact,
 			tsWindow:         window,
+			tsLocale:         cfg.Timestamp_Locale,
+			tsAnchor:         v.Timestamp_Anchor,
+			tsCounters:       timegrinder.NewCounters(),
+			tsZones:          zones,
 		}
 		if jhc.proc, err = cfg.Preprocessor.ProcessorSet(igst, v.Preprocessor); err != nil {
 			lg.Fatal("preprocessor error", log.KVErr(err))
 		}
 		f.Add(jhc.proc)
+		if err = ib.RegisterPreprocessorStats(k, jhc.proc); err != nil {
+			lg.Warn("failed to register preprocessor stats", log.KV("listener", k), log.KVErr(err))
+		}
+		if err = ib.RegisterTimestampStats(k, jhc.tsCounters); err != nil {
+			lg.Warn("failed to register timestamp stats", log.KV("listener", k), log.KVErr(err))
+		}
 		if jhc.flds, err = v.GetJsonFields(); err != nil {
 			return err
 		}
@@ -214,6 +233,10 @@ func jsonAcceptorUDP(conn *net.UDPConn, id int, igst *ingest.IngestMuxer, cfg js
 	buff := make([]byte, 16*1024) //local buffer that should be big enough for even the largest UDP packets
 	tcfg := timegrinder.Config{
 		TSWindow:           cfg.tsWindow,
+		Locale:             cfg.tsLocale,
+		Anchor:             cfg.tsAnchor,
+		Counters:           cfg.tsCounters,
+		Zones:              cfg.tsZones,
 		EnableLeftMostSeed: true,
 	}
 	tg, err := timegrinder.NewTimeGrinder(tcfg)
@@ -261,6 +284,7 @@ func jsonAcceptorUDP(conn *net.UDPConn, id int, igst *ingest.IngestMuxer, cfg js
 		} else {
 			rip = cfg.src
 		}
+		tg.SetSource(raddr.IP, ``)
 		// get a local logger up that will always add some more info
 		handleJSONStream(bytes.NewReader(buff[0:]), cfg, rip, tg, ll)
 	}
@@ -297,6 +321,10 @@ func jsonConnHandler(c net.Conn, cfg jsonHandlerConfig, igst *ingest.IngestMuxer
 		var err error
 		tcfg := timegrinder.Config{
 			TSWindow:           cfg.tsWindow,
+			Locale:             cfg.tsLocale,
+			Anchor:             cfg.tsAnchor,
+			Counters:           cfg.tsCounters,
+			Zones:              cfg.tsZones,
 			EnableLeftMostSeed: true,
 		}
 		tg, err = timegrinder.NewTimeGrinder(tcfg)
@@ -322,6 +350,7 @@ func jsonConnHandler(c net.Conn, cfg jsonHandlerConfig, igst *ingest.IngestMuxer
 				return
 			}
 		}
+		tg.SetSource(lip, ``)
 	}
 
 	if err := handleJSONStream(c, cfg, rip, tg, ll); err != nil {
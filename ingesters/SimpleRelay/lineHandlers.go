@@ -47,6 +47,10 @@ func lineConnHandlerTCP(c net.Conn, cfg handlerConfig) {
 		var err error
 		tcfg := timegrinder.Config{
 			TSWindow:           cfg.tsWindow,
+			Locale:             cfg.tsLocale,
+			Anchor:             cfg.tsAnchor,
+			Counters:           cfg.tsCounters,
+			Zones:              cfg.tsZones,
 			EnableLeftMostSeed: true,
 		}
 		tg, err = timegrinder.NewTimeGrinder(tcfg)
@@ -73,14 +77,18 @@ func lineConnHandlerTCP(c net.Conn, cfg handlerConfig) {
 				return
 			}
 		}
+		tg.SetSource(remoteIP(c.RemoteAddr()), ``)
 	}
 	bio := bufio.NewReader(c)
+	var data, line []byte
+	var err error
 	for {
-		data, err := bio.ReadBytes('\n')
+		//lines are read into reused buffers, handlePooledLog copies them out
+		data, line, err = readLine(bio, line)
 		data = bytes.Trim(data, "\n\r\t ")
 
 		if len(data) > 0 {
-			if ent, err := handleLog(data, rip, cfg.ignoreTimestamps, cfg.tag, tg); err != nil {
+			if ent, err := handlePooledLog(cfg.pool, data, rip, cfg.ignoreTimestamps, cfg.tag, tg); err != nil {
 				return
 			} else if err = cfg.proc.ProcessContext(ent, cfg.ctx); err != nil {
 				return
@@ -104,6 +112,10 @@ func lineConnHandlerUDP(c *net.UDPConn, cfg handlerConfig) {
 	buff := make([]byte, 16*1024) //local buffer that should be big enough for even the largest UDP packets
 	tcfg := timegrinder.Config{
 		TSWindow:           cfg.tsWindow,
+		Locale:             cfg.tsLocale,
+		Anchor:             cfg.tsAnchor,
+		Counters:           cfg.tsCounters,
+		Zones:              cfg.tsZones,
 		EnableLeftMostSeed: true,
 	}
 	tg, err := timegrinder.NewTimeGrinder(tcfg)
@@ -151,6 +163,7 @@ func lineConnHandlerUDP(c *net.UDPConn, cfg handlerConfig) {
 		} else {
 			rip = cfg.src
 		}
+		tg.SetSource(raddr.IP, ``)
 
 		lns := bytes.Split(buff[:n], sp)
 		for _, ln := range lns {
@@ -158,8 +171,8 @@ func lineConnHandlerUDP(c *net.UDPConn, cfg handlerConfig) {
 			if len(ln) == 0 {
 				continue
 			}
-			//because we are using and reusing a local buffer, we have to copy the bytes when handing in
-			if ent, err := handleLog(append([]byte(nil), ln...), rip, cfg.ignoreTimestamps, cfg.tag, tg); err != nil {
+			//because we are using and reusing a local buffer, handlePooledLog copies the bytes out
+			if ent, err := handlePooledLog(cfg.pool, ln, rip, cfg.ignoreTimestamps, cfg.tag, tg); err != nil {
 				return
 			} else if err = cfg.proc.ProcessContext(ent, cfg.ctx); err != nil {
 				return
@@ -168,3 +181,21 @@ func lineConnHandlerUDP(c *net.UDPConn, cfg handlerConfig) {
 	}
 
 }
+
+// readLine reads up to and including the next newline without allocating a new buffer per line.
+// Short lines are returned straight out of the bufio buffer, lines that do not fit are assembled in line
+// which is handed back for reuse.  The returned data is only valid until the next call.
+func readLine(bio *bufio.Reader, line []byte) (data, buff []byte, err error) {
+	buff = line[:0]
+	for {
+		if data, err = bio.ReadSlice('\n'); err != bufio.ErrBufferFull {
+			break
+		}
+		buff = append(buff, data...)
+	}
+	if len(buff) > 0 {
+		buff = append(buff, data...)
+		data = buff
+	}
+	return
+}
@@ -0,0 +1,54 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/gravwell/gravwell/v3/ingest/entry"
+)
+
+func TestReadLine(t *testing.T) {
+	long := strings.Repeat(`x`, 10000)
+	input := "short\n" + long + "\r\nno newline"
+	bio := bufio.NewReaderSize(strings.NewReader(input), 16)
+	pool := entry.NewPool()
+	var ents []*entry.Entry
+	var data, line []byte
+	var err error
+	for err == nil {
+		data, line, err = readLine(bio, line)
+		if data = bytes.Trim(data, "\n\r\t "); len(data) == 0 {
+			continue
+		}
+		ent, lerr := handlePooledLog(pool, data, net.ParseIP(`127.0.0.1`), true, 0, nil)
+		if lerr != nil {
+			t.Fatal(lerr)
+		}
+		ents = append(ents, ent)
+	}
+	if err != io.EOF {
+		t.Fatal(err)
+	}
+	exp := []string{`short`, long, `no newline`}
+	if len(ents) != len(exp) {
+		t.Fatalf("bad entry count: %d != %d", len(ents), len(exp))
+	}
+	//the read buffers are reused, so every entry must have its own copy
+	for i := range exp {
+		if string(ents[i].Data) != exp[i] {
+			t.Fatalf("entry %d is bad: %q", i, ents[i].Data)
+		}
+	}
+}
@@ -90,17 +90,17 @@ func main() {
 	ctx, cancel := context.WithCancel(context.Background())
 
 	//fire off our simple listeners
-	if err := startSimpleListeners(cfg, igst, wg, &flshr, ctx); err != nil {
+	if err := startSimpleListeners(cfg, igst, &ib, wg, &flshr, ctx); err != nil {
 		lg.FatalCode(0, "Failed to start simple listeners", log.KV("ingesteruuid", id), log.KVErr(err))
 		return
 	}
 	// fire off our regex listeners
-	if err := startRegexListeners(cfg, igst, wg, &flshr, ctx); err != nil {
+	if err := startRegexListeners(cfg, igst, &ib, wg, &flshr, ctx); err != nil {
 		lg.FatalCode(0, "Failed to start regex listeners", log.KV("ingesteruuid", id), log.KVErr(err))
 		return
 	}
 	//fire off our json listeners
-	if err := startJSONListeners(cfg, igst, wg, &flshr, ctx); err != nil {
+	if err := startJSONListeners(cfg, igst, &ib, wg, &flshr, ctx); err != nil {
 		lg.FatalCode(0, "Failed to start json listeners", log.KV("ingesteruuid", id), log.KVErr(err))
 		return
 	}
@@ -27,6 +27,7 @@ import (
 	"github.com/gravwell/gravwell/v3/ingest/entry"
 	"github.com/gravwell/gravwell/v3/ingest/log"
 	"github.com/gravwell/gravwell/v3/ingest/processors"
+	"github.com/gravwell/gravwell/v3/ingesters/base"
 	"github.com/gravwell/gravwell/v3/timegrinder"
 )
 
@@ -50,9 +51,13 @@ type regexHandlerConfig struct {
 	trimWhitespace   bool
 	maxBuffer        int
 	tsWindow         timegrinder.TimestampWindow
+	tsLocale         string
+	tsAnchor         string
+	tsCounters       *timegrinder.Counters
+	tsZones          *timegrinder.SourceZones
 }
 
-func startRegexListeners(cfg *cfgType, igst *ingest.IngestMuxer, wg *sync.WaitGroup, f *flusher, ctx context.Context) error {
+func startRegexListeners(cfg *cfgType, igst *ingest.IngestMuxer, ib *base.IngesterBase, wg *sync.WaitGroup, f *flusher, ctx context.Context) error {
 	var err error
 	//short circuit out on empty
 	if len(cfg.RegexListener) == 0 {
@@ -65,6 +70,10 @@ func startRegexListeners(cfg *cfgType, igst *ingest.IngestMuxer, wg *sync.WaitGr
 		err = fmt.Errorf("Failed to get global timestamp window: %v", err)
 		return err
 	}
+	zones, err := cfg.SourceZones()
+	if err != nil {
+		return fmt.Errorf("Failed to build source timezones: %v", err)
+	}
 	for k, v := range cfg.RegexListener {
 		rhc := regexHandlerConfig{
 			name:             k,
@@ -79,11 +88,21 @@ func startRegexListeners(cfg *cfgType, igst *ingest.IngestMuxer, wg *sync.WaitGr
 			trimWhitespace:   v.Trim_Whitespace,
 			maxBuffer:        v.Max_Buffer,
 			tsWindow:         window,
+			tsLocale:         cfg.Timestamp_Locale,
+			tsAnchor:         v.Timestamp_Anchor,
+			tsCounters:       timegrinder.NewCounters(),
+			tsZones:          zones,
 		}
 		if rhc.proc, err = cfg.Preprocessor.ProcessorSet(igst, v.Preprocessor); err != nil {
 			lg.Fatal("preprocessor error", log.KVErr(err))
 		}
 		f.Add(rhc.proc)
+		if err = ib.RegisterPreprocessorStats(k, rhc.proc); err != nil {
+			lg.Warn("failed to register preprocessor stats", log.KV("listener", k), log.KVErr(err))
+		}
+		if err = ib.RegisterTimestampStats(k, rhc.tsCounters); err != nil {
+			lg.Warn("failed to register timestamp stats", log.KV("listener", k), log.KVErr(err))
+		}
 		if _, err = regexp.Compile(v.Regex); err != nil {
 			return err
 		}
@@ -199,6 +218,10 @@ func regexAcceptorUDP(conn *net.UDPConn, id int, cfg regexHandlerConfig, igst *i
 	buff := make([]byte, 16*1024) //local buffer that should be big enough for even the largest UDP packets
 	tcfg := timegrinder.Config{
 		TSWindow:           cfg.tsWindow,
+		Locale:             cfg.tsLocale,
+		Anchor:             cfg.tsAnchor,
+		Counters:           cfg.tsCounters,
+		Zones:              cfg.tsZones,
 		EnableLeftMostSeed: true,
 	}
 	tg, err := timegrinder.NewTimeGrinder(tcfg)
@@ -259,6 +282,7 @@ func regexAcceptorUDP(conn *net.UDPConn, id int, cfg regexHandlerConfig, igst *i
 		} else {
 			rip = cfg.src
 		}
+		tg.SetSource(raddr.IP, ``)
 		regexLoop(bytes.NewReader(buff[:n]), cfg, rip, rs, tg)
 	}
 
@@ -305,6 +329,10 @@ func regexConnHandler(c net.Conn, cfg regexHandlerConfig, igst *ingest.IngestMux
 		var err error
 		tcfg := timegrinder.Config{
 			TSWindow:           cfg.tsWindow,
+			Locale:             cfg.tsLocale,
+			Anchor:             cfg.tsAnchor,
+			Counters:           cfg.tsCounters,
+			Zones:              cfg.tsZones,
 			EnableLeftMostSeed: true,
 		}
 		tg, err = timegrinder.NewTimeGrinder(tcfg)
@@ -331,6 +359,7 @@ func regexConnHandler(c net.Conn, cfg regexHandlerConfig, igst *ingest.IngestMux
 				return
 			}
 		}
+		tg.SetSource(remoteIP(c.RemoteAddr()), ``)
 	}
 
 	regexLoop(c, cfg, rip, rs, tg)
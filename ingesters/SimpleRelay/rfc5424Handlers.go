@@ -57,6 +57,10 @@ func rfc5424ConnHandlerTCP(c net.Conn, cfg handlerConfig) {
 
 	tcfg := timegrinder.Config{
 		TSWindow:           cfg.tsWindow,
+		Locale:             cfg.tsLocale,
+		Anchor:             cfg.tsAnchor,
+		Counters:           cfg.tsCounters,
+		Zones:              cfg.tsZones,
 		EnableLeftMostSeed: true,
 	}
 	tg, err := timegrinder.NewTimeGrinder(tcfg)
@@ -84,6 +88,7 @@ func rfc5424ConnHandlerTCP(c net.Conn, cfg handlerConfig) {
 			return
 		}
 	}
+	tg.SetSource(remoteIP(c.RemoteAddr()), ``)
 	//wrap our connection in a read pumper so that we can force the scanner to wake up periodcally
 	//this lets us detect a message that doesn't have a terminator and has been sitting in the buffer
 	//for a while.  Overall this is a way to enable the SimpleRelay ingster to detect the "last log message" and push it once its been sitting for a while
@@ -169,6 +174,10 @@ func rfc5424ConnHandlerUDP(c *net.UDPConn, cfg handlerConfig) {
 	buff := make([]byte, 16*1024) //local buffer that should be big enough for even the largest UDP packets
 	tcfg := timegrinder.Config{
 		TSWindow:           cfg.tsWindow,
+		Locale:             cfg.tsLocale,
+		Anchor:             cfg.tsAnchor,
+		Counters:           cfg.tsCounters,
+		Zones:              cfg.tsZones,
 		EnableLeftMostSeed: true,
 	}
 	tg, err := timegrinder.NewTimeGrinder(tcfg)
@@ -215,6 +224,7 @@ func rfc5424ConnHandlerUDP(c *net.UDPConn, cfg handlerConfig) {
 			} else {
 				rip = cfg.src
 			}
+			tg.SetSource(raddr.IP, ``)
 			handleRFC5424Packet(append([]byte(nil), buff[:n]...), rip, cfg.ignoreTimestamps, cfg.dropPriority, cfg.tag, tg, cfg.proc, cfg.ctx)
 		}
 	}
@@ -82,6 +82,10 @@ func rfc6587ConnHandlerTCP(c net.Conn, cfg handlerConfig) {
 
 	tcfg := timegrinder.Config{
 		TSWindow:           cfg.tsWindow,
+		Locale:             cfg.tsLocale,
+		Anchor:             cfg.tsAnchor,
+		Counters:           cfg.tsCounters,
+		Zones:              cfg.tsZones,
 		EnableLeftMostSeed: true,
 	}
 	tg, err := timegrinder.NewTimeGrinder(tcfg)
@@ -109,6 +113,7 @@ func rfc6587ConnHandlerTCP(c net.Conn, cfg handlerConfig) {
 			return
 		}
 	}
+	tg.SetSource(remoteIP(c.RemoteAddr()), ``)
 	s := bufio.NewScanner(c)
 	s.Buffer(make([]byte, initDataSize), maxDataSize)
 	splitter := func(data []byte, atEOF bool) (advance int, token []byte, err error) {
@@ -22,6 +22,7 @@ import (
 	"github.com/gravwell/gravwell/v3/ingest/entry"
 	"github.com/gravwell/gravwell/v3/ingest/log"
 	"github.com/gravwell/gravwell/v3/ingest/processors"
+	"github.com/gravwell/gravwell/v3/ingesters/base"
 	"github.com/gravwell/gravwell/v3/timegrinder"
 )
 
@@ -50,9 +51,14 @@ type handlerConfig struct {
 	ctx              context.Context
 	timeFormats      config.CustomTimeFormat
 	tsWindow         timegrinder.TimestampWindow
+	tsLocale         string
+	tsAnchor         string
+	tsCounters       *timegrinder.Counters
+	tsZones          *timegrinder.SourceZones
+	pool             *entry.Pool // only set when entries go straight to the muxer
 }
 
-func startSimpleListeners(cfg *cfgType, igst *ingest.IngestMuxer, wg *sync.WaitGroup, f *flusher, ctx context.Context) error {
+func startSimpleListeners(cfg *cfgType, igst *ingest.IngestMuxer, ib *base.IngesterBase, wg *sync.WaitGroup, f *flusher, ctx context.Context) error {
 	//short circuit out on empty
 	if len(cfg.Listener) == 0 {
 		return nil
@@ -62,6 +68,10 @@ func startSimpleListeners(cfg *cfgType, igst *ingest.IngestMuxer, wg *sync.WaitG
 		err = fmt.Errorf("Failed to get global timestamp window: %v", err)
 		return err
 	}
+	zones, err := cfg.SourceZones()
+	if err != nil {
+		return fmt.Errorf("Failed to build source timezones: %v", err)
+	}
 	//fire up our simple backends
 	for k, v := range cfg.Listener {
 		var src net.IP
@@ -105,11 +115,22 @@ func startSimpleListeners(cfg *cfgType, igst *ingest.IngestMuxer, wg *sync.WaitG
 			ctx:              ctx,
 			timeFormats:      cfg.TimeFormat,
 			tsWindow:         window,
+			tsLocale:         cfg.Timestamp_Locale,
+			tsAnchor:         v.Timestamp_Anchor,
+			tsCounters:       timegrinder.NewCounters(),
+			tsZones:          zones,
 		}
 		if hcfg.proc, err = cfg.Preprocessor.ProcessorSet(igst, v.Preprocessor); err != nil {
 			lg.Fatal("preprocessor error", log.KVErr(err))
 		}
 		f.Add(hcfg.proc)
+		if err = ib.RegisterPreprocessorStats(k, hcfg.proc); err != nil {
+			lg.Warn("failed to register preprocessor stats", log.KV("listener", k), log.KVErr(err))
+		}
+		hcfg.pool = entryPool(igst, hcfg.proc)
+		if err = ib.RegisterTimestampStats(k, hcfg.tsCounters); err != nil {
+			lg.Warn("failed to register timestamp stats", log.KV("listener", k), log.KVErr(err))
+		}
 		if tp.TCP() {
 			//get the socket
 			addr, err := net.ResolveTCPAddr(tp.String(), str)
@@ -221,8 +242,39 @@ func handleLog(b []byte, ip net.IP, ignoreTS bool, tag entry.EntryTag, tg *timeg
 	if len(b) == 0 {
 		return
 	}
-	var ok bool
 	var ts entry.Timestamp
+	if ts, err = extractTimestamp(b, ignoreTS, tg); err != nil {
+		return
+	}
+	//debugout("GOT (%v) %s\n", ts, string(b))
+	ent = &entry.Entry{
+		SRC:  ip,
+		TS:   ts,
+		Tag:  tag,
+		Data: b,
+	}
+	return
+}
+
+// handlePooledLog is handleLog for callers that reuse their read buffers, the entry gets a copy of b
+// drawn from the pool so b may be overwritten as soon as this returns.  A nil pool just copies.
+func handlePooledLog(pool *entry.Pool, b []byte, ip net.IP, ignoreTS bool, tag entry.EntryTag, tg *timegrinder.TimeGrinder) (ent *entry.Entry, err error) {
+	if len(b) == 0 {
+		return
+	}
+	var ts entry.Timestamp
+	if ts, err = extractTimestamp(b, ignoreTS, tg); err != nil {
+		return
+	}
+	ent = pool.GetCopy(b)
+	ent.SRC = ip
+	ent.TS = ts
+	ent.Tag = tag
+	return
+}
+
+func extractTimestamp(b []byte, ignoreTS bool, tg *timegrinder.TimeGrinder) (ts entry.Timestamp, err error) {
+	var ok bool
 	var extracted time.Time
 	if !ignoreTS {
 		if extracted, ok, err = tg.Extract(b); err != nil {
@@ -235,16 +287,18 @@ func handleLog(b []byte, ip net.IP, ignoreTS bool, tag entry.EntryTag, tg *timeg
 	if !ok {
 		ts = entry.Now()
 	}
-	//debugout("GOT (%v) %s\n", ts, string(b))
-	ent = &entry.Entry{
-		SRC:  ip,
-		TS:   ts,
-		Tag:  tag,
-		Data: b,
-	}
 	return
 }
 
+// entryPool hands back the muxer's entry pool if entries from a listener go straight to the muxer.
+// Preprocessors are free to hold on to or rewrite entries, so listeners with preprocessors don't pool.
+func entryPool(igst *ingest.IngestMuxer, proc *processors.ProcessorSet) *entry.Pool {
+	if proc.Enabled() {
+		return nil
+	}
+	return igst.EntryPool()
+}
+
 func addConn(c closer) int {
 	mtx.Lock()
 	connId++
@@ -265,3 +319,18 @@ func connCount() int {
 	defer mtx.Unlock()
 	return len(connClosers)
 }
+
+// remoteIP returns the address of the remote end of a connection regardless of any source override,
+// it is used to resolve the timezone of the sender
+func remoteIP(a net.Addr) net.IP {
+	switch v := a.(type) {
+	case *net.TCPAddr:
+		return v.IP
+	case *net.UDPAddr:
+		return v.IP
+	}
+	if host, _, err := net.SplitHostPort(a.String()); err == nil {
+		return net.ParseIP(host)
+	}
+	return nil
+}
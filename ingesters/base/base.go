@@ -30,6 +30,7 @@ import (
 	"github.com/gravwell/gravwell/v3/ingest/log"
 	"github.com/gravwell/gravwell/v3/ingesters/utils"
 	"github.com/gravwell/gravwell/v3/ingesters/version"
+	"github.com/gravwell/gravwell/v3/timegrinder"
 
 	"github.com/crewjam/rfc5424"
 	"github.com/shirou/gopsutil/host"
@@ -65,6 +66,7 @@ type IngesterBase struct {
 	Cfg           interface{}
 	id            uuid.UUID
 	sm            *utils.StatsManager
+	igst          *ingest.IngestMuxer
 	configFile    string
 	configOverlay string
 }
@@ -244,6 +246,12 @@ func (ib *IngesterBase) GetMuxer() (igst *ingest.IngestMuxer, err error) {
 	}
 	ib.Debug("Rate limiting connection to %d bps\n", lmt)
 
+	schemas, err := ingest.LoadSchemaFiles(cfg.Schema_File...)
+	if err != nil {
+		ib.Logger.FatalCode(0, "failed to load tag schemas", log.KVErr(err))
+		return
+	}
+
 	//fire up the ingesters
 	ib.Debug("INSECURE skip TLS certificate verification: %v\n", cfg.InsecureSkipTLSVerification())
 	id, ok := cfg.IngesterUUID()
@@ -269,12 +277,14 @@ func (ib *IngesterBase) GetMuxer() (igst *ingest.IngestMuxer, err error) {
 		CacheMode:          cfg.Cache_Mode,
 		LogSourceOverride:  net.ParseIP(cfg.Log_Source_Override),
 		Attach:             ch.AttachConfig(),
+		Schemas:            schemas,
 	}
 	if igst, err = ingest.NewUniformMuxer(igCfg); err != nil {
 		ib.Logger.Fatal("failed to build our ingest system", log.KVErr(err))
 		return
 	}
 
+	ib.igst = igst
 	ib.Debug("Started ingester muxer\n")
 	if cfg.SelfIngest() {
 		ib.Logger.AddRelay(igst)
@@ -434,6 +444,77 @@ func (ib *IngesterBase) RegisterStat(name string) (*utils.StatsItem, error) {
 	return ib.sm.RegisterItem(name)
 }
 
+// RegisterPreprocessorStats publishes the counters of a preprocessor set, typically a *processors.ProcessorSet,
+// in the ingester state and in the periodic stats log.  Stats log items are named <name>.<preprocessor>.<counter>.
+func (ib *IngesterBase) RegisterPreprocessorStats(name string, src ingest.PreprocessorStatsSource) (err error) {
+	if ib == nil || src == nil {
+		return errors.New("not ready")
+	}
+	if ib.igst != nil {
+		ib.igst.RegisterPreprocessorStats(name, src)
+	}
+	if ib.sm == nil {
+		return
+	}
+	for _, v := range src.PreprocessorStats() {
+		pp := v.Name
+		counters := []struct {
+			name string
+			get  func(ingest.PreprocessorStats) uint64
+		}{
+			{`entries-in`, func(s ingest.PreprocessorStats) uint64 { return s.EntriesIn }},
+			{`entries-out`, func(s ingest.PreprocessorStats) uint64 { return s.EntriesOut }},
+			{`dropped`, func(s ingest.PreprocessorStats) uint64 { return s.Dropped }},
+			{`errors`, func(s ingest.PreprocessorStats) uint64 { return s.Errors }},
+			{`processing-us`, func(s ingest.PreprocessorStats) uint64 { return uint64(s.ProcessingTime.Microseconds()) }},
+		}
+		for _, c := range counters {
+			get := c.get
+			fn := func() uint64 {
+				for _, s := range src.PreprocessorStats() {
+					if s.Name == pp {
+						return get(s)
+					}
+				}
+				return 0
+			}
+			if err = ib.sm.RegisterCounter(name+`.`+pp+`.`+c.name, fn); err != nil {
+				return
+			}
+		}
+	}
+	return
+}
+
+// RegisterTimestampStats publishes timestamp extraction counters, typically a *timegrinder.Counters,
+// in the ingester state and in the periodic stats log.  Stats log items are named <name>.timestamp-<counter>.
+func (ib *IngesterBase) RegisterTimestampStats(name string, src ingest.TimestampStatsSource) (err error) {
+	if ib == nil || src == nil {
+		return errors.New("not ready")
+	}
+	if ib.igst != nil {
+		ib.igst.RegisterTimestampStats(name, src)
+	}
+	if ib.sm == nil {
+		return
+	}
+	counters := []struct {
+		name string
+		get  func(timegrinder.Stats) uint64
+	}{
+		{`timestamp-hits`, func(s timegrinder.Stats) uint64 { return s.Hits }},
+		{`timestamp-misses`, func(s timegrinder.Stats) uint64 { return s.Misses }},
+		{`timestamp-fallbacks`, func(s timegrinder.Stats) uint64 { return s.Fallbacks }},
+	}
+	for _, c := range counters {
+		get := c.get
+		if err = ib.sm.RegisterCounter(name+`.`+c.name, func() uint64 { return get(src.Stats()) }); err != nil {
+			return
+		}
+	}
+	return
+}
+
 func (ibc IngesterBaseConfig) validate() error {
 	if ibc.IngesterName == `` {
 		return errors.New("missing ingester name")
@@ -100,6 +100,9 @@ func main() {
 		if cc.proc, err = cfg.Preprocessor.ProcessorSet(igst, v.Preprocessor); err != nil {
 			lg.Fatal("preprocessor error", log.KV("collector", k), log.KV("preprocessor", v.Preprocessor), log.KVErr(err))
 		}
+		if err = ib.RegisterPreprocessorStats(k, cc.proc); err != nil {
+			lg.Warn("failed to register preprocessor stats", log.KV("collector", k), log.KVErr(err))
+		}
 
 		cc.src = nil
 
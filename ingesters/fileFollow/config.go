@@ -62,6 +62,7 @@ type follower struct {
 	Timestamp_Format_Override string //override the timestamp format
 	Timestamp_Delimited       bool
 	Timezone_Override         string
+	Timestamp_Anchor          string //restrict timestamp extraction to a field, e.g. json:meta.ts, csv:2, or kv:ts
 	Regex_Delimiter           string
 	Preprocessor              []string
 	// these two must be used together
@@ -143,6 +144,9 @@ func (c *cfgType) Verify() error {
 				return fmt.Errorf("Failed to parse Timestamp-Regex and Timestamp-Format-String defs: %v", err)
 			}
 		}
+		if err := timegrinder.ValidateAnchor(v.Timestamp_Anchor); err != nil {
+			return fmt.Errorf("Invalid Timestamp-Anchor in follower %v: %v", k, err)
+		}
 		if ingest.CheckTag(v.Tag_Name) != nil {
 			return errors.New("Invalid characters in the Tag-Name for " + k)
 		}
@@ -110,6 +110,10 @@ func main() {
 	if err != nil {
 		lg.Fatal("Failed to get global timestamp window", log.KVErr(err))
 	}
+	zones, err := cfg.SourceZones()
+	if err != nil {
+		lg.Fatal("Failed to build source timezones", log.KVErr(err))
+	}
 
 	//build a list of base directories and globs
 	for k, val := range cfg.Follower {
@@ -118,6 +122,9 @@ func main() {
 			lg.FatalCode(0, "preprocessor construction error", log.KVErr(err))
 		}
 		procs = append(procs, pproc)
+		if err = ib.RegisterPreprocessorStats(k, pproc); err != nil {
+			lg.Warn("failed to register preprocessor stats", log.KV("watcher", k), log.KVErr(err))
+		}
 		//get the tag for this listener
 		tag, err := igst.GetTag(val.Tag_Name)
 		if err != nil {
@@ -148,10 +155,21 @@ func main() {
 			AttachFilename:          val.Attach_Filename,
 			Trim:                    val.Trim,
 			TimestampWindow:         window,
+			TimestampLocale:         cfg.Timestamp_Locale,
+			TimestampAnchor:         val.Timestamp_Anchor,
+			TimestampCounters:       timegrinder.NewCounters(),
+			TimestampZones:          zones,
+		}
+		if !pproc.Enabled() {
+			//entries go straight to the muxer, so we can draw them from its pool
+			cfg.Pool = igst.EntryPool()
 		}
 		if debugOn {
 			cfg.Debugger = debugout
 		}
+		if err = ib.RegisterTimestampStats(k, cfg.TimestampCounters); err != nil {
+			lg.Warn("failed to register timestamp stats", log.KV("watcher", k), log.KVErr(err))
+		}
 		lh, err := filewatch.NewLogHandler(cfg, pproc)
 		if err != nil {
 			lg.Fatal("failed to generate handler", log.KVErr(err))
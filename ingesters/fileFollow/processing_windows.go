@@ -332,6 +332,11 @@ func (m *mainService) init(ctx context.Context) error {
 		errorout("Failed to get global timestamp window: %v", err)
 		return err
 	}
+	zones, err := m.cfg.SourceZones()
+	if err != nil {
+		errorout("Failed to build source timezones: %v", err)
+		return err
+	}
 
 	//build up the handlers
 	for k, val := range m.flocs {
@@ -341,6 +346,7 @@ func (m *mainService) init(ctx context.Context) error {
 			return err
 		}
 		m.procs = append(m.procs, pproc)
+		igst.RegisterPreprocessorStats(k, pproc)
 		//get the tag for this listener
 		tag, err := igst.GetTag(val.Tag_Name)
 		if err != nil {
@@ -372,8 +378,17 @@ func (m *mainService) init(ctx context.Context) error {
 			AttachFilename:          val.Attach_Filename,
 			Trim:                    val.Trim,
 			TimestampWindow:         window,
+			TimestampLocale:         m.cfg.Timestamp_Locale,
+			TimestampAnchor:         val.Timestamp_Anchor,
+			TimestampCounters:       timegrinder.NewCounters(),
+			TimestampZones:          zones,
+		}
+		if !pproc.Enabled() {
+			//entries go straight to the muxer, so we can draw them from its pool
+			cfg.Pool = igst.EntryPool()
 		}
 
+		m.igst.RegisterTimestampStats(k, cfg.TimestampCounters)
 		lh, err := filewatch.NewLogHandler(cfg, pproc)
 		if err != nil {
 			errorout("Failed to generate handler: %v", err)
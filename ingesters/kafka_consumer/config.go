@@ -317,6 +317,7 @@ func (cc ConfigConsumer) validateAndProcess(cr cfgReadType) (c consumerCfg, err
 			EnableLeftMostSeed: true,
 			FormatOverride:     cc.Timestamp_Format_Override,
 			TSWindow:           c.timeWindow,
+			Locale:             cr.Global.Timestamp_Locale,
 		}
 		if c.tg, err = timegrinder.NewTimeGrinder(tcfg); err != nil {
 			err = fmt.Errorf("Failed to generate new timegrinder: %v", err)
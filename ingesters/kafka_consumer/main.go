@@ -100,6 +100,9 @@ func main() {
 				log.KV("consumer", k), log.KVErr(err))
 		}
 		procs = append(procs, kcfg.pproc)
+		if err = ib.RegisterPreprocessorStats(k, kcfg.pproc); err != nil {
+			lg.Warn("failed to register preprocessor stats", log.KV("consumer", k), log.KVErr(err))
+		}
 		kc, err := newKafkaConsumer(kcfg)
 		if err != nil {
 			lg.Error("failed to build kafka consumer",
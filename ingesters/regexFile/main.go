@@ -15,9 +15,11 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"net"
 	"os"
 	"regexp"
 	"runtime/debug"
+	"strings"
 	"time"
 
 	// Embed tzdata so that we don't rely on potentially broken timezone DBs on the host
@@ -40,6 +42,8 @@ const (
 var (
 	tso          = flag.String("timestamp-override", "", "Timestamp override")
 	tzo          = flag.String("timezone-override", "", "Timezone override e.g. America/Chicago")
+	srcOverride  = flag.String("source-override", "", "Source address applied to entries and used for timezone map lookups")
+	tzMap        = flag.String("timezone-map", "", "Comma separated source timezones e.g. \"10.0.0.0/8 America/Denver,10.1.0.0/16 America/New_York\"")
 	inFile       = flag.String("i", "", "Input file to process (specify - for stdin)")
 	ver          = flag.Bool("version", false, "Print version and exit")
 	utc          = flag.Bool("utc", false, "Assume UTC time")
@@ -56,6 +60,7 @@ var (
 	nlBytes    = "\n"
 	ignoreTS   bool
 	custTs     timegrinder.CustomFormat
+	zones      *timegrinder.SourceZones
 )
 
 func init() {
@@ -107,6 +112,12 @@ func main() {
 		}
 	}
 
+	if *tzMap != "" {
+		if zones, err = timegrinder.NewSourceZones(strings.Split(*tzMap, ","), false); err != nil {
+			log.Fatalf("Invalid timezone map: %v\n", err)
+		}
+	}
+
 	//get a handle on the input file with a wrapped decompressor if needed
 	var fin io.ReadCloser
 	if *inFile == "-" {
@@ -165,6 +176,7 @@ func ingestFile(fin io.Reader, igst *ingest.IngestMuxer, tag entry.EntryTag, tso
 		c := timegrinder.Config{
 			EnableLeftMostSeed: true,
 			FormatOverride:     tso,
+			Zones:              zones,
 		}
 		var err error
 		if tg, err = timegrinder.NewTimeGrinder(c); err != nil {
@@ -194,6 +206,14 @@ func ingestFile(fin io.Reader, igst *ingest.IngestMuxer, tag entry.EntryTag, tso
 	if err != nil {
 		return err
 	}
+	if *srcOverride != "" {
+		if src = net.ParseIP(*srcOverride); src == nil {
+			return fmt.Errorf("invalid source override %q", *srcOverride)
+		}
+	}
+	if tg != nil {
+		tg.SetSource(src, ``)
+	}
 
 	scn := bufio.NewScanner(fin)
 	scn.Split(regexSplitter)
@@ -260,6 +260,7 @@ func (c *cfgType) newTimeGrinder(tc TimeConfig) (tg *timegrinder.TimeGrinder, er
 	tcfg := timegrinder.Config{
 		TSWindow:           window,
 		EnableLeftMostSeed: true,
+		Locale:             c.Timestamp_Locale,
 	}
 	if tg, err = timegrinder.NewTimeGrinder(tcfg); err != nil {
 		err = fmt.Errorf("failed to get a handle on the timegrinder %w", err)
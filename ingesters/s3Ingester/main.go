@@ -103,6 +103,9 @@ func main() {
 			ib.Logger.FatalCode(0, "preprocessor failure",
 				log.KV("bucket", k), log.KVErr(err))
 		}
+		if err = ib.RegisterPreprocessorStats(k, bcfg.Proc); err != nil {
+			ib.Logger.Warn("failed to register preprocessor stats", log.KV("bucket", k), log.KVErr(err))
+		}
 		if !bcfg.Ignore_Timestamps {
 			if bcfg.TG, err = cfg.newTimeGrinder(v.TimeConfig); err != nil {
 				ib.Logger.FatalCode(0, "failed to create timegrinder",
@@ -144,6 +147,9 @@ func main() {
 			ib.Logger.FatalCode(0, "preprocessor failure",
 				log.KV("bucket", k), log.KVErr(err))
 		}
+		if err = ib.RegisterPreprocessorStats(k, scfg.Proc); err != nil {
+			ib.Logger.Warn("failed to register preprocessor stats", log.KV("bucket", k), log.KVErr(err))
+		}
 		if !scfg.Ignore_Timestamps {
 			if scfg.TG, err = cfg.newTimeGrinder(v.TimeConfig); err != nil {
 				ib.Logger.FatalCode(0, "failed to create timegrinder",
@@ -101,6 +101,9 @@ func main() {
 			ib.Logger.FatalCode(0, "preprocessor failure",
 				log.KV("listener", name), log.KVErr(err))
 		}
+		if err = ib.RegisterPreprocessorStats(name, proc); err != nil {
+			ib.Logger.Warn("failed to register preprocessor stats", log.KV("listener", name), log.KVErr(err))
+		}
 
 		l := gosnmp.NewTrapListener()
 		l.Params = &gosnmp.GoSNMP{
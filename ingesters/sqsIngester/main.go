@@ -149,6 +149,9 @@ func main() {
 		if hcfg.proc, err = cfg.Preprocessor.ProcessorSet(igst, v.Preprocessor); err != nil {
 			lg.Fatal("preprocessor failure", log.KVErr(err))
 		}
+		if err = ib.RegisterPreprocessorStats(k, hcfg.proc); err != nil {
+			lg.Warn("failed to register preprocessor stats", log.KV("queue", k), log.KVErr(err))
+		}
 
 		wg.Add(1)
 		go queueRunner(hcfg)
@@ -0,0 +1,592 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package utils
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sort"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/arrow-go/v18/parquet"
+	"github.com/apache/arrow-go/v18/parquet/compress"
+	"github.com/apache/arrow-go/v18/parquet/file"
+	"github.com/apache/arrow-go/v18/parquet/pqarrow"
+	"github.com/gravwell/gravwell/v3/ingest/entry"
+)
+
+// Parquet files carry one row per entry with the columns TS, SRC, Tag, and Data followed by
+// one nullable column per enumerated value name.  Enumerated value columns are typed according to
+// the enumerated value type, IPs and MACs are written as strings, durations as int64 nanoseconds, and lists and maps
+// are carried as encoded enumerated values.  The enumerated value name and type are stored in the column metadata
+// so that files written here round trip losslessly; files from other tools are imported using the native column types.
+// Enumerated values that do not fit the schema are carried in the EVs column as an encoded enumerated value block.
+const (
+	ParquetFormat string = `parquet`
+
+	parquetTSCol   = `TS`
+	parquetSRCCol  = `SRC`
+	parquetTagCol  = `Tag`
+	parquetDataCol = `Data`
+	parquetEVsCol  = `EVs`
+
+	parquetEVNameKey = `gravwell.ev.name`
+	parquetEVTypeKey = `gravwell.ev.type`
+	parquetEVsKey    = `gravwell.evs`
+	parquetEVPrefix  = `ev.` // applied to enumerated values whose names collide with the fixed columns
+
+	parquetRowGroupSize  = 64 * 1024
+	parquetRowGroupBytes = 64 * 1024 * 1024
+)
+
+var (
+	ErrParquetWriterClosed = errors.New("parquet writer is closed")
+	ErrMissingDataColumn   = errors.New("parquet file does not contain a Data column")
+)
+
+// TagNamer resolves entry tags back to their names, the IngestMuxer implements this interface
+type TagNamer interface {
+	LookupTag(entry.EntryTag) (string, bool)
+}
+
+// ParquetWriter encodes entries into a parquet file.
+// Entries are held in memory and written out as a row group every parquetRowGroupSize entries or parquetRowGroupBytes
+// bytes, whichever comes first.  The schema is built from the enumerated values seen in the first row group, enumerated
+// values that appear with more than one type in the first row group are written as strings.  Enumerated values that show up
+// in later row groups with a new name or a different type are written to the EVs column.
+type ParquetWriter struct {
+	w       io.Writer
+	tn      TagNamer
+	ents    []*entry.Entry
+	size    uint64
+	maxRows int
+	maxSize uint64
+	evs     map[string]uint8
+	names   []string //enumerated value columns, populated once the schema is fixed
+	fw      *pqarrow.FileWriter
+	bldr    *array.RecordBuilder
+	closed  bool
+}
+
+// NewParquetWriter creates a writer that encodes entries to w, tags are resolved to names using tn
+func NewParquetWriter(w io.Writer, tn TagNamer) (*ParquetWriter, error) {
+	if w == nil || tn == nil {
+		return nil, errors.New("invalid parameters")
+	}
+	return &ParquetWriter{
+		w:       w,
+		tn:      tn,
+		maxRows: parquetRowGroupSize,
+		maxSize: parquetRowGroupBytes,
+		evs:     map[string]uint8{},
+	}, nil
+}
+
+// WriteEntry queues an entry for encoding, the entry must not be modified until the row group holding it is
+// written out, which happens on a later call to WriteEntry or on Close
+func (pw *ParquetWriter) WriteEntry(ent *entry.Entry) error {
+	if pw.closed {
+		return ErrParquetWriterClosed
+	} else if ent == nil {
+		return nil
+	}
+	if _, ok := pw.tn.LookupTag(ent.Tag); !ok {
+		return fmt.Errorf("unknown tag %d", ent.Tag)
+	}
+	if pw.fw == nil {
+		for _, ev := range ent.EVB.Values() {
+			if id, ok := pw.evs[ev.Name]; !ok {
+				pw.evs[ev.Name] = ev.TypeID()
+			} else if id != ev.TypeID() {
+				pw.evs[ev.Name] = 0 //mixed types, fall back to strings
+			}
+		}
+	}
+	pw.ents = append(pw.ents, ent)
+	pw.size += ent.Size()
+	if len(pw.ents) >= pw.maxRows || pw.size >= pw.maxSize {
+		return pw.flush()
+	}
+	return nil
+}
+
+// Close writes out any queued entries and the parquet footer, it does not close the underlying writer
+func (pw *ParquetWriter) Close() (err error) {
+	if pw.closed {
+		return ErrParquetWriterClosed
+	}
+	pw.closed = true
+	if len(pw.ents) > 0 || pw.fw == nil {
+		err = pw.flush()
+	}
+	if pw.fw != nil {
+		if lerr := pw.fw.Close(); err == nil {
+			err = lerr
+		}
+		pw.bldr.Release()
+	}
+	pw.ents = nil
+	return
+}
+
+// flush writes the queued entries as a single row group, the first flush fixes the schema and starts the file
+func (pw *ParquetWriter) flush() (err error) {
+	if pw.fw == nil {
+		var schema *arrow.Schema
+		schema, pw.names = pw.schema()
+		props := parquet.NewWriterProperties(
+			parquet.WithCompression(compress.Codecs.Snappy),
+			parquet.WithMaxRowGroupLength(int64(pw.maxRows)),
+		)
+		if pw.fw, err = pqarrow.NewFileWriter(schema, pw.w, props, pqarrow.NewArrowWriterProperties(pqarrow.WithStoreSchema())); err != nil {
+			return
+		}
+		pw.bldr = array.NewRecordBuilder(memory.DefaultAllocator, schema)
+	}
+	err = pw.appendBlock(pw.bldr, pw.names, pw.ents)
+	clear(pw.ents)
+	pw.ents, pw.size = pw.ents[:0], 0
+	if err != nil {
+		return
+	}
+	rec := pw.bldr.NewRecord()
+	err = pw.fw.Write(rec)
+	rec.Release()
+	return
+}
+
+// schema builds the arrow schema along with the enumerated value name for each enumerated value column
+func (pw *ParquetWriter) schema() (*arrow.Schema, []string) {
+	names := make([]string, 0, len(pw.evs))
+	for k := range pw.evs {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	fields := []arrow.Field{
+		{Name: parquetTSCol, Type: arrow.FixedWidthTypes.Timestamp_ns},
+		{Name: parquetSRCCol, Type: arrow.BinaryTypes.String, Nullable: true},
+		{Name: parquetTagCol, Type: arrow.BinaryTypes.String},
+		{Name: parquetDataCol, Type: arrow.BinaryTypes.Binary},
+		{Name: parquetEVsCol, Type: arrow.BinaryTypes.Binary, Nullable: true, Metadata: arrow.NewMetadata([]string{parquetEVsKey}, []string{`true`})},
+	}
+	for _, name := range names {
+		id := pw.evs[name]
+		col := name
+		if isParquetFixedCol(col) {
+			col = parquetEVPrefix + col
+		}
+		fields = append(fields, arrow.Field{
+			Name:     col,
+			Type:     parquetEVArrowType(id),
+			Nullable: true,
+			Metadata: arrow.NewMetadata(
+				[]string{parquetEVNameKey, parquetEVTypeKey},
+				[]string{name, entry.EVTypeName(id)},
+			),
+		})
+	}
+	return arrow.NewSchema(fields, nil), names
+}
+
+func (pw *ParquetWriter) appendBlock(bldr *array.RecordBuilder, names []string, ents []*entry.Entry) error {
+	tsb := bldr.Field(0).(*array.TimestampBuilder)
+	srcb := bldr.Field(1).(*array.StringBuilder)
+	tagb := bldr.Field(2).(*array.StringBuilder)
+	datab := bldr.Field(3).(*array.BinaryBuilder)
+	evsb := bldr.Field(4).(*array.BinaryBuilder)
+	for _, ent := range ents {
+		tag, _ := pw.tn.LookupTag(ent.Tag)
+		tsb.Append(arrow.Timestamp(ent.TS.StandardTime().UnixNano()))
+		if len(ent.SRC) == 0 {
+			srcb.AppendNull()
+		} else {
+			srcb.Append(ent.SRC.String())
+		}
+		tagb.Append(tag)
+		datab.Append(ent.Data)
+		for i, name := range names {
+			b := bldr.Field(5 + i)
+			if ev, ok := ent.EVB.Get(name); !ok || !pw.fitsSchema(ev) {
+				b.AppendNull()
+			} else if err := appendParquetEV(b, pw.evs[name], ev); err != nil {
+				return fmt.Errorf("enumerated value %q %w", name, err)
+			}
+		}
+		var extra entry.EVBlock
+		for _, ev := range ent.EVB.Values() {
+			if !pw.fitsSchema(ev) {
+				extra.Add(ev)
+			}
+		}
+		if !extra.Populated() {
+			evsb.AppendNull()
+		} else if bts, err := extra.Encode(); err != nil {
+			return fmt.Errorf("enumerated values %w", err)
+		} else {
+			evsb.Append(bts)
+		}
+	}
+	return nil
+}
+
+// fitsSchema reports whether an enumerated value can be written to its own column
+func (pw *ParquetWriter) fitsSchema(ev entry.EnumeratedValue) bool {
+	id, ok := pw.evs[ev.Name]
+	return ok && (id == 0 || id == ev.TypeID())
+}
+
+func isParquetFixedCol(name string) bool {
+	switch name {
+	case parquetTSCol, parquetSRCCol, parquetTagCol, parquetDataCol, parquetEVsCol:
+		return true
+	}
+	return false
+}
+
+func parquetEVArrowType(id uint8) arrow.DataType {
+	switch entry.EVTypeName(id) {
+	case `bool`:
+		return arrow.FixedWidthTypes.Boolean
+	case `byte`:
+		return arrow.PrimitiveTypes.Uint8
+	case `int8`:
+		return arrow.PrimitiveTypes.Int8
+	case `int16`:
+		return arrow.PrimitiveTypes.Int16
+	case `uint16`:
+		return arrow.PrimitiveTypes.Uint16
+	case `int32`:
+		return arrow.PrimitiveTypes.Int32
+	case `uint32`:
+		return arrow.PrimitiveTypes.Uint32
+	case `int64`, `duration`:
+		return arrow.PrimitiveTypes.Int64
+	case `uint64`:
+		return arrow.PrimitiveTypes.Uint64
+	case `float32`:
+		return arrow.PrimitiveTypes.Float32
+	case `float64`:
+		return arrow.PrimitiveTypes.Float64
+	case `timestamp`:
+		return arrow.FixedWidthTypes.Timestamp_ns
+	case `bytes`, `list`, `map`:
+		return arrow.BinaryTypes.Binary
+	}
+	return arrow.BinaryTypes.String //strings, IPs, MACs, and mixed types
+}
+
+func appendParquetEV(b array.Builder, id uint8, ev entry.EnumeratedValue) error {
+	if id == 0 {
+		b.(*array.StringBuilder).Append(ev.Value.String())
+		return nil
+	}
+	switch x := ev.Value.Interface().(type) {
+	case bool:
+		b.(*array.BooleanBuilder).Append(x)
+	case uint8:
+		b.(*array.Uint8Builder).Append(x)
+	case int8:
+		b.(*array.Int8Builder).Append(x)
+	case int16:
+		b.(*array.Int16Builder).Append(x)
+	case uint16:
+		b.(*array.Uint16Builder).Append(x)
+	case int32:
+		b.(*array.Int32Builder).Append(x)
+	case uint32:
+		b.(*array.Uint32Builder).Append(x)
+	case int64:
+		b.(*array.Int64Builder).Append(x)
+	case time.Duration:
+		b.(*array.Int64Builder).Append(int64(x))
+	case uint64:
+		b.(*array.Uint64Builder).Append(x)
+	case float32:
+		b.(*array.Float32Builder).Append(x)
+	case float64:
+		b.(*array.Float64Builder).Append(x)
+	case entry.Timestamp:
+		b.(*array.TimestampBuilder).Append(arrow.Timestamp(x.StandardTime().UnixNano()))
+	case []byte:
+		b.(*array.BinaryBuilder).Append(x)
+	case []interface{}, map[string]interface{}:
+		b.(*array.BinaryBuilder).Append(ev.Encode())
+	case string, net.IP, net.HardwareAddr:
+		b.(*array.StringBuilder).Append(ev.Value.String())
+	default:
+		return fmt.Errorf("unsupported type %T", x)
+	}
+	return nil
+}
+
+// ParquetReader reads entries out of a parquet file, it implements the ReimportReader interface.
+// Only the Data column is required, the TS, SRC, and Tag columns are used if present and every other
+// column is attached to entries as an enumerated value.
+type ParquetReader struct {
+	TagHandler
+	rr         pqarrow.RecordReader
+	rec        arrow.Record
+	row        int
+	cnt        int
+	ts         int
+	src        int
+	tag        int
+	data       int
+	evb        int
+	evs        []parquetEVCol
+	disableEVs bool
+}
+
+type parquetEVCol struct {
+	idx  int
+	name string
+	id   uint8 //declared enumerated value type, zero if the column was not written by us
+}
+
+// NewParquetReader opens a parquet file for reading.  Parquet files must be randomly accessed, so if rdr
+// is not an io.ReaderAt and io.Seeker (an *os.File for example) the entire file is read into memory.
+func NewParquetReader(rdr io.Reader, th TagHandler) (*ParquetReader, error) {
+	if rdr == nil || th == nil {
+		return nil, errors.New("invalid parameters")
+	}
+	ras, ok := rdr.(parquet.ReaderAtSeeker)
+	if !ok {
+		bts, err := io.ReadAll(rdr)
+		if err != nil {
+			return nil, err
+		}
+		ras = bytes.NewReader(bts)
+	}
+	pf, err := file.NewParquetReader(ras)
+	if err != nil {
+		return nil, err
+	}
+	fr, err := pqarrow.NewFileReader(pf, pqarrow.ArrowReadProperties{BatchSize: parquetRowGroupSize}, memory.DefaultAllocator)
+	if err != nil {
+		return nil, err
+	}
+	schema, err := fr.Schema()
+	if err != nil {
+		return nil, err
+	}
+	pr := &ParquetReader{
+		TagHandler: th,
+		ts:         -1,
+		src:        -1,
+		tag:        -1,
+		data:       -1,
+		evb:        -1,
+	}
+	for i, f := range schema.Fields() {
+		if name, ok := parquetFieldMeta(f, parquetEVNameKey); ok {
+			col := parquetEVCol{idx: i, name: name}
+			if tn, ok := parquetFieldMeta(f, parquetEVTypeKey); ok {
+				col.id, _ = entry.ParseEVType(tn)
+			}
+			pr.evs = append(pr.evs, col)
+			continue
+		} else if _, ok := parquetFieldMeta(f, parquetEVsKey); ok {
+			pr.evb = i
+			continue
+		}
+		switch f.Name {
+		case parquetTSCol:
+			if f.Type.ID() != arrow.TIMESTAMP {
+				return nil, fmt.Errorf("invalid %s column type %v", f.Name, f.Type)
+			}
+			pr.ts = i
+		case parquetSRCCol:
+			pr.src = i
+		case parquetTagCol:
+			pr.tag = i
+		case parquetDataCol:
+			pr.data = i
+		default:
+			pr.evs = append(pr.evs, parquetEVCol{idx: i, name: f.Name})
+		}
+	}
+	if pr.data < 0 {
+		return nil, ErrMissingDataColumn
+	}
+	if pr.rr, err = fr.GetRecordReader(context.Background(), nil, nil); err != nil {
+		return nil, err
+	}
+	return pr, nil
+}
+
+func (p *ParquetReader) DisableEVs() {
+	p.disableEVs = true
+}
+
+func (p *ParquetReader) ReadEntry() (ent *entry.Entry, err error) {
+	for p.rec == nil || p.row >= int(p.rec.NumRows()) {
+		p.rec, p.row = nil, 0
+		if !p.rr.Next() {
+			if err = p.rr.Err(); err == nil || err == io.EOF {
+				err = io.EOF
+				p.rr.Release()
+			}
+			return
+		}
+		p.rec = p.rr.Record()
+	}
+	row := p.row
+	p.row++
+	p.cnt++
+
+	var tagName string
+	if p.tag >= 0 {
+		tagName = parquetString(p.rec.Column(p.tag), row)
+	}
+	ent = &entry.Entry{
+		Data: parquetBytes(p.rec.Column(p.data), row),
+	}
+	if ent.Tag, err = p.GetTag(tagName); err != nil {
+		ent, err = nil, fmt.Errorf("%v on row %d", err, p.cnt)
+		return
+	}
+	if p.ts >= 0 {
+		if col := p.rec.Column(p.ts).(*array.Timestamp); col.IsValid(row) {
+			ent.TS = entry.FromStandard(col.Value(row).ToTime(col.DataType().(*arrow.TimestampType).Unit))
+		}
+	}
+	if p.src >= 0 {
+		switch col := p.rec.Column(p.src).(type) {
+		case *array.Binary:
+			if col.IsValid(row) {
+				ent.SRC = net.IP(bytes.Clone(col.Value(row)))
+			}
+		default:
+			ent.SRC = net.ParseIP(parquetString(col, row))
+		}
+	}
+	if p.disableEVs {
+		return
+	}
+	for _, c := range p.evs {
+		col := p.rec.Column(c.idx)
+		if !col.IsValid(row) {
+			continue
+		}
+		var ev entry.EnumeratedValue
+		if ev, err = parquetEV(c, col, row); err != nil {
+			ent, err = nil, fmt.Errorf("invalid enumerated value %q on row %d: %w", c.name, p.cnt, err)
+			return
+		}
+		ent.AddEnumeratedValue(ev)
+	}
+	if p.evb >= 0 {
+		if b := parquetBytes(p.rec.Column(p.evb), row); len(b) > 0 {
+			var eb entry.EVBlock
+			if _, err = eb.Decode(b); err != nil {
+				ent, err = nil, fmt.Errorf("invalid enumerated values on row %d: %w", p.cnt, err)
+				return
+			}
+			for _, ev := range eb.Values() {
+				ent.AddEnumeratedValue(ev)
+			}
+		}
+	}
+	return
+}
+
+func parquetFieldMeta(f arrow.Field, key string) (string, bool) {
+	if idx := f.Metadata.FindKey(key); idx >= 0 {
+		return f.Metadata.Values()[idx], true
+	}
+	return ``, false
+}
+
+func parquetString(arr arrow.Array, row int) string {
+	if !arr.IsValid(row) {
+		return ``
+	}
+	switch a := arr.(type) {
+	case *array.String:
+		return a.Value(row)
+	case *array.LargeString:
+		return a.Value(row)
+	}
+	return arr.ValueStr(row)
+}
+
+func parquetBytes(arr arrow.Array, row int) []byte {
+	if !arr.IsValid(row) {
+		return nil
+	}
+	switch a := arr.(type) {
+	case *array.Binary:
+		return bytes.Clone(a.Value(row))
+	case *array.LargeBinary:
+		return bytes.Clone(a.Value(row))
+	}
+	return []byte(parquetString(arr, row))
+}
+
+// parquetEV converts a single column value to an enumerated value, honoring the declared type if there is one
+func parquetEV(c parquetEVCol, arr arrow.Array, row int) (ev entry.EnumeratedValue, err error) {
+	ev.Name = c.name
+	switch entry.EVTypeName(c.id) {
+	case `list`, `map`:
+		var cev entry.EnumeratedValue
+		if _, err = cev.Decode(parquetBytes(arr, row)); err == nil {
+			ev.Value = cev.Value
+		}
+		return
+	case `duration`:
+		if a, ok := arr.(*array.Int64); ok {
+			ev.Value = entry.DurationEnumData(time.Duration(a.Value(row)))
+			return
+		}
+	}
+	switch a := arr.(type) {
+	case *array.Boolean:
+		ev.Value = entry.BoolEnumData(a.Value(row))
+	case *array.Uint8:
+		ev.Value = entry.ByteEnumData(a.Value(row))
+	case *array.Int8:
+		ev.Value = entry.Int8EnumData(a.Value(row))
+	case *array.Int16:
+		ev.Value = entry.Int16EnumData(a.Value(row))
+	case *array.Uint16:
+		ev.Value = entry.Uint16EnumData(a.Value(row))
+	case *array.Int32:
+		ev.Value = entry.Int32EnumData(a.Value(row))
+	case *array.Uint32:
+		ev.Value = entry.Uint32EnumData(a.Value(row))
+	case *array.Int64:
+		ev.Value = entry.Int64EnumData(a.Value(row))
+	case *array.Uint64:
+		ev.Value = entry.Uint64EnumData(a.Value(row))
+	case *array.Float32:
+		ev.Value = entry.Float32EnumData(a.Value(row))
+	case *array.Float64:
+		ev.Value = entry.Float64EnumData(a.Value(row))
+	case *array.Timestamp:
+		ev.Value = entry.TSEnumData(entry.FromStandard(a.Value(row).ToTime(a.DataType().(*arrow.TimestampType).Unit)))
+	case *array.Binary, *array.LargeBinary:
+		ev.Value = entry.SliceEnumData(parquetBytes(a, row))
+	default:
+		ev.Value = entry.StringEnumData(parquetString(a, row))
+	}
+	//IPs and MACs are carried as strings, get them back to their native types
+	if c.id != 0 && ev.TypeID() != c.id {
+		if v, lerr := ev.Value.Coerce(c.id); lerr == nil {
+			ev.Value = v
+		}
+	}
+	return
+}
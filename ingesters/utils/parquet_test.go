@@ -0,0 +1,279 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/arrow-go/v18/parquet/file"
+	"github.com/apache/arrow-go/v18/parquet/pqarrow"
+	"github.com/gravwell/gravwell/v3/ingest/entry"
+)
+
+type testTags struct {
+	names []string
+}
+
+func (tt *testTags) OverrideTags(entry.EntryTag) {}
+
+func (tt *testTags) GetTag(v string) (entry.EntryTag, error) {
+	for i, n := range tt.names {
+		if n == v {
+			return entry.EntryTag(i), nil
+		}
+	}
+	tt.names = append(tt.names, v)
+	return entry.EntryTag(len(tt.names) - 1), nil
+}
+
+func (tt *testTags) LookupTag(tg entry.EntryTag) (string, bool) {
+	if int(tg) >= len(tt.names) {
+		return ``, false
+	}
+	return tt.names[tg], true
+}
+
+func TestParquetCycle(t *testing.T) {
+	tt := &testTags{names: []string{`default`, `foo`, `bar`}}
+	base := time.Date(2026, 3, 14, 15, 9, 26, 535897932, time.UTC)
+	var ents []*entry.Entry
+	for i := 0; i < 1000; i++ {
+		ent := &entry.Entry{
+			TS:   entry.FromStandard(base.Add(time.Duration(i) * time.Second)),
+			Tag:  entry.EntryTag(i % 3),
+			Data: []byte(fmt.Sprintf("entry %d", i)),
+		}
+		if i%2 == 0 {
+			ent.SRC = net.ParseIP(`10.0.0.1`).To4()
+		} else if i%5 == 0 {
+			ent.SRC = net.ParseIP(`fe80::1`)
+		}
+		ent.AddEnumeratedValueEx(`index`, i)
+		ent.AddEnumeratedValueEx(`port`, uint16(i))
+		ent.AddEnumeratedValueEx(`ratio`, float64(i)/3)
+		ent.AddEnumeratedValueEx(`name`, fmt.Sprintf("name%d", i))
+		ent.AddEnumeratedValueEx(`ok`, i%2 == 0)
+		ent.AddEnumeratedValueEx(`ip`, net.ParseIP(`192.168.1.1`).To4())
+		ent.AddEnumeratedValueEx(`mac`, net.HardwareAddr{0, 1, 2, 3, 4, byte(i)})
+		ent.AddEnumeratedValueEx(`when`, entry.FromStandard(base))
+		ent.AddEnumeratedValueEx(`took`, time.Duration(i)*time.Millisecond)
+		ent.AddEnumeratedValueEx(`raw`, []byte{1, 2, byte(i)})
+		ent.AddEnumeratedValueEx(`Data`, `collides with a fixed column`)
+		if i%7 == 0 {
+			//sparse values
+			ent.AddEnumeratedValueEx(`list`, []string{`a`, `b`})
+			ent.AddEnumeratedValueEx(`map`, map[string]interface{}{`x`: int64(i), `y`: `z`})
+		}
+		ents = append(ents, ent)
+	}
+	//mixed types fall back to strings
+	ents[0].AddEnumeratedValueEx(`mixed`, 1)
+	ents[1].AddEnumeratedValueEx(`mixed`, `one`)
+
+	var bb bytes.Buffer
+	pw, err := NewParquetWriter(&bb, tt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, ent := range ents {
+		if err = pw.WriteEntry(ent); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = pw.Close(); err != nil {
+		t.Fatal(err)
+	} else if err = pw.WriteEntry(ents[0]); err != ErrParquetWriterClosed {
+		t.Fatalf("failed to catch closed writer: %v", err)
+	}
+
+	//read back out through both a seekable reader and a stream
+	for _, rdr := range []io.Reader{bytes.NewReader(bb.Bytes()), io.MultiReader(bytes.NewReader(bb.Bytes()))} {
+		ir, err := GetImportReader(ParquetFormat, io.NopCloser(rdr), tt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, exp := range ents {
+			ent, err := ir.ReadEntry()
+			if err != nil {
+				t.Fatalf("%d: %v", i, err)
+			}
+			if err = compareParquetEntry(exp, ent); err != nil {
+				t.Fatalf("%d: %v", i, err)
+			}
+		}
+		if _, err = ir.ReadEntry(); err != io.EOF {
+			t.Fatalf("failed to get EOF: %v", err)
+		}
+	}
+}
+
+func compareParquetEntry(exp, ent *entry.Entry) error {
+	if !exp.TS.Equal(ent.TS) {
+		return fmt.Errorf("bad timestamp %v != %v", exp.TS, ent.TS)
+	} else if exp.Tag != ent.Tag {
+		return fmt.Errorf("bad tag %v != %v", exp.Tag, ent.Tag)
+	} else if !exp.SRC.Equal(ent.SRC) {
+		return fmt.Errorf("bad src %v != %v", exp.SRC, ent.SRC)
+	} else if !bytes.Equal(exp.Data, ent.Data) {
+		return fmt.Errorf("bad data %q != %q", exp.Data, ent.Data)
+	} else if exp.EVB.Count() != ent.EVB.Count() {
+		return fmt.Errorf("bad EV count %d != %d", exp.EVB.Count(), ent.EVB.Count())
+	}
+	for _, ev := range exp.EVB.Values() {
+		got, ok := ent.EVB.Get(ev.Name)
+		if !ok {
+			return fmt.Errorf("missing EV %q", ev.Name)
+		} else if ev.Name == `mixed` {
+			if got.Value.String() != ev.Value.String() {
+				return fmt.Errorf("bad mixed EV %v != %v", got.Value, ev.Value)
+			}
+		} else if err := ev.Compare(got); err != nil {
+			return fmt.Errorf("EV %q: %w", ev.Name, err)
+		}
+	}
+	return nil
+}
+
+func TestParquetRowGroups(t *testing.T) {
+	tt := &testTags{names: []string{`default`}}
+	var ents []*entry.Entry
+	for i := 0; i < 1000; i++ {
+		ent := &entry.Entry{
+			TS:   entry.Now(),
+			Data: []byte(fmt.Sprintf("entry %d", i)),
+		}
+		ent.AddEnumeratedValueEx(`index`, i)
+		if i >= 500 {
+			//names that first show up after the schema is fixed
+			ent.AddEnumeratedValueEx(`late`, uint32(i))
+		}
+		if i == 700 {
+			//so do type changes
+			ent.AddEnumeratedValueEx(`port`, `http`)
+		} else {
+			ent.AddEnumeratedValueEx(`port`, uint16(i))
+		}
+		ents = append(ents, ent)
+	}
+
+	var bb bytes.Buffer
+	pw, err := NewParquetWriter(&bb, tt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pw.maxRows = 100
+	for i, ent := range ents {
+		if err = pw.WriteEntry(ent); err != nil {
+			t.Fatal(err)
+		} else if len(pw.ents) != (i+1)%100 {
+			t.Fatalf("entries were not flushed: %d queued after %d", len(pw.ents), i+1)
+		}
+	}
+	//a small size limit flushes every entry
+	pw.maxSize = 1
+	if err = pw.WriteEntry(ents[0]); err != nil {
+		t.Fatal(err)
+	} else if len(pw.ents) != 0 {
+		t.Fatalf("entries were not flushed on size: %d", len(pw.ents))
+	}
+	if err = pw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	ents = append(ents, ents[0])
+
+	pf, err := file.NewParquetReader(bytes.NewReader(bb.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	} else if n := pf.NumRowGroups(); n != 11 {
+		t.Fatalf("bad row group count: %d", n)
+	}
+	ir, err := NewParquetReader(bytes.NewReader(bb.Bytes()), tt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, exp := range ents {
+		ent, err := ir.ReadEntry()
+		if err != nil {
+			t.Fatalf("%d: %v", i, err)
+		} else if err = compareParquetEntry(exp, ent); err != nil {
+			t.Fatalf("%d: %v", i, err)
+		}
+	}
+	if _, err = ir.ReadEntry(); err != io.EOF {
+		t.Fatalf("failed to get EOF: %v", err)
+	}
+}
+
+func TestParquetForeign(t *testing.T) {
+	//files produced by other tools won't have our metadata and may skip columns
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: `Data`, Type: arrow.BinaryTypes.String},
+		{Name: `TS`, Type: &arrow.TimestampType{Unit: arrow.Millisecond}},
+		{Name: `count`, Type: arrow.PrimitiveTypes.Int64, Nullable: true},
+		{Name: `day`, Type: arrow.FixedWidthTypes.Date32},
+	}, nil)
+	bldr := array.NewRecordBuilder(memory.DefaultAllocator, schema)
+	defer bldr.Release()
+	ts := time.Date(2026, 1, 2, 3, 4, 5, 6000000, time.UTC)
+	bldr.Field(0).(*array.StringBuilder).AppendValues([]string{`a`, `b`}, nil)
+	bldr.Field(1).(*array.TimestampBuilder).AppendValues([]arrow.Timestamp{arrow.Timestamp(ts.UnixMilli()), 0}, nil)
+	bldr.Field(2).(*array.Int64Builder).AppendValues([]int64{5, 0}, []bool{true, false})
+	bldr.Field(3).(*array.Date32Builder).AppendValues([]arrow.Date32{arrow.Date32FromTime(ts), 0}, nil)
+	rec := bldr.NewRecord()
+	defer rec.Release()
+	tbl := array.NewTableFromRecords(schema, []arrow.Record{rec})
+	defer tbl.Release()
+	var bb bytes.Buffer
+	if err := pqarrow.WriteTable(tbl, &bb, 1024, nil, pqarrow.DefaultWriterProps()); err != nil {
+		t.Fatal(err)
+	}
+
+	tt := &testTags{}
+	pr, err := NewParquetReader(bytes.NewReader(bb.Bytes()), tt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ent, err := pr.ReadEntry()
+	if err != nil {
+		t.Fatal(err)
+	} else if string(ent.Data) != `a` || !ent.TS.StandardTime().Equal(ts) {
+		t.Fatalf("bad entry: %v %q", ent.TS, ent.Data)
+	} else if ev, ok := ent.EVB.Get(`count`); !ok || ev.Value.Interface() != int64(5) {
+		t.Fatalf("bad count EV: %v", ev)
+	} else if ev, ok = ent.EVB.Get(`day`); !ok || ev.Value.String() != `2026-01-02` {
+		t.Fatalf("bad day EV: %v", ev)
+	}
+	pr.DisableEVs()
+	if ent, err = pr.ReadEntry(); err != nil {
+		t.Fatal(err)
+	} else if string(ent.Data) != `b` || ent.EVB.Populated() {
+		t.Fatalf("bad entry: %q %d", ent.Data, ent.EVB.Count())
+	} else if _, err = pr.ReadEntry(); err != io.EOF {
+		t.Fatalf("failed to get EOF: %v", err)
+	}
+
+	//Data is required
+	bb.Reset()
+	schema = arrow.NewSchema([]arrow.Field{{Name: `foo`, Type: arrow.BinaryTypes.String}}, nil)
+	tbl = array.NewTableFromSlice(schema, [][]arrow.Array{{}})
+	defer tbl.Release()
+	if err = pqarrow.WriteTable(tbl, &bb, 1024, nil, pqarrow.DefaultWriterProps()); err != nil {
+		t.Fatal(err)
+	} else if _, err = NewParquetReader(bytes.NewReader(bb.Bytes()), tt); err != ErrMissingDataColumn {
+		t.Fatalf("failed to catch missing data column: %v", err)
+	}
+}
@@ -258,6 +258,10 @@ func GetImportReader(format string, fin io.ReadCloser, th TagHandler) (ir Reimpo
 		if ir, err = NewJSONReader(fin, th); err != nil {
 			err = fmt.Errorf("Failed to make JSON reader: %v\n", err)
 		}
+	case ParquetFormat:
+		if ir, err = NewParquetReader(fin, th); err != nil {
+			err = fmt.Errorf("Failed to make Parquet reader: %v\n", err)
+		}
 	default:
 		err = fmt.Errorf("Invalid format %v\n", format)
 	}
@@ -278,6 +282,10 @@ func GetImportFormat(override, fp string) (format string, err error) {
 		fallthrough
 	case CsvFormat:
 		format = CsvFormat
+	case `.parquet`:
+		fallthrough
+	case ParquetFormat:
+		format = ParquetFormat
 	default:
 		err = fmt.Errorf("Failed to determine input format")
 	}
@@ -29,6 +29,8 @@ type StatsItem struct {
 	name string
 	last uint64
 	curr uint64
+	fn   func() uint64 // optional cumulative counter source
+	base uint64        // value of fn at the last tick
 }
 
 type StatsManager struct {
@@ -105,6 +107,22 @@ func (sm *StatsManager) RegisterItem(name string) (si *StatsItem, err error) {
 	return
 }
 
+// RegisterCounter registers a stats item whose value is pulled from a cumulative counter, each tick
+// reports the change in the counter since the previous tick
+func (sm *StatsManager) RegisterCounter(name string, fn func() uint64) (err error) {
+	if fn == nil {
+		return errors.New("nil counter function")
+	}
+	var si *StatsItem
+	if si, err = sm.RegisterItem(name); err == nil {
+		sm.Lock()
+		si.fn = fn
+		si.base = fn()
+		sm.Unlock()
+	}
+	return
+}
+
 func (sm *StatsManager) routine() {
 	defer sm.wg.Done()
 	if sm.interval <= 0 {
@@ -157,7 +175,12 @@ func (si *StatsItem) Add(v uint64) {
 }
 
 func (si *StatsItem) reset() (curr uint64) {
-	if si != nil {
+	if si != nil && si.fn != nil {
+		v := si.fn()
+		si.last = v - si.base
+		si.base = v
+		curr = si.last
+	} else if si != nil {
 		//reset and
 		si.last = atomic.SwapUint64(&si.curr, 0)
 		curr = si.last // this could theoretically race, but reset should be controlled by a ticker, so not a huge worry
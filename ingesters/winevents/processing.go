@@ -311,6 +311,7 @@ func (m *mainService) init() error {
 		}
 		tcfg := timegrinder.Config{
 			TSWindow: window,
+			Locale:   m.cfg.Global.Timestamp_Locale,
 		}
 		tg, err := timegrinder.NewTimeGrinder(tcfg)
 		if err != nil {
@@ -414,6 +415,7 @@ func (m *mainService) initStream(c winevent.EventStreamParams, errReport bool) (
 		//failing to create the preprocessor set is fatal
 		return eventSrc{}, true, fmt.Errorf("Preprocessor construction error: %v", err)
 	}
+	m.igst.RegisterPreprocessorStats(c.Name, pproc)
 
 	var evt *winevent.EventStreamHandle
 	if evt, err = winevent.NewStream(c, last); err != nil {
@@ -88,6 +88,7 @@ func fileJob(cfgName string, ctx context.Context, uc chan string) (err error) {
 	if !val.Ignore_Timestamps {
 		tcfg := timegrinder.Config{
 			EnableLeftMostSeed: true,
+			Locale:             cfg.Timestamp_Locale,
 		}
 		if tg, err = timegrinder.NewTimeGrinder(tcfg); err != nil {
 			lg.Error("failed to create timegrinder", log.KVErr(err))
@@ -0,0 +1,247 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package timegrinder
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gravwell/jsonparser"
+)
+
+type anchorType int
+
+const (
+	jsonAnchor anchorType = iota
+	csvAnchor
+	kvAnchor
+)
+
+var (
+	ErrInvalidAnchor = errors.New("invalid timestamp anchor, expected json:<path>, csv:<column>, tsv:<column>, or kv:<key>")
+)
+
+// anchor locates the region of a payload that holds the timestamp so that the processors
+// only search that region.  Anchors are specified as:
+//
+//	json:<path>    a dotted JSON path, e.g. json:meta."event.time" or json:events.[0].ts
+//	csv:<column>   a zero based column in comma separated data
+//	tsv:<column>   a zero based column in tab separated data
+//	kv:<key>       the value of a key=value pair, e.g. kv:ts
+type anchor struct {
+	typ    anchorType
+	spec   string
+	keys   []string
+	column int
+	delim  byte
+	key    []byte
+}
+
+// ValidateAnchor checks that a timestamp anchor specification is valid, an empty
+// specification disables anchoring.
+func ValidateAnchor(spec string) error {
+	_, err := parseAnchor(spec)
+	return err
+}
+
+func parseAnchor(spec string) (a *anchor, err error) {
+	if spec = strings.TrimSpace(spec); spec == `` {
+		return
+	}
+	kind, arg, ok := strings.Cut(spec, `:`)
+	if !ok || arg == `` {
+		err = ErrInvalidAnchor
+		return
+	}
+	a = &anchor{spec: spec}
+	switch strings.ToLower(kind) {
+	case `json`:
+		a.typ = jsonAnchor
+		if a.keys, err = splitAnchorPath(arg); err != nil {
+			a = nil
+		}
+	case `csv`, `tsv`:
+		a.typ, a.delim = csvAnchor, ','
+		if strings.ToLower(kind) == `tsv` {
+			a.delim = '\t'
+		}
+		if a.column, err = strconv.Atoi(arg); err != nil || a.column < 0 {
+			a, err = nil, fmt.Errorf("invalid timestamp anchor column %q", arg)
+		}
+	case `kv`:
+		a.typ, a.key = kvAnchor, []byte(arg)
+		if strings.ContainsAny(arg, " \t=\"") {
+			a, err = nil, fmt.Errorf("invalid timestamp anchor key %q", arg)
+		}
+	default:
+		a, err = nil, ErrInvalidAnchor
+	}
+	return
+}
+
+// splitAnchorPath splits a dotted JSON path, members containing dots may be quoted
+func splitAnchorPath(p string) (keys []string, err error) {
+	var cur strings.Builder
+	var quoted bool
+	for i := 0; i < len(p); i++ {
+		switch c := p[i]; {
+		case c == '"':
+			quoted = !quoted
+		case c == '.' && !quoted:
+			if cur.Len() == 0 {
+				return nil, fmt.Errorf("empty member in timestamp anchor path %q", p)
+			}
+			keys = append(keys, cur.String())
+			cur.Reset()
+		default:
+			cur.WriteByte(c)
+		}
+	}
+	if quoted {
+		return nil, fmt.Errorf("unterminated quote in timestamp anchor path %q", p)
+	} else if cur.Len() == 0 {
+		return nil, fmt.Errorf("empty member in timestamp anchor path %q", p)
+	}
+	keys = append(keys, cur.String())
+	return
+}
+
+// locate returns the region of d that holds the timestamp and its offset within d
+func (a *anchor) locate(d []byte) (off int, r []byte, ok bool) {
+	var end int
+	switch a.typ {
+	case jsonAnchor:
+		off, end, ok = jsonRegion(d, a.keys)
+	case csvAnchor:
+		off, end, ok = csvRegion(d, a.column, a.delim)
+	case kvAnchor:
+		off, end, ok = kvRegion(d, a.key)
+	}
+	if ok {
+		r = d[off:end]
+	}
+	return
+}
+
+func jsonRegion(d []byte, keys []string) (start, end int, ok bool) {
+	v, vt, end, err := jsonparser.Get(d, keys...)
+	if err != nil || vt == jsonparser.Null || vt == jsonparser.NotExist {
+		return
+	}
+	start = end - len(v)
+	if vt == jsonparser.String {
+		start, end = start-1, end-1 //strip the quotes
+	}
+	ok = true
+	return
+}
+
+// csvRegion finds a column in the first record of d, quoted fields return the quoted contents
+func csvRegion(d []byte, col int, delim byte) (start, end int, ok bool) {
+	var i, idx int
+	for i <= len(d) {
+		start = i
+		quoted := i < len(d) && d[i] == '"'
+		if quoted {
+			start++
+			for i = start; i < len(d); i++ {
+				if d[i] == '"' {
+					if i+1 < len(d) && d[i+1] == '"' {
+						i++ //escaped quote
+						continue
+					}
+					break
+				}
+			}
+			if i >= len(d) {
+				return //unterminated quote
+			}
+			end = i
+			i++
+		}
+		for ; i < len(d) && d[i] != delim && d[i] != '\n'; i++ {
+		}
+		if !quoted {
+			end = i
+			if end > start && d[end-1] == '\r' {
+				end--
+			}
+		}
+		if idx == col {
+			ok = end > start
+			return
+		}
+		if i >= len(d) || d[i] == '\n' {
+			return
+		}
+		i++
+		idx++
+	}
+	return
+}
+
+// kvRegion finds the value of key in key=value data.  Quoted values return the quoted contents,
+// unquoted values run up to the next key=value pair so that timestamps may contain spaces.
+func kvRegion(d, key []byte) (start, end int, ok bool) {
+	for off := 0; off < len(d); {
+		idx := bytes.Index(d[off:], key)
+		if idx < 0 {
+			return
+		}
+		idx += off
+		off = idx + len(key)
+		if (idx > 0 && !isKVSeparator(d[idx-1])) || off >= len(d) || d[off] != '=' {
+			continue
+		}
+		start = off + 1
+		if start < len(d) && (d[start] == '"' || d[start] == '\'') {
+			q := d[start]
+			start++
+			if end = bytes.IndexByte(d[start:], q); end < 0 {
+				return
+			}
+			end += start
+		} else {
+			end = nextKVPair(d, start)
+		}
+		ok = end > start
+		return
+	}
+	return
+}
+
+func isKVSeparator(c byte) bool {
+	return c == ' ' || c == '\t' || c == ',' || c == ';' || c == '|' || c == '\n'
+}
+
+// nextKVPair returns the offset of the separator ahead of the next key=value pair
+func nextKVPair(d []byte, i int) int {
+	for ; i < len(d); i++ {
+		if c := d[i]; c == '\n' || c == '\r' {
+			return i
+		} else if !isKVSeparator(c) {
+			continue
+		}
+		j := i
+		for j < len(d) && isKVSeparator(d[j]) {
+			j++
+		}
+		k := j
+		for k < len(d) && !isKVSeparator(d[k]) && d[k] != '=' && d[k] != '"' {
+			k++
+		}
+		if k > j && k < len(d) && d[k] == '=' {
+			return i
+		}
+	}
+	return len(d)
+}
@@ -0,0 +1,107 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package timegrinder
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAnchorSpecs(t *testing.T) {
+	good := []string{``, `json:ts`, `json:meta."event.time"`, `json:events.[0].ts`, `csv:0`, `CSV:12`, `tsv:3`, `kv:ts`}
+	for _, v := range good {
+		if err := ValidateAnchor(v); err != nil {
+			t.Fatalf("%q: %v", v, err)
+		}
+	}
+	bad := []string{`ts`, `json:`, `json:a..b`, `json:"a.b`, `csv:-1`, `csv:foo`, `kv:a b`, `kv:a=b`, `xml:ts`}
+	for _, v := range bad {
+		if err := ValidateAnchor(v); err == nil {
+			t.Fatalf("failed to catch bad anchor %q", v)
+		}
+	}
+	if _, err := New(Config{Anchor: `xml:ts`}); err == nil {
+		t.Fatal("failed to catch bad anchor in config")
+	}
+	if keys, err := splitAnchorPath(`meta."event.time".x`); err != nil {
+		t.Fatal(err)
+	} else if strings.Join(keys, `|`) != `meta|event.time|x` {
+		t.Fatalf("bad path split: %v", keys)
+	}
+}
+
+func TestAnchorExtract(t *testing.T) {
+	first := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+	exp := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+	tests := []struct {
+		anchor string
+		data   string
+		ts     string // the timestamp as it appears in data
+	}{
+		{`json:event.time`, `{"received":"2023-01-02T03:04:05Z","event":{"id":1,"time":"2024-05-06T07:08:09Z"}}`, `2024-05-06T07:08:09Z`},
+		{`json:"event.time"`, `{"received":"2023-01-02T03:04:05Z","event.time":"2024-05-06T07:08:09Z"}`, `2024-05-06T07:08:09Z`},
+		{`json:times.[1]`, `{"times":["2023-01-02T03:04:05Z", 1714979289]}`, `1714979289`},
+		{`csv:2`, `2023-01-02 03:04:05,"bob, smith","Mon, 06 May 2024 07:08:09 +0000",ok`, `06 May 2024 07:08:09 +0000`},
+		{`tsv:1`, "2023-01-02 03:04:05\t2024-05-06 07:08:09\tdone", `2024-05-06 07:08:09`},
+		{`kv:ts`, `rcvd=2023-01-02T03:04:05Z ts=2024-05-06 07:08:09 user=bob`, `2024-05-06 07:08:09`},
+		{`kv:ts`, `last_ts=2023-01-02T03:04:05Z, ts="May  6 07:08:09 2024", user=bob`, `May  6 07:08:09 2024`},
+	}
+	for _, tt := range tests {
+		tg, err := New(Config{Anchor: tt.anchor})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ts, ok, err := tg.Extract([]byte(tt.data))
+		if err != nil || !ok {
+			t.Fatalf("%s: failed to extract from %q: %v", tt.anchor, tt.data, err)
+		} else if !ts.Equal(exp) {
+			t.Fatalf("%s: bad timestamp %v != %v", tt.anchor, ts, exp)
+		}
+		start, end, ok := tg.Match([]byte(tt.data))
+		if !ok {
+			t.Fatalf("%s: failed to match", tt.anchor)
+		} else if r := tt.data[start:end]; !strings.HasPrefix(r, tt.ts) {
+			t.Fatalf("%s: bad match offsets %q != %q", tt.anchor, r, tt.ts)
+		}
+
+		//without the anchor the leftmost timestamp wins
+		if tg, err = New(Config{EnableLeftMostSeed: true}); err != nil {
+			t.Fatal(err)
+		} else if ts, ok, _ = tg.Extract([]byte(tt.data)); !ok || !ts.Equal(first) {
+			t.Fatalf("%s: unanchored extraction did not find the first timestamp: %v", tt.anchor, ts)
+		}
+	}
+
+	//missing fields produce no timestamp
+	tg, err := New(Config{Anchor: `json:missing`})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := []byte(`{"ts":"2024-05-06T07:08:09Z"}`)
+	if _, ok, _ := tg.Extract(data); ok {
+		t.Fatal("extracted timestamp from a missing field")
+	} else if _, off, _, _ := tg.DebugExtract(data); off != -1 {
+		t.Fatalf("bad debug offset on a missing field: %d", off)
+	}
+}
+
+func TestAnchorLocale(t *testing.T) {
+	tg, err := New(Config{Anchor: `csv:1`, Locale: `de`})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := `Mär 1 01:02:03 2023,"12 Mär 2024 10:11:12 +0100",Mai`
+	if ts, ok, _ := tg.Extract([]byte(data)); !ok || !ts.Equal(time.Date(2024, 3, 12, 9, 11, 12, 0, time.UTC)) {
+		t.Fatalf("bad timestamp %v", ts)
+	}
+	if _, off, _, _ := tg.DebugExtract([]byte(data)); off != strings.Index(data, `12 Mär`) {
+		t.Fatalf("bad debug offset %d", off)
+	}
+}
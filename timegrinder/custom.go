@@ -37,6 +37,10 @@ type CustomFormat struct {
 	// optional pre-extraction system that can go get the meat of a timestamp before actually trying to handle the timestamp
 	Extraction_Regex string
 
+	// optional strftime or Java DateTimeFormatter patterns, the Regex and Format are generated from the pattern
+	Strftime_Format string
+	Java_Format     string
+
 	dateMissing bool // indicates that the extraction only gets time, so add date
 	yearMissing bool // indicates that the extraction doesn't set the year
 
@@ -45,6 +49,7 @@ type CustomFormat struct {
 
 // Validate will check that the custom format is well formed and usable
 // we require a name, extraction regex, and time decoding format.
+// If a strftime or Java pattern is provided the regex and format are generated from it.
 // We will attempt to compile the regex and will also try to encode and decode
 // the timeformat.  The time format must be capable of encoding and decoding.
 // Validate will also detect if we are missing a date so that extractions will compensate
@@ -52,6 +57,8 @@ func (cf *CustomFormat) Validate() (err error) {
 	//check that everything is specified
 	if cf.Name == `` {
 		return ErrMissingName
+	} else if err = cf.expandPattern(); err != nil {
+		return
 	} else if cf.Format == `` {
 		return ErrMissingFormat
 	}
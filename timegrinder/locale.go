@@ -0,0 +1,369 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package timegrinder
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	maxLocaleWordLen = 32
+)
+
+// localeData lists the month and weekday names of a locale, each entry is a space separated
+// list of alternate spellings.  Weekdays start with Sunday to match time.Weekday.
+type localeData struct {
+	months      [12]string
+	shortMonths [12]string
+	days        [7]string
+	shortDays   [7]string
+}
+
+var locales = map[string]localeData{
+	`de`: {
+		months: [12]string{`Januar Jänner`, `Februar Feber`, `März`, `April`, `Mai`, `Juni`,
+			`Juli`, `August`, `September`, `Oktober`, `November`, `Dezember`},
+		shortMonths: [12]string{`Jan Jän`, `Feb`, `Mär Mrz`, `Apr`, `Mai`, `Jun`,
+			`Jul`, `Aug`, `Sep Sept`, `Okt`, `Nov`, `Dez`},
+		days:      [7]string{`Sonntag`, `Montag`, `Dienstag`, `Mittwoch`, `Donnerstag`, `Freitag`, `Samstag Sonnabend`},
+		shortDays: [7]string{`So`, `Mo`, `Di`, `Mi`, `Do`, `Fr`, `Sa`},
+	},
+	`fr`: {
+		months: [12]string{`janvier`, `février`, `mars`, `avril`, `mai`, `juin`,
+			`juillet`, `août`, `septembre`, `octobre`, `novembre`, `décembre`},
+		shortMonths: [12]string{`janv jan`, `févr fév`, `mars`, `avr`, `mai`, `juin`,
+			`juil`, `août`, `sept`, `oct`, `nov`, `déc`},
+		days:      [7]string{`dimanche`, `lundi`, `mardi`, `mercredi`, `jeudi`, `vendredi`, `samedi`},
+		shortDays: [7]string{`dim`, `lun`, `mar`, `mer`, `jeu`, `ven`, `sam`},
+	},
+	`es`: {
+		months: [12]string{`enero`, `febrero`, `marzo`, `abril`, `mayo`, `junio`,
+			`julio`, `agosto`, `septiembre setiembre`, `octubre`, `noviembre`, `diciembre`},
+		shortMonths: [12]string{`ene`, `feb`, `mar`, `abr`, `may`, `jun`,
+			`jul`, `ago`, `sep sept set`, `oct`, `nov`, `dic`},
+		days:      [7]string{`domingo`, `lunes`, `martes`, `miércoles`, `jueves`, `viernes`, `sábado`},
+		shortDays: [7]string{`dom`, `lun`, `mar`, `mié`, `jue`, `vie`, `sáb`},
+	},
+	`it`: {
+		months: [12]string{`gennaio`, `febbraio`, `marzo`, `aprile`, `maggio`, `giugno`,
+			`luglio`, `agosto`, `settembre`, `ottobre`, `novembre`, `dicembre`},
+		shortMonths: [12]string{`gen`, `feb`, `mar`, `apr`, `mag`, `giu`,
+			`lug`, `ago`, `set`, `ott`, `nov`, `dic`},
+		days:      [7]string{`domenica`, `lunedì`, `martedì`, `mercoledì`, `giovedì`, `venerdì`, `sabato`},
+		shortDays: [7]string{`dom`, `lun`, `mar`, `mer`, `gio`, `ven`, `sab`},
+	},
+	`pt`: {
+		months: [12]string{`janeiro`, `fevereiro`, `março`, `abril`, `maio`, `junho`,
+			`julho`, `agosto`, `setembro`, `outubro`, `novembro`, `dezembro`},
+		shortMonths: [12]string{`jan`, `fev`, `mar`, `abr`, `mai`, `jun`,
+			`jul`, `ago`, `set`, `out`, `nov`, `dez`},
+		days: [7]string{`domingo`, `segunda-feira segunda`, `terça-feira terça`, `quarta-feira quarta`,
+			`quinta-feira quinta`, `sexta-feira sexta`, `sábado`},
+		shortDays: [7]string{`dom`, `seg`, `ter`, `qua`, `qui`, `sex`, `sáb`},
+	},
+	`nl`: {
+		months: [12]string{`januari`, `februari`, `maart`, `april`, `mei`, `juni`,
+			`juli`, `augustus`, `september`, `oktober`, `november`, `december`},
+		shortMonths: [12]string{`jan`, `feb`, `mrt`, `apr`, `mei`, `jun`,
+			`jul`, `aug`, `sep sept`, `okt`, `nov`, `dec`},
+		days:      [7]string{`zondag`, `maandag`, `dinsdag`, `woensdag`, `donderdag`, `vrijdag`, `zaterdag`},
+		shortDays: [7]string{`zo`, `ma`, `di`, `wo`, `do`, `vr`, `za`},
+	},
+	`ca`: {
+		months: [12]string{`gener`, `febrer`, `març`, `abril`, `maig`, `juny`,
+			`juliol`, `agost`, `setembre`, `octubre`, `novembre`, `desembre`},
+		shortMonths: [12]string{`gen`, `febr feb`, `març`, `abr`, `maig`, `juny`,
+			`jul`, `ag ago`, `set`, `oct`, `nov`, `des`},
+		days:      [7]string{`diumenge`, `dilluns`, `dimarts`, `dimecres`, `dijous`, `divendres`, `dissabte`},
+		shortDays: [7]string{`dg`, `dl`, `dt`, `dc`, `dj`, `dv`, `ds`},
+	},
+}
+
+// localeName describes how a localized word maps onto English month and weekday names
+type localeName struct {
+	month      time.Month // zero if the word is not a month
+	monthShort bool
+	day        time.Weekday
+	isDay      bool
+	dayShort   bool
+}
+
+// englishNames holds the English month and weekday names as they are written in English timestamps,
+// capitalized or upper case, in full and abbreviated
+var englishNames = map[string]localeName{}
+
+func init() {
+	add := func(name string, ln localeName) {
+		englishNames[name] = ln
+		englishNames[strings.ToUpper(name)] = ln
+	}
+	for m := time.January; m <= time.December; m++ {
+		add(m.String(), localeName{month: m})
+		add(m.String()[:3], localeName{month: m, monthShort: true})
+	}
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		add(d.String(), localeName{day: d, isDay: true})
+		add(d.String()[:3], localeName{day: d, isDay: true, dayShort: true})
+	}
+}
+
+// agrees returns true if the localized word can carry the meaning of the English name en
+func (ln localeName) agrees(en localeName) bool {
+	if en.isDay {
+		return ln.isDay && ln.day == en.day
+	}
+	return ln.month == en.month
+}
+
+// locale is a lookup table of localized month and weekday names
+type locale struct {
+	name   string
+	names  map[string]localeName
+	minLen int
+	maxLen int
+}
+
+// Locales returns the sorted list of supported locale names
+func Locales() (r []string) {
+	for k := range locales {
+		r = append(r, k)
+	}
+	sort.Strings(r)
+	return
+}
+
+// ValidateLocale checks that a locale name is supported.  Names may carry a region and
+// encoding (e.g. de_AT.UTF-8), an empty name or English disables localization.
+func ValidateLocale(name string) error {
+	_, err := getLocale(name)
+	return err
+}
+
+func getLocale(name string) (l *locale, err error) {
+	lang := strings.ToLower(strings.TrimSpace(name))
+	if lang == `` {
+		return //english is handled natively
+	}
+	if i := strings.IndexByte(lang, '.'); i >= 0 {
+		lang = lang[:i]
+	}
+	if i := strings.IndexAny(lang, `_-`); i >= 0 {
+		lang = lang[:i]
+	}
+	if lang == `en` || lang == `c` || lang == `posix` {
+		return //english is handled natively
+	}
+	ld, ok := locales[lang]
+	if !ok {
+		err = fmt.Errorf("unsupported timestamp locale %q", name)
+		return
+	}
+	l = newLocale(lang, ld)
+	return
+}
+
+func newLocale(name string, ld localeData) *locale {
+	l := &locale{
+		name:   name,
+		names:  map[string]localeName{},
+		minLen: maxLocaleWordLen,
+	}
+	//full names go in first so that words which double as an abbreviation are treated as one
+	for i := range ld.months {
+		m := time.Month(i + 1)
+		l.add(ld.months[i], func(ln *localeName) { ln.month, ln.monthShort = m, false })
+		l.add(ld.shortMonths[i], func(ln *localeName) { ln.month, ln.monthShort = m, true })
+	}
+	for i := range ld.days {
+		d := time.Weekday(i)
+		l.add(ld.days[i], func(ln *localeName) { ln.day, ln.isDay, ln.dayShort = d, true, false })
+		l.add(ld.shortDays[i], func(ln *localeName) { ln.day, ln.isDay, ln.dayShort = d, true, true })
+	}
+	return l
+}
+
+func (l *locale) add(words string, set func(*localeName)) {
+	for _, w := range strings.Fields(words) {
+		for _, v := range []string{strings.ToLower(w), stripAccents(strings.ToLower(w))} {
+			ln := l.names[v]
+			set(&ln)
+			l.names[v] = ln
+			if len(v) < l.minLen {
+				l.minLen = len(v)
+			}
+			if len(v) > l.maxLen {
+				l.maxLen = len(v)
+			}
+		}
+	}
+}
+
+// lookup finds a word regardless of case without allocating
+func (l *locale) lookup(w []byte) (ln localeName, ok bool) {
+	if len(w) < l.minLen || len(w) > l.maxLen {
+		return
+	}
+	var scratch [maxLocaleWordLen * 2]byte
+	b := scratch[:0]
+	for _, r := range string(w) {
+		b = utf8.AppendRune(b, unicode.ToLower(r))
+	}
+	ln, ok = l.names[string(b)]
+	return
+}
+
+// english returns the English name for the word, next is the byte following the word.
+// Words that are both a month and a weekday are treated as a weekday only when followed by
+// a comma, e.g. "mar, 12 mar 2024".
+func (ln localeName) english(next byte) (name string, short bool) {
+	if ln.month != 0 && (!ln.isDay || next != ',') {
+		if name, short = ln.month.String(), ln.monthShort; short {
+			name = name[:3]
+		}
+	} else if name, short = ln.day.String(), ln.dayShort; short {
+		name = name[:3]
+	}
+	return
+}
+
+type localeSpan struct {
+	orig, origEnd int
+	norm, normEnd int
+}
+
+// localizer rewrites localized month and weekday names into English so that the processors
+// can parse them, the rewritten spans are tracked so offsets can be mapped back.
+type localizer struct {
+	loc   *locale
+	buf   []byte
+	spans []localeSpan
+}
+
+// normalize returns d with any localized names rewritten, if nothing was rewritten d is
+// returned as is.  The returned buffer is only valid until the next call.
+func (lz *localizer) normalize(d []byte) []byte {
+	lz.buf = lz.buf[:0]
+	lz.spans = lz.spans[:0]
+	var last int
+	for i := 0; i < len(d); {
+		if !isLetterAt(d, i) {
+			i++
+			continue
+		}
+		start := i
+		end := wordEnd(d, i, false)
+		i = end
+		ln, ok := lz.loc.lookup(d[start:end])
+		if hend := wordEnd(d, start, true); hend > end {
+			//hyphenated names like segunda-feira
+			if hln, hok := lz.loc.lookup(d[start:hend]); hok {
+				ln, ok, end = hln, hok, hend
+				i = end
+			}
+		}
+		if !ok {
+			continue
+		} else if en, isEn := englishNames[string(d[start:end])]; isEn && !ln.agrees(en) {
+			continue //English is always understood, e.g. Mar is March and not the French mardi
+		}
+		var next byte
+		if n := end; n < len(d) {
+			if d[n] == '.' && n+1 < len(d) {
+				n++
+			}
+			next = d[n]
+		}
+		name, short := ln.english(next)
+		if short && end < len(d) && d[end] == '.' {
+			end++ //abbreviations such as janv. carry a trailing period
+			i = end
+		}
+		lz.buf = append(lz.buf, d[last:start]...)
+		sp := localeSpan{orig: start, origEnd: end, norm: len(lz.buf)}
+		lz.buf = append(lz.buf, name...)
+		sp.normEnd = len(lz.buf)
+		lz.spans = append(lz.spans, sp)
+		last = end
+	}
+	if len(lz.spans) == 0 {
+		return d
+	}
+	lz.buf = append(lz.buf, d[last:]...)
+	return lz.buf
+}
+
+// offset maps an offset in the most recently normalized buffer back to the original data
+func (lz *localizer) offset(n int) int {
+	if n < 0 {
+		return n
+	}
+	var delta int
+	for _, sp := range lz.spans {
+		if n < sp.norm {
+			break
+		} else if n < sp.normEnd {
+			if n == sp.norm {
+				return sp.orig
+			}
+			return sp.origEnd
+		}
+		delta = sp.origEnd - sp.normEnd
+	}
+	return n + delta
+}
+
+func isLetterAt(d []byte, i int) bool {
+	if c := d[i]; c < utf8.RuneSelf {
+		return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+	}
+	r, _ := utf8.DecodeRune(d[i:])
+	return unicode.IsLetter(r)
+}
+
+// wordEnd returns the end of the run of letters starting at i, optionally joining hyphenated words
+func wordEnd(d []byte, i int, hyphens bool) int {
+	for i < len(d) {
+		if isLetterAt(d, i) {
+			_, sz := utf8.DecodeRune(d[i:])
+			i += sz
+		} else if hyphens && d[i] == '-' && i+1 < len(d) && isLetterAt(d, i+1) {
+			i++
+		} else {
+			break
+		}
+	}
+	return i
+}
+
+func stripAccents(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case 'á', 'à', 'â', 'ä', 'ã', 'å':
+			return 'a'
+		case 'é', 'è', 'ê', 'ë':
+			return 'e'
+		case 'í', 'ì', 'î', 'ï':
+			return 'i'
+		case 'ó', 'ò', 'ô', 'ö', 'õ':
+			return 'o'
+		case 'ú', 'ù', 'û', 'ü':
+			return 'u'
+		case 'ç':
+			return 'c'
+		case 'ñ':
+			return 'n'
+		}
+		return r
+	}, s)
+}
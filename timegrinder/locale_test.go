@@ -0,0 +1,168 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package timegrinder
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLocaleNames(t *testing.T) {
+	for _, v := range []string{``, `en`, `en_US.UTF-8`, `C`, `de`, `de_AT.UTF-8`, `fr-CA`, `es_MX`, `es_419`, `pt_BR`, `it`, `nl_BE`, `ca`} {
+		if err := ValidateLocale(v); err != nil {
+			t.Fatalf("%q: %v", v, err)
+		}
+	}
+	for _, v := range []string{`xx`, `klingon`, `_DE`} {
+		if err := ValidateLocale(v); err == nil {
+			t.Fatalf("failed to catch bad locale %q", v)
+		}
+	}
+	if _, err := New(Config{Locale: `xx`}); err == nil {
+		t.Fatal("failed to catch bad locale in config")
+	}
+	if len(Locales()) != len(locales) {
+		t.Fatal("bad locale list")
+	}
+}
+
+func TestLocaleNormalize(t *testing.T) {
+	tests := []struct {
+		locale string
+		in     string
+		out    string
+	}{
+		{`de`, `12 MÄRZ 2024 Mär Mrz Marz`, `12 March 2024 Mar Mar March`},
+		{`de`, `Mo., 12. Dez 2024`, `Mon, 12. Dec 2024`},
+		{`fr`, `mar. 12 mars 2024 janv. juin`, `Tue 12 Mar 2024 Jan Jun`},
+		{`fr`, `le 3 fevrier, fin.`, `le 3 February, fin.`},
+		{`es`, `mar, 12 mar 2024 miercoles`, `Tue, 12 Mar 2024 Wednesday`},
+		{`pt`, `segunda-feira, 11 de março de 2024`, `Monday, 11 de March de 2024`},
+		{`pt`, `terça, seg-feira`, `Tuesday, Mon-feira`},
+		{`it`, `nothing to see here`, `nothing to see here`},
+	}
+	for _, tt := range tests {
+		l, err := getLocale(tt.locale)
+		if err != nil {
+			t.Fatal(err)
+		}
+		lz := &localizer{loc: l}
+		if r := string(lz.normalize([]byte(tt.in))); r != tt.out {
+			t.Fatalf("%s: bad normalization of %q: %q != %q", tt.locale, tt.in, r, tt.out)
+		}
+	}
+}
+
+func TestLocaleExtract(t *testing.T) {
+	tests := []struct {
+		locale string
+		data   string
+		exp    time.Time
+		ts     string // the timestamp as it appears in data
+	}{
+		{`de`, `<13>12 Mär 2024 10:11:12 +0100 host app: hallo`, time.Date(2024, 3, 12, 9, 11, 12, 0, time.UTC), `12 Mär 2024 10:11:12 +0100`},
+		{`fr`, `10.0.0.1 - - [12/déc./2024:10:11:12 +0100] "GET /"`, time.Date(2024, 12, 12, 9, 11, 12, 0, time.UTC), `12/déc./2024:10:11:12 +0100`},
+		{`es_MX`, `foo 05-ene-2024 10:11:12.123 bar`, time.Date(2024, 1, 5, 10, 11, 12, 123000000, time.UTC), `05-ene-2024 10:11:12.123`},
+		{`it`, `Ago 7 10:11:12 2023 avvio`, time.Date(2023, 8, 7, 10, 11, 12, 0, time.UTC), `Ago 7 10:11:12 2023`},
+	}
+	for _, tt := range tests {
+		tg, err := New(Config{Locale: tt.locale})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ts, ok, err := tg.Extract([]byte(tt.data))
+		if err != nil || !ok {
+			t.Fatalf("%s: failed to extract from %q: %v", tt.locale, tt.data, err)
+		} else if !ts.Equal(tt.exp) {
+			t.Fatalf("%s: bad timestamp %v != %v", tt.locale, ts, tt.exp)
+		}
+		start, end, ok := tg.Match([]byte(tt.data))
+		if !ok {
+			t.Fatalf("%s: failed to match %q", tt.locale, tt.data)
+		} else if r := tt.data[start:end]; r != tt.ts {
+			t.Fatalf("%s: bad match offsets %q != %q", tt.locale, r, tt.ts)
+		}
+		if _, off, name, _ := tg.DebugExtract([]byte(tt.data)); name == `` || off != strings.Index(tt.data, tt.ts) {
+			t.Fatalf("%s: bad debug extract offset %d %q", tt.locale, off, name)
+		}
+
+		//without the locale nothing should be found
+		if tg, err = New(Config{}); err != nil {
+			t.Fatal(err)
+		} else if ts, ok, _ = tg.Extract([]byte(tt.data)); ok && ts.Equal(tt.exp) {
+			t.Fatalf("%s: extracted localized timestamp without a locale", tt.locale)
+		}
+	}
+}
+
+func TestLocaleEnglish(t *testing.T) {
+	//English names are always understood, even where a locale uses the same word for something else
+	data := []string{
+		`Mar 12 10:00:00 host app: hello`,
+		`Tue, 12 Mar 2024 10:00:00 +0000`,
+		`Sat Mar  2 10:00:00 2024`,
+		`Thu May 30 10:00:00 2024`,
+		`Sun, 01 Dec 2024 10:00:00 GMT`,
+		`Wednesday, 04-Sep-24 10:00:00 UTC`,
+		`12 Jan 2024 10:00:00 +0100`,
+	}
+	base, err := New(Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range Locales() {
+		tg, err := New(Config{Locale: name})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, v := range data {
+			exp, ok, _ := base.Extract([]byte(v))
+			if !ok {
+				t.Fatalf("failed to extract %q without a locale", v)
+			}
+			if ts, ok, err := tg.Extract([]byte(v)); err != nil || !ok {
+				t.Fatalf("%s: failed to extract %q: %v", name, v, err)
+			} else if !ts.Equal(exp) {
+				t.Fatalf("%s: bad timestamp from %q %v != %v", name, v, ts, exp)
+			}
+		}
+	}
+}
+
+func TestLocaleCustomFormat(t *testing.T) {
+	tg, err := New(Config{})
+	if err != nil {
+		t.Fatal(err)
+	} else if err = tg.SetLocale(`es`); err != nil {
+		t.Fatal(err)
+	}
+	cf := CustomFormat{Name: `spanish`, Java_Format: `EEE, dd MMM yyyy HH:mm:ss Z`}
+	p, err := NewCustomProcessor(cf)
+	if err != nil {
+		t.Fatal(err)
+	} else if _, err = tg.AddProcessor(p); err != nil {
+		t.Fatal(err)
+	} else if err = tg.SetFormatOverride(`spanish`); err != nil {
+		t.Fatal(err)
+	}
+	ts, ok, err := tg.Extract([]byte(`evento mar, 12 mar 2024 10:11:12 -0600 listo`))
+	if err != nil || !ok {
+		t.Fatalf("failed to extract: %v", err)
+	} else if exp := time.Date(2024, 3, 12, 16, 11, 12, 0, time.UTC); !ts.Equal(exp) {
+		t.Fatalf("bad timestamp %v != %v", ts, exp)
+	}
+
+	//going back to english disables the rewriting
+	if err = tg.SetLocale(``); err != nil {
+		t.Fatal(err)
+	} else if _, ok, _ = tg.Extract([]byte(`evento mié, 13 mar 2024 10:11:12 -0600 listo`)); ok {
+		t.Fatal("extracted localized weekday without a locale")
+	}
+}
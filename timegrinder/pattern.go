@@ -0,0 +1,380 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package timegrinder
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var (
+	ErrMultiplePatterns   = errors.New("Only one of Strftime-Format and Java-Format may be specified")
+	ErrPatternConflict    = errors.New("Regex and Format cannot be specified with a Strftime-Format or Java-Format pattern")
+	ErrUnterminatedQuote  = errors.New("Unterminated quote in Java-Format pattern")
+	ErrFractionPlacement  = errors.New("Fractional seconds must immediately follow a '.' or ',' separator")
+	ErrUnsupportedPattern = errors.New("Unsupported pattern directive")
+	ErrEmptyPattern       = errors.New("Pattern does not contain any time directives")
+)
+
+// patternToken is a single pattern directive translated to a Go layout and a regular expression
+type patternToken struct {
+	layout string
+	rx     string
+}
+
+var strftimeTokens = map[byte]patternToken{
+	'Y': {`2006`, `\d{4}`},
+	'y': {`06`, `\d{2}`},
+	'm': {`01`, `\d{2}`},
+	'd': {`02`, `\d{2}`},
+	'e': {`_2`, ` ?\d{1,2}`},
+	'j': {`002`, `\d{3}`},
+	'H': {`15`, `\d{2}`},
+	'I': {`03`, `\d{2}`},
+	'M': {`04`, `\d{2}`},
+	'S': {`05`, `\d{2}`},
+	'p': {`PM`, `[AP]M`},
+	'b': {`Jan`, `[A-Za-z]{3}`},
+	'h': {`Jan`, `[A-Za-z]{3}`},
+	'B': {`January`, `[A-Za-z]{3,9}`},
+	'a': {`Mon`, `[A-Za-z]{3}`},
+	'A': {`Monday`, `[A-Za-z]{6,9}`},
+	'z': {`-0700`, `[+-]\d{4}`},
+	'Z': {`MST`, `[A-Za-z]{3,5}`},
+	'T': {`15:04:05`, `\d{2}:\d{2}:\d{2}`},
+	'R': {`15:04`, `\d{2}:\d{2}`},
+	'D': {`01/02/06`, `\d{2}/\d{2}/\d{2}`},
+	'F': {`2006-01-02`, `\d{4}-\d{2}-\d{2}`},
+	'%': {`%`, `%`},
+}
+
+// strftime directives that accept the '-' flag to drop padding
+var strftimeUnpadded = map[byte]patternToken{
+	'd': {`2`, `\d{1,2}`},
+	'm': {`1`, `\d{1,2}`},
+	'H': {`15`, `\d{1,2}`},
+	'I': {`3`, `\d{1,2}`},
+	'M': {`4`, `\d{1,2}`},
+	'S': {`5`, `\d{1,2}`},
+}
+
+// strftime fractional second directives and their digit counts
+var strftimeFractions = map[byte]int{
+	'L': 3,
+	'f': 6,
+	'N': 9,
+}
+
+// ParseStrftime converts a strftime pattern such as "%Y-%m-%d %H:%M:%S.%f %z" into a Go time layout and
+// a regular expression that matches timestamps in that format.  Fractional seconds are accepted with the
+// %L (milliseconds), %f (microseconds), and %N (nanoseconds) directives.  The %p marker must be AM or PM.
+func ParseStrftime(pattern string) (layout, rx string, err error) {
+	var pb patternBuilder
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		if c != '%' {
+			pb.literal(string(c))
+			continue
+		}
+		if i++; i >= len(pattern) {
+			err = fmt.Errorf("%w: trailing %%", ErrUnsupportedPattern)
+			return
+		}
+		c = pattern[i]
+		if c == '-' {
+			if i++; i >= len(pattern) {
+				err = fmt.Errorf("%w: trailing %%-", ErrUnsupportedPattern)
+				return
+			}
+			tok, ok := strftimeUnpadded[pattern[i]]
+			if !ok {
+				err = fmt.Errorf("%w %%-%c", ErrUnsupportedPattern, pattern[i])
+				return
+			}
+			pb.token(tok)
+		} else if tok, ok := strftimeTokens[c]; ok {
+			pb.token(tok)
+		} else if n, ok := strftimeFractions[c]; ok {
+			if err = pb.fraction(n); err != nil {
+				return
+			}
+		} else {
+			err = fmt.Errorf("%w %%%c", ErrUnsupportedPattern, c)
+			return
+		}
+	}
+	return pb.finish()
+}
+
+// expandPattern generates the Regex and Format from a strftime or Java pattern if one is set
+func (cf *CustomFormat) expandPattern() (err error) {
+	var layout, rx string
+	switch {
+	case cf.Strftime_Format != `` && cf.Java_Format != ``:
+		return ErrMultiplePatterns
+	case cf.Strftime_Format != ``:
+		layout, rx, err = ParseStrftime(cf.Strftime_Format)
+	case cf.Java_Format != ``:
+		layout, rx, err = ParseJavaFormat(cf.Java_Format)
+	default:
+		return
+	}
+	if err != nil {
+		return fmt.Errorf("Invalid pattern: %w", err)
+	} else if (cf.Regex != `` && cf.Regex != rx) || (cf.Format != `` && cf.Format != layout) {
+		//validating an already expanded format is fine
+		return ErrPatternConflict
+	}
+	cf.Regex, cf.Format = rx, layout
+	return
+}
+
+// javaToken returns the translation of a run of count repeated Java DateTimeFormatter pattern letters
+func javaToken(c byte, count int) (tok patternToken, ok bool) {
+	switch c {
+	case 'y', 'u':
+		switch count {
+		case 2:
+			return patternToken{`06`, `\d{2}`}, true
+		case 1, 4:
+			return patternToken{`2006`, `\d{4}`}, true
+		}
+	case 'M', 'L':
+		switch count {
+		case 1:
+			return patternToken{`1`, `\d{1,2}`}, true
+		case 2:
+			return patternToken{`01`, `\d{2}`}, true
+		case 3:
+			return patternToken{`Jan`, `[A-Za-z]{3}`}, true
+		case 4:
+			return patternToken{`January`, `[A-Za-z]{3,9}`}, true
+		}
+	case 'd':
+		switch count {
+		case 1:
+			return patternToken{`2`, `\d{1,2}`}, true
+		case 2:
+			return patternToken{`02`, `\d{2}`}, true
+		}
+	case 'D':
+		if count == 3 {
+			return patternToken{`002`, `\d{3}`}, true
+		}
+	case 'H':
+		switch count {
+		case 1:
+			return patternToken{`15`, `\d{1,2}`}, true
+		case 2:
+			return patternToken{`15`, `\d{2}`}, true
+		}
+	case 'h':
+		switch count {
+		case 1:
+			return patternToken{`3`, `\d{1,2}`}, true
+		case 2:
+			return patternToken{`03`, `\d{2}`}, true
+		}
+	case 'm':
+		switch count {
+		case 1:
+			return patternToken{`4`, `\d{1,2}`}, true
+		case 2:
+			return patternToken{`04`, `\d{2}`}, true
+		}
+	case 's':
+		switch count {
+		case 1:
+			return patternToken{`5`, `\d{1,2}`}, true
+		case 2:
+			return patternToken{`05`, `\d{2}`}, true
+		}
+	case 'a':
+		if count == 1 {
+			return patternToken{`PM`, `[AP]M`}, true
+		}
+	case 'E':
+		switch count {
+		case 1, 2, 3:
+			return patternToken{`Mon`, `[A-Za-z]{3}`}, true
+		case 4:
+			return patternToken{`Monday`, `[A-Za-z]{6,9}`}, true
+		}
+	case 'X':
+		switch count {
+		case 1:
+			return patternToken{`Z07`, `(?:Z|[+-]\d{2})`}, true
+		case 2:
+			return patternToken{`Z0700`, `(?:Z|[+-]\d{4})`}, true
+		case 3:
+			return patternToken{`Z07:00`, `(?:Z|[+-]\d{2}:\d{2})`}, true
+		}
+	case 'x':
+		switch count {
+		case 1:
+			return patternToken{`-07`, `[+-]\d{2}`}, true
+		case 2:
+			return patternToken{`-0700`, `[+-]\d{4}`}, true
+		case 3:
+			return patternToken{`-07:00`, `[+-]\d{2}:\d{2}`}, true
+		}
+	case 'Z':
+		switch count {
+		case 1, 2, 3:
+			return patternToken{`-0700`, `[+-]\d{4}`}, true
+		case 5:
+			return patternToken{`Z07:00`, `(?:Z|[+-]\d{2}:\d{2})`}, true
+		}
+	case 'z':
+		switch count {
+		case 1, 2, 3:
+			return patternToken{`MST`, `[A-Za-z]{3,5}`}, true
+		}
+	}
+	return
+}
+
+// ParseJavaFormat converts a Java DateTimeFormatter pattern such as "yyyy-MM-dd'T'HH:mm:ss.SSSXXX" into a
+// Go time layout and a regular expression that matches timestamps in that format.  Quoted literals are
+// supported, optional sections and text styles without a Go equivalent are not.  The a marker must be AM or PM.
+func ParseJavaFormat(pattern string) (layout, rx string, err error) {
+	var pb patternBuilder
+	for i := 0; i < len(pattern); {
+		c := pattern[i]
+		switch {
+		case c == '\'':
+			//quoted literal, a doubled quote is a literal quote
+			if i+1 < len(pattern) && pattern[i+1] == '\'' {
+				pb.literal(`'`)
+				i += 2
+				continue
+			}
+			var lit strings.Builder
+			closed := false
+			for i++; i < len(pattern); i++ {
+				if pattern[i] != '\'' {
+					lit.WriteByte(pattern[i])
+				} else if i+1 < len(pattern) && pattern[i+1] == '\'' {
+					lit.WriteByte('\'')
+					i++
+				} else {
+					closed = true
+					i++
+					break
+				}
+			}
+			if !closed {
+				err = ErrUnterminatedQuote
+				return
+			}
+			pb.literal(lit.String())
+		case (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z'):
+			count := 1
+			for i+count < len(pattern) && pattern[i+count] == c {
+				count++
+			}
+			i += count
+			if c == 'S' {
+				if count > 9 {
+					err = fmt.Errorf("%w %s", ErrUnsupportedPattern, strings.Repeat(`S`, count))
+					return
+				} else if err = pb.fraction(count); err != nil {
+					return
+				}
+			} else if tok, ok := javaToken(c, count); ok {
+				pb.token(tok)
+			} else {
+				err = fmt.Errorf("%w %s", ErrUnsupportedPattern, strings.Repeat(string(c), count))
+				return
+			}
+		case c == '[' || c == ']' || c == '{' || c == '}' || c == '#':
+			err = fmt.Errorf("%w %c", ErrUnsupportedPattern, c)
+			return
+		default:
+			pb.literal(string(c))
+			i++
+		}
+	}
+	return pb.finish()
+}
+
+// patternBuilder accumulates the Go layout and regular expression for a pattern
+type patternBuilder struct {
+	layout strings.Builder
+	rx     strings.Builder
+	lit    strings.Builder // pending literal text
+	tokens int
+	err    error
+}
+
+func (pb *patternBuilder) literal(s string) {
+	pb.lit.WriteString(s)
+}
+
+func (pb *patternBuilder) token(tok patternToken) {
+	pb.flushLiteral()
+	pb.layout.WriteString(tok.layout)
+	pb.rx.WriteString(tok.rx)
+	pb.tokens++
+}
+
+// fraction adds fractional seconds, the separator must be the last literal character
+func (pb *patternBuilder) fraction(digits int) error {
+	lit := pb.lit.String()
+	if lit == `` || (lit[len(lit)-1] != '.' && lit[len(lit)-1] != ',') {
+		return ErrFractionPlacement
+	}
+	sep := lit[len(lit)-1:]
+	pb.lit.Reset()
+	pb.lit.WriteString(lit[:len(lit)-1])
+	pb.flushLiteral()
+	pb.layout.WriteString(sep + strings.Repeat(`0`, digits))
+	pb.rx.WriteString(regexp.QuoteMeta(sep) + fmt.Sprintf(`\d{%d}`, digits))
+	pb.tokens++
+	return nil
+}
+
+func (pb *patternBuilder) flushLiteral() {
+	if pb.lit.Len() == 0 {
+		return
+	}
+	s := pb.lit.String()
+	pb.lit.Reset()
+	// a literal that happens to contain a Go layout element would be misinterpreted
+	if pb.err == nil && !isLayoutLiteral(s) {
+		pb.err = fmt.Errorf("Literal text %q conflicts with the Go time layout", s)
+	}
+	pb.layout.WriteString(s)
+	pb.rx.WriteString(regexp.QuoteMeta(s))
+}
+
+func (pb *patternBuilder) finish() (layout, rx string, err error) {
+	pb.flushLiteral()
+	if pb.err != nil {
+		err = pb.err
+	} else if pb.tokens == 0 {
+		err = ErrEmptyPattern
+	} else {
+		layout, rx = pb.layout.String(), pb.rx.String()
+	}
+	return
+}
+
+var (
+	literalCheckA = time.Date(2009, time.November, 10, 23, 41, 51, 123456789, time.FixedZone(`AAA`, 3600))
+	literalCheckB = time.Date(2013, time.March, 4, 8, 12, 33, 987654321, time.FixedZone(`BBB`, -7200))
+)
+
+// isLayoutLiteral returns true if s contains no Go layout elements
+func isLayoutLiteral(s string) bool {
+	return literalCheckA.Format(s) == s && literalCheckB.Format(s) == s
+}
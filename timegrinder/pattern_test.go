@@ -0,0 +1,141 @@
+package timegrinder
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseStrftime(t *testing.T) {
+	tests := []struct {
+		pattern string
+		layout  string
+		rx      string
+	}{
+		{`%Y-%m-%d %H:%M:%S.%f %z`, `2006-01-02 15:04:05.000000 -0700`, `\d{4}-\d{2}-\d{2} \d{2}:\d{2}:\d{2}\.\d{6} [+-]\d{4}`},
+		{`%d/%b/%Y:%T %z`, `02/Jan/2006:15:04:05 -0700`, `\d{2}/[A-Za-z]{3}/\d{4}:\d{2}:\d{2}:\d{2} [+-]\d{4}`},
+		{`%F %-H:%M:%S,%L`, `2006-01-02 15:04:05,000`, `\d{4}-\d{2}-\d{2} \d{1,2}:\d{2}:\d{2},\d{3}`},
+		{`%b %e %T`, `Jan _2 15:04:05`, `[A-Za-z]{3}  ?\d{1,2} \d{2}:\d{2}:\d{2}`},
+		{`%I:%M %p %%`, `03:04 PM %`, ``},
+	}
+	for _, tt := range tests {
+		layout, rx, err := ParseStrftime(tt.pattern)
+		if err != nil {
+			t.Fatalf("%q: %v", tt.pattern, err)
+		} else if layout != tt.layout {
+			t.Fatalf("%q: bad layout %q != %q", tt.pattern, layout, tt.layout)
+		} else if tt.rx != `` && rx != tt.rx {
+			t.Fatalf("%q: bad regex %q != %q", tt.pattern, rx, tt.rx)
+		}
+	}
+	bad := []string{`%Y-%m-%d %Q`, `%H:%M:%S%f`, `%s`, `%Y %`, `%-j`, `no directives`, `%Y 2`, `%H:%M Jan`, `%I:%M %p 100%%`}
+	for _, v := range bad {
+		if _, _, err := ParseStrftime(v); err == nil {
+			t.Fatalf("failed to catch bad pattern %q", v)
+		}
+	}
+}
+
+func TestParseJavaFormat(t *testing.T) {
+	tests := []struct {
+		pattern string
+		layout  string
+		rx      string
+	}{
+		{`yyyy-MM-dd'T'HH:mm:ss.SSSXXX`, `2006-01-02T15:04:05.000Z07:00`, `\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}\.\d{3}(?:Z|[+-]\d{2}:\d{2})`},
+		{`dd/MMM/yyyy:HH:mm:ss Z`, `02/Jan/2006:15:04:05 -0700`, `\d{2}/[A-Za-z]{3}/\d{4}:\d{2}:\d{2}:\d{2} [+-]\d{4}`},
+		{`EEE, d MMMM yy h:mm a z`, `Mon, 2 January 06 3:04 PM MST`, ``},
+		{`yyyyMMdd'T'HHmmss'Z'`, `20060102T150405Z`, `\d{4}\d{2}\d{2}T\d{2}\d{2}\d{2}Z`},
+		{`HH:mm:ss,SSSSSS 'o''clock'`, `15:04:05,000000 o'clock`, ``},
+	}
+	for _, tt := range tests {
+		layout, rx, err := ParseJavaFormat(tt.pattern)
+		if err != nil {
+			t.Fatalf("%q: %v", tt.pattern, err)
+		} else if layout != tt.layout {
+			t.Fatalf("%q: bad layout %q != %q", tt.pattern, layout, tt.layout)
+		} else if tt.rx != `` && rx != tt.rx {
+			t.Fatalf("%q: bad regex %q != %q", tt.pattern, rx, tt.rx)
+		}
+	}
+	bad := []string{`yyyy-MM-ddTHH:mm`, `yyyy-MM-dd 'T`, `HH:mm:ssSSS`, `yyyy[-MM]`, `VV`, `yyyyy`, `HH:mm '1'`}
+	for _, v := range bad {
+		if _, _, err := ParseJavaFormat(v); err == nil {
+			t.Fatalf("failed to catch bad pattern %q", v)
+		}
+	}
+	if _, _, err := ParseJavaFormat(`yyyy-MM-dd 'T`); !errors.Is(err, ErrUnterminatedQuote) {
+		t.Fatalf("bad error on unterminated quote: %v", err)
+	}
+}
+
+func TestCustomPatternExtract(t *testing.T) {
+	tests := []struct {
+		cf   CustomFormat
+		data string
+		exp  time.Time
+	}{
+		{
+			cf:   CustomFormat{Name: `strf`, Strftime_Format: `%Y-%m-%d %H:%M:%S.%f %z`},
+			data: `INFO 2024-03-04 05:06:07.123456 -0500 request handled`,
+			exp:  time.Date(2024, 3, 4, 10, 6, 7, 123456000, time.UTC),
+		},
+		{
+			cf:   CustomFormat{Name: `java`, Java_Format: `yyyy-MM-dd'T'HH:mm:ss.SSSXXX`},
+			data: `{"ts":"2024-03-04T05:06:07.890+02:00","msg":"hi"}`,
+			exp:  time.Date(2024, 3, 4, 3, 6, 7, 890000000, time.UTC),
+		},
+		{
+			cf:   CustomFormat{Name: `java2`, Java_Format: `dd/MMM/yyyy:HH:mm:ss Z`},
+			data: `127.0.0.1 - - [10/Oct/2023:13:55:36 -0700] "GET / HTTP/1.1"`,
+			exp:  time.Date(2023, 10, 10, 20, 55, 36, 0, time.UTC),
+		},
+		{
+			cf:   CustomFormat{Name: `ampm`, Strftime_Format: `%Y-%m-%d %I:%M:%S %p`},
+			data: `2024-03-04 05:06:07 PM login`,
+			exp:  time.Date(2024, 3, 4, 17, 6, 7, 0, time.UTC),
+		},
+	}
+	for _, tt := range tests {
+		p, err := NewCustomProcessor(tt.cf)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.cf.Name, err)
+		}
+		ts, ok, _ := p.Extract([]byte(tt.data), time.UTC)
+		if !ok {
+			t.Fatalf("%s: failed to extract", tt.cf.Name)
+		} else if !ts.Equal(tt.exp) {
+			t.Fatalf("%s: bad timestamp %v != %v", tt.cf.Name, ts, tt.exp)
+		}
+	}
+
+	//the PM layout only parses upper case markers, so lower case must not match
+	p, err := NewCustomProcessor(CustomFormat{Name: `lower`, Java_Format: `yyyy-MM-dd hh:mm:ss a`})
+	if err != nil {
+		t.Fatal(err)
+	} else if _, _, ok := p.Match([]byte(`2024-03-04 05:06:07 pm login`)); ok {
+		t.Fatal("matched a lower case AM/PM marker")
+	}
+
+	//validate fills in the regex and format and is safe to call again
+	cf := CustomFormat{Name: `foo`, Strftime_Format: `%H:%M:%S`}
+	if err := cf.Validate(); err != nil {
+		t.Fatal(err)
+	} else if cf.Format != `15:04:05` || !cf.dateMissing {
+		t.Fatalf("bad expanded format %q", cf.Format)
+	} else if err = cf.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	bad := []CustomFormat{
+		{Name: `both`, Strftime_Format: `%H:%M:%S`, Java_Format: `HH:mm:ss`},
+		{Name: `regex`, Strftime_Format: `%H:%M:%S`, Regex: `\d+`},
+		{Name: `format`, Java_Format: `HH:mm:ss`, Format: time.Kitchen},
+		{Name: `invalid`, Java_Format: `HH:mm:ss[.SSS]`},
+	}
+	for _, v := range bad {
+		if err := v.Validate(); err == nil {
+			t.Fatalf("failed to catch bad custom format %s", v.Name)
+		}
+	}
+}
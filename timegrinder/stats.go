@@ -0,0 +1,96 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package timegrinder
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// Stats describes how well timestamps are being extracted
+type Stats struct {
+	Hits      uint64            // extractions satisfied by the most recently successful format
+	Misses    uint64            // extractions that required scanning the other formats
+	Fallbacks uint64            // extractions that found no timestamp, callers typically fall back to the current time
+	Formats   map[string]uint64 `json:",omitempty"` // successful extractions by format name
+}
+
+// Total returns the number of extractions attempted
+func (s Stats) Total() uint64 {
+	return s.Hits + s.Misses + s.Fallbacks
+}
+
+// Add accumulates another set of stats
+func (s *Stats) Add(o Stats) {
+	s.Hits += o.Hits
+	s.Misses += o.Misses
+	s.Fallbacks += o.Fallbacks
+	if len(o.Formats) > 0 && s.Formats == nil {
+		s.Formats = make(map[string]uint64, len(o.Formats))
+	}
+	for k, v := range o.Formats {
+		s.Formats[k] += v
+	}
+}
+
+// Counters accumulates extraction statistics.  A single Counters may be shared by many
+// TimeGrinders, such as one per connection on a listener, and is safe for concurrent use.
+type Counters struct {
+	hits      uint64
+	misses    uint64
+	fallbacks uint64
+	mtx       sync.RWMutex
+	formats   map[string]*uint64
+}
+
+func NewCounters() *Counters {
+	return &Counters{
+		formats: map[string]*uint64{},
+	}
+}
+
+// Stats returns a snapshot of the counters
+func (c *Counters) Stats() (s Stats) {
+	s.Hits = atomic.LoadUint64(&c.hits)
+	s.Misses = atomic.LoadUint64(&c.misses)
+	s.Fallbacks = atomic.LoadUint64(&c.fallbacks)
+	c.mtx.RLock()
+	if len(c.formats) > 0 {
+		s.Formats = make(map[string]uint64, len(c.formats))
+		for k, v := range c.formats {
+			s.Formats[k] = atomic.LoadUint64(v)
+		}
+	}
+	c.mtx.RUnlock()
+	return
+}
+
+func (c *Counters) hit(name string, first bool) {
+	if first {
+		atomic.AddUint64(&c.hits, 1)
+	} else {
+		atomic.AddUint64(&c.misses, 1)
+	}
+	c.mtx.RLock()
+	v, ok := c.formats[name]
+	c.mtx.RUnlock()
+	if !ok {
+		c.mtx.Lock()
+		if v, ok = c.formats[name]; !ok {
+			v = new(uint64)
+			c.formats[name] = v
+		}
+		c.mtx.Unlock()
+	}
+	atomic.AddUint64(v, 1)
+}
+
+func (c *Counters) fallback() {
+	atomic.AddUint64(&c.fallbacks, 1)
+}
@@ -0,0 +1,209 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package timegrinder
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestStats(t *testing.T) {
+	tg, err := New(Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := []string{
+		`2024-05-06T07:08:09Z first`,   // miss, RFC3339 is not first in line
+		`2024-05-06T07:08:10Z second`,  // hit
+		`2024-05-06T07:08:11Z third`,   // hit
+		`no timestamp here`,            // fallback
+		`06/May/2024:07:08:09 -0700 x`, // miss, apache
+		`2024-05-06T07:08:12Z fourth`,  // miss
+	}
+	for _, l := range lines {
+		tg.Extract([]byte(l))
+	}
+	s := tg.Stats()
+	if s.Hits != 2 || s.Misses != 3 || s.Fallbacks != 1 || s.Total() != uint64(len(lines)) {
+		t.Fatalf("bad stats: %+v", s)
+	} else if s.Formats[RFC3339.String()] != 4 || s.Formats[Apache.String()] != 1 || len(s.Formats) != 2 {
+		t.Fatalf("bad format stats: %+v", s.Formats)
+	}
+
+	//override hits are always first hits
+	if err = tg.SetFormatOverride(Apache.String()); err != nil {
+		t.Fatal(err)
+	}
+	tg.Extract([]byte(`06/May/2024:07:08:09 -0700 x`))
+	if s = tg.Stats(); s.Hits != 3 || s.Formats[Apache.String()] != 2 {
+		t.Fatalf("bad override stats: %+v", s)
+	}
+
+	//anchors that miss fall back
+	if tg, err = New(Config{Anchor: `kv:ts`}); err != nil {
+		t.Fatal(err)
+	}
+	tg.Extract([]byte(`time=2024-05-06T07:08:09Z`))
+	if s = tg.Stats(); s.Fallbacks != 1 || s.Total() != 1 {
+		t.Fatalf("bad anchor stats: %+v", s)
+	}
+
+	var total Stats
+	total.Add(s)
+	total.Add(Stats{Hits: 2, Formats: map[string]uint64{`foo`: 2}})
+	if total.Hits != 2 || total.Fallbacks != 1 || total.Formats[`foo`] != 2 {
+		t.Fatalf("bad stats addition: %+v", total)
+	}
+}
+
+func TestSharedCounters(t *testing.T) {
+	ctr := NewCounters()
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			tg, err := New(Config{Counters: ctr})
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			for j := 0; j < 100; j++ {
+				tg.Extract([]byte(`2024-05-06 07:08:09 hello`))
+				ctr.Stats()
+			}
+		}()
+	}
+	wg.Wait()
+	if s := ctr.Stats(); s.Total() != 400 || s.Misses != 4 || s.Formats[DPKG.String()] != 400 {
+		t.Fatalf("bad shared stats: %+v", s)
+	}
+}
+
+func TestLearning(t *testing.T) {
+	corpus := mixedCorpus(1000, map[string]int{time.RFC1123Z: 1, NGINXFormat: 9})
+	tg, err := New(Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range corpus {
+		if _, ok, _ := tg.Extract(v); !ok {
+			t.Fatalf("failed to extract %q", v)
+		}
+	}
+	if n := tg.procs[0].Name(); n != NGINX.String() {
+		t.Fatalf("most frequent format was not promoted: %s", n)
+	} else if n = tg.procs[1].Name(); n != RFC1123Z.String() {
+		t.Fatalf("second most frequent format was not promoted: %s", n)
+	}
+	for i := 1; i < len(tg.hist); i++ {
+		if tg.hist[i] > tg.hist[i-1] {
+			t.Fatalf("processors out of order at %d: %v", i, tg.hist)
+		}
+	}
+
+	//added processors keep precedence over learned formats
+	p, err := NewUserProcessor(`custom`, `\d{4}#\d\d#\d\d`, `2006#01#02`)
+	if err != nil {
+		t.Fatal(err)
+	} else if _, err = tg.AddProcessor(p); err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range corpus {
+		tg.Extract(v)
+	}
+	if n := tg.procs[0].Name(); n != `custom` {
+		t.Fatalf("added processor lost precedence to %s", n)
+	} else if n = tg.procs[1].Name(); n != NGINX.String() {
+		t.Fatalf("learned order was not kept behind the added processor: %s", n)
+	}
+	if ts, ok, err := tg.Extract([]byte(`at 2026#03#14`)); err != nil || !ok || ts.Day() != 14 {
+		t.Fatalf("added processor failed: %v %v %v", ts, ok, err)
+	}
+
+	//with learning disabled the order never changes
+	if tg, err = New(Config{DisableLearning: true}); err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, p := range tg.procs {
+		names = append(names, p.Name())
+	}
+	for _, v := range corpus {
+		tg.Extract(v)
+	}
+	for i, p := range tg.procs {
+		if p.Name() != names[i] {
+			t.Fatalf("processor order changed with learning disabled: %s != %s", p.Name(), names[i])
+		}
+	}
+}
+
+// mixedCorpus generates lines with timestamps in the given layouts, weighted by frequency
+func mixedCorpus(cnt int, layouts map[string]int) (r [][]byte) {
+	var weighted []string
+	for k, v := range layouts {
+		for i := 0; i < v; i++ {
+			weighted = append(weighted, k)
+		}
+	}
+	rng := rand.New(rand.NewSource(1))
+	ts := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+	for i := 0; i < cnt; i++ {
+		layout := weighted[rng.Intn(len(weighted))]
+		r = append(r, []byte(fmt.Sprintf("host%d app[%d]: %s request handled status=200", i%7, i, ts.Add(time.Duration(i)*time.Second).Format(layout))))
+	}
+	return
+}
+
+var benchCorpora = []struct {
+	name    string
+	layouts map[string]int
+}{
+	{`single`, map[string]int{RFC3339Format: 1}},
+	{`dominant`, map[string]int{SyslogFileFormat: 18, ApacheFormat: 1, UnixFormat: 1}},
+	{`split`, map[string]int{DPKGFormat: 1, RFC1123ZFormat: 1, NGINXFormat: 1}},
+	{`scattered`, map[string]int{AnsiCFormat: 1, RFC850Format: 1, ApacheFormat: 1, NGINXFormat: 1, SyslogFileFormat: 1, GravwellTimePickerFormat: 1, DirectAdminFormat: 1, RFC3339NanoFormat: 1}},
+	{`mostly-missing`, map[string]int{`no timestamp`: 9, RFC3339Format: 1}},
+}
+
+func benchmarkCorpora(b *testing.B, cfg Config) {
+	for _, c := range benchCorpora {
+		corpus := mixedCorpus(4096, c.layouts)
+		b.Run(c.name, func(b *testing.B) {
+			tg, err := New(cfg)
+			if err != nil {
+				b.Fatal(err)
+			}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				tg.Extract(corpus[i%len(corpus)])
+			}
+			b.StopTimer()
+			if s := tg.Stats(); s.Total() > 0 {
+				b.ReportMetric(float64(s.Misses)/float64(s.Total()), `misses/op`)
+			}
+		})
+	}
+}
+
+func BenchmarkMixedCorpus(b *testing.B) {
+	benchmarkCorpora(b, Config{})
+}
+
+func BenchmarkMixedCorpusNoLearning(b *testing.B) {
+	benchmarkCorpora(b, Config{DisableLearning: true})
+}
+
+func BenchmarkMixedCorpusLeftMostSeed(b *testing.B) {
+	benchmarkCorpora(b, Config{EnableLeftMostSeed: true})
+}
@@ -16,6 +16,7 @@ package timegrinder
 import (
 	"errors"
 	"fmt"
+	"net"
 	"sync"
 	"time"
 )
@@ -41,9 +42,18 @@ type TimeGrinder struct {
 	procs    []Processor
 	curr     int
 	count    int
+	pinned   int // processors added with AddProcessor, learning never moves them
 	seed     bool
 	override Processor
 	loc      *time.Location
+	lz       *localizer
+	anchor   *anchor
+	hist     []uint64 // successful extractions by each processor, ordered with procs
+	counters *Counters
+	srcIP    net.IP
+	srcName  string
+	srcKey   string         // source awaiting an inferred timezone
+	srcLoc   *time.Location // timezone of the current source, overrides loc
 }
 
 // Config defines a few configuration options when instantiating a new TimeGrinder.
@@ -56,6 +66,18 @@ type Config struct {
 	FormatOverride string
 	// TSWindow sets maximum deltas into the past & future for timestamp parsing. Any timestamp extracted which falls outside those deltas from the current time will be considered invalid and skipped.
 	TSWindow TimestampWindow
+	// Locale enables parsing of localized month and weekday names (e.g. "de" or "es_MX"), English is always understood.
+	Locale string
+	// Anchor restricts extraction to a single field, see SetAnchor.
+	Anchor string
+	// DisableLearning keeps the processors in their default order rather than promoting the
+	// formats that extract most often.  Processors added with AddProcessor always stay ahead of the built in formats.
+	DisableLearning bool
+	// Counters optionally accumulates extraction statistics across many TimeGrinders,
+	// if nil each TimeGrinder keeps its own.
+	Counters *Counters
+	// Zones optionally maps sources to the timezone used for their zoneless timestamps, see SetSource.
+	Zones *SourceZones
 }
 
 func Extract(b []byte) (t time.Time, ok bool, err error) {
@@ -192,14 +214,29 @@ func New(c Config) (tg *TimeGrinder, err error) {
 	}
 
 	tg = &TimeGrinder{
-		Config: c,
-		procs:  procs,
-		count:  len(procs),
-		loc:    time.UTC,
-		seed:   c.EnableLeftMostSeed,
+		Config:   c,
+		procs:    procs,
+		count:    len(procs),
+		loc:      time.UTC,
+		seed:     c.EnableLeftMostSeed,
+		hist:     make([]uint64, len(procs)),
+		counters: c.Counters,
+	}
+	if tg.counters == nil {
+		tg.counters = NewCounters()
 	}
 	if c.FormatOverride != `` {
-		err = tg.SetFormatOverride(c.FormatOverride)
+		if err = tg.SetFormatOverride(c.FormatOverride); err != nil {
+			return
+		}
+	}
+	if c.Locale != `` {
+		if err = tg.SetLocale(c.Locale); err != nil {
+			return
+		}
+	}
+	if c.Anchor != `` {
+		err = tg.SetAnchor(c.Anchor)
 	}
 	return
 }
@@ -237,6 +274,108 @@ func (tg *TimeGrinder) SetTimezone(f string) error {
 	return nil
 }
 
+// SetSource identifies the source of the data handed to subsequent extractions.  When the
+// TimeGrinder is configured with Zones, zoneless timestamps are interpreted in the timezone
+// mapped to ip, or the timezone inferred for the source once it is locked in.  The name
+// identifies the source when inferring, if empty the address is used.
+func (tg *TimeGrinder) SetSource(ip net.IP, name string) {
+	if tg.Zones == nil || (ip.Equal(tg.srcIP) && name == tg.srcName) {
+		return
+	}
+	tg.srcIP, tg.srcName = ip, name
+	tg.srcKey, tg.srcLoc = ``, nil
+	if loc, ok := tg.Zones.Lookup(ip); ok {
+		tg.srcLoc = loc
+		return
+	}
+	if name == `` && ip != nil {
+		name = ip.String()
+	}
+	if loc, ok := tg.Zones.Inferred(name); ok {
+		tg.srcLoc = loc
+	} else if tg.Zones.infer {
+		tg.srcKey = name
+	}
+}
+
+// location returns the timezone used for zoneless timestamps
+func (tg *TimeGrinder) location() *time.Location {
+	if tg.srcLoc != nil {
+		return tg.srcLoc
+	}
+	return tg.loc
+}
+
+// infer feeds a successful extraction to the timezone inference for the current source,
+// once the source's timezone is locked in the timestamp is reinterpreted in it
+func (tg *TimeGrinder) infer(t time.Time) time.Time {
+	loc := tg.Zones.observe(tg.srcKey, t, time.Now())
+	if loc == nil {
+		return t
+	}
+	tg.srcKey, tg.srcLoc = ``, loc
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), loc)
+}
+
+// SetLocale enables parsing of month and weekday names in the given locale for all processors,
+// including custom formats.  Localized names are rewritten to their English equivalents before
+// extraction, so custom regular expressions should expect English names.  An empty locale
+// restores the default English only behavior.
+func (tg *TimeGrinder) SetLocale(name string) error {
+	l, err := getLocale(name)
+	if err != nil {
+		return err
+	}
+	if l == nil {
+		tg.lz = nil
+	} else {
+		tg.lz = &localizer{loc: l}
+	}
+	tg.Locale = name
+	return nil
+}
+
+// SetAnchor restricts timestamp extraction to the field identified by the anchor specification,
+// which is one of json:<path>, csv:<column>, tsv:<column>, or kv:<key>.  The field is located
+// first and only its contents are handed to the processors, data without the field produces no
+// timestamp.  An empty specification restores scanning of the entire payload.
+func (tg *TimeGrinder) SetAnchor(spec string) error {
+	a, err := parseAnchor(spec)
+	if err != nil {
+		return err
+	}
+	tg.anchor = a
+	tg.Anchor = spec
+	return nil
+}
+
+// scope narrows data to the anchored field and rewrites localized names, base is the offset
+// of the scoped region within data
+func (tg *TimeGrinder) scope(data []byte) (d []byte, base int, ok bool) {
+	d = data
+	if tg.anchor != nil {
+		if base, d, ok = tg.anchor.locate(data); !ok {
+			return
+		}
+	}
+	if tg.lz != nil {
+		d = tg.lz.normalize(d)
+	}
+	ok = true
+	return
+}
+
+// origOffset maps an offset in a scoped region back to the original data
+func (tg *TimeGrinder) origOffset(n, base int) int {
+	if n < 0 {
+		return n
+	}
+	if tg.lz != nil {
+		n = tg.lz.offset(n)
+	}
+	return n + base
+}
+
 func (tg *TimeGrinder) OverrideProcessor() (Processor, error) {
 	if tg.override != nil {
 		return tg.override, nil
@@ -244,7 +383,8 @@ func (tg *TimeGrinder) OverrideProcessor() (Processor, error) {
 	return nil, errors.New("No override processor set")
 }
 
-// AddProcessor inserts a new Processor at the *beginning* of the processor list.
+// AddProcessor inserts a new Processor at the *beginning* of the processor list,
+// learning never promotes the built in formats ahead of added processors.
 // For compatibility, it still returns the index of the inserted processor, but that
 // index will always be 0.
 func (tg *TimeGrinder) AddProcessor(p Processor) (idx int, err error) {
@@ -259,7 +399,10 @@ func (tg *TimeGrinder) AddProcessor(p Processor) (idx int, err error) {
 	// make sure the cutoff is set
 	p.SetWindow(tg.TSWindow)
 	tg.procs = append([]Processor{p}, tg.procs...)
+	tg.hist = append([]uint64{0}, tg.hist...)
 	tg.count++
+	tg.pinned++
+	tg.curr = 0
 	idx = 0
 	return
 }
@@ -287,7 +430,7 @@ func (tg *TimeGrinder) setSeed(data []byte) (hit bool) {
 
 	//go until we get a hit
 	for i < len(tg.procs) {
-		if _, ok, leftmost = tg.procs[i].Extract(data, tg.loc); ok {
+		if _, ok, leftmost = tg.procs[i].Extract(data, tg.location()); ok {
 			tg.curr = i
 			hit = true
 			break
@@ -296,7 +439,7 @@ func (tg *TimeGrinder) setSeed(data []byte) (hit bool) {
 	}
 	//search for something even more left
 	for i < len(tg.procs) {
-		if _, ok, offset = tg.procs[i].Extract(data, tg.loc); ok {
+		if _, ok, offset = tg.procs[i].Extract(data, tg.location()); ok {
 			if offset < leftmost {
 				leftmost = offset
 				tg.curr = i
@@ -311,11 +454,60 @@ func (tg *TimeGrinder) setSeed(data []byte) (hit bool) {
 // Extract returns time and error.  If no time can be extracted time is the zero
 // value and bool is false.  Error indicates a catastrophic failure.
 func (tg *TimeGrinder) Extract(data []byte) (t time.Time, ok bool, err error) {
+	if data, _, ok = tg.scope(data); ok {
+		var p Processor
+		if t, p, ok, err = tg.extract(data); ok && tg.srcKey != `` && zoneless(p, data) {
+			t = tg.infer(t)
+		}
+	} else {
+		tg.counters.fallback()
+	}
+	return
+}
+
+// Stats returns the extraction statistics, if the TimeGrinder was configured with shared
+// Counters the stats cover every TimeGrinder sharing them.
+func (tg *TimeGrinder) Stats() Stats {
+	return tg.counters.Stats()
+}
+
+// next returns the index of the processor to try after a miss on the c'th attempt at index i.
+// When learning, the current processor is followed by the rest in order of frequency.
+func (tg *TimeGrinder) next(i, c int) int {
+	if tg.DisableLearning {
+		return (i + 1) % tg.count
+	} else if c == 0 {
+		i = -1
+	}
+	if i++; i == tg.curr {
+		i++
+	}
+	return i
+}
+
+// learn records a successful extraction by the processor at index i and promotes it ahead of
+// processors with fewer extractions, the new index of the processor is returned.
+// Added processors are pinned to the front and are never reordered.
+func (tg *TimeGrinder) learn(i int) int {
+	tg.hist[i]++
+	if tg.DisableLearning {
+		return i
+	}
+	for ; i > tg.pinned && tg.hist[i] > tg.hist[i-1]; i-- {
+		tg.procs[i], tg.procs[i-1] = tg.procs[i-1], tg.procs[i]
+		tg.hist[i], tg.hist[i-1] = tg.hist[i-1], tg.hist[i]
+	}
+	return i
+}
+
+func (tg *TimeGrinder) extract(data []byte) (t time.Time, p Processor, ok bool, err error) {
 	var i int
 	var c int
 
 	if tg.override != nil {
-		if t, ok, _ = tg.override.Extract(data, tg.loc); ok {
+		if t, ok, _ = tg.override.Extract(data, tg.location()); ok {
+			tg.counters.hit(tg.override.Name(), true)
+			p = tg.override
 			return
 		}
 	}
@@ -328,15 +520,17 @@ func (tg *TimeGrinder) Extract(data []byte) (t time.Time, ok bool, err error) {
 
 	i = tg.curr
 	for c = 0; c < tg.count; c++ {
-		t, ok, _ = tg.procs[i].Extract(data, tg.loc)
+		t, ok, _ = tg.procs[i].Extract(data, tg.location())
 		if ok {
-			tg.curr = i
+			tg.counters.hit(tg.procs[i].Name(), c == 0)
+			p = tg.procs[i]
+			tg.curr = tg.learn(i)
 			return
 		}
-		//move the current forward
-		i = (i + 1) % tg.count
+		i = tg.next(i, c)
 	}
 	//if we hit here we failed to extract a timestamp, reset to zero the attempts at zero
+	tg.counters.fallback()
 	tg.curr = 0
 	ok = false
 	return
@@ -347,6 +541,17 @@ func (tg *TimeGrinder) Extract(data []byte) (t time.Time, ok bool, err error) {
 // the timestamp.  This is a faster way to say "a timestamp could be here".
 // ok is always true on successful match
 func (tg *TimeGrinder) Match(data []byte) (start, end int, ok bool) {
+	var base int
+	if data, base, ok = tg.scope(data); !ok {
+		return
+	}
+	if start, end, ok = tg.match(data); ok {
+		start, end = tg.origOffset(start, base), tg.origOffset(end, base)
+	}
+	return
+}
+
+func (tg *TimeGrinder) match(data []byte) (start, end int, ok bool) {
 	var i int
 	var c int
 
@@ -380,11 +585,23 @@ func (tg *TimeGrinder) Match(data []byte) (start, end int, ok bool) {
 // DebugExtract returns a time, offset, and error.  If no time was extracted, the offset is -1
 // Error indicates a catastrophic failure.
 func (tg *TimeGrinder) DebugExtract(data []byte) (t time.Time, offset int, name string, err error) {
+	var base int
+	var ok bool
+	if data, base, ok = tg.scope(data); !ok {
+		offset = -1
+		return
+	}
+	t, offset, name, err = tg.debugExtract(data)
+	offset = tg.origOffset(offset, base)
+	return
+}
+
+func (tg *TimeGrinder) debugExtract(data []byte) (t time.Time, offset int, name string, err error) {
 	var i int
 	var c int
 
 	if tg.override != nil {
-		if t, _, offset = tg.override.Extract(data, tg.loc); offset < 0 {
+		if t, _, offset = tg.override.Extract(data, tg.location()); offset < 0 {
 			return
 		}
 		name = tg.override.Name()
@@ -399,7 +616,7 @@ func (tg *TimeGrinder) DebugExtract(data []byte) (t time.Time, offset int, name
 
 	i = tg.curr
 	for c = 0; c < tg.count; c++ {
-		t, _, offset = tg.procs[i].Extract(data, tg.loc)
+		t, _, offset = tg.procs[i].Extract(data, tg.location())
 		if offset >= 0 {
 			tg.curr = i
 			name = tg.procs[i].Name()
@@ -418,12 +635,23 @@ func (tg *TimeGrinder) DebugExtract(data []byte) (t time.Time, offset int, name
 // DebugMatch attempts to match a timestamp within a given data set and returns additional metadata about
 // which processor matched and where in the data it matched
 func (tg *TimeGrinder) DebugMatch(data []byte) (ts time.Time, name string, start, end int, ok bool) {
+	var base int
+	if data, base, ok = tg.scope(data); !ok {
+		return
+	}
+	if ts, name, start, end, ok = tg.debugMatch(data); ok {
+		start, end = tg.origOffset(start, base), tg.origOffset(end, base)
+	}
+	return
+}
+
+func (tg *TimeGrinder) debugMatch(data []byte) (ts time.Time, name string, start, end int, ok bool) {
 	var i int
 	var c int
 
 	if tg.override != nil {
 		if start, end, ok = tg.override.Match(data); ok {
-			if ts, ok, _ = tg.override.Extract(data[start:end], tg.loc); ok {
+			if ts, ok, _ = tg.override.Extract(data[start:end], tg.location()); ok {
 				name = tg.override.Name()
 			}
 		}
@@ -439,7 +667,7 @@ func (tg *TimeGrinder) DebugMatch(data []byte) (ts time.Time, name string, start
 	i = tg.curr
 	for c = 0; c < tg.count; c++ {
 		if start, end, ok = tg.procs[i].Match(data); ok {
-			if ts, ok, _ = tg.procs[i].Extract(data[start:end], tg.loc); ok {
+			if ts, ok, _ = tg.procs[i].Extract(data[start:end], tg.location()); ok {
 				name = tg.procs[i].Name()
 				tg.curr = i
 				return //hit
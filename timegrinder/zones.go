@@ -0,0 +1,196 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package timegrinder
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// inferSamples is the number of consecutive agreeing observations required before
+	// an inferred source offset is locked in
+	inferSamples = 16
+	// inferStep is the granularity of inferred offsets, every zone in use is a multiple of 15 minutes
+	inferStep   = 15 * time.Minute
+	minInferOff = -12 * time.Hour
+	maxInferOff = 14 * time.Hour
+)
+
+var (
+	// probe locations used to tell zoneless timestamps apart, no real zone has an offset in seconds
+	zoneProbeA = time.FixedZone(`timegrinder-probe-a`, 1)
+	zoneProbeB = time.FixedZone(`timegrinder-probe-b`, 2)
+)
+
+// SourceZones resolves the timezone used for zoneless timestamps on a per source basis.
+// Sources are mapped to IANA timezones by address, sources that are not mapped may optionally
+// have their timezone inferred from the gap between the wall clock time in their timestamps
+// and the time they were received.  A single SourceZones may be shared by many TimeGrinders
+// and is safe for concurrent use.
+//
+// An inferred timezone is a fixed UTC offset, there is no way to tell which IANA zone produced
+// an offset and so no way to follow daylight saving time.  Once locked in, the offset is used
+// for the source until the process restarts, sources in zones that observe daylight saving time
+// should be mapped explicitly.
+type SourceZones struct {
+	nets    []zoneNet //ordered most specific first
+	infer   bool
+	mtx     sync.Mutex
+	guesses map[string]*zoneGuess
+}
+
+type zoneNet struct {
+	net  *net.IPNet
+	bits int
+	loc  *time.Location
+}
+
+type zoneGuess struct {
+	off time.Duration
+	n   int
+	loc *time.Location //set once locked in
+}
+
+// ValidateZoneMap checks a set of timezone mappings, each of the form "<CIDR or IP> <timezone>",
+// e.g. "10.1.0.0/16 America/New_York".
+func ValidateZoneMap(specs []string) error {
+	_, err := parseZoneMap(specs)
+	return err
+}
+
+// NewSourceZones builds a SourceZones from timezone mappings of the form "<CIDR or IP> <timezone>".
+// If infer is true the timezones of unmapped sources are inferred from live data and locked in once
+// they are consistent, inference is only meaningful for data that is received as it is generated.
+// Only timestamps without zone information are used for inference, and an inferred timezone is a
+// fixed offset that does not follow daylight saving time.
+func NewSourceZones(specs []string, infer bool) (sz *SourceZones, err error) {
+	var nets []zoneNet
+	if nets, err = parseZoneMap(specs); err != nil {
+		return
+	}
+	sz = &SourceZones{
+		nets:    nets,
+		infer:   infer,
+		guesses: map[string]*zoneGuess{},
+	}
+	return
+}
+
+func parseZoneMap(specs []string) (nets []zoneNet, err error) {
+	for _, spec := range specs {
+		flds := strings.Fields(spec)
+		if len(flds) != 2 {
+			return nil, fmt.Errorf("invalid timezone mapping %q, expected \"<CIDR> <timezone>\"", spec)
+		}
+		var zn zoneNet
+		if _, zn.net, err = net.ParseCIDR(flds[0]); err != nil {
+			ip := net.ParseIP(flds[0])
+			if ip == nil {
+				return nil, fmt.Errorf("invalid timezone mapping source %q", flds[0])
+			}
+			err = nil
+			zn.net = &net.IPNet{IP: ip, Mask: net.CIDRMask(len(ip)*8, len(ip)*8)}
+			if ip4 := ip.To4(); ip4 != nil {
+				zn.net = &net.IPNet{IP: ip4, Mask: net.CIDRMask(32, 32)}
+			}
+		}
+		zn.bits, _ = zn.net.Mask.Size()
+		if zn.loc, err = time.LoadLocation(flds[1]); err != nil {
+			return nil, fmt.Errorf("invalid timezone mapping zone %q: %w", flds[1], err)
+		}
+		nets = append(nets, zn)
+	}
+	sort.SliceStable(nets, func(i, j int) bool {
+		return nets[i].bits > nets[j].bits
+	})
+	return
+}
+
+// Lookup returns the timezone mapped to a source address, the most specific mapping wins
+func (sz *SourceZones) Lookup(ip net.IP) (*time.Location, bool) {
+	if sz == nil || ip == nil {
+		return nil, false
+	}
+	for _, zn := range sz.nets {
+		if zn.net.Contains(ip) {
+			return zn.loc, true
+		}
+	}
+	return nil, false
+}
+
+// Inferred returns the timezone inferred for a source once it has been locked in
+func (sz *SourceZones) Inferred(key string) (loc *time.Location, ok bool) {
+	if sz == nil || !sz.infer {
+		return
+	}
+	sz.mtx.Lock()
+	if g := sz.guesses[key]; g != nil && g.loc != nil {
+		loc, ok = g.loc, true
+	}
+	sz.mtx.Unlock()
+	return
+}
+
+// observe records the gap between the wall clock reading of a zoneless timestamp and the time it
+// was received, the offset is locked in and returned once enough consecutive observations agree
+func (sz *SourceZones) observe(key string, ts, recv time.Time) (loc *time.Location) {
+	wall := time.Date(ts.Year(), ts.Month(), ts.Day(), ts.Hour(), ts.Minute(), ts.Second(), ts.Nanosecond(), time.UTC)
+	off := wall.Sub(recv).Round(inferStep)
+	if off < minInferOff || off > maxInferOff {
+		return //replayed or badly skewed data tells us nothing
+	}
+	sz.mtx.Lock()
+	g, ok := sz.guesses[key]
+	if !ok {
+		g = &zoneGuess{off: off}
+		sz.guesses[key] = g
+	}
+	if g.loc == nil {
+		if g.off != off {
+			g.off, g.n = off, 0
+		}
+		if g.n++; g.n >= inferSamples {
+			g.loc = offsetZone(off)
+		}
+	}
+	loc = g.loc
+	sz.mtx.Unlock()
+	return
+}
+
+// zoneless reports whether the timestamp p extracts from data lacks zone information.  Zoneless
+// timestamps take their instant from the location they are parsed in, timestamps with an explicit
+// zone and epoch timestamps do not.
+func zoneless(p Processor, data []byte) bool {
+	a, okA, _ := p.Extract(data, zoneProbeA)
+	b, okB, _ := p.Extract(data, zoneProbeB)
+	return okA && okB && !a.Equal(b)
+}
+
+// offsetZone builds the fixed zone for an inferred offset, see the SourceZones notes on daylight saving time
+func offsetZone(off time.Duration) *time.Location {
+	if off == 0 {
+		return time.UTC
+	}
+	sign := '+'
+	if off < 0 {
+		sign, off = '-', -off
+	}
+	name := fmt.Sprintf("UTC%c%02d:%02d", sign, int(off.Hours()), int(off.Minutes())%60)
+	if sign == '-' {
+		off = -off
+	}
+	return time.FixedZone(name, int(off.Seconds()))
+}
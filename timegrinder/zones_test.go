@@ -0,0 +1,169 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package timegrinder
+
+import (
+	"fmt"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestZoneMap(t *testing.T) {
+	specs := []string{
+		`10.0.0.0/8 America/Denver`,
+		`10.1.0.0/16   America/New_York`,
+		`10.1.2.3 Asia/Kolkata`,
+		`fd00::/8 Europe/Berlin`,
+	}
+	if err := ValidateZoneMap(specs); err != nil {
+		t.Fatal(err)
+	}
+	sz, err := NewSourceZones(specs, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		ip   string
+		zone string
+	}{
+		{`10.9.9.9`, `America/Denver`},
+		{`10.1.9.9`, `America/New_York`},
+		{`10.1.2.3`, `Asia/Kolkata`},
+		{`fd00::1`, `Europe/Berlin`},
+		{`192.168.1.1`, ``},
+	}
+	for _, tt := range tests {
+		loc, ok := sz.Lookup(net.ParseIP(tt.ip))
+		if tt.zone == `` {
+			if ok {
+				t.Fatalf("%s: unexpected zone %v", tt.ip, loc)
+			}
+		} else if !ok || loc.String() != tt.zone {
+			t.Fatalf("%s: bad zone %v != %s", tt.ip, loc, tt.zone)
+		}
+	}
+
+	bad := [][]string{
+		{`10.0.0.0/8`},
+		{`10.0.0.0/33 UTC`},
+		{`foo America/Denver`},
+		{`10.0.0.0/8 Mars/Olympus_Mons`},
+		{`10.0.0.0/8 America/Denver extra`},
+	}
+	for _, v := range bad {
+		if err := ValidateZoneMap(v); err == nil {
+			t.Fatalf("failed to catch bad zone map %v", v)
+		}
+	}
+}
+
+func TestSourceZoneExtract(t *testing.T) {
+	sz, err := NewSourceZones([]string{`10.0.0.0/8 America/Chicago`}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tg, err := New(Config{Zones: sz})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := []byte(`2024-05-06 07:08:09 hello`)
+	local := time.Date(2024, 5, 6, 12, 8, 9, 0, time.UTC)
+	utc := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+
+	tg.SetSource(net.ParseIP(`10.1.2.3`), ``)
+	if ts, ok, _ := tg.Extract(data); !ok || !ts.Equal(local) {
+		t.Fatalf("bad mapped timestamp %v != %v", ts, local)
+	}
+	//unmapped sources use the default timezone
+	tg.SetSource(net.ParseIP(`192.168.1.1`), ``)
+	if ts, ok, _ := tg.Extract(data); !ok || !ts.Equal(utc) {
+		t.Fatalf("bad unmapped timestamp %v != %v", ts, utc)
+	}
+	//zoned timestamps are unaffected
+	tg.SetSource(net.ParseIP(`10.1.2.3`), ``)
+	exp := time.Date(2024, 5, 6, 5, 8, 9, 0, time.UTC)
+	if ts, ok, _ := tg.Extract([]byte(`2024-05-06T07:08:09+02:00 hello`)); !ok || !ts.Equal(exp) {
+		t.Fatalf("bad zoned timestamp %v != %v", ts, exp)
+	}
+}
+
+func TestSourceZoneInference(t *testing.T) {
+	sz, err := NewSourceZones(nil, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tg, err := New(Config{Zones: sz})
+	if err != nil {
+		t.Fatal(err)
+	}
+	east := time.FixedZone(`east`, 5*3600+1800)
+	ip := net.ParseIP(`192.168.1.1`)
+	tg.SetSource(ip, ``)
+	for i := 0; i < inferSamples; i++ {
+		now := time.Now()
+		ts, ok, _ := tg.Extract([]byte(now.In(east).Format(DPKGFormat) + ` hello`))
+		if !ok {
+			t.Fatal("failed to extract")
+		}
+		if i < inferSamples-1 {
+			if _, ok = sz.Inferred(ip.String()); ok {
+				t.Fatalf("zone locked in after %d samples", i+1)
+			}
+		} else if d := ts.Sub(now.Truncate(time.Second)); d != 0 {
+			t.Fatalf("locking sample was not reinterpreted: %v", d)
+		}
+	}
+	loc, ok := sz.Inferred(ip.String())
+	if !ok {
+		t.Fatal("zone was not locked in")
+	} else if loc.String() != `UTC+05:30` {
+		t.Fatalf("bad inferred zone %v", loc)
+	}
+
+	//other grinders pick up the locked zone
+	tg2, err := New(Config{Zones: sz})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tg2.SetSource(ip, ``)
+	exp := time.Date(2024, 5, 6, 1, 38, 9, 0, time.UTC)
+	if ts, ok, _ := tg2.Extract([]byte(`2024-05-06 07:08:09 hello`)); !ok || !ts.Equal(exp) {
+		t.Fatalf("bad inferred timestamp %v != %v", ts, exp)
+	}
+
+	//replayed data never locks in
+	tg.SetSource(nil, `old.log`)
+	for i := 0; i < 2*inferSamples; i++ {
+		tg.Extract([]byte(`2001-05-06 07:08:09 hello`))
+	}
+	if _, ok = sz.Inferred(`old.log`); ok {
+		t.Fatal("locked in a zone from replayed data")
+	}
+
+	//timestamps that carry a zone, or are epochs, say nothing about the source zone
+	for _, src := range []string{`zoned.log`, `epoch.log`} {
+		tg.SetSource(nil, src)
+		for i := 0; i < 2*inferSamples; i++ {
+			now := time.Now().In(east)
+			v := now.Format(time.RFC3339)
+			if src == `epoch.log` {
+				v = fmt.Sprintf("%d.000", now.Unix())
+			}
+			if ts, ok, _ := tg.Extract([]byte(v + ` hello`)); !ok {
+				t.Fatal("failed to extract")
+			} else if d := ts.Sub(now.Truncate(time.Second)); d != 0 {
+				t.Fatalf("%s: bad timestamp offset %v", src, d)
+			}
+		}
+		if _, ok = sz.Inferred(src); ok {
+			t.Fatalf("%s: locked in a zone", src)
+		}
+	}
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/Bowery/prompt"
 	"github.com/gravwell/gravwell/v3/client"
 	"github.com/gravwell/gravwell/v3/client/objlog"
+	"github.com/gravwell/gravwell/v3/ingesters/utils"
 )
 
 var (
@@ -31,6 +32,7 @@ var (
 	noCertsEnf  = flag.Bool("insecure", false, "Do NOT enforce webserver certificates, TLS operates in insecure mode")
 	noHttps     = flag.Bool("insecure-no-https", false, "Use insecure HTTP connection, passwords are shipped plaintext")
 	maxDuration = flag.String("max-duration", "", "maximum duration in the past to export data")
+	outFormat   = flag.String("format", jsonFormat, "Output format, json or parquet")
 
 	cutoff time.Time
 )
@@ -52,6 +54,11 @@ func init() {
 		}
 		cutoff = time.Now().Add(dur)
 	}
+	switch *outFormat {
+	case jsonFormat, utils.ParquetFormat:
+	default:
+		log.Fatalf("Invalid output format %q\n", *outFormat)
+	}
 }
 
 func main() {
@@ -12,9 +12,14 @@ import (
 	"github.com/gravwell/gravwell/v3/client"
 	"github.com/gravwell/gravwell/v3/client/types"
 	"github.com/gravwell/gravwell/v3/ingest/entry"
+	"github.com/gravwell/gravwell/v3/ingesters/utils"
 )
 
-const maxChunkSize = 256 * 1024 * 1024 //256MB at a time
+const (
+	maxChunkSize = 256 * 1024 * 1024 //256MB at a time
+
+	jsonFormat = `json`
+)
 
 var (
 	totalProcessed uint64
@@ -63,7 +68,7 @@ func processChunk(cli *client.Client, s, e time.Time, pth, query string) (sz int
 	var search client.Search
 	var fout *os.File
 	var rdr io.ReadCloser
-	fpath := filepath.Join(pth, s.Format("2006-01-02-15:04:05.json.gz"))
+	fpath := filepath.Join(pth, s.Format("2006-01-02-15:04:05")+chunkExt())
 
 	//check if we have already exported this chunk
 	if _, err = os.Stat(fpath); err == nil {
@@ -88,7 +93,6 @@ func processChunk(cli *client.Client, s, e time.Time, pth, query string) (sz int
 		return
 	}
 	defer fout.Close()
-	wtr := gzip.NewWriter(fout)
 	tr := types.TimeRange{
 		StartTS: entry.FromStandard(s),
 		EndTS:   entry.FromStandard(e),
@@ -97,9 +101,13 @@ func processChunk(cli *client.Client, s, e time.Time, pth, query string) (sz int
 		err = fmt.Errorf("Failed to download data %w", err)
 		return
 	}
-	sz, _ = io.Copy(wtr, rdr)
+	if *outFormat == utils.ParquetFormat {
+		sz, err = writeParquet(fout, rdr)
+	} else {
+		sz, err = writeJSON(fout, rdr)
+	}
 	rdr.Close()
-	if err = wtr.Close(); err != nil {
+	if err != nil {
 		return
 	}
 	if sz == 0 {
@@ -109,6 +117,84 @@ func processChunk(cli *client.Client, s, e time.Time, pth, query string) (sz int
 	return
 }
 
+func chunkExt() string {
+	if *outFormat == utils.ParquetFormat {
+		return `.parquet`
+	}
+	return `.json.gz`
+}
+
+func writeJSON(fout io.Writer, rdr io.Reader) (sz int64, err error) {
+	wtr := gzip.NewWriter(fout)
+	sz, _ = io.Copy(wtr, rdr)
+	err = wtr.Close()
+	return
+}
+
+// writeParquet decodes the JSON download and re-encodes it as parquet, the returned size is the size of the download
+func writeParquet(fout io.Writer, rdr io.Reader) (sz int64, err error) {
+	var pw *utils.ParquetWriter
+	var jr *utils.JSONReader
+	var ent *entry.Entry
+	cr := &countingReader{Reader: rdr}
+	th := &exportTags{ids: map[string]entry.EntryTag{}}
+	if jr, err = utils.NewJSONReader(cr, th); err != nil {
+		return
+	} else if pw, err = utils.NewParquetWriter(fout, th); err != nil {
+		return
+	}
+	for {
+		if ent, err = jr.ReadEntry(); err != nil {
+			break
+		} else if err = pw.WriteEntry(ent); err != nil {
+			break
+		}
+	}
+	if err != io.EOF {
+		pw.Close()
+		return
+	}
+	sz = cr.n
+	err = pw.Close()
+	return
+}
+
+type countingReader struct {
+	io.Reader
+	n int64
+}
+
+func (cr *countingReader) Read(b []byte) (n int, err error) {
+	n, err = cr.Reader.Read(b)
+	cr.n += int64(n)
+	return
+}
+
+// exportTags hands out local tag IDs so that the tag names in the download can be carried into the output
+type exportTags struct {
+	names []string
+	ids   map[string]entry.EntryTag
+}
+
+func (et *exportTags) OverrideTags(entry.EntryTag) {}
+
+func (et *exportTags) GetTag(name string) (tg entry.EntryTag, err error) {
+	var ok bool
+	if tg, ok = et.ids[name]; !ok {
+		tg = entry.EntryTag(len(et.names))
+		et.names = append(et.names, name)
+		et.ids[name] = tg
+	}
+	return
+}
+
+func (et *exportTags) LookupTag(tg entry.EntryTag) (string, bool) {
+	if int(tg) >= len(et.names) {
+		return ``, false
+	}
+	return et.names[tg], true
+}
+
 func resolveChunkDuration(dur time.Duration, totalSize uint64) (rdur time.Duration) {
 	if totalSize <= maxChunkSize {
 		return dur
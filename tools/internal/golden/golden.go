@@ -0,0 +1,333 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+// Package golden implements golden file test cases for the preprocessor and plugin test tools.
+//
+// A case is a directory containing:
+//
+//	config.conf   - the preprocessor configuration
+//	input.json    - a JSON array of input entries
+//	expected.json - a JSON array of the expected output entries
+//
+// Entries are encoded as objects with Tag, SRC, TS, Data (or DataBase64 for binary payloads),
+// and an optional Enumerated list of Name/Value pairs.  Cases are executed from within their
+// directory so relative paths in the configuration (plugin files, lookup tables, etc.) are
+// resolved against the case directory.
+package golden
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gravwell/gravwell/v3/ingest/entry"
+)
+
+const (
+	ConfigFile   = `config.conf`
+	InputFile    = `input.json`
+	ExpectedFile = `expected.json`
+)
+
+var (
+	ErrNoCases         = errors.New("no test cases found")
+	ErrMissingExpected = errors.New("missing " + ExpectedFile + ", run with -update to generate it")
+)
+
+// Tagger translates between tag names in the case files and the tags handed to preprocessors
+type Tagger interface {
+	NegotiateTag(string) (entry.EntryTag, error)
+	LookupTag(entry.EntryTag) (string, bool)
+}
+
+type EV struct {
+	Name  string
+	Value json.RawMessage
+}
+
+type Entry struct {
+	Tag        string
+	SRC        string `json:",omitempty"`
+	TS         string `json:",omitempty"` // RFC3339Nano
+	Data       string `json:",omitempty"`
+	DataBase64 string `json:",omitempty"`
+	Enumerated []EV   `json:",omitempty"`
+}
+
+type Case struct {
+	Name   string
+	Dir    string
+	Config []byte
+	Input  []Entry
+}
+
+// LoadCases loads every case under dir, if dir is itself a case it is the only case returned
+func LoadCases(dir string) (cases []Case, err error) {
+	if _, err = os.Stat(filepath.Join(dir, ConfigFile)); err == nil {
+		var c Case
+		if c, err = LoadCase(dir); err == nil {
+			cases = append(cases, c)
+		}
+		return
+	}
+	var dents []os.DirEntry
+	if dents, err = os.ReadDir(dir); err != nil {
+		return
+	}
+	for _, d := range dents {
+		if !d.IsDir() {
+			continue
+		}
+		pth := filepath.Join(dir, d.Name())
+		if _, err := os.Stat(filepath.Join(pth, ConfigFile)); err != nil {
+			continue
+		}
+		var c Case
+		if c, err = LoadCase(pth); err != nil {
+			return
+		}
+		cases = append(cases, c)
+	}
+	if len(cases) == 0 {
+		err = ErrNoCases
+	}
+	return
+}
+
+func LoadCase(dir string) (c Case, err error) {
+	if dir, err = filepath.Abs(dir); err != nil {
+		return
+	}
+	c.Name = filepath.Base(dir)
+	c.Dir = dir
+	if c.Config, err = os.ReadFile(filepath.Join(dir, ConfigFile)); err != nil {
+		return
+	}
+	if c.Input, err = loadEntries(filepath.Join(dir, InputFile)); err != nil {
+		err = fmt.Errorf("%s %w", InputFile, err)
+	}
+	return
+}
+
+func loadEntries(pth string) (ents []Entry, err error) {
+	var bts []byte
+	if bts, err = os.ReadFile(pth); err != nil {
+		return
+	}
+	err = json.Unmarshal(bts, &ents)
+	return
+}
+
+// Entries converts the case input into entries, negotiating tags with the tagger
+func (c Case) Entries(tg Tagger) (ents []*entry.Entry, err error) {
+	ents = make([]*entry.Entry, 0, len(c.Input))
+	for i, v := range c.Input {
+		var ent *entry.Entry
+		if ent, err = v.entry(tg); err != nil {
+			err = fmt.Errorf("input entry %d %w", i, err)
+			return
+		}
+		ents = append(ents, ent)
+	}
+	return
+}
+
+func (e Entry) entry(tg Tagger) (ent *entry.Entry, err error) {
+	ent = &entry.Entry{}
+	if ent.Tag, err = tg.NegotiateTag(e.Tag); err != nil {
+		return
+	}
+	if e.SRC != `` {
+		if ent.SRC = net.ParseIP(e.SRC); ent.SRC == nil {
+			err = fmt.Errorf("invalid SRC %q", e.SRC)
+			return
+		}
+	}
+	if e.TS != `` {
+		var ts time.Time
+		if ts, err = time.Parse(time.RFC3339Nano, e.TS); err != nil {
+			return
+		}
+		ent.TS = entry.FromStandard(ts)
+	}
+	if e.DataBase64 != `` {
+		if ent.Data, err = base64.StdEncoding.DecodeString(e.DataBase64); err != nil {
+			return
+		}
+	} else {
+		ent.Data = []byte(e.Data)
+	}
+	for _, v := range e.Enumerated {
+		var x interface{}
+		var ed entry.EnumeratedData
+		if err = json.Unmarshal(v.Value, &x); err != nil {
+			return
+		} else if ed, err = entry.InferEnumeratedData(x); err != nil {
+			err = fmt.Errorf("enumerated value %s %w", v.Name, err)
+			return
+		}
+		if err = ent.AddEnumeratedValue(entry.EnumeratedValue{Name: v.Name, Value: ed}); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// Render converts processed entries into the golden file representation
+func Render(tg Tagger, ents []*entry.Entry) (r []Entry, err error) {
+	r = make([]Entry, 0, len(ents))
+	for _, ent := range ents {
+		if ent == nil {
+			continue
+		}
+		var e Entry
+		var ok bool
+		if e.Tag, ok = tg.LookupTag(ent.Tag); !ok {
+			e.Tag = fmt.Sprintf("UNKNOWN(%d)", ent.Tag)
+		}
+		if ent.SRC != nil {
+			e.SRC = ent.SRC.String()
+		}
+		if !ent.TS.IsZero() {
+			e.TS = ent.TS.StandardTime().UTC().Format(time.RFC3339Nano)
+		}
+		if utf8.Valid(ent.Data) {
+			e.Data = string(ent.Data)
+		} else {
+			e.DataBase64 = base64.StdEncoding.EncodeToString(ent.Data)
+		}
+		for _, ev := range ent.EVB.Values() {
+			var bts []byte
+			if bts, err = json.Marshal(ev.Value); err != nil {
+				return
+			}
+			e.Enumerated = append(e.Enumerated, EV{Name: ev.Name, Value: bts})
+		}
+		r = append(r, e)
+	}
+	return
+}
+
+// Compare returns a human readable diff of the expected and actual entries, an empty diff means they match
+func Compare(expected, actual []Entry) (diff string, err error) {
+	var bb bytes.Buffer
+	if len(expected) != len(actual) {
+		fmt.Fprintf(&bb, "entry count: expected %d, got %d\n", len(expected), len(actual))
+	}
+	for i := 0; i < len(expected) || i < len(actual); i++ {
+		var exp, act []byte
+		if i < len(expected) {
+			if exp, err = canonical(expected[i]); err != nil {
+				return
+			}
+		}
+		if i < len(actual) {
+			if act, err = canonical(actual[i]); err != nil {
+				return
+			}
+		}
+		if !bytes.Equal(exp, act) {
+			fmt.Fprintf(&bb, "entry %d:\n", i)
+			if exp != nil {
+				fmt.Fprintf(&bb, "\t- %s\n", exp)
+			}
+			if act != nil {
+				fmt.Fprintf(&bb, "\t+ %s\n", act)
+			}
+		}
+	}
+	diff = bb.String()
+	return
+}
+
+// canonical encodes an entry as compact JSON with enumerated values ordered by name
+func canonical(e Entry) ([]byte, error) {
+	evs := append([]EV(nil), e.Enumerated...)
+	sort.SliceStable(evs, func(i, j int) bool { return evs[i].Name < evs[j].Name })
+	e.Enumerated = evs
+	return json.Marshal(e)
+}
+
+// Check compares the output of a case against its expected file
+func (c Case) Check(actual []Entry) (diff string, err error) {
+	var expected []Entry
+	if expected, err = loadEntries(filepath.Join(c.Dir, ExpectedFile)); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			err = ErrMissingExpected
+		}
+		return
+	}
+	return Compare(expected, actual)
+}
+
+// Update rewrites the expected file for a case
+func (c Case) Update(actual []Entry) error {
+	if actual == nil {
+		actual = []Entry{}
+	}
+	bts, err := json.MarshalIndent(actual, "", "\t")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(c.Dir, ExpectedFile), append(bts, '\n'), 0644)
+}
+
+// Run executes every case under dir using the provided function, reporting results to wtr.
+// When update is set the expected files are regenerated rather than checked.
+// The number of failed cases is returned.
+func Run(dir string, update bool, wtr io.Writer, fn func(Case) ([]Entry, error)) (failed int, err error) {
+	var cases []Case
+	if cases, err = LoadCases(dir); err != nil {
+		return
+	}
+	var wd string
+	if wd, err = os.Getwd(); err != nil {
+		return
+	}
+	defer os.Chdir(wd)
+	for _, c := range cases {
+		if err = os.Chdir(c.Dir); err != nil {
+			return
+		}
+		actual, lerr := fn(c)
+		if lerr != nil {
+			fmt.Fprintf(wtr, "FAIL\t%s\t%v\n", c.Name, lerr)
+			failed++
+			continue
+		}
+		if update {
+			if lerr = c.Update(actual); lerr != nil {
+				fmt.Fprintf(wtr, "FAIL\t%s\t%v\n", c.Name, lerr)
+				failed++
+			} else {
+				fmt.Fprintf(wtr, "UPDATED\t%s\n", c.Name)
+			}
+			continue
+		}
+		if diff, lerr := c.Check(actual); lerr != nil {
+			fmt.Fprintf(wtr, "FAIL\t%s\t%v\n", c.Name, lerr)
+			failed++
+		} else if diff != `` {
+			fmt.Fprintf(wtr, "FAIL\t%s\n%s", c.Name, diff)
+			failed++
+		} else {
+			fmt.Fprintf(wtr, "PASS\t%s\n", c.Name)
+		}
+	}
+	fmt.Fprintf(wtr, "%d cases, %d failed\n", len(cases), failed)
+	return
+}
@@ -0,0 +1,139 @@
+/*************************************************************************
+ * Copyright 2026 Gravwell, Inc. All rights reserved.
+ * Contact: <legal@gravwell.io>
+ *
+ * This software may be modified and distributed under the terms of the
+ * BSD 2-clause license. See the LICENSE file for details.
+ **************************************************************************/
+
+package golden
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gravwell/gravwell/v3/ingest/entry"
+)
+
+type testTagger map[string]entry.EntryTag
+
+func (tt testTagger) NegotiateTag(v string) (entry.EntryTag, error) {
+	tg, ok := tt[v]
+	if !ok {
+		tg = entry.EntryTag(len(tt))
+		tt[v] = tg
+	}
+	return tg, nil
+}
+
+func (tt testTagger) LookupTag(tg entry.EntryTag) (string, bool) {
+	for k, v := range tt {
+		if v == tg {
+			return k, true
+		}
+	}
+	return ``, false
+}
+
+const testInput = `[
+	{"Tag": "foo", "SRC": "10.0.0.1", "TS": "2026-01-02T03:04:05.123Z", "Data": "hello", "Enumerated": [{"Name": "a", "Value": 1}, {"Name": "b", "Value": "x"}]},
+	{"Tag": "bar", "DataBase64": "AAEC/w=="}
+]`
+
+func TestRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, ConfigFile), nil, 0644); err != nil {
+		t.Fatal(err)
+	} else if err = os.WriteFile(filepath.Join(dir, InputFile), []byte(testInput), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cases, err := LoadCases(dir)
+	if err != nil {
+		t.Fatal(err)
+	} else if len(cases) != 1 {
+		t.Fatalf("bad case count %d", len(cases))
+	}
+	tt := testTagger{}
+	ents, err := cases[0].Entries(tt)
+	if err != nil {
+		t.Fatal(err)
+	} else if len(ents) != 2 || !bytes.Equal(ents[1].Data, []byte{0, 1, 2, 0xff}) {
+		t.Fatalf("bad entries %+v", ents)
+	}
+	out, err := Render(tt, ents)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// rendered output must match the input exactly
+	if diff, err := Compare(cases[0].Input, out); err != nil {
+		t.Fatal(err)
+	} else if diff != `` {
+		t.Fatalf("round trip mismatch:\n%s", diff)
+	}
+
+	// missing expected file is a failure
+	if _, err = cases[0].Check(out); err != ErrMissingExpected {
+		t.Fatalf("bad error on missing expected: %v", err)
+	} else if err = cases[0].Update(out); err != nil {
+		t.Fatal(err)
+	} else if diff, err := cases[0].Check(out); err != nil || diff != `` {
+		t.Fatalf("check after update failed: %v %q", err, diff)
+	}
+
+	// change an EV and make sure it is caught
+	ents[0].EVB = entry.EVBlock{}
+	ents[0].AddEnumeratedValue(entry.EnumeratedValue{Name: `a`, Value: entry.StringEnumData(`changed`)})
+	if out, err = Render(tt, ents); err != nil {
+		t.Fatal(err)
+	}
+	if diff, err := cases[0].Check(out); err != nil {
+		t.Fatal(err)
+	} else if !strings.Contains(diff, `entry 0:`) || strings.Contains(diff, `entry 1:`) {
+		t.Fatalf("bad diff:\n%s", diff)
+	}
+}
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{`one`, `two`} {
+		pth := filepath.Join(dir, name)
+		if err := os.Mkdir(pth, 0755); err != nil {
+			t.Fatal(err)
+		} else if err = os.WriteFile(filepath.Join(pth, ConfigFile), []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		} else if err = os.WriteFile(filepath.Join(pth, InputFile), []byte(`[{"Tag": "foo", "Data": "x"}]`), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	passthrough := func(c Case) ([]Entry, error) {
+		return c.Input, nil
+	}
+	var bb bytes.Buffer
+	if failed, err := Run(dir, false, &bb, passthrough); err != nil {
+		t.Fatal(err)
+	} else if failed != 2 {
+		t.Fatalf("missing expected files not caught: %d\n%s", failed, bb.String())
+	}
+	if failed, err := Run(dir, true, &bb, passthrough); err != nil || failed != 0 {
+		t.Fatalf("update failed: %d %v", failed, err)
+	}
+	if failed, err := Run(dir, false, &bb, passthrough); err != nil || failed != 0 {
+		t.Fatalf("check failed: %d %v", failed, err)
+	}
+	modify := func(c Case) ([]Entry, error) {
+		r := append([]Entry(nil), c.Input...)
+		if string(c.Config) == `two` {
+			r[0].Data = `y`
+		}
+		return r, nil
+	}
+	bb.Reset()
+	if failed, err := Run(dir, false, &bb, modify); err != nil {
+		t.Fatal(err)
+	} else if failed != 1 || !strings.Contains(bb.String(), "FAIL\ttwo") {
+		t.Fatalf("modified output not caught: %d\n%s", failed, bb.String())
+	}
+}
@@ -25,21 +25,34 @@ import (
 	"github.com/gravwell/gravwell/v3/ingest/entry"
 	"github.com/gravwell/gravwell/v3/ingest/processors"
 	"github.com/gravwell/gravwell/v3/ingesters/utils"
+	"github.com/gravwell/gravwell/v3/tools/internal/golden"
 )
 
 var (
-	configPath = flag.String("config-path", "", "Path to the plugin configuration")
+	configPath = flag.String("config-path", "", "Path to the plugin or script configuration")
 	dataPath   = flag.String("data-path", "", "Optional path to data export file")
 	fmtF       = flag.String("import-format", "", "Set the import file format manually")
 	verbose    = flag.Bool("verbose", false, "Print each entry as its processed")
+	testDir    = flag.String("test-dir", "", "Run the golden file test cases in a directory instead of processing a data file")
+	update     = flag.Bool("update", false, "Regenerate expected output for the test cases in -test-dir")
 )
 
 func main() {
 	flag.Parse()
-	var pc processors.PluginConfig
-	var p *processors.Plugin
+	if *testDir != `` {
+		failed, err := golden.Run(*testDir, *update, os.Stdout, runCase)
+		if err != nil {
+			fmt.Printf("Failed to run test cases in %q: %v\n", *testDir, err)
+			os.Exit(1)
+		} else if failed > 0 {
+			os.Exit(1)
+		}
+		return
+	}
+	var p processors.Processor
 	var rdr utils.ReimportReader
 	var vc *config.VariableConfig
+	var ptype string
 	var err error
 	if *configPath == `` {
 		fmt.Println("missing config-path")
@@ -48,14 +61,11 @@ func main() {
 	if config_data, err := os.ReadFile(*configPath); err != nil {
 		fmt.Printf("Failed to load plugin config file %q: %v\n", *configPath, err)
 		os.Exit(1)
-	} else if vc, err = loadPluginConfig(config_data); err != nil {
+	} else if ptype, vc, err = loadPluginConfig(config_data); err != nil {
 		fmt.Printf("Failed to load plugin config: %v\n", err)
 		os.Exit(1)
-	} else if pc, err = processors.PluginLoadConfig(vc); err != nil {
-		fmt.Printf("Failed to PluginLoadConfig: %v\n", err)
-		os.Exit(1)
-	} else if p, err = processors.NewPluginProcessor(pc, &testTagHandler{}); err != nil {
-		fmt.Printf("Failed to create plugin: %v\n", err)
+	} else if p, err = newProcessor(ptype, vc, &testTagHandler{}); err != nil {
+		fmt.Printf("Failed to create %s: %v\n", ptype, err)
 		os.Exit(1)
 	}
 
@@ -122,6 +132,34 @@ func main() {
 	fmt.Println("PROCESSING RATE:", ingest.HumanEntryRate(uint64(input), dur))
 }
 
+// runCase hands the case input to the plugin as a single batch and then flushes it
+func runCase(c golden.Case) (out []golden.Entry, err error) {
+	var vc *config.VariableConfig
+	var ptype string
+	var p processors.Processor
+	var ents, set []*entry.Entry
+	th := &testTagHandler{}
+	if ptype, vc, err = loadPluginConfig(c.Config); err != nil {
+		return
+	} else if p, err = newProcessor(ptype, vc, th); err != nil {
+		return
+	}
+	if ents, err = c.Entries(th); err != nil {
+		p.Close()
+		return
+	} else if len(ents) > 0 {
+		if set, err = p.Process(ents); err != nil {
+			p.Close()
+			return
+		}
+	}
+	set = append(append([]*entry.Entry(nil), set...), p.Flush()...)
+	if err = p.Close(); err != nil {
+		return
+	}
+	return golden.Render(th, set)
+}
+
 func popEnts(rdr utils.ReimportReader) (ents []*entry.Entry, err error) {
 	cnt := rand.Intn(15) + 1
 	for i := 0; i < cnt; i++ {
@@ -138,7 +176,7 @@ func popEnts(rdr utils.ReimportReader) (ents []*entry.Entry, err error) {
 	return
 }
 
-func loadPluginConfig(cnt []byte) (r *config.VariableConfig, err error) {
+func loadPluginConfig(cnt []byte) (ptype string, r *config.VariableConfig, err error) {
 	var cfg testConfig
 	//load it up and make sure there is exactly one preprocessor config defined
 	if err = config.LoadConfigBytes(&cfg, cnt); err != nil {
@@ -147,19 +185,39 @@ func loadPluginConfig(cnt []byte) (r *config.VariableConfig, err error) {
 		err = fmt.Errorf("plugin config does not contain exactly one plugin configuration: count %d", len(cfg.Preprocessor))
 		return
 	}
-	//grab the preprocessor and check that the defined preprocessor is of type plugin
+	//grab the preprocessor and check that the defined preprocessor is of type plugin or script
 	ptype, vc, ok := cfg.pop()
 	if !ok || vc == nil {
 		err = fmt.Errorf("failed to pull plugin configuration")
 		return
-	} else if ptype != "plugin" {
-		err = fmt.Errorf("Configuration stanza is of the wrong type: %q != plugin", ptype)
+	}
+	switch ptype = strings.TrimSpace(strings.ToLower(ptype)); ptype {
+	case processors.PluginProcessor, processors.ScriptProcessor:
+	default:
+		err = fmt.Errorf("Configuration stanza is of the wrong type: %q is not %s or %s", ptype, processors.PluginProcessor, processors.ScriptProcessor)
 		return
 	}
 	r = vc
 	return
 }
 
+// newProcessor builds the plugin or script preprocessor described by the config
+func newProcessor(ptype string, vc *config.VariableConfig, tgr processors.Tagger) (p processors.Processor, err error) {
+	switch ptype {
+	case processors.ScriptProcessor:
+		var sc processors.ScriptConfig
+		if sc, err = processors.ScriptLoadConfig(vc); err == nil {
+			p, err = processors.NewScript(sc, tgr)
+		}
+	default:
+		var pc processors.PluginConfig
+		if pc, err = processors.PluginLoadConfig(vc); err == nil {
+			p, err = processors.NewPluginProcessor(pc, tgr)
+		}
+	}
+	return
+}
+
 type testConfig struct {
 	Preprocessor map[string]*config.VariableConfig
 }
@@ -25,6 +25,7 @@ import (
 	"github.com/gravwell/gravwell/v3/ingest/entry"
 	"github.com/gravwell/gravwell/v3/ingest/processors"
 	"github.com/gravwell/gravwell/v3/ingesters/utils"
+	"github.com/gravwell/gravwell/v3/tools/internal/golden"
 )
 
 var (
@@ -32,10 +33,22 @@ var (
 	dataPath   = flag.String("data-path", "", "Optional path to data export file")
 	fmtF       = flag.String("import-format", "", "Set the import file format manually")
 	verbose    = flag.Bool("verbose", false, "Print each entry as its processed")
+	testDir    = flag.String("test-dir", "", "Run the golden file test cases in a directory instead of processing a data file")
+	update     = flag.Bool("update", false, "Regenerate expected output for the test cases in -test-dir")
 )
 
 func main() {
 	flag.Parse()
+	if *testDir != `` {
+		failed, err := golden.Run(*testDir, *update, os.Stdout, runCase)
+		if err != nil {
+			fmt.Printf("Failed to run test cases in %q: %v\n", *testDir, err)
+			os.Exit(1)
+		} else if failed > 0 {
+			os.Exit(1)
+		}
+		return
+	}
 	var rdr utils.ReimportReader
 	var ps *processors.ProcessorSet
 	var ew *entWriter
@@ -98,6 +111,30 @@ func main() {
 	fmt.Println("PROCESSING RATE:", ingest.HumanEntryRate(uint64(input), dur))
 }
 
+// runCase pushes the case input through the configured preprocessors as a single batch
+func runCase(c golden.Case) (out []golden.Entry, err error) {
+	var ew *entWriter
+	var ps *processors.ProcessorSet
+	var ents []*entry.Entry
+	if ew, ps, err = loadConfig(c.Config); err != nil {
+		return
+	}
+	ew.capture = true
+	if ents, err = c.Entries(ew); err != nil {
+		ps.Close()
+		return
+	} else if len(ents) > 0 {
+		if err = ps.ProcessBatch(ents); err != nil {
+			ps.Close()
+			return
+		}
+	}
+	if err = ps.Close(); err != nil {
+		return
+	}
+	return golden.Render(ew, ew.ents)
+}
+
 func popEnts(rdr utils.ReimportReader) (ents []*entry.Entry, err error) {
 	cnt := rand.Intn(15) + 1
 	for i := 0; i < cnt; i++ {
@@ -199,8 +236,10 @@ func (tth *testTagHandler) KnownTags() (r []string) {
 
 type entWriter struct {
 	testTagHandler
-	count uint64
-	bytes uint64
+	count   uint64
+	bytes   uint64
+	capture bool // hang onto written entries for golden file tests
+	ents    []*entry.Entry
 }
 
 func (ew *entWriter) WriteEntry(ent *entry.Entry) (err error) {
@@ -220,6 +259,9 @@ func (ew *entWriter) WriteEntry(ent *entry.Entry) (err error) {
 		}
 		ew.count++
 		ew.bytes = ent.Size()
+		if ew.capture {
+			ew.ents = append(ew.ents, ent)
+		}
 	}
 	return
 }
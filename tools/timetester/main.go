@@ -56,6 +56,8 @@ func main() {
 				Regex:            v.Regex,
 				Format:           v.Format,
 				Extraction_Regex: v.Extraction_Regex,
+				Strftime_Format:  v.Strftime_Format,
+				Java_Format:      v.Java_Format,
 			}
 			if cp, err := timegrinder.NewCustomProcessor(cf); err != nil {
 				log.Fatalf("Invalid custom format %q: %v\n", k, err)